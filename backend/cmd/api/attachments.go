@@ -0,0 +1,27 @@
+package main
+
+import (
+	"todo_app_golang/internal/config"
+	"todo_app_golang/internal/domain"
+	"todo_app_golang/internal/infrastructure"
+)
+
+// newBlobStore は attachments.store で選んだ添付ファイルの中身の保存先を返します。
+// デモモードの場合は DB と同じく、設定に関わらずメモリ上に保存する
+func newBlobStore(cfg config.AttachmentsConfig, demo bool) (domain.BlobStore, error) {
+	switch {
+	case demo:
+		return infrastructure.NewMemoryBlobStore(), nil
+	case cfg.Store == config.BlobStoreS3:
+		return infrastructure.NewS3BlobStore(infrastructure.S3BlobStoreOptions{
+			Endpoint:        cfg.S3.Endpoint,
+			Region:          cfg.S3.Region,
+			Bucket:          cfg.S3.Bucket,
+			AccessKeyID:     cfg.S3.AccessKeyID,
+			SecretAccessKey: cfg.S3.SecretAccessKey,
+			PathStyle:       cfg.S3.PathStyle,
+		})
+	default:
+		return infrastructure.NewLocalBlobStore(cfg.Dir)
+	}
+}
@@ -0,0 +1,20 @@
+package main
+
+import (
+	"net/http"
+
+	"todo_app_golang/internal/config"
+	"todo_app_golang/internal/interface/middleware"
+)
+
+// authSkipPaths は認証しないパスです（監視・API 仕様と、トークンで保護しているカレンダーフィード）
+var authSkipPaths = []string{"/healthz", "/readyz", "/metrics", "/openapi.json", "/calendar.ics"}
+
+// newAuthenticate は認証プロキシが付けたヘッダーからユーザーを取り出すミドルウェアを返します。
+// auth.user_header が空の場合は何もしないミドルウェアを返します（タスクの所有者による権限の確認も行わない）
+func newAuthenticate(cfg config.AuthConfig) func(http.Handler) http.Handler {
+	if cfg.UserHeader == "" {
+		return func(next http.Handler) http.Handler { return next }
+	}
+	return middleware.Authenticate(cfg.UserHeader, authSkipPaths)
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+
+	"todo_app_golang/internal/config"
+	"todo_app_golang/internal/domain"
+	"todo_app_golang/internal/infrastructure"
+	"todo_app_golang/internal/logging"
+)
+
+// newCachedTodos は todos の取得結果を cfg.Backend にキャッシュするリポジトリを返します。
+// backend が none の場合は nil を返します。closeCache は終了時にキャッシュへの接続を閉じます
+func newCachedTodos(ctx context.Context, todos domain.TodoRepository, cfg config.CacheConfig) (cached *infrastructure.CachedTodoRepository, closeCache func() error) {
+	var store infrastructure.CacheStore
+	closeCache = func() error { return nil }
+
+	switch cfg.Backend {
+	case config.CacheMemory:
+		store = infrastructure.NewLRUCache(cfg.Size)
+	case config.CacheRedis:
+		redis := infrastructure.NewRedisCache(cfg.RedisURL)
+		// キャッシュに接続できなくても DB から返せるため、起動は止めずに警告だけ出す
+		if err := redis.Ping(ctx); err != nil {
+			logging.FromContext(ctx).WarnContext(ctx, "Cache is unreachable, reading from DB", slog.Any("error", err))
+		}
+		store, closeCache = redis, redis.Close
+	default:
+		return nil, closeCache
+	}
+	return infrastructure.NewCachedTodoRepository(todos, store, cfg.ListTTL, cfg.ItemTTL), closeCache
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"todo_app_golang/internal/domain"
+)
+
+// demoTodo はデモ用のサンプルデータです。日時は起動時刻からの相対値で指定します
+type demoTodo struct {
+	title       string
+	description string
+	priority    string
+	createdAgo  time.Duration
+	dueIn       *time.Duration // nil の場合は期限なし
+	doneAgo     *time.Duration // nil の場合は未完了
+}
+
+func hours(h int) *time.Duration {
+	d := time.Duration(h) * time.Hour
+	return &d
+}
+
+// demoTodos は統計画面にも値が出るよう、期限切れ・今日・今週が期限のタスクと過去2週間の完了済みタスクを含めています
+var demoTodos = []demoTodo{
+	{title: "牛乳を買う @買い物", priority: "low", createdAgo: 2 * time.Hour, dueIn: hours(5)},
+	{title: "週次レポートを提出する +仕事", description: "先週分の進捗をまとめる", priority: "high", createdAgo: 72 * time.Hour, dueIn: hours(-20)},
+	{title: "歯医者を予約する", priority: "medium", createdAgo: 30 * time.Hour, dueIn: hours(50)},
+	{title: "API のレビュー +仕事", description: "統計 API のプルリクエスト", priority: "high", createdAgo: 8 * time.Hour, dueIn: hours(26)},
+	{title: "部屋の掃除 +家", priority: "low", createdAgo: 120 * time.Hour},
+	{title: "Go の本を読む +勉強", priority: "medium", createdAgo: 200 * time.Hour, dueIn: hours(24 * 14)},
+	{title: "リリースノートを書く +仕事", priority: "high", createdAgo: 340 * time.Hour, doneAgo: hours(300)},
+	{title: "電気代を払う +家", priority: "high", createdAgo: 260 * time.Hour, doneAgo: hours(250)},
+	{title: "会議の議事録 +仕事", priority: "medium", createdAgo: 170 * time.Hour, doneAgo: hours(160)},
+	{title: "ランニング 5km", priority: "low", createdAgo: 100 * time.Hour, doneAgo: hours(75)},
+	{title: "バグ修正 #42 +仕事", priority: "high", createdAgo: 60 * time.Hour, doneAgo: hours(30)},
+	{title: "洗濯 +家", priority: "low", createdAgo: 10 * time.Hour, doneAgo: hours(3)},
+}
+
+// seedDemoData はサンプルデータを repo に登録します
+func seedDemoData(ctx context.Context, repo domain.TodoRepository, now time.Time) error {
+	for _, d := range demoTodos {
+		todo := &domain.Todo{
+			Title:       d.title,
+			Description: d.description,
+			Priority:    d.priority,
+			CreatedAt:   now.Add(-d.createdAgo),
+		}
+		if d.dueIn != nil {
+			due := now.Add(*d.dueIn)
+			todo.DueDate = &due
+		}
+		if d.doneAgo != nil {
+			done := now.Add(-*d.doneAgo)
+			todo.IsCompleted = true
+			todo.CompletedAt = &done
+		}
+		if err := repo.Create(ctx, todo); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"net"
+
+	"todo_app_golang/internal/config"
+	"todo_app_golang/internal/interface/grpcapi"
+	"todo_app_golang/internal/server"
+)
+
+// newGRPCWorkers は gRPC API を待ち受けるワーカーを返します。grpc.addr が空の場合は何も返しません。
+// ポートを使えない場合に起動時に気付けるよう、待ち受けはここで始めます。
+// auth.user_header が設定されている場合は、REST API と同じ名前のメタデータでユーザーを認証します
+func newGRPCWorkers(logger *slog.Logger, cfg config.GRPCConfig, auth config.AuthConfig, todos grpcapi.TodoUseCaseInterface) ([]server.Worker, error) {
+	if cfg.Addr == "" {
+		return nil, nil
+	}
+	ln, err := net.Listen("tcp", cfg.Addr)
+	if err != nil {
+		return nil, err
+	}
+
+	srv := grpcapi.NewServer(logger, todos, grpcapi.Options{UserMetadataKey: auth.UserHeader})
+	return []server.Worker{{
+		Name: "grpc",
+		Run: func(ctx context.Context) error {
+			logger.Info("gRPC server starting", slog.String("addr", cfg.Addr))
+			return srv.Serve(ctx, ln)
+		},
+	}}, nil
+}
@@ -1,51 +1,202 @@
 package main
 
 import (
-	"log"
-	"net/http"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/rs/cors"
 
-	"todo_app_golang/internal/infrastructure"
-	"todo_app_golang/internal/interface/handler"
+	"todo_app_golang/internal/config"
+	"todo_app_golang/internal/interface/graphqlapi"
+	"todo_app_golang/internal/interface/health"
+	"todo_app_golang/internal/interface/metrics"
+	"todo_app_golang/internal/interface/middleware"
+	"todo_app_golang/internal/interface/openapi"
+	"todo_app_golang/internal/logging"
+	"todo_app_golang/internal/server"
+	"todo_app_golang/internal/tracing"
 	"todo_app_golang/internal/usecase"
 )
 
 func main() {
-	// 1. DB接続 (インフラ層)
-	db, err := infrastructure.NewDB()
+	// サブコマンド: api migrate [flags] up|down|version|force (未指定の場合はサーバーを起動)
+	args := os.Args[1:]
+	migrateCommand := len(args) > 0 && args[0] == "migrate"
+	if migrateCommand {
+		args = args[1:]
+	}
+
+	// 0. 設定 (既定値 < 設定ファイル < 環境変数 < コマンドライン引数)
+	cfg, opts, err := config.Load(args, os.LookupEnv, os.Stderr)
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return
+		}
+		fmt.Fprintln(os.Stderr, "invalid config:", err)
+		os.Exit(2)
+	}
+	if opts.PrintConfig {
+		cfg.WriteYAML(os.Stdout)
+		return
+	}
+	if migrateCommand {
+		os.Exit(runMigrate(cfg, opts.Args))
+	}
+	if len(opts.Args) > 0 {
+		fmt.Fprintf(os.Stderr, "unknown command: %q\n", opts.Args[0])
+		os.Exit(2)
+	}
+
+	// ロガー (JSON形式)
+	logger := logging.New(os.Stdout, cfg.Log.Level)
+	slog.SetDefault(logger)
+	logger.Info("Config loaded", slog.String("file", opts.File), slog.Any("config", cfg))
+
+	// API 仕様 (リクエストは仕様で検証してからハンドラーに渡す)
+	spec, err := openapi.Load()
+	if err != nil {
+		logger.Error("Failed to load OpenAPI spec", slog.Any("error", err))
+		os.Exit(1)
+	}
+
+	// トレース
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Config{
+		Exporter:    cfg.Tracing.Exporter,
+		FilePath:    cfg.Tracing.File,
+		ServiceName: cfg.Tracing.ServiceName,
+	})
+	if err != nil {
+		logger.Error("Failed to set up tracing", slog.Any("error", err))
+		os.Exit(1)
+	}
+
+	// 1. DB接続 (インフラ層。ドライバーは database.driver で選択、-demo の場合はメモリ上)
+	var store *storage
+	if opts.Demo {
+		store, err = newDemoStorage(context.Background())
+		logger.Warn("Demo mode: data is kept in memory and lost on restart")
+	} else {
+		store, err = openStorage(cfg.Database)
+	}
+	if err != nil {
+		logger.Error("Failed to connect to DB", slog.Any("error", err))
+		shutdownTracing(context.Background())
+		os.Exit(1)
+	}
+
+	// 起動時のマイグレーション (複数インスタンスが同時に起動してもアドバイザリロックで1つずつ実行される)
+	if store.migrator != nil && migrateOnStart(cfg.Database) {
+		if err := store.migrator.Up(logging.WithLogger(context.Background(), logger)); err != nil {
+			logger.Error("Failed to run migrations", slog.Any("error", err))
+			store.close()
+			shutdownTracing(context.Background())
+			os.Exit(1)
+		}
+	}
+
+	// 添付ファイルの中身の保存先 (attachments.store で選択)
+	blobs, err := newBlobStore(cfg.Attachments, opts.Demo)
 	if err != nil {
-		log.Fatalf("Failed to connect to DB: %v", err)
+		logger.Error("Failed to set up attachment storage", slog.Any("error", err))
+		store.close()
+		shutdownTracing(context.Background())
+		os.Exit(1)
 	}
-	defer db.Close()
 
 	// 2. 依存注入 (DI)
-	repo := infrastructure.NewTodoRepository(db)
-	todoUseCase := usecase.NewTodoUseCase(repo)
-	todoHandler := handler.NewTodoHandler(todoUseCase) // ハンドラーを生成
+	// 取得結果のキャッシュ (cache.backend が none 以外の場合)
+	todos := store.todos
+	cachedTodos, closeCache := newCachedTodos(logging.WithLogger(context.Background(), logger), store.todos, cfg.Cache)
+	if cachedTodos != nil {
+		todos = cachedTodos
+	}
+	todoUseCase := usecase.NewTodoUseCase(todos)
+	commentUseCase := usecase.NewCommentUseCase(todos, store.comments)
+	attachmentUseCase := usecase.NewAttachmentUseCase(todos, store.attachments, blobs, cfg.Attachments.MaxSize, cfg.Attachments.AllowedTypes)
+	todoUseCase.OnDelete(attachmentUseCase.DeleteTodoAttachments) // タスクの削除で情報だけが消え、中身が残らないようにする
+	statsUseCase := usecase.NewStatsUseCase(store.stats)
+	appMetrics := metrics.New(store.db, statsUseCase)
+	if cachedTodos != nil {
+		appMetrics.RegisterCache(func() (uint64, uint64) {
+			s := cachedTodos.Stats()
+			return s.Hits, s.Misses
+		})
+	}
+	// gRPC API (grpc.addr が空の場合は起動しない。HTTP と同じく終了時は処理中の RPC を待つ)
+	grpcWorkers, err := newGRPCWorkers(logger, cfg.GRPC, cfg.Auth, todoUseCase)
+	if err != nil {
+		logger.Error("Failed to listen for gRPC", slog.String("addr", cfg.GRPC.Addr), slog.Any("error", err))
+		closeCache()
+		store.close()
+		shutdownTracing(context.Background())
+		os.Exit(1)
+	}
+	workers := server.NewWorkers(grpcWorkers...)
+	healthHandler := health.NewHandler(append(store.healthChecks(),
+		health.Check{Name: "workers", Run: workers.Check},
+	)...)
 
 	// 3. ルーティング
-	mux := http.NewServeMux()
-
-	// インターフェース層のメソッドを紐付け
-	mux.HandleFunc("POST /todos", todoHandler.CreateTodoHandler)
-	mux.HandleFunc("GET /todos", todoHandler.GetAllTodosHandler)
-	mux.HandleFunc("GET /todos/{id}", todoHandler.GetTodoByIDHandler)
-	mux.HandleFunc("DELETE /todos/{id}", todoHandler.DeleteTodoHandler)
-	mux.HandleFunc("PATCH /todos/{id}", todoHandler.UpdateTodoStatusHandler)
+	graphqlHandler := graphqlapi.NewHandler(todoUseCase, statsUseCase, graphqlapi.Options{AllowedOrigins: cfg.CORS.AllowedOrigins})
+	mux := newMux(
+		apiRoutes(todoUseCase, todoUseCase, commentUseCase, attachmentUseCase, statsUseCase, cfg.Calendar.FeedToken),
+		graphqlRoutes(graphqlHandler),
+		opsRoutes(appMetrics.Handler(), healthHandler, spec),
+	)
 
 	// CORS 設定
 	c := cors.New(cors.Options{
-		AllowedOrigins: []string{"http://localhost:5173"}, // フロントエンドのURLを許可
+		AllowedOrigins: cfg.CORS.AllowedOrigins, // フロントエンドのURLを許可
 		AllowedMethods: []string{"GET", "POST", "PATCH", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders: []string{"Content-Type", "Authorization"},
+		AllowedHeaders: []string{"Content-Type", "Authorization", middleware.RequestIDHeader, "traceparent", "tracestate"},
+		ExposedHeaders: []string{
+			middleware.RequestIDHeader, "Retry-After",
+			middleware.RateLimitLimitHeader, middleware.RateLimitRemainingHeader, middleware.RateLimitResetHeader,
+		},
 	})
 
-	// mux を cors ハンドラーで包む
-	handler := c.Handler(mux)
+	// レート制限 (rate_limit.backend が none 以外の場合)
+	rateLimit := newRateLimit(logging.WithLogger(context.Background(), logger), cfg.RateLimit, store)
+
+	// 認証 (auth.user_header が設定されている場合。レート制限より先に行い、ユーザーごとに制限する)
+	authenticate := newAuthenticate(cfg.Auth)
+
+	// mux をリクエストの検証・レート制限・認証・メトリクス計測・リクエストログ・トレース・cors ハンドラーで包む
+	handler := c.Handler(tracing.HTTPHandler(middleware.RequestLogger(logger)(appMetrics.Middleware(authenticate(rateLimit(spec.Middleware(mux)))))))
+
+	// 4. 起動 (SIGINT / SIGTERM を受けたら処理中のリクエストを捌いてから終了する)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	logger.Info("Server starting", slog.String("addr", cfg.Server.Addr))
+	srv := server.New(cfg.Server, handler)
+	srv.RegisterOnShutdown(graphqlHandler.Shutdown) // Shutdown はハイジャックした WebSocket の接続を待たないため、こちらで閉じる
+	runErr := server.Run(logging.WithLogger(ctx, logger), srv, cfg.Server.ShutdownTimeout, workers)
+	if runErr != nil {
+		logger.Error("Server stopped", slog.Any("error", runErr))
+	}
+
+	// リクエストがすべて終わってからトレースを書き出し、キャッシュと DB への接続を閉じる
+	flushCtx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+	defer cancel()
+	if err := shutdownTracing(flushCtx); err != nil {
+		logger.Error("Failed to flush traces", slog.Any("error", err))
+	}
+	if err := closeCache(); err != nil {
+		logger.Error("Failed to close cache", slog.Any("error", err))
+	}
+	if err := store.close(); err != nil {
+		logger.Error("Failed to close DB", slog.Any("error", err))
+	}
 
-	log.Println("Server starting on :8080...")
-	if err := http.ListenAndServe(":8080", handler); err != nil {
-		log.Fatal(err)
+	if runErr != nil {
+		os.Exit(1)
 	}
+	logger.Info("Server stopped")
 }
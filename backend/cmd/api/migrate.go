@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+
+	"todo_app_golang/internal/config"
+	"todo_app_golang/internal/logging"
+)
+
+const migrateUsage = `usage: api migrate [flags] <command>
+
+commands:
+  up             未適用のマイグレーションをすべて適用する
+  down [N|all]   直近のマイグレーションを N 個（既定 1）取り消す。all の場合はすべて取り消す
+  version        現在のスキーマのバージョンを表示する
+  force V        dirty になったスキーマをバージョン V として記録する（SQL は実行しない）`
+
+// runMigrate は migrate サブコマンドを実行し、終了コードを返します
+func runMigrate(cfg *config.Config, args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		return 2
+	}
+
+	logger := logging.New(os.Stderr, cfg.Log.Level)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	ctx = logging.WithLogger(ctx, logger)
+
+	store, err := openStorage(cfg.Database)
+	if err != nil {
+		logger.Error("Failed to connect to DB", slog.Any("error", err))
+		return 1
+	}
+	defer store.db.Close()
+	migrator := store.migrator
+
+	switch cmd, rest := args[0], args[1:]; {
+	case cmd == "up" && len(rest) == 0:
+		err = migrator.Up(ctx)
+	case cmd == "down" && len(rest) <= 1:
+		steps := 1
+		if len(rest) == 1 {
+			if steps, err = parseSteps(rest[0]); err != nil {
+				break
+			}
+		}
+		err = migrator.Down(ctx, steps)
+	case cmd == "version" && len(rest) == 0:
+		var (
+			version uint
+			dirty   bool
+		)
+		if version, dirty, err = migrator.Version(ctx); err == nil {
+			if dirty {
+				fmt.Printf("%d (dirty)\n", version)
+			} else {
+				fmt.Println(version)
+			}
+		}
+	case cmd == "force" && len(rest) == 1:
+		var version int
+		if version, err = strconv.Atoi(rest[0]); err == nil {
+			err = migrator.Force(ctx, version)
+		}
+	default:
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		return 2
+	}
+
+	if err != nil {
+		logger.Error("Migration failed", slog.String("command", args[0]), slog.Any("error", err))
+		return 1
+	}
+	return 0
+}
+
+// parseSteps は down の引数を解釈します。all は 0（すべて）を表します
+func parseSteps(s string) (int, error) {
+	if s == "all" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid number of steps: %q", s)
+	}
+	return n, nil
+}
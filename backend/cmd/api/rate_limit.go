@@ -0,0 +1,42 @@
+package main
+
+import (
+	"context"
+	"net/http"
+
+	"todo_app_golang/internal/config"
+	"todo_app_golang/internal/infrastructure"
+	"todo_app_golang/internal/interface/middleware"
+	"todo_app_golang/internal/logging"
+	"todo_app_golang/internal/ratelimit"
+)
+
+// rateLimitSkipPaths はレート制限をかけないパスです（監視やオーケストレーターからの定期的なリクエスト）
+var rateLimitSkipPaths = []string{"/healthz", "/readyz", "/metrics"}
+
+// newRateLimit は cfg.Backend に状態を保持するレート制限のミドルウェアを返します。
+// backend が none の場合は何もしないミドルウェアを返します
+func newRateLimit(ctx context.Context, cfg config.RateLimitConfig, store *storage) func(http.Handler) http.Handler {
+	var limiter ratelimit.Limiter
+	switch cfg.Backend {
+	case config.RateLimitMemory:
+		limiter = ratelimit.NewMemory()
+	case config.RateLimitPostgres:
+		if store.db == nil {
+			// デモモードでは DB が無いため、プロセス内で制限する
+			logging.FromContext(ctx).WarnContext(ctx, "No database for rate limiting, falling back to memory")
+			limiter = ratelimit.NewMemory()
+		} else {
+			limiter = infrastructure.NewPostgresRateLimiter(store.db)
+		}
+	default:
+		return func(next http.Handler) http.Handler { return next }
+	}
+
+	return middleware.RateLimit(limiter, middleware.RateLimitOptions{
+		Read:       ratelimit.Budget{Rate: cfg.ReadRate, Burst: cfg.ReadBurst},
+		Write:      ratelimit.Budget{Rate: cfg.WriteRate, Burst: cfg.WriteBurst},
+		TrustProxy: cfg.TrustProxy,
+		SkipPaths:  rateLimitSkipPaths,
+	})
+}
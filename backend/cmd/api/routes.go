@@ -0,0 +1,86 @@
+package main
+
+import (
+	"net/http"
+
+	"todo_app_golang/internal/interface/graphqlapi"
+	"todo_app_golang/internal/interface/handler"
+	"todo_app_golang/internal/interface/health"
+	"todo_app_golang/internal/interface/openapi"
+)
+
+// route は ServeMux に登録するパターンとハンドラーの組です。
+// 登録するルートはすべて OpenAPI 仕様 (internal/interface/openapi/openapi.yaml) に記載してください（routes_test で確認している）
+type route struct {
+	pattern string
+	handler http.Handler
+}
+
+// apiRoutes はアプリケーションの API のルーティングです
+func apiRoutes(todoUseCase handler.TodoUseCaseInterface, assigneeUseCase handler.AssigneeUseCaseInterface, commentUseCase handler.CommentUseCaseInterface, attachmentUseCase handler.AttachmentUseCaseInterface, statsUseCase handler.StatsUseCaseInterface, calendarFeedToken string) []route {
+	todoHandler := handler.NewTodoHandler(todoUseCase) // ハンドラーを生成
+	assigneeHandler := handler.NewAssigneeHandler(assigneeUseCase)
+	commentHandler := handler.NewCommentHandler(commentUseCase)
+	attachmentHandler := handler.NewAttachmentHandler(attachmentUseCase)
+	calendarHandler := handler.NewCalendarHandler(todoUseCase, calendarFeedToken)
+	todoTxtHandler := handler.NewTodoTxtHandler(todoUseCase)
+	statsHandler := handler.NewStatsHandler(statsUseCase)
+
+	// インターフェース層のメソッドを紐付け
+	return []route{
+		{"POST /todos", http.HandlerFunc(todoHandler.CreateTodoHandler)},
+		{"GET /todos", http.HandlerFunc(todoHandler.GetAllTodosHandler)},
+		{"GET /todos/{id}", http.HandlerFunc(todoHandler.GetTodoByIDHandler)},
+		{"PUT /todos/{id}", http.HandlerFunc(todoHandler.UpdateTodoHandler)},
+		{"DELETE /todos/{id}", http.HandlerFunc(todoHandler.DeleteTodoHandler)},
+		{"PATCH /todos/{id}", http.HandlerFunc(todoHandler.UpdateTodoStatusHandler)},
+		{"PUT /todos/{id}/assignee", http.HandlerFunc(assigneeHandler.AssignTodoHandler)},
+		{"DELETE /todos/{id}/assignee", http.HandlerFunc(assigneeHandler.UnassignTodoHandler)},
+		{"PUT /todos/{id}/watchers/{user}", http.HandlerFunc(assigneeHandler.AddWatcherHandler)},
+		{"DELETE /todos/{id}/watchers/{user}", http.HandlerFunc(assigneeHandler.RemoveWatcherHandler)},
+		{"GET /todos/{id}/comments", http.HandlerFunc(commentHandler.ListCommentsHandler)},
+		{"POST /todos/{id}/comments", http.HandlerFunc(commentHandler.CreateCommentHandler)},
+		{"PATCH /comments/{id}", http.HandlerFunc(commentHandler.UpdateCommentHandler)},
+		{"DELETE /comments/{id}", http.HandlerFunc(commentHandler.DeleteCommentHandler)},
+		{"GET /todos/{id}/attachments", http.HandlerFunc(attachmentHandler.ListAttachmentsHandler)},
+		{"POST /todos/{id}/attachments", http.HandlerFunc(attachmentHandler.UploadAttachmentHandler)},
+		{"GET /attachments/{id}", http.HandlerFunc(attachmentHandler.DownloadAttachmentHandler)},
+		{"DELETE /attachments/{id}", http.HandlerFunc(attachmentHandler.DeleteAttachmentHandler)},
+		{"GET /calendar.ics", http.HandlerFunc(calendarHandler.CalendarFeedHandler)},
+		{"POST /todos/import/ics", http.HandlerFunc(calendarHandler.ImportICSHandler)},
+		{"GET /todos/export.txt", http.HandlerFunc(todoTxtHandler.ExportHandler)},
+		{"POST /todos/import/todotxt", http.HandlerFunc(todoTxtHandler.ImportHandler)},
+		{"GET /stats", http.HandlerFunc(statsHandler.GetStatsHandler)},
+		{"GET /analytics/lead-time", http.HandlerFunc(statsHandler.GetLeadTimesHandler)},
+		{"GET /analytics/throughput", http.HandlerFunc(statsHandler.GetThroughputHandler)},
+	}
+}
+
+// graphqlRoutes は GraphQL API のルーティングです（GET はクエリと WebSocket でのサブスクリプション、POST はクエリとミューテーション）
+func graphqlRoutes(h *graphqlapi.Handler) []route {
+	return []route{
+		{"GET /graphql", h},
+		{"POST /graphql", h},
+	}
+}
+
+// opsRoutes はメトリクス・ヘルスチェック・API 仕様などの運用向けのルーティングです
+func opsRoutes(metricsHandler http.Handler, healthHandler *health.Handler, spec *openapi.Spec) []route {
+	return []route{
+		{"GET /metrics", metricsHandler},
+		{"GET /healthz", http.HandlerFunc(healthHandler.LivenessHandler)},
+		{"GET /readyz", http.HandlerFunc(healthHandler.ReadinessHandler)},
+		{"GET /openapi.json", spec.Handler()},
+	}
+}
+
+// newMux は routes を登録した ServeMux を返します
+func newMux(routes ...[]route) *http.ServeMux {
+	mux := http.NewServeMux()
+	for _, rs := range routes {
+		for _, r := range rs {
+			mux.Handle(r.pattern, r.handler)
+		}
+	}
+	return mux
+}
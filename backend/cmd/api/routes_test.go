@@ -0,0 +1,535 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"todo_app_golang/internal/config"
+	"todo_app_golang/internal/domain"
+	"todo_app_golang/internal/infrastructure"
+	"todo_app_golang/internal/interface/graphqlapi"
+	"todo_app_golang/internal/interface/health"
+	"todo_app_golang/internal/interface/metrics"
+	"todo_app_golang/internal/interface/middleware"
+	"todo_app_golang/internal/interface/openapi"
+	"todo_app_golang/internal/usecase"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestServer はメモリ上のリポジトリを使って、本番と同じルーティング・リクエストの検証のサーバーを起動します。
+// すべてのレスポンスは OpenAPI 仕様に一致するかを検証します。middlewares を指定するとその外側で包みます（認証など）
+func newTestServer(t *testing.T, middlewares ...func(http.Handler) http.Handler) *httptest.Server {
+	t.Helper()
+	repo := infrastructure.NewMemoryTodoRepository()
+	spec := loadSpec(t)
+	todoUseCase := usecase.NewTodoUseCase(repo)
+	attachmentUseCase := usecase.NewAttachmentUseCase(repo, infrastructure.NewMemoryAttachmentRepository(repo), infrastructure.NewMemoryBlobStore(), 1<<20, []string{"image/png", "application/pdf"})
+	todoUseCase.OnDelete(attachmentUseCase.DeleteTodoAttachments)
+	statsUseCase := usecase.NewStatsUseCase(infrastructure.NewComputedStatsRepository(repo))
+	mux := newMux(
+		apiRoutes(
+			todoUseCase,
+			todoUseCase,
+			usecase.NewCommentUseCase(repo, infrastructure.NewMemoryCommentRepository(repo)),
+			attachmentUseCase,
+			statsUseCase,
+			"token",
+		),
+		graphqlRoutes(graphqlapi.NewHandler(todoUseCase, statsUseCase, graphqlapi.Options{})),
+		opsRoutes(metrics.New(nil, nil).Handler(), health.NewHandler(), spec),
+	)
+	handler := spec.Middleware(validateResponses(t, spec, mux))
+	for _, m := range middlewares {
+		handler = m(handler)
+	}
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func loadSpec(t *testing.T) *openapi.Spec {
+	t.Helper()
+	spec, err := openapi.Load()
+	if err != nil {
+		t.Fatalf("openapi.Load: %v", err)
+	}
+	return spec
+}
+
+// validateResponses は mux のレスポンスが、一致したルートの仕様に合っているかを検証します
+func validateResponses(t *testing.T, spec *openapi.Spec, mux *http.ServeMux) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, r)
+
+		if _, pattern := mux.Handler(r); pattern != "" {
+			if err := spec.ValidateResponse(pattern, rec.Code, rec.Header(), rec.Body.Bytes()); err != nil {
+				t.Errorf("%s %s: response does not match the spec: %v", r.Method, r.URL, err)
+			}
+		}
+		for k, v := range rec.Header() {
+			w.Header()[k] = v
+		}
+		w.WriteHeader(rec.Code)
+		w.Write(rec.Body.Bytes())
+	})
+}
+
+func doRequest(t *testing.T, method, url, body string) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	assert.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	t.Cleanup(func() { res.Body.Close() })
+	return res
+}
+
+func decodeTodos(t *testing.T, res *http.Response) []*domain.Todo {
+	t.Helper()
+	var todos []*domain.Todo
+	assert.NoError(t, json.NewDecoder(res.Body).Decode(&todos))
+	return todos
+}
+
+// multipartFile は file フィールドに content を入れた multipart/form-data のボディと Content-Type を返します
+func multipartFile(filename, content string) (contentType, body string) {
+	const boundary = "todo-test-boundary"
+	body = "--" + boundary + "\r\n" +
+		`Content-Disposition: form-data; name="file"; filename="` + filename + `"` + "\r\n" +
+		"Content-Type: application/octet-stream\r\n\r\n" +
+		content + "\r\n--" + boundary + "--\r\n"
+	return "multipart/form-data; boundary=" + boundary, body
+}
+
+func TestRoutes(t *testing.T) {
+	srv := newTestServer(t)
+
+	t.Run("作成・一覧・完了・詳細・削除を HTTP 経由で行えること", func(t *testing.T) {
+		res := doRequest(t, http.MethodPost, srv.URL+"/todos", `{"title":"E2E テスト"}`)
+		assert.Equal(t, http.StatusCreated, res.StatusCode)
+
+		todos := decodeTodos(t, doRequest(t, http.MethodGet, srv.URL+"/todos", ""))
+		assert.Len(t, todos, 1)
+		id := todos[0].ID
+		path := srv.URL + "/todos/" + strconv.Itoa(id)
+
+		res = doRequest(t, http.MethodPatch, path, `{"is_completed":true}`)
+		assert.Equal(t, http.StatusNoContent, res.StatusCode)
+
+		var todo domain.Todo
+		res = doRequest(t, http.MethodGet, path, "")
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.NoError(t, json.NewDecoder(res.Body).Decode(&todo))
+		assert.True(t, todo.IsCompleted)
+		assert.NotNil(t, todo.CompletedAt)
+
+		res = doRequest(t, http.MethodDelete, path, "")
+		assert.Equal(t, http.StatusNoContent, res.StatusCode)
+		assert.Equal(t, http.StatusNotFound, doRequest(t, http.MethodGet, path, "").StatusCode)
+	})
+
+	t.Run("コメントを投稿・編集でき、タスクを削除するとコメントも消えること", func(t *testing.T) {
+		res := doRequest(t, http.MethodPost, srv.URL+"/todos", `{"title":"レビュー"}`)
+		var todo domain.Todo
+		assert.NoError(t, json.NewDecoder(res.Body).Decode(&todo))
+		path := srv.URL + "/todos/" + strconv.Itoa(todo.ID)
+
+		res = doRequest(t, http.MethodPost, path+"/comments", `{"author":"alice","body":"- [ ] 確認"}`)
+		assert.Equal(t, http.StatusCreated, res.StatusCode)
+		commentURL := srv.URL + res.Header.Get("Location")
+
+		res = doRequest(t, http.MethodPatch, commentURL, `{"author":"bob","body":"- [x] 確認"}`)
+		assert.Equal(t, http.StatusForbidden, res.StatusCode)
+		res = doRequest(t, http.MethodPatch, commentURL, `{"author":"alice","body":"- [x] 確認"}`)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+
+		var comments []*domain.Comment
+		res = doRequest(t, http.MethodGet, path+"/comments", "")
+		assert.NoError(t, json.NewDecoder(res.Body).Decode(&comments))
+		if assert.Len(t, comments, 1) {
+			assert.Equal(t, "- [x] 確認", comments[0].Body)
+			if assert.Len(t, comments[0].History, 1) {
+				assert.Equal(t, "- [ ] 確認", comments[0].History[0].Body)
+			}
+		}
+
+		assert.Equal(t, http.StatusNoContent, doRequest(t, http.MethodDelete, path, "").StatusCode)
+		assert.Equal(t, http.StatusNotFound, doRequest(t, http.MethodGet, path+"/comments", "").StatusCode)
+		assert.Equal(t, http.StatusNotFound, doRequest(t, http.MethodDelete, commentURL, "").StatusCode)
+	})
+
+	t.Run("ファイルを添付して範囲指定でダウンロードでき、同じ中身は共有されること", func(t *testing.T) {
+		res := doRequest(t, http.MethodPost, srv.URL+"/todos", `{"title":"不具合の報告"}`)
+		var todo domain.Todo
+		assert.NoError(t, json.NewDecoder(res.Body).Decode(&todo))
+		path := srv.URL + "/todos/" + strconv.Itoa(todo.ID) + "/attachments"
+		pdf := "%PDF-1.7\n" + strings.Repeat("0123456789", 10)
+
+		upload := func(filename string) *domain.Attachment {
+			t.Helper()
+			contentType, body := multipartFile(filename, pdf)
+			res, err := http.Post(path, contentType, strings.NewReader(body))
+			if err != nil {
+				t.Fatalf("upload failed: %v", err)
+			}
+			defer res.Body.Close()
+			assert.Equal(t, http.StatusCreated, res.StatusCode)
+			var a domain.Attachment
+			assert.NoError(t, json.NewDecoder(res.Body).Decode(&a))
+			return &a
+		}
+		first, second := upload("報告.pdf"), upload("報告（コピー）.pdf")
+		assert.Equal(t, "application/pdf", first.ContentType)
+		assert.Equal(t, first.SHA256, second.SHA256)
+
+		req, _ := http.NewRequest(http.MethodGet, srv.URL+"/attachments/"+strconv.Itoa(first.ID), nil)
+		req.Header.Set("Range", "bytes=0-7")
+		res, err := http.DefaultClient.Do(req)
+		assert.NoError(t, err)
+		defer res.Body.Close()
+		assert.Equal(t, http.StatusPartialContent, res.StatusCode)
+		assert.Equal(t, "application/pdf", res.Header.Get("Content-Type"))
+		got, _ := io.ReadAll(res.Body)
+		assert.Equal(t, "%PDF-1.7", string(got))
+
+		// 片方を削除しても、もう片方は同じ中身をダウンロードできる
+		assert.Equal(t, http.StatusNoContent, doRequest(t, http.MethodDelete, srv.URL+"/attachments/"+strconv.Itoa(first.ID), "").StatusCode)
+		assert.Equal(t, http.StatusOK, doRequest(t, http.MethodGet, srv.URL+"/attachments/"+strconv.Itoa(second.ID), "").StatusCode)
+
+		contentType, body := multipartFile("script.pdf", "<html><script>alert(1)</script>")
+		res, err = http.Post(path, contentType, strings.NewReader(body))
+		assert.NoError(t, err)
+		defer res.Body.Close()
+		assert.Equal(t, http.StatusUnsupportedMediaType, res.StatusCode)
+
+		assert.Equal(t, http.StatusNoContent, doRequest(t, http.MethodDelete, srv.URL+"/todos/"+strconv.Itoa(todo.ID), "").StatusCode)
+		assert.Equal(t, http.StatusNotFound, doRequest(t, http.MethodGet, srv.URL+"/attachments/"+strconv.Itoa(second.ID), "").StatusCode)
+	})
+
+	t.Run("タイトルが空の場合は作成されないこと", func(t *testing.T) {
+		res := doRequest(t, http.MethodPost, srv.URL+"/todos", `{"title":""}`)
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+		assert.Equal(t, "application/problem+json", res.Header.Get("Content-Type"))
+		assert.Empty(t, decodeTodos(t, doRequest(t, http.MethodGet, srv.URL+"/todos", "")))
+	})
+
+	t.Run("todo.txt を取り込み、統計に反映されること", func(t *testing.T) {
+		res, err := http.Post(srv.URL+"/todos/import/todotxt", "text/plain",
+			strings.NewReader("(A) 資料作成 +仕事 due:2099-01-01\nx 2026-10-01 2026-09-30 買い物\n"))
+		assert.NoError(t, err)
+		defer res.Body.Close()
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+
+		var stats domain.Stats
+		res = doRequest(t, http.MethodGet, srv.URL+"/stats", "")
+		assert.NoError(t, json.NewDecoder(res.Body).Decode(&stats))
+		assert.Equal(t, 2, stats.Total)
+		assert.Equal(t, 1, stats.Completed)
+		assert.Equal(t, 1, stats.ByPriority["high"])
+	})
+
+	t.Run("カレンダーフィードを取り込んでもタスクが増えず、元のタスクが更新されること", func(t *testing.T) {
+		srv := newTestServer(t)
+		res := doRequest(t, http.MethodPost, srv.URL+"/todos", `{"title":"請求書を送る","due_date":"2026-11-01T00:00:00Z"}`)
+		assert.Equal(t, http.StatusCreated, res.StatusCode)
+
+		res = doRequest(t, http.MethodGet, srv.URL+"/calendar.ics?token=token&events=true", "")
+		feed, _ := io.ReadAll(res.Body)
+		for range 2 {
+			res, err := http.Post(srv.URL+"/todos/import/ics", "text/calendar", bytes.NewReader(feed))
+			assert.NoError(t, err)
+			defer res.Body.Close()
+			var result domain.ImportResult
+			assert.NoError(t, json.NewDecoder(res.Body).Decode(&result))
+			assert.Zero(t, result.Created)
+			assert.Equal(t, 1, result.Updated)
+			if assert.Len(t, result.Skipped, 1) {
+				assert.Equal(t, "VEVENT", result.Skipped[0].Component)
+			}
+		}
+
+		todos := decodeTodos(t, doRequest(t, http.MethodGet, srv.URL+"/todos", ""))
+		if assert.Len(t, todos, 1) {
+			assert.Equal(t, "請求書を送る", todos[0].Title)
+			assert.Nil(t, todos[0].ICalUID) // 元のタスクの UID は変えない
+		}
+	})
+
+	t.Run("todo.txt のエクスポートを取り込むと説明を保ったまま新しいタスクとして作成されること", func(t *testing.T) {
+		srv := newTestServer(t)
+		res := doRequest(t, http.MethodPost, srv.URL+"/todos", `{"title":"議事録","description":"参加者: 佐藤\n次回は来週"}`)
+		assert.Equal(t, http.StatusCreated, res.StatusCode)
+
+		res = doRequest(t, http.MethodGet, srv.URL+"/todos/export.txt", "")
+		export, _ := io.ReadAll(res.Body)
+		res, err := http.Post(srv.URL+"/todos/import/todotxt", "text/plain", bytes.NewReader(export))
+		assert.NoError(t, err)
+		defer res.Body.Close()
+		var result domain.ImportResult
+		assert.NoError(t, json.NewDecoder(res.Body).Decode(&result))
+		assert.Equal(t, 1, result.Created)
+		assert.Zero(t, result.Updated)
+
+		todos := decodeTodos(t, doRequest(t, http.MethodGet, srv.URL+"/todos", ""))
+		if assert.Len(t, todos, 2) {
+			assert.Equal(t, todos[0].Title, todos[1].Title)
+			assert.Equal(t, "参加者: 佐藤\n次回は来週", todos[1].Description)
+		}
+	})
+}
+
+func TestRoutes_Assignees(t *testing.T) {
+	srv := newTestServer(t, newAuthenticate(config.AuthConfig{UserHeader: "X-Forwarded-User"}))
+	asType := func(user, method, path, contentType, body string) *http.Response {
+		t.Helper()
+		req, err := http.NewRequest(method, srv.URL+path, strings.NewReader(body))
+		assert.NoError(t, err)
+		req.Header.Set("Content-Type", contentType)
+		if user != "" {
+			req.Header.Set("X-Forwarded-User", user)
+		}
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		t.Cleanup(func() { res.Body.Close() })
+		return res
+	}
+	as := func(user, method, path, body string) *http.Response {
+		t.Helper()
+		return asType(user, method, path, "application/json", body)
+	}
+	decodeTodo := func(res *http.Response) *domain.Todo {
+		t.Helper()
+		var todo domain.Todo
+		assert.NoError(t, json.NewDecoder(res.Body).Decode(&todo))
+		return &todo
+	}
+
+	t.Run("担当者は完了状態だけを変更でき、削除や編集はできないこと", func(t *testing.T) {
+		todo := decodeTodo(as("alice", http.MethodPost, "/todos", `{"title":"請求書を送る"}`))
+		assert.Equal(t, "alice", *todo.OwnerID)
+		path := "/todos/" + strconv.Itoa(todo.ID)
+
+		res := as("alice", http.MethodPut, path+"/assignee", `{"assignee_id":"bob"}`)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, "bob", *decodeTodo(res).AssigneeID)
+
+		assert.Len(t, decodeTodos(t, as("bob", http.MethodGet, "/todos?assignee=me", "")), 1)
+		assert.Empty(t, decodeTodos(t, as("carol", http.MethodGet, "/todos?assignee=me", "")))
+
+		assert.Equal(t, http.StatusNoContent, as("bob", http.MethodPatch, path, `{"is_completed":true}`).StatusCode)
+		assert.Equal(t, http.StatusForbidden, as("bob", http.MethodDelete, path, "").StatusCode)
+		assert.Equal(t, http.StatusForbidden, as("bob", http.MethodPut, path, `{"title":"書き換え"}`).StatusCode)
+		assert.Equal(t, http.StatusForbidden, as("bob", http.MethodPut, path+"/assignee", `{"assignee_id":"carol"}`).StatusCode)
+		assert.Equal(t, http.StatusForbidden, as("carol", http.MethodPatch, path, `{"is_completed":false}`).StatusCode)
+
+		// 自分自身はウォッチできるが、ほかのユーザーは追加できない
+		res = as("bob", http.MethodPut, path+"/watchers/me", "")
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, []string{"bob"}, decodeTodo(res).Watchers)
+		assert.Equal(t, http.StatusForbidden, as("bob", http.MethodPut, path+"/watchers/carol", "").StatusCode)
+
+		// 担当者は自分の担当を外せる
+		res = as("bob", http.MethodDelete, path+"/assignee", "")
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Nil(t, decodeTodo(res).AssigneeID)
+
+		assert.Equal(t, http.StatusNoContent, as("alice", http.MethodDelete, path, "").StatusCode)
+	})
+
+	t.Run("添付ファイルの追加と削除は所有者だけができること", func(t *testing.T) {
+		todo := decodeTodo(as("alice", http.MethodPost, "/todos", `{"title":"見積書を確認する"}`))
+		path := "/todos/" + strconv.Itoa(todo.ID)
+		assert.Equal(t, http.StatusOK, as("alice", http.MethodPut, path+"/assignee", `{"assignee_id":"bob"}`).StatusCode)
+
+		contentType, body := multipartFile("見積書.pdf", "%PDF-1.7\n")
+		res := asType("alice", http.MethodPost, path+"/attachments", contentType, body)
+		assert.Equal(t, http.StatusCreated, res.StatusCode)
+		attachment := res.Header.Get("Location")
+
+		for _, user := range []string{"bob", "carol"} {
+			assert.Equal(t, http.StatusForbidden, asType(user, http.MethodPost, path+"/attachments", contentType, body).StatusCode, user)
+			assert.Equal(t, http.StatusForbidden, as(user, http.MethodDelete, attachment, "").StatusCode, user)
+		}
+		// 閲覧はできる
+		assert.Equal(t, http.StatusOK, as("bob", http.MethodGet, attachment, "").StatusCode)
+		assert.Equal(t, http.StatusNoContent, as("alice", http.MethodDelete, attachment, "").StatusCode)
+	})
+
+	t.Run("認証を有効にしてもメトリクスとアクセスログにルートパターンが記録されること", func(t *testing.T) {
+		// main と同じ順序で包む（ログ → メトリクス → 認証）
+		var buf bytes.Buffer
+		appMetrics := metrics.New(nil, nil)
+		srv := newTestServer(t,
+			newAuthenticate(config.AuthConfig{UserHeader: "X-Forwarded-User"}),
+			appMetrics.Middleware,
+			middleware.RequestLogger(slog.New(slog.NewJSONHandler(&buf, nil))),
+		)
+		req, err := http.NewRequest(http.MethodGet, srv.URL+"/todos/1", nil)
+		assert.NoError(t, err)
+		req.Header.Set("X-Forwarded-User", "alice")
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		res.Body.Close()
+
+		rec := httptest.NewRecorder()
+		appMetrics.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+		assert.Contains(t, rec.Body.String(), `http_requests_total{method="GET",route="GET /todos/{id}",status="404"} 1`)
+		assert.NotContains(t, rec.Body.String(), `route="unmatched"`)
+
+		var entry map[string]any
+		assert.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+		assert.Equal(t, "GET /todos/{id}", entry["route"])
+	})
+
+	t.Run("認証プロキシのヘッダーがない場合は 401 になること", func(t *testing.T) {
+		res := as("", http.MethodGet, "/todos", "")
+		assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
+		assert.Equal(t, "application/problem+json", res.Header.Get("Content-Type"))
+		assert.Equal(t, http.StatusOK, as("", http.MethodGet, "/healthz", "").StatusCode)
+	})
+}
+
+func TestRoutes_OpenAPI(t *testing.T) {
+	spec := loadSpec(t)
+
+	t.Run("登録したすべてのルートが仕様に記載されていること", func(t *testing.T) {
+		var routes []route
+		for _, rs := range [][]route{
+			apiRoutes(nil, nil, nil, nil, nil, ""),
+			graphqlRoutes(nil),
+			opsRoutes(http.NotFoundHandler(), health.NewHandler(), spec),
+		} {
+			routes = append(routes, rs...)
+		}
+		var patterns []string
+		for _, r := range routes {
+			patterns = append(patterns, r.pattern)
+			assert.Contains(t, spec.Operations(), r.pattern, "openapi.yaml に %s を追加してください", r.pattern)
+		}
+		// 仕様にだけ残っているルートも無いこと
+		assert.ElementsMatch(t, patterns, spec.Operations())
+	})
+
+	t.Run("すべてのルートのレスポンスが仕様に一致すること", func(t *testing.T) {
+		srv := newTestServer(t)
+		pngType, png := multipartFile("screenshot.png", "\x89PNG\r\n\x1a\n0000")
+		textType, text := multipartFile("memo.png", "テキスト")
+		ics := "BEGIN:VCALENDAR\r\nBEGIN:VTODO\r\nUID:a@example.com\r\nSUMMARY:会議の準備\r\nEND:VTODO\r\nEND:VCALENDAR\r\n"
+
+		// newTestServer がレスポンスを検証するため、ここでは各ルートを一通り呼び出す
+		for _, req := range []struct {
+			method, path, contentType, body string
+		}{
+			{http.MethodGet, "/todos", "", ""},
+			{http.MethodPost, "/todos", "application/json", `{"title":"仕様のテスト +api"}`},
+			{http.MethodPost, "/todos", "application/json", `{"title":"期限付き","priority":"high","due_date":"2026-11-01T00:00:00Z"}`},
+			{http.MethodPut, "/todos/2", "application/json", `{"title":"期限付き（更新）","priority":"low","due_date":null}`},
+			{http.MethodPut, "/todos/999", "application/json", `{"title":"存在しない"}`},
+			{http.MethodPatch, "/todos/999", "application/json", `{"is_completed":true}`},
+			{http.MethodGet, "/todos?completed=false&priority=low&q=%E6%9C%9F%E9%99%90", "", ""},
+			{http.MethodPost, "/todos/import/ics", "text/calendar", ics},
+			{http.MethodPost, "/todos/import/todotxt", "text/plain", "(B) 買い物 due:2026-10-20\n"},
+			{http.MethodGet, "/todos", "", ""},
+			{http.MethodPatch, "/todos/1", "application/json", `{"is_completed":true}`},
+			{http.MethodGet, "/todos/1", "", ""},
+			{http.MethodGet, "/todos/999", "", ""},
+			{http.MethodGet, "/todos/abc", "", ""},
+			{http.MethodGet, "/todos/export.txt", "", ""},
+			{http.MethodGet, "/calendar.ics?token=token&events=true", "", ""},
+			{http.MethodGet, "/calendar.ics", "", ""},
+			{http.MethodGet, "/stats?tz=Asia/Tokyo", "", ""},
+			{http.MethodGet, "/stats?tz=Mars/Olympus", "", ""},
+			{http.MethodGet, "/analytics/lead-time", "", ""},
+			{http.MethodGet, "/analytics/throughput?from=2026-09-01&to=2026-10-31", "", ""},
+			{http.MethodPost, "/graphql", "application/json", `{"query":"{ todos { id title projects } stats { total } }"}`},
+			{http.MethodGet, "/graphql?query=%7B%20todo(id%3A%20%22999%22)%20%7B%20id%20%7D%20%7D", "", ""},
+			{http.MethodGet, "/graphql", "", ""},
+			{http.MethodPost, "/graphql", "application/json", `{"query":1}`},
+			{http.MethodPost, "/todos/1/comments", "application/json", `{"author":"alice","body":"**確認**しました"}`},
+			{http.MethodPost, "/todos/1/comments", "application/json", `{"body":""}`},
+			{http.MethodPost, "/todos/999/comments", "application/json", `{"author":"alice","body":"存在しない"}`},
+			{http.MethodPatch, "/comments/1", "application/json", `{"body":"確認しました（追記）"}`},
+			{http.MethodPatch, "/comments/1", "application/json", `{"author":"bob","body":"なりすまし"}`},
+			{http.MethodPatch, "/comments/999", "application/json", `{"body":"存在しない"}`},
+			{http.MethodGet, "/todos/1/comments", "", ""},
+			{http.MethodGet, "/todos/999/comments", "", ""},
+			{http.MethodDelete, "/comments/999", "", ""},
+			{http.MethodPost, "/todos/1/attachments", pngType, png},
+			{http.MethodPost, "/todos/1/attachments", textType, text},
+			{http.MethodPost, "/todos/1/attachments", "application/json", `{"file":"x"}`},
+			{http.MethodPost, "/todos/999/attachments", pngType, png},
+			{http.MethodGet, "/todos/1/attachments", "", ""},
+			{http.MethodGet, "/todos/999/attachments", "", ""},
+			{http.MethodGet, "/attachments/1", "", ""},
+			{http.MethodGet, "/attachments/999", "", ""},
+			{http.MethodDelete, "/attachments/1", "", ""},
+			{http.MethodDelete, "/attachments/999", "", ""},
+			{http.MethodPut, "/todos/1/assignee", "application/json", `{"assignee_id":"bob"}`},
+			{http.MethodPut, "/todos/1/assignee", "application/json", `{"assignee_id":"me"}`},
+			{http.MethodPut, "/todos/1/assignee", "application/json", `{"assignee_id":""}`},
+			{http.MethodPut, "/todos/999/assignee", "application/json", `{"assignee_id":"bob"}`},
+			{http.MethodGet, "/todos?assignee=bob", "", ""},
+			{http.MethodGet, "/todos?assignee=me", "", ""},
+			{http.MethodDelete, "/todos/1/assignee", "", ""},
+			{http.MethodPut, "/todos/1/watchers/bob", "", ""},
+			{http.MethodPut, "/todos/999/watchers/bob", "", ""},
+			{http.MethodDelete, "/todos/1/watchers/bob", "", ""},
+			{http.MethodDelete, "/todos/1/watchers/me", "", ""},
+			{http.MethodDelete, "/todos/1", "", ""},
+			{http.MethodGet, "/healthz", "", ""},
+			{http.MethodGet, "/readyz", "", ""},
+			{http.MethodGet, "/metrics", "", ""},
+			{http.MethodGet, "/openapi.json", "", ""},
+		} {
+			r, err := http.NewRequest(req.method, srv.URL+req.path, strings.NewReader(req.body))
+			assert.NoError(t, err)
+			if req.contentType != "" {
+				r.Header.Set("Content-Type", req.contentType)
+			}
+			res, err := http.DefaultClient.Do(r)
+			if err != nil {
+				t.Fatalf("%s %s: %v", req.method, req.path, err)
+			}
+			res.Body.Close()
+		}
+	})
+}
+
+func TestSeedDemoData(t *testing.T) {
+	ctx := context.Background()
+	repo := infrastructure.NewMemoryTodoRepository()
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+
+	assert.NoError(t, seedDemoData(ctx, repo, now))
+
+	todos, err := repo.FetchAll(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, todos, len(demoTodos))
+
+	// 統計画面で使う値がそろっていること
+	stats, err := infrastructure.NewComputedStatsRepository(repo).Stats(ctx, domain.StatsQuery{
+		Now: now, From: now.AddDate(0, 0, -13), To: now, Location: time.UTC,
+	})
+	assert.NoError(t, err)
+	assert.NotZero(t, stats.Completed)
+	assert.NotZero(t, stats.Overdue)
+	assert.NotZero(t, stats.DueToday)
+}
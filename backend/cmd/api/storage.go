@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"todo_app_golang/internal/config"
+	"todo_app_golang/internal/domain"
+	"todo_app_golang/internal/infrastructure"
+	"todo_app_golang/internal/interface/health"
+	"todo_app_golang/migrations"
+)
+
+// storage は DB ドライバーごとに異なる接続・マイグレーション・リポジトリをまとめたものです
+type storage struct {
+	db            *sql.DB // デモモードの場合は nil
+	migrator      *infrastructure.Migrator
+	latestVersion uint // 同梱しているマイグレーションの最新バージョン（readiness チェックで使う）
+	todos         domain.TodoRepository
+	comments      domain.CommentRepository
+	attachments   domain.AttachmentRepository
+	stats         domain.StatsRepository
+}
+
+// openStorage は設定されたドライバーで DB を開きます
+func openStorage(cfg config.DatabaseConfig) (*storage, error) {
+	if cfg.Driver == config.DriverSQLite {
+		db, err := infrastructure.NewSQLiteDB(cfg.Source)
+		if err != nil {
+			return nil, err
+		}
+		todos := infrastructure.NewSQLiteTodoRepository(db)
+		return &storage{
+			db:            db,
+			migrator:      infrastructure.NewSQLiteMigrator(db),
+			latestVersion: migrations.LatestSQLiteVersion(),
+			todos:         todos,
+			comments:      infrastructure.NewSQLiteCommentRepository(db),
+			attachments:   infrastructure.NewSQLiteAttachmentRepository(db),
+			stats:         infrastructure.NewComputedStatsRepository(todos),
+		}, nil
+	}
+
+	db, err := infrastructure.NewDB(cfg.Source)
+	if err != nil {
+		return nil, err
+	}
+	return &storage{
+		db:            db,
+		migrator:      infrastructure.NewMigrator(db),
+		latestVersion: migrations.LatestVersion(),
+		todos:         infrastructure.NewTodoRepository(db),
+		comments:      infrastructure.NewCommentRepository(db),
+		attachments:   infrastructure.NewAttachmentRepository(db),
+		stats:         infrastructure.NewStatsRepository(db),
+	}, nil
+}
+
+// newDemoStorage は DB を使わず、サンプルデータを入れたメモリ上のリポジトリを用意します
+func newDemoStorage(ctx context.Context) (*storage, error) {
+	todos := infrastructure.NewMemoryTodoRepository()
+	if err := seedDemoData(ctx, todos, time.Now()); err != nil {
+		return nil, err
+	}
+	return &storage{
+		todos:       todos,
+		comments:    infrastructure.NewMemoryCommentRepository(todos),
+		attachments: infrastructure.NewMemoryAttachmentRepository(todos),
+		stats:       infrastructure.NewComputedStatsRepository(todos),
+	}, nil
+}
+
+// healthChecks は readiness で確認する DB の状態です
+func (s *storage) healthChecks() []health.Check {
+	if s.db == nil {
+		return nil
+	}
+	return []health.Check{
+		health.DatabaseCheck(s.db),
+		health.MigrationCheck(func(ctx context.Context) (uint, bool, error) {
+			return infrastructure.SchemaVersion(ctx, s.db)
+		}, s.latestVersion),
+	}
+}
+
+func (s *storage) close() error {
+	if s.db == nil {
+		return nil
+	}
+	return s.db.Close()
+}
+
+// migrateOnStart は起動時にマイグレーションを適用するかを返します。
+// SQLite は単一バイナリで完結させるため、設定に関わらず常に適用する
+func migrateOnStart(cfg config.DatabaseConfig) bool {
+	return cfg.MigrateOnStart || cfg.Driver == config.DriverSQLite
+}
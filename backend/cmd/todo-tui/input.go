@@ -0,0 +1,50 @@
+package main
+
+import "slices"
+
+// lineInput は1行の入力欄です（タイトル・詳細説明・期限の編集や検索で使う）
+type lineInput struct {
+	prompt string
+	text   []rune
+	pos    int // カーソルの位置（文字単位）
+}
+
+func newLineInput(prompt, value string) lineInput {
+	text := []rune(value)
+	return lineInput{prompt: prompt, text: text, pos: len(text)}
+}
+
+func (in *lineInput) String() string {
+	return string(in.text)
+}
+
+// handle はキーで入力欄を編集します。入力欄で扱わないキーの場合は false を返します
+func (in *lineInput) handle(k key) bool {
+	switch k.code {
+	case keyRune:
+		in.text = slices.Insert(in.text, in.pos, k.r)
+		in.pos++
+	case keyBackspace:
+		if in.pos > 0 {
+			in.text = slices.Delete(in.text, in.pos-1, in.pos)
+			in.pos--
+		}
+	case keyDelete:
+		if in.pos < len(in.text) {
+			in.text = slices.Delete(in.text, in.pos, in.pos+1)
+		}
+	case keyLeft:
+		in.pos = max(in.pos-1, 0)
+	case keyRight:
+		in.pos = min(in.pos+1, len(in.text))
+	case keyHome:
+		in.pos = 0
+	case keyEnd:
+		in.pos = len(in.text)
+	case keyCtrlU:
+		in.text, in.pos = nil, 0
+	default:
+		return false
+	}
+	return true
+}
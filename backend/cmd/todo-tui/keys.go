@@ -0,0 +1,100 @@
+package main
+
+import (
+	"bytes"
+	"unicode/utf8"
+)
+
+// keyCode はキーの種類です。文字の入力は keyRune で、文字は key.r に入ります
+type keyCode int
+
+const (
+	keyRune keyCode = iota
+	keyEnter
+	keyEsc
+	keyTab
+	keyBackspace
+	keyDelete
+	keyUp
+	keyDown
+	keyLeft
+	keyRight
+	keyHome
+	keyEnd
+	keyPageUp
+	keyPageDown
+	keyCtrlC
+	keyCtrlU
+	keyUnknown // 扱わないシーケンス（F キーなど）
+)
+
+type key struct {
+	code keyCode
+	r    rune
+}
+
+// escapeKeys は端末が送るエスケープシーケンスとキーの対応です（xterm 互換）
+var escapeKeys = map[string]keyCode{
+	"\x1b[A": keyUp, "\x1b[B": keyDown, "\x1b[C": keyRight, "\x1b[D": keyLeft,
+	"\x1bOA": keyUp, "\x1bOB": keyDown, "\x1bOC": keyRight, "\x1bOD": keyLeft,
+	"\x1b[H": keyHome, "\x1b[F": keyEnd, "\x1bOH": keyHome, "\x1bOF": keyEnd,
+	"\x1b[1~": keyHome, "\x1b[4~": keyEnd, "\x1b[7~": keyHome, "\x1b[8~": keyEnd,
+	"\x1b[3~": keyDelete, "\x1b[5~": keyPageUp, "\x1b[6~": keyPageDown,
+}
+
+// controlKeys は制御文字とキーの対応です
+var controlKeys = map[byte]keyCode{
+	'\r': keyEnter, '\n': keyEnter, '\t': keyTab,
+	0x7f: keyBackspace, 0x08: keyBackspace,
+	0x03: keyCtrlC, 0x15: keyCtrlU,
+	0x01: keyHome, 0x05: keyEnd, // Ctrl+A / Ctrl+E
+	0x0e: keyDown, 0x10: keyUp, // Ctrl+N / Ctrl+P
+}
+
+// parseKeys は端末から読んだバイト列をキーにします。
+// 途中で切れた UTF-8 の文字は rest として返すため、次に読んだバイト列の前に付けてください
+func parseKeys(b []byte) (keys []key, rest []byte) {
+	for len(b) > 0 {
+		if b[0] == 0x1b {
+			k, n := parseEscape(b)
+			keys = append(keys, k)
+			b = b[n:]
+			continue
+		}
+		if code, ok := controlKeys[b[0]]; ok {
+			keys = append(keys, key{code: code})
+			b = b[1:]
+			continue
+		}
+		if !utf8.FullRune(b) {
+			return keys, b
+		}
+		r, n := utf8.DecodeRune(b)
+		b = b[n:]
+		if r < 0x20 || r == utf8.RuneError {
+			continue // 扱わない制御文字と不正なバイト
+		}
+		keys = append(keys, key{code: keyRune, r: r})
+	}
+	return keys, nil
+}
+
+// parseEscape は ESC で始まるバイト列の先頭のキーと、その長さを返します
+func parseEscape(b []byte) (key, int) {
+	for seq, code := range escapeKeys {
+		if bytes.HasPrefix(b, []byte(seq)) {
+			return key{code: code}, len(seq)
+		}
+	}
+	if len(b) >= 2 && (b[1] == '[' || b[1] == 'O') {
+		// 知らないシーケンスは終端の文字まで読み飛ばす
+		for i := 2; i < len(b); i++ {
+			if b[i] >= 0x40 && b[i] <= 0x7e {
+				return key{code: keyUnknown}, i + 1
+			}
+		}
+		return key{code: keyUnknown}, len(b)
+	}
+	// 単独の ESC（Alt+キーの場合も ESC として扱い、続く文字は次のキーにする）
+	return key{code: keyEsc}, 1
+}
@@ -0,0 +1,206 @@
+// Command todo-tui は REST API を使ってタスクを操作するターミナルの UI です。
+//
+// 一覧の移動・完了状態と優先度での絞り込み・タイトル / 詳細説明 / 期限のその場での編集・完了の切り替えができ、
+// ほかのクライアントによる変更は GraphQL のサブスクリプション（接続できない場合は定期的な読み込み）で反映します。
+// 接続先は todo と同じ設定ファイル・環境変数で指定します
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+	"todo_app_golang/internal/apiclient"
+
+	"golang.org/x/term"
+)
+
+const usage = `usage: todo-tui [flags]
+
+flags:
+  -config PATH    設定ファイル（既定: TODO_CONFIG、無ければ ~/.config/todo/config.yaml）
+  -url URL        API の URL（TODO_API_URL、設定ファイルの base_url。既定: http://localhost:8080）
+  -token TOKEN    Authorization: Bearer で送るトークン（TODO_TOKEN、設定ファイルの token）
+  -poll DURATION  サブスクリプションに接続できない間に一覧を読み込み直す間隔（既定: 5s）`
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if err := run(ctx, os.Args[1:], os.Getenv); err != nil {
+		fmt.Fprintln(os.Stderr, "todo-tui:", err)
+		if errors.Is(err, errUsage) {
+			fmt.Fprintln(os.Stderr, usage)
+			os.Exit(2)
+		}
+		os.Exit(1)
+	}
+}
+
+// errUsage は引数や設定の誤りを表します（使い方を表示して終了コード 2 で終わる）
+var errUsage = errors.New("usage error")
+
+func run(ctx context.Context, args []string, getenv func(string) string) error {
+	fs := flag.NewFlagSet("todo-tui", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	configPath := fs.String("config", "", "")
+	baseURL := fs.String("url", "", "")
+	token := fs.String("token", "", "")
+	poll := fs.Duration("poll", 5*time.Second, "")
+	if err := fs.Parse(args); err != nil {
+		return fmt.Errorf("%w: %v", errUsage, err)
+	}
+	if fs.NArg() > 0 || *poll <= 0 {
+		return fmt.Errorf("%w: unexpected arguments", errUsage)
+	}
+
+	cfg, err := apiclient.LoadConfig(*configPath, getenv)
+	if err != nil {
+		return fmt.Errorf("%w: config: %v", errUsage, err)
+	}
+	if *baseURL != "" {
+		cfg.BaseURL = *baseURL
+	}
+	if *token != "" {
+		cfg.Token = *token
+	}
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("%w: %v", errUsage, err)
+	}
+
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) || !term.IsTerminal(int(os.Stdout.Fd())) {
+		return errors.New("stdin and stdout must be a terminal (use the todo command for scripts)")
+	}
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return err
+	}
+	defer term.Restore(fd, state)
+
+	// 代替画面に切り替え、終了時に元の画面に戻す
+	os.Stdout.WriteString("\x1b[?1049h\x1b[?25l")
+	defer os.Stdout.WriteString("\x1b[?25h\x1b[?1049l")
+
+	p := &program{
+		model: newModel(apiclient.New(cfg)),
+		in:    os.Stdin,
+		out:   os.Stdout,
+		size:  func() (int, int, error) { return term.GetSize(int(os.Stdout.Fd())) },
+		watch: func(ctx context.Context, send func(msg)) { watch(ctx, cfg, *poll, send) },
+	}
+	return p.run(ctx)
+}
+
+// program は端末とのやり取りとイベントループです。
+// キー入力・画面の大きさの変化・変更の通知・API の結果を1つの goroutine で model に反映し、そのたびに描画します
+type program struct {
+	model *model
+	in    io.Reader
+	out   io.Writer
+	size  func() (width, height int, err error)
+	watch func(ctx context.Context, send func(msg))
+}
+
+func (p *program) run(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	msgs := make(chan msg, 64)
+	send := func(m msg) {
+		select {
+		case msgs <- m:
+		case <-ctx.Done():
+		}
+	}
+	exec := func(c cmd) {
+		if c != nil {
+			go func() { send(c(ctx)) }()
+		}
+	}
+
+	go p.readKeys(ctx, send)
+	go p.watchSize(ctx, send)
+	go p.watch(ctx, send)
+	exec(p.model.init())
+
+	for {
+		if err := p.draw(); err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case m := <-msgs:
+			exec(p.model.update(m))
+			if p.model.quit {
+				return nil
+			}
+		}
+	}
+}
+
+// readKeys は端末から読んだキーを送ります。読み込みは止められないため、終了時は読み込み中のまま残ります
+func (p *program) readKeys(ctx context.Context, send func(msg)) {
+	buf := make([]byte, 256)
+	var rest []byte
+	for {
+		n, err := p.in.Read(buf)
+		if err != nil {
+			return
+		}
+		var keys []key
+		keys, rest = parseKeys(append(rest, buf[:n]...))
+		for _, k := range keys {
+			send(keyMsg(k))
+		}
+		if ctx.Err() != nil {
+			return
+		}
+	}
+}
+
+// watchSize は画面の大きさが変わったら送ります（SIGWINCH を使わず、Windows でも同じように動くよう定期的に確認する）
+func (p *program) watchSize(ctx context.Context, send func(msg)) {
+	var last resizeMsg
+	ticker := time.NewTicker(200 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		if w, h, err := p.size(); err == nil && (resizeMsg{w, h}) != last {
+			last = resizeMsg{w, h}
+			send(last)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// draw は画面全体を描き直します
+func (p *program) draw() error {
+	f := p.model.view(time.Now())
+	var b strings.Builder
+	b.WriteString("\x1b[H")
+	for i, line := range f.lines {
+		if i > 0 {
+			b.WriteString("\r\n")
+		}
+		b.WriteString(line)
+		b.WriteString("\x1b[K")
+	}
+	b.WriteString("\x1b[J")
+	if f.showCursor {
+		fmt.Fprintf(&b, "\x1b[%d;%dH\x1b[?25h", f.row+1, f.col+1)
+	} else {
+		b.WriteString("\x1b[?25l")
+	}
+	_, err := io.WriteString(p.out, b.String())
+	return err
+}
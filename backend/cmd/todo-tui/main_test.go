@@ -0,0 +1,368 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"todo_app_golang/internal/apiclient"
+	"todo_app_golang/internal/domain"
+	"todo_app_golang/internal/infrastructure"
+	"todo_app_golang/internal/interface/graphqlapi"
+	"todo_app_golang/internal/interface/handler"
+	"todo_app_golang/internal/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestServer はメモリ上のリポジトリを使って REST API と GraphQL のサーバーを起動します
+func newTestServer(t *testing.T) (*httptest.Server, *usecase.TodoUseCase) {
+	t.Helper()
+	repo := infrastructure.NewMemoryTodoRepository()
+	todoUseCase := usecase.NewTodoUseCase(repo)
+	statsUseCase := usecase.NewStatsUseCase(infrastructure.NewComputedStatsRepository(repo))
+	h := handler.NewTodoHandler(todoUseCase)
+	gql := graphqlapi.NewHandler(todoUseCase, statsUseCase, graphqlapi.Options{})
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /todos", h.CreateTodoHandler)
+	mux.HandleFunc("GET /todos", h.GetAllTodosHandler)
+	mux.HandleFunc("PUT /todos/{id}", h.UpdateTodoHandler)
+	mux.HandleFunc("PATCH /todos/{id}", h.UpdateTodoStatusHandler)
+	mux.HandleFunc("DELETE /todos/{id}", h.DeleteTodoHandler)
+	mux.Handle("GET /graphql", gql)
+
+	srv := httptest.NewServer(mux)
+	t.Cleanup(func() {
+		gql.Shutdown()
+		srv.Close()
+	})
+	return srv, todoUseCase
+}
+
+// start は最初の読み込みを行います
+func start(t *testing.T, m *model) {
+	t.Helper()
+	for c := m.init(); c != nil; {
+		c = m.update(c(context.Background()))
+	}
+}
+
+// drive はイベントを順に反映し、返された処理をその場で実行して結果も反映します（API の呼び出しを待つ）
+func drive(t *testing.T, m *model, msgs ...msg) {
+	t.Helper()
+	for _, msg := range msgs {
+		for c := m.update(msg); c != nil; {
+			c = m.update(c(context.Background()))
+		}
+	}
+}
+
+// keys は文字列を1文字ずつのキー入力にします
+func keys(s string) []msg {
+	var msgs []msg
+	for _, r := range s {
+		msgs = append(msgs, keyMsg{code: keyRune, r: r})
+	}
+	return msgs
+}
+
+func special(code keyCode) msg {
+	return keyMsg{code: code}
+}
+
+func titles(m *model) []string {
+	var s []string
+	for _, t := range m.todos {
+		s = append(s, t.Title)
+	}
+	return s
+}
+
+func addTodo(t *testing.T, uc *usecase.TodoUseCase, in domain.TodoInput) *domain.Todo {
+	t.Helper()
+	todo, err := uc.AddTodo(context.Background(), in)
+	require.NoError(t, err)
+	return todo
+}
+
+func TestModel(t *testing.T) {
+	t.Run("成功：一覧を読み込み、移動して完了を切り替えられること", func(t *testing.T) {
+		srv, uc := newTestServer(t)
+		addTodo(t, uc, domain.TodoInput{Title: "1件目"})
+		second := addTodo(t, uc, domain.TodoInput{Title: "2件目"})
+		m := newModel(apiclient.New(apiclient.Config{BaseURL: srv.URL}))
+		start(t, m)
+		require.Len(t, m.todos, 2)
+
+		drive(t, m, keys("j")...)
+		assert.Equal(t, 1, m.cursor)
+		drive(t, m, keys("k")...)
+		assert.Equal(t, 0, m.cursor)
+
+		selected := m.selected()
+		drive(t, m, keys(" ")...)
+		assert.Equal(t, "Completed #"+strconv.Itoa(selected.ID), m.status)
+		assert.True(t, m.find(selected.ID).IsCompleted)
+		assert.Equal(t, selected.ID, m.selected().ID, "更新後も同じタスクを選択していること")
+
+		drive(t, m, keys("x")...)
+		assert.False(t, m.find(selected.ID).IsCompleted)
+		assert.NotNil(t, m.find(second.ID))
+	})
+
+	t.Run("成功：完了状態と優先度で絞り込み、Esc で解除できること", func(t *testing.T) {
+		srv, uc := newTestServer(t)
+		addTodo(t, uc, domain.TodoInput{Title: "高", Priority: domain.PriorityHigh})
+		addTodo(t, uc, domain.TodoInput{Title: "低", Priority: domain.PriorityLow})
+		done := addTodo(t, uc, domain.TodoInput{Title: "完了", Priority: domain.PriorityHigh})
+		require.NoError(t, uc.UpdateTodoStatus(context.Background(), done.ID, true))
+		m := newModel(apiclient.New(apiclient.Config{BaseURL: srv.URL}))
+		start(t, m)
+
+		drive(t, m, keys("s")...) // 未完了
+		assert.ElementsMatch(t, []string{"高", "低"}, titles(m))
+		drive(t, m, keys("p")...) // high
+		assert.Equal(t, []string{"高"}, titles(m))
+		drive(t, m, keys("s")...) // 完了
+		assert.Equal(t, []string{"完了"}, titles(m))
+		assert.Contains(t, m.view(time.Now()).lines[0], "status:done  priority:high")
+
+		drive(t, m, special(keyEsc))
+		assert.Len(t, m.todos, 3)
+
+		drive(t, m, keys("/低")...)
+		drive(t, m, special(keyEnter))
+		assert.Equal(t, []string{"低"}, titles(m))
+	})
+
+	t.Run("成功：タイトル・詳細説明・期限をその場で編集できること", func(t *testing.T) {
+		srv, uc := newTestServer(t)
+		todo := addTodo(t, uc, domain.TodoInput{Title: "編集前"})
+		m := newModel(apiclient.New(apiclient.Config{BaseURL: srv.URL}))
+		start(t, m)
+
+		drive(t, m, keys("e")...)
+		assert.Equal(t, modeInput, m.mode)
+		drive(t, m, special(keyCtrlU))
+		drive(t, m, keys("編集後")...)
+		drive(t, m, special(keyEnter))
+		assert.Equal(t, modeList, m.mode)
+
+		drive(t, m, keys("d")...)
+		drive(t, m, keys(`1行目\n2行目`)...)
+		drive(t, m, special(keyEnter))
+
+		drive(t, m, keys("t2026-11-01")...)
+		drive(t, m, special(keyEnter))
+
+		got, err := uc.GetTodoByID(context.Background(), todo.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "編集後", got.Title)
+		assert.Equal(t, "1行目\n2行目", got.Description)
+		assert.Equal(t, "2026-11-01", apiclient.FormatDate(got.DueDate))
+
+		// 期限を空にすると外す。詳細説明の改行は \n として編集欄に出す
+		drive(t, m, keys("t")...)
+		drive(t, m, special(keyCtrlU), special(keyEnter))
+		drive(t, m, keys("d")...)
+		assert.Equal(t, `1行目\n2行目`, m.input.String())
+		drive(t, m, special(keyEsc))
+		got, err = uc.GetTodoByID(context.Background(), todo.ID)
+		require.NoError(t, err)
+		assert.Nil(t, got.DueDate)
+		assert.Equal(t, "1行目\n2行目", got.Description, "Esc では変更しないこと")
+	})
+
+	t.Run("失敗：正しくない入力は入力欄を開いたままエラーを表示すること", func(t *testing.T) {
+		srv, uc := newTestServer(t)
+		addTodo(t, uc, domain.TodoInput{Title: "タスク"})
+		m := newModel(apiclient.New(apiclient.Config{BaseURL: srv.URL}))
+		start(t, m)
+
+		drive(t, m, keys("t11/01")...)
+		drive(t, m, special(keyEnter))
+		assert.Equal(t, modeInput, m.mode)
+		assert.True(t, m.isError)
+
+		drive(t, m, special(keyEsc), keys("e")[0], special(keyCtrlU), special(keyEnter))
+		assert.Equal(t, modeInput, m.mode)
+		assert.Equal(t, domain.ErrTitleEmpty.Error(), m.status)
+	})
+
+	t.Run("成功：追加したタスクを選択し、確認の後に削除できること", func(t *testing.T) {
+		srv, uc := newTestServer(t)
+		addTodo(t, uc, domain.TodoInput{Title: "既存"})
+		m := newModel(apiclient.New(apiclient.Config{BaseURL: srv.URL}))
+		start(t, m)
+		drive(t, m, keys("j")...)
+
+		drive(t, m, keys("a新しいタスク")...)
+		drive(t, m, special(keyEnter))
+		require.Len(t, m.todos, 2)
+		assert.Equal(t, "新しいタスク", m.selected().Title)
+
+		drive(t, m, keys("Xn")...)
+		assert.Len(t, m.todos, 2)
+		assert.Equal(t, "Delete cancelled", m.status)
+
+		drive(t, m, keys("Xy")...)
+		assert.Equal(t, []string{"既存"}, titles(m))
+	})
+
+	t.Run("成功：ほかのクライアントの変更の通知で読み込み直すこと", func(t *testing.T) {
+		srv, uc := newTestServer(t)
+		first := addTodo(t, uc, domain.TodoInput{Title: "1件目"})
+		m := newModel(apiclient.New(apiclient.Config{BaseURL: srv.URL}))
+		start(t, m)
+		require.Equal(t, first.ID, m.selected().ID)
+
+		addTodo(t, uc, domain.TodoInput{Title: "2件目"})
+		drive(t, m, changedMsg{})
+		assert.Len(t, m.todos, 2)
+		assert.Equal(t, first.ID, m.selected().ID, "選択しているタスクは変わらないこと")
+	})
+
+	t.Run("成功：読み込み中の通知はまとめ、古い結果は捨てること", func(t *testing.T) {
+		srv, _ := newTestServer(t)
+		m := newModel(apiclient.New(apiclient.Config{BaseURL: srv.URL}))
+		first := m.init()
+		assert.Nil(t, m.update(changedMsg{}))
+		assert.Nil(t, m.update(changedMsg{}))
+		filtered := m.update(keys("s")[0]) // 絞り込みを変えると、読み込み中の結果は使わない
+
+		assert.Nil(t, m.update(first(context.Background())))
+		assert.True(t, m.loading)
+		next := m.update(filtered(context.Background()))
+		require.NotNil(t, next, "読み込み中に受けた通知の分をもう一度読み込むこと")
+		assert.Nil(t, m.update(next(context.Background())))
+		assert.False(t, m.loading)
+	})
+
+	t.Run("失敗：API に接続できない場合はエラーを表示すること", func(t *testing.T) {
+		m := newModel(apiclient.New(apiclient.Config{BaseURL: "http://127.0.0.1:1"}))
+		start(t, m)
+		assert.True(t, m.isError)
+		assert.Empty(t, m.todos)
+	})
+}
+
+func TestModel_View(t *testing.T) {
+	due := time.Date(2026, 11, 1, 0, 0, 0, 0, time.Local)
+	m := newModel(nil)
+	m.width, m.height = 40, 10
+	m.todos = []*domain.Todo{
+		{ID: 1, Title: "とても長いタイトルのタスクで画面の幅を超える", Priority: domain.PriorityHigh, DueDate: &due},
+		{ID: 2, Title: "エスケープ\x1b[2J", Priority: domain.PriorityLow},
+	}
+
+	t.Run("成功：全角文字を含む行を画面の幅に収めること", func(t *testing.T) {
+		f := m.view(time.Now())
+		assert.Len(t, f.lines, m.height)
+		for _, line := range f.lines {
+			assert.LessOrEqual(t, displayWidth(stripStyle(line)), m.width, line)
+		}
+		assert.Contains(t, f.lines[2], "2026-11-01")
+		assert.NotContains(t, f.lines[3], "\x1b[2J", "タイトルの制御文字は表示しないこと")
+		assert.False(t, f.showCursor)
+	})
+
+	t.Run("成功：入力欄ではカーソルの位置を返すこと", func(t *testing.T) {
+		m.startInput(inputQuery, 0, "資料")
+		defer func() { m.mode = modeList }()
+		f := m.view(time.Now())
+		assert.True(t, f.showCursor)
+		assert.Equal(t, "Search: 資料", f.lines[f.row])
+		assert.Equal(t, len("Search: ")+4, f.col)
+	})
+}
+
+// stripStyle は SGR のエスケープシーケンスを取り除きます
+func stripStyle(s string) string {
+	for _, style := range []string{styleReset, styleBold, styleDim, styleReverse, styleRed} {
+		s = strings.ReplaceAll(s, style, "")
+	}
+	return s
+}
+
+func TestParseKeys(t *testing.T) {
+	t.Run("成功：文字・制御文字・エスケープシーケンスをキーにすること", func(t *testing.T) {
+		got, rest := parseKeys([]byte("aあ\r\x7f\x1b[A\x1b[3~\x1bOB\x1b[15~\x1b\x03"))
+		assert.Empty(t, rest)
+		assert.Equal(t, []key{
+			{code: keyRune, r: 'a'}, {code: keyRune, r: 'あ'}, {code: keyEnter}, {code: keyBackspace},
+			{code: keyUp}, {code: keyDelete}, {code: keyDown}, {code: keyUnknown}, {code: keyEsc}, {code: keyCtrlC},
+		}, got)
+	})
+
+	t.Run("成功：途中で切れた UTF-8 の文字は次の読み込みに回すこと", func(t *testing.T) {
+		b := []byte("xあ")
+		got, rest := parseKeys(b[:2])
+		assert.Equal(t, []key{{code: keyRune, r: 'x'}}, got)
+		got, rest = parseKeys(append(rest, b[2:]...))
+		assert.Empty(t, rest)
+		assert.Equal(t, []key{{code: keyRune, r: 'あ'}}, got)
+	})
+}
+
+func TestLineInput(t *testing.T) {
+	in := newLineInput("Title", "abc")
+	for _, k := range []key{{code: keyLeft}, {code: keyBackspace}, {code: keyRune, r: 'X'}, {code: keyHome}, {code: keyDelete}, {code: keyEnd}, {code: keyRune, r: '!'}} {
+		assert.True(t, in.handle(k))
+	}
+	assert.Equal(t, "Xc!", in.String())
+	assert.False(t, in.handle(key{code: keyUp}))
+}
+
+func TestWatch(t *testing.T) {
+	t.Run("成功：サブスクリプションで変更を受け取ること", func(t *testing.T) {
+		srv, uc := newTestServer(t)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		msgs := make(chan msg, 16)
+		go watch(ctx, apiclient.Config{BaseURL: srv.URL}, time.Hour, func(m msg) { msgs <- m })
+
+		assert.Equal(t, liveMsg{connected: true}, receive(t, msgs))
+		assert.Equal(t, changedMsg{}, receive(t, msgs), "接続した時点で読み込み直すこと")
+
+		// 購読の開始と変更が前後しないよう、通知を受けるまで変更を続ける
+		deadline := time.After(5 * time.Second)
+		for {
+			addTodo(t, uc, domain.TodoInput{Title: "通知"})
+			select {
+			case m := <-msgs:
+				assert.Equal(t, changedMsg{}, m)
+				return
+			case <-time.After(50 * time.Millisecond):
+			case <-deadline:
+				t.Fatal("no change notification")
+			}
+		}
+	})
+
+	t.Run("成功：接続できない場合は定期的に読み込み直すこと", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		msgs := make(chan msg, 16)
+		go watch(ctx, apiclient.Config{BaseURL: "http://127.0.0.1:1"}, 10*time.Millisecond, func(m msg) { msgs <- m })
+
+		assert.Equal(t, liveMsg{connected: false}, receive(t, msgs))
+		assert.Equal(t, changedMsg{}, receive(t, msgs))
+	})
+}
+
+func receive(t *testing.T, msgs <-chan msg) msg {
+	t.Helper()
+	select {
+	case m := <-msgs:
+		return m
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out")
+		return nil
+	}
+}
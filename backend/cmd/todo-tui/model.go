@@ -0,0 +1,503 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"todo_app_golang/internal/apiclient"
+	"todo_app_golang/internal/domain"
+)
+
+// todoAPI は TUI が使う REST API の操作です
+type todoAPI interface {
+	List(ctx context.Context, filter domain.TodoFilter) ([]*domain.Todo, error)
+	Create(ctx context.Context, req apiclient.TodoRequest) (*domain.Todo, error)
+	Update(ctx context.Context, id int, req apiclient.TodoRequest) (*domain.Todo, error)
+	SetCompleted(ctx context.Context, id int, completed bool) error
+	Delete(ctx context.Context, id int) error
+}
+
+// msg は画面の状態を変えるイベントです（キー入力・API の結果・ほかのクライアントによる変更など）
+type msg any
+
+// cmd は API の呼び出しなどの時間のかかる処理です。イベントループとは別の goroutine で実行し、結果を msg として返します
+type cmd func(ctx context.Context) msg
+
+type (
+	keyMsg    key
+	resizeMsg struct{ width, height int }
+	// loadedMsg は一覧の読み込みの結果です。seq が最後の読み込みと異なる場合は古い結果として捨てます
+	loadedMsg struct {
+		seq   int
+		todos []*domain.Todo
+		err   error
+	}
+	// doneMsg は作成・更新・削除の結果です。成功した場合は status を表示し、selectID のタスクを選択します
+	doneMsg struct {
+		status   string
+		selectID int
+		err      error
+	}
+	// changedMsg はほかのクライアントでタスクが変更されたことを表します
+	changedMsg struct{}
+	// liveMsg はサブスクリプションの接続状態が変わったことを表します
+	liveMsg struct{ connected bool }
+)
+
+type mode int
+
+const (
+	modeList          mode = iota
+	modeInput              // 画面下の入力欄で編集している
+	modeConfirmDelete      // 削除の確認
+)
+
+// inputKind は入力欄で編集しているものです
+type inputKind int
+
+const (
+	inputTitle inputKind = iota
+	inputDescription
+	inputDue
+	inputNew   // 新しいタスクのタイトル
+	inputQuery // 絞り込みのキーワード
+)
+
+var inputPrompts = map[inputKind]string{
+	inputTitle:       "Title",
+	inputDescription: `Description (\n for a line break)`,
+	inputDue:         "Due (YYYY-MM-DD, empty to clear)",
+	inputNew:         "New todo",
+	inputQuery:       "Search",
+}
+
+// chromeLines は一覧以外に使う行数です（見出し・列名・区切り・詳細2行・状態・キーの説明）
+const chromeLines = 7
+
+// model は画面の状態です。update でイベントを反映し、view で描画します
+type model struct {
+	api todoAPI
+
+	todos  []*domain.Todo
+	cursor int // 選択している行
+	offset int // 一覧の先頭に表示している行
+	filter domain.TodoFilter
+
+	mode      mode
+	input     lineInput
+	inputKind inputKind
+	target    int // 編集・削除しているタスクの ID
+
+	status   string
+	isError  bool
+	selectID int // 次の読み込みの後に選択するタスク（作成したタスクなど）
+
+	loadSeq       int
+	loading       bool
+	reloadPending bool // 読み込み中に変更の通知を受けた
+	live          bool
+
+	width, height int
+	quit          bool
+}
+
+func newModel(api todoAPI) *model {
+	return &model{api: api, width: 80, height: 24}
+}
+
+// init は最初の一覧の読み込みです
+func (m *model) init() cmd {
+	return m.load()
+}
+
+// update はイベントを反映し、次に実行する処理を返します
+func (m *model) update(msg msg) cmd {
+	switch msg := msg.(type) {
+	case keyMsg:
+		return m.handleKey(key(msg))
+	case resizeMsg:
+		m.width, m.height = msg.width, msg.height
+		m.scroll()
+	case loadedMsg:
+		if msg.seq != m.loadSeq {
+			return nil
+		}
+		m.loading = false
+		if msg.err != nil {
+			m.setError(msg.err)
+		} else {
+			m.setTodos(msg.todos)
+		}
+		if m.reloadPending {
+			m.reloadPending = false
+			return m.load()
+		}
+	case doneMsg:
+		if msg.err != nil {
+			m.setError(msg.err)
+		} else {
+			m.setStatus(msg.status)
+			m.selectID = msg.selectID
+		}
+		// 失敗した場合も、ほかのクライアントで削除されたなどの状態を反映するため読み込み直す
+		return m.load()
+	case changedMsg:
+		return m.reload()
+	case liveMsg:
+		m.live = msg.connected
+	}
+	return nil
+}
+
+// load は一覧を読み込みます。読み込み中の結果は捨てます（絞り込みを変えた場合など）
+func (m *model) load() cmd {
+	m.loadSeq++
+	m.loading = true
+	seq, filter, api := m.loadSeq, m.filter, m.api
+	return func(ctx context.Context) msg {
+		todos, err := api.List(ctx, filter)
+		return loadedMsg{seq: seq, todos: todos, err: err}
+	}
+}
+
+// reload は変更の通知を受けて読み込み直します。読み込み中の場合は、終わった後にもう一度だけ読み込みます（通知が続いた場合にまとめる）
+func (m *model) reload() cmd {
+	if m.loading {
+		m.reloadPending = true
+		return nil
+	}
+	return m.load()
+}
+
+// setTodos は読み込んだ一覧に置き換えます。選択していたタスクが残っている場合は選択したままにします
+func (m *model) setTodos(todos []*domain.Todo) {
+	id := m.selectID
+	if id == 0 {
+		if t := m.selected(); t != nil {
+			id = t.ID
+		}
+	}
+	m.selectID = 0
+	m.todos = todos
+	if i := slices.IndexFunc(todos, func(t *domain.Todo) bool { return t.ID == id }); i >= 0 {
+		m.cursor = i
+	}
+	m.cursor = max(min(m.cursor, len(todos)-1), 0)
+	m.scroll()
+}
+
+func (m *model) selected() *domain.Todo {
+	if m.cursor < len(m.todos) {
+		return m.todos[m.cursor]
+	}
+	return nil
+}
+
+func (m *model) find(id int) *domain.Todo {
+	if i := slices.IndexFunc(m.todos, func(t *domain.Todo) bool { return t.ID == id }); i >= 0 {
+		return m.todos[i]
+	}
+	return nil
+}
+
+func (m *model) setStatus(s string) {
+	m.status, m.isError = s, false
+}
+
+func (m *model) setError(err error) {
+	m.status, m.isError = err.Error(), true
+}
+
+// listHeight は一覧に使える行数です
+func (m *model) listHeight() int {
+	return max(m.height-chromeLines, 1)
+}
+
+func (m *model) move(delta int) {
+	m.cursor = max(min(m.cursor+delta, len(m.todos)-1), 0)
+	m.scroll()
+}
+
+// scroll は選択している行が見えるように一覧をスクロールします
+func (m *model) scroll() {
+	h := m.listHeight()
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+h {
+		m.offset = m.cursor - h + 1
+	}
+	m.offset = max(min(m.offset, len(m.todos)-h), 0)
+}
+
+func (m *model) handleKey(k key) cmd {
+	if k.code == keyCtrlC {
+		m.quit = true
+		return nil
+	}
+	switch m.mode {
+	case modeInput:
+		return m.handleInputKey(k)
+	case modeConfirmDelete:
+		m.mode = modeList
+		if k.code == keyRune && (k.r == 'y' || k.r == 'Y') {
+			return m.delete(m.target)
+		}
+		m.setStatus("Delete cancelled")
+		return nil
+	}
+
+	m.setStatus("")
+	switch k.code {
+	case keyUp:
+		m.move(-1)
+	case keyDown:
+		m.move(1)
+	case keyPageUp:
+		m.move(-m.listHeight())
+	case keyPageDown:
+		m.move(m.listHeight())
+	case keyHome:
+		m.move(-len(m.todos))
+	case keyEnd:
+		m.move(len(m.todos))
+	case keyEnter:
+		m.startEdit(inputTitle)
+	case keyDelete:
+		m.confirmDelete()
+	case keyEsc:
+		if m.filter != (domain.TodoFilter{}) {
+			m.filter = domain.TodoFilter{}
+			return m.load()
+		}
+	case keyRune:
+		return m.handleListRune(k.r)
+	}
+	return nil
+}
+
+func (m *model) handleListRune(r rune) cmd {
+	switch r {
+	case 'q':
+		m.quit = true
+	case 'k':
+		m.move(-1)
+	case 'j':
+		m.move(1)
+	case 'g':
+		m.move(-len(m.todos))
+	case 'G':
+		m.move(len(m.todos))
+	case ' ', 'x':
+		return m.toggle()
+	case 'a':
+		m.startInput(inputNew, 0, "")
+	case 'e':
+		m.startEdit(inputTitle)
+	case 'd':
+		m.startEdit(inputDescription)
+	case 't':
+		m.startEdit(inputDue)
+	case 'P':
+		return m.cyclePriority()
+	case 'X':
+		m.confirmDelete()
+	case 's':
+		m.filter.IsCompleted = nextStatusFilter(m.filter.IsCompleted)
+		return m.load()
+	case 'p':
+		m.filter.Priority = nextPriorityFilter(m.filter.Priority)
+		return m.load()
+	case '/':
+		m.startInput(inputQuery, 0, m.filter.Query)
+	case 'r':
+		return m.load()
+	}
+	return nil
+}
+
+// nextStatusFilter は完了状態の絞り込みを すべて → 未完了 → 完了 の順に切り替えます
+func nextStatusFilter(current *bool) *bool {
+	switch {
+	case current == nil:
+		open := false
+		return &open
+	case !*current:
+		done := true
+		return &done
+	default:
+		return nil
+	}
+}
+
+// nextPriorityFilter は優先度の絞り込みを すべて → high → medium → low の順に切り替えます
+func nextPriorityFilter(current string) string {
+	switch current {
+	case "":
+		return domain.PriorityHigh
+	case domain.PriorityHigh:
+		return domain.PriorityMedium
+	case domain.PriorityMedium:
+		return domain.PriorityLow
+	default:
+		return ""
+	}
+}
+
+func (m *model) startInput(kind inputKind, target int, value string) {
+	m.mode, m.inputKind, m.target = modeInput, kind, target
+	m.input = newLineInput(inputPrompts[kind], value)
+	m.setStatus("")
+}
+
+// startEdit は選択しているタスクの項目を入力欄で編集し始めます
+func (m *model) startEdit(kind inputKind) {
+	t := m.selected()
+	if t == nil {
+		return
+	}
+	var value string
+	switch kind {
+	case inputTitle:
+		value = t.Title
+	case inputDescription:
+		// 入力欄は1行のため、改行は \n と書く
+		value = strings.ReplaceAll(t.Description, "\n", `\n`)
+	case inputDue:
+		if t.DueDate != nil {
+			value = apiclient.FormatDate(t.DueDate)
+		}
+	}
+	m.startInput(kind, t.ID, value)
+}
+
+func (m *model) handleInputKey(k key) cmd {
+	switch k.code {
+	case keyEsc:
+		m.mode = modeList
+		return nil
+	case keyEnter:
+		return m.submit()
+	}
+	m.input.handle(k)
+	return nil
+}
+
+// submit は入力欄の内容を反映します。内容が正しくない場合は入力欄を開いたままにします
+func (m *model) submit() cmd {
+	value := strings.TrimSpace(m.input.String())
+	switch m.inputKind {
+	case inputQuery:
+		m.mode = modeList
+		m.filter.Query = value
+		return m.load()
+	case inputNew:
+		m.mode = modeList
+		if value == "" {
+			return nil
+		}
+		return m.create(value)
+	}
+
+	t := m.find(m.target)
+	if t == nil {
+		m.mode = modeList
+		m.setError(fmt.Errorf("#%d no longer exists", m.target))
+		return nil
+	}
+	req := requestFrom(t)
+	switch m.inputKind {
+	case inputTitle:
+		if value == "" {
+			m.setError(domain.ErrTitleEmpty)
+			return nil
+		}
+		req.Title = value
+	case inputDescription:
+		req.Description = strings.ReplaceAll(value, `\n`, "\n")
+	case inputDue:
+		req.DueDate = nil
+		if value != "" {
+			due, err := apiclient.ParseDate(value)
+			if err != nil {
+				m.setError(err)
+				return nil
+			}
+			req.DueDate = &due
+		}
+	}
+	m.mode = modeList
+	return m.save(t.ID, req)
+}
+
+// requestFrom は現在の内容の更新リクエストです（PUT は全項目を置き換えるため、変える項目だけを書き換えて使う）
+func requestFrom(t *domain.Todo) apiclient.TodoRequest {
+	return apiclient.TodoRequest{Title: t.Title, Description: t.Description, Priority: t.Priority, DueDate: t.DueDate}
+}
+
+func (m *model) confirmDelete() {
+	if t := m.selected(); t != nil {
+		m.mode, m.target = modeConfirmDelete, t.ID
+	}
+}
+
+func (m *model) toggle() cmd {
+	t := m.selected()
+	if t == nil {
+		return nil
+	}
+	id, completed, api := t.ID, !t.IsCompleted, m.api
+	return func(ctx context.Context) msg {
+		status := fmt.Sprintf("Completed #%d", id)
+		if !completed {
+			status = fmt.Sprintf("Reopened #%d", id)
+		}
+		return doneMsg{status: status, err: api.SetCompleted(ctx, id, completed)}
+	}
+}
+
+// cyclePriority は選択しているタスクの優先度を low → medium → high → low の順に変えます
+func (m *model) cyclePriority() cmd {
+	t := m.selected()
+	if t == nil {
+		return nil
+	}
+	req := requestFrom(t)
+	switch t.Priority {
+	case domain.PriorityLow:
+		req.Priority = domain.PriorityMedium
+	case domain.PriorityMedium:
+		req.Priority = domain.PriorityHigh
+	default:
+		req.Priority = domain.PriorityLow
+	}
+	return m.save(t.ID, req)
+}
+
+func (m *model) save(id int, req apiclient.TodoRequest) cmd {
+	api := m.api
+	return func(ctx context.Context) msg {
+		_, err := api.Update(ctx, id, req)
+		return doneMsg{status: fmt.Sprintf("Updated #%d", id), err: err}
+	}
+}
+
+func (m *model) create(title string) cmd {
+	api := m.api
+	return func(ctx context.Context) msg {
+		todo, err := api.Create(ctx, apiclient.TodoRequest{Title: title})
+		if err != nil {
+			return doneMsg{err: err}
+		}
+		return doneMsg{status: fmt.Sprintf("Added #%d", todo.ID), selectID: todo.ID}
+	}
+}
+
+func (m *model) delete(id int) cmd {
+	api := m.api
+	return func(ctx context.Context) msg {
+		err := api.Delete(ctx, id)
+		return doneMsg{status: fmt.Sprintf("Deleted #%d", id), err: err}
+	}
+}
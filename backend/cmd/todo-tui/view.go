@@ -0,0 +1,226 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"todo_app_golang/internal/apiclient"
+	"todo_app_golang/internal/domain"
+	"unicode"
+
+	"golang.org/x/text/width"
+)
+
+// 文字の装飾（SGR）
+const (
+	styleReset   = "\x1b[0m"
+	styleBold    = "\x1b[1m"
+	styleDim     = "\x1b[2m"
+	styleReverse = "\x1b[7m"
+	styleRed     = "\x1b[31m"
+)
+
+// frame は1回分の画面です。showCursor の場合は入力欄のカーソル位置 (row, col) に端末のカーソルを表示します
+type frame struct {
+	lines      []string
+	showCursor bool
+	row, col   int
+}
+
+var helpText = map[mode]string{
+	modeList:          "j/k move  space done  a add  e/d/t edit title/description/due  P priority  X delete  s/p filter  / search  esc clear  q quit",
+	modeInput:         "enter save  esc cancel  ctrl+u clear",
+	modeConfirmDelete: "y delete  any other key cancel",
+}
+
+// view は画面を描画します。各行は画面の幅に収まるように切り詰めます
+func (m *model) view(now time.Time) frame {
+	w := max(m.width, 20)
+	var f frame
+	add := func(style, s string) {
+		s = truncate(sanitize(s), w)
+		if style != "" {
+			s = style + pad(s, w) + styleReset
+		}
+		f.lines = append(f.lines, s)
+	}
+
+	add(styleBold, m.header(w))
+	add(styleDim, fmt.Sprintf("     %5s  %-6s  %-10s  %s", "ID", "PRI", "DUE", "TITLE"))
+	h := m.listHeight()
+	for i := m.offset; i < m.offset+h; i++ {
+		if i >= len(m.todos) {
+			if i == 0 {
+				add(styleDim, "  (no todos)")
+			} else {
+				add("", "")
+			}
+			continue
+		}
+		t := m.todos[i]
+		style := ""
+		switch {
+		case i == m.cursor:
+			style = styleReverse
+		case t.IsCompleted:
+			style = styleDim
+		case t.DueDate != nil && t.DueDate.Before(now):
+			style = styleRed // 期限切れ
+		}
+		add(style, row(t, i == m.cursor))
+	}
+	add(styleDim, strings.Repeat("─", w))
+	for _, line := range m.detail() {
+		add("", line)
+	}
+
+	switch m.mode {
+	case modeInput:
+		line, col := m.inputLine(w)
+		f.lines = append(f.lines, line)
+		f.showCursor, f.row, f.col = true, len(f.lines)-1, col
+	case modeConfirmDelete:
+		title := ""
+		if t := m.find(m.target); t != nil {
+			title = t.Title
+		}
+		add(styleBold, fmt.Sprintf("Delete #%d %q? (y/N)", m.target, title))
+	default:
+		if m.isError {
+			add(styleRed, m.status)
+		} else {
+			add("", m.status)
+		}
+	}
+	add(styleDim, helpText[m.mode])
+	return f
+}
+
+// header は1行目です（絞り込みの条件・件数・変更の受け取り方）
+func (m *model) header(w int) string {
+	left := "Todo  status:" + statusLabel(m.filter.IsCompleted) + "  priority:" + or(m.filter.Priority, "all")
+	if m.filter.Query != "" {
+		left += fmt.Sprintf("  search:%q", m.filter.Query)
+	}
+	right := fmt.Sprintf("%d todos  ", len(m.todos))
+	if m.loading {
+		right = "loading…  "
+	}
+	if m.live {
+		right += "● live"
+	} else {
+		right += "○ polling"
+	}
+	gap := w - displayWidth(left) - displayWidth(right)
+	if gap < 1 {
+		return left + " " + right
+	}
+	return left + strings.Repeat(" ", gap) + right
+}
+
+// row は一覧の1行です
+func row(t *domain.Todo, selected bool) string {
+	marker, check := " ", "[ ]"
+	if selected {
+		marker = ">"
+	}
+	if t.IsCompleted {
+		check = "[x]"
+	}
+	due := ""
+	if t.DueDate != nil {
+		due = apiclient.FormatDate(t.DueDate)
+	}
+	return fmt.Sprintf("%s %s %5d  %-6s  %-10s  %s", marker, check, t.ID, t.Priority, due, t.Title)
+}
+
+// detail は選択しているタスクの詳細（詳細説明の1行目と日時）の2行です
+func (m *model) detail() []string {
+	t := m.selected()
+	if t == nil {
+		return []string{"", ""}
+	}
+	desc, _, more := strings.Cut(t.Description, "\n")
+	if more {
+		desc += " …"
+	}
+	times := "Created " + t.CreatedAt.Local().Format(time.DateTime)
+	if t.CompletedAt != nil {
+		times += "  Completed " + t.CompletedAt.Local().Format(time.DateTime)
+	}
+	return []string{or(desc, "(no description)"), times}
+}
+
+// inputLine は入力欄の行と、カーソルの桁を返します。長い場合はカーソルが見えるように左側を省きます
+func (m *model) inputLine(w int) (string, int) {
+	prompt := m.input.prompt + ": "
+	text := m.input.text
+	start := 0
+	for start < m.input.pos && displayWidth(prompt)+displayWidth(string(text[start:m.input.pos])) >= w {
+		start++
+	}
+	line := truncate(sanitize(prompt+string(text[start:])), w)
+	return line, displayWidth(prompt) + displayWidth(string(text[start:m.input.pos]))
+}
+
+func statusLabel(completed *bool) string {
+	switch {
+	case completed == nil:
+		return "all"
+	case *completed:
+		return "done"
+	default:
+		return "open"
+	}
+}
+
+func or(s, fallback string) string {
+	if s == "" {
+		return fallback
+	}
+	return s
+}
+
+// sanitize は制御文字を空白にします（タイトルなどに含まれるエスケープシーケンスで画面が崩れないようにする）
+func sanitize(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return ' '
+		}
+		return r
+	}, s)
+}
+
+// runeWidth は端末に表示したときの文字の幅です（全角は 2）
+func runeWidth(r rune) int {
+	switch width.LookupRune(r).Kind() {
+	case width.EastAsianWide, width.EastAsianFullwidth:
+		return 2
+	}
+	return 1
+}
+
+func displayWidth(s string) int {
+	n := 0
+	for _, r := range s {
+		n += runeWidth(r)
+	}
+	return n
+}
+
+// truncate は s を幅 w に収まるように切り詰めます
+func truncate(s string, w int) string {
+	n := 0
+	for i, r := range s {
+		if n+runeWidth(r) > w {
+			return s[:i]
+		}
+		n += runeWidth(r)
+	}
+	return s
+}
+
+// pad は s の右を空白で埋めて幅 w にします（反転表示の行を画面の端まで伸ばす）
+func pad(s string, w int) string {
+	return s + strings.Repeat(" ", max(w-displayWidth(s), 0))
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+	"todo_app_golang/internal/apiclient"
+
+	"github.com/coder/websocket"
+)
+
+// wsProtocol は GraphQL のサブスクリプションのプロトコルです（サーバーの graphqlapi と同じ）
+const wsProtocol = "graphql-transport-ws"
+
+// changesQuery は変更を受け取るサブスクリプションです。一覧は読み込み直すため、種類と ID だけを受け取ります
+const changesQuery = `subscription { todoChanged { type id } }`
+
+type wsMessage struct {
+	ID      string          `json:"id,omitempty"`
+	Type    string          `json:"type"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+// watch はほかのクライアントによる変更を受け取り、そのたびに changedMsg を送ります。ctx が終わるまで戻りません。
+// GraphQL のサブスクリプション (todoChanged) で受け取り、接続できない間や切断された後は poll ごとに読み込み直しながら再接続します
+func watch(ctx context.Context, cfg apiclient.Config, poll time.Duration, send func(msg)) {
+	for {
+		subscribe(ctx, cfg, send)
+		if ctx.Err() != nil {
+			return
+		}
+		send(liveMsg{connected: false})
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(poll):
+		}
+		send(changedMsg{})
+	}
+}
+
+// subscribe はサブスクリプションを始め、切断されるまで変更を受け取ります。
+// 接続した時点で、切断されていた間の変更を反映するために一度読み込み直します
+func subscribe(ctx context.Context, cfg apiclient.Config, send func(msg)) error {
+	opts := &websocket.DialOptions{Subprotocols: []string{wsProtocol}}
+	if cfg.Token != "" {
+		opts.HTTPHeader = http.Header{"Authorization": {"Bearer " + cfg.Token}}
+	}
+	dialCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	conn, _, err := websocket.Dial(dialCtx, websocketURL(cfg.BaseURL), opts)
+	cancel()
+	if err != nil {
+		return err
+	}
+	defer conn.CloseNow()
+
+	write := func(m wsMessage) error {
+		b, err := json.Marshal(m)
+		if err != nil {
+			return err
+		}
+		return conn.Write(ctx, websocket.MessageText, b)
+	}
+	if err := write(wsMessage{Type: "connection_init"}); err != nil {
+		return err
+	}
+	for {
+		_, data, err := conn.Read(ctx)
+		if err != nil {
+			return err
+		}
+		var m wsMessage
+		if err := json.Unmarshal(data, &m); err != nil {
+			return err
+		}
+		switch m.Type {
+		case "connection_ack":
+			payload, _ := json.Marshal(map[string]string{"query": changesQuery})
+			if err := write(wsMessage{ID: "changes", Type: "subscribe", Payload: payload}); err != nil {
+				return err
+			}
+			send(liveMsg{connected: true})
+			send(changedMsg{})
+		case "next":
+			send(changedMsg{})
+		case "ping":
+			if err := write(wsMessage{Type: "pong"}); err != nil {
+				return err
+			}
+		case "error":
+			return fmt.Errorf("subscription failed: %s", m.Payload)
+		case "complete":
+			// 受信が追いつかなかった場合など。読み込み直してから購読し直す
+			return errors.New("subscription completed by the server")
+		}
+	}
+}
+
+// websocketURL は API の URL から GraphQL の WebSocket の URL を作ります（http → ws、https → wss）
+func websocketURL(baseURL string) string {
+	return "ws" + strings.TrimPrefix(baseURL, "http") + "/graphql"
+}
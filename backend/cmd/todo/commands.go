@@ -0,0 +1,252 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+	"todo_app_golang/internal/apiclient"
+	"todo_app_golang/internal/domain"
+)
+
+// add: todo add TITLE [--priority P] [--due YYYY-MM-DD] [--description TEXT]
+func (a *app) add(ctx context.Context, args []string) error {
+	fs := a.flags()
+	priority := fs.String("priority", "", "")
+	due := fs.String("due", "", "")
+	description := fs.String("description", "", "")
+	pos, err := a.parse(fs, args)
+	if err != nil {
+		return err
+	}
+
+	// 引用符で囲まなくても todo add 資料 作成 のように書けるようにする
+	req := apiclient.TodoRequest{Title: strings.TrimSpace(strings.Join(pos, " ")), Description: *description}
+	if req.Title == "" {
+		return fmt.Errorf("%w: TITLE is required", errUsage)
+	}
+	if req.Priority, err = priorityFlag(*priority); err != nil {
+		return err
+	}
+	if *due != "" {
+		d, err := apiclient.ParseDate(*due)
+		if err != nil {
+			return fmt.Errorf("%w: %v", errUsage, err)
+		}
+		req.DueDate = &d
+	}
+
+	todo, err := a.client.Create(ctx, req)
+	if err != nil {
+		return err
+	}
+	return a.out.Todo(todo)
+}
+
+// list: todo ls [--open | --done] [--priority P] [--query TEXT] [--due-before YYYY-MM-DD]
+func (a *app) list(ctx context.Context, args []string) error {
+	fs := a.flags()
+	open := fs.Bool("open", false, "")
+	done := fs.Bool("done", false, "")
+	priority := fs.String("priority", "", "")
+	query := fs.String("query", "", "")
+	dueBefore := fs.String("due-before", "", "")
+	pos, err := a.parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(pos) > 0 {
+		return fmt.Errorf("%w: unexpected argument %q", errUsage, pos[0])
+	}
+	if *open && *done {
+		return fmt.Errorf("%w: --open and --done cannot be used together", errUsage)
+	}
+
+	filter := domain.TodoFilter{Query: *query}
+	if *open || *done {
+		filter.IsCompleted = done
+	}
+	if filter.Priority, err = priorityFlag(*priority); err != nil {
+		return err
+	}
+	if *dueBefore != "" {
+		d, err := apiclient.ParseDate(*dueBefore)
+		if err != nil {
+			return fmt.Errorf("%w: %v", errUsage, err)
+		}
+		filter.DueBefore = &d
+	}
+
+	todos, err := a.client.List(ctx, filter)
+	if err != nil {
+		return err
+	}
+	return a.out.List(todos)
+}
+
+// show: todo show ID
+func (a *app) show(ctx context.Context, args []string) error {
+	id, err := a.parseID(a.flags(), args)
+	if err != nil {
+		return err
+	}
+	todo, err := a.client.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	return a.out.Todo(todo)
+}
+
+// done: todo done ID... [--undo]。更新後のタスクを一覧の形式で出力します
+func (a *app) done(ctx context.Context, args []string) error {
+	fs := a.flags()
+	undo := fs.Bool("undo", false, "")
+	pos, err := a.parse(fs, args)
+	if err != nil {
+		return err
+	}
+	ids, err := parseIDs(pos)
+	if err != nil {
+		return err
+	}
+
+	todos := make([]*domain.Todo, 0, len(ids))
+	for _, id := range ids {
+		if err := a.client.SetCompleted(ctx, id, !*undo); err != nil {
+			return fmt.Errorf("#%d: %w", id, err)
+		}
+		todo, err := a.client.Get(ctx, id)
+		if err != nil {
+			return fmt.Errorf("#%d: %w", id, err)
+		}
+		todos = append(todos, todo)
+	}
+	return a.out.List(todos)
+}
+
+// remove: todo rm ID...
+func (a *app) remove(ctx context.Context, args []string) error {
+	fs := a.flags()
+	pos, err := a.parse(fs, args)
+	if err != nil {
+		return err
+	}
+	ids, err := parseIDs(pos)
+	if err != nil {
+		return err
+	}
+
+	deleted := make([]int, 0, len(ids))
+	for _, id := range ids {
+		if err := a.client.Delete(ctx, id); err != nil {
+			// 途中で失敗した場合も、それまでに削除したものは出力する
+			a.out.Deleted(deleted)
+			return fmt.Errorf("#%d: %w", id, err)
+		}
+		deleted = append(deleted, id)
+	}
+	return a.out.Deleted(deleted)
+}
+
+// edit: todo edit ID [--title T] [--priority P] [--due YYYY-MM-DD] [--description TEXT]。
+// フラグを指定しない場合はエディタで開きます
+func (a *app) edit(ctx context.Context, args []string) error {
+	fs := a.flags()
+	title := fs.String("title", "", "")
+	priority := fs.String("priority", "", "")
+	due := fs.String("due", "", "") // 空文字を指定すると期限を外す
+	description := fs.String("description", "", "")
+	id, err := a.parseID(fs, args)
+	if err != nil {
+		return err
+	}
+
+	todo, err := a.client.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	// PUT は全項目を置き換えるため、現在の値から始める
+	req := apiclient.TodoRequest{Title: todo.Title, Description: todo.Description, Priority: todo.Priority, DueDate: todo.DueDate}
+
+	set := map[string]bool{}
+	fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+	delete(set, "o")
+	if len(set) == 0 {
+		req, err = a.editTodo(todo)
+		switch {
+		case errors.Is(err, errEditAborted):
+			fmt.Fprintln(a.stderr, "todo edit:", err)
+			return nil
+		case err != nil:
+			return err
+		case sameEdit(req, todo):
+			fmt.Fprintln(a.stderr, "todo edit: no changes")
+			return nil
+		}
+	} else {
+		if set["title"] {
+			req.Title = strings.TrimSpace(*title)
+		}
+		if set["description"] {
+			req.Description = *description
+		}
+		if set["priority"] {
+			if req.Priority, err = priorityFlag(*priority); err != nil {
+				return err
+			}
+		}
+		if set["due"] {
+			req.DueDate = nil
+			if *due != "" {
+				d, err := apiclient.ParseDate(*due)
+				if err != nil {
+					return fmt.Errorf("%w: %v", errUsage, err)
+				}
+				req.DueDate = &d
+			}
+		}
+	}
+
+	updated, err := a.client.Update(ctx, id, req)
+	if err != nil {
+		return err
+	}
+	return a.out.Todo(updated)
+}
+
+// editTodo はタスクをエディタで開き、編集した内容を返します
+func (a *app) editTodo(todo *domain.Todo) (apiclient.TodoRequest, error) {
+	edited, err := a.editInEditor(formatForEdit(todo))
+	if err != nil {
+		return apiclient.TodoRequest{}, err
+	}
+	return parseEdited(strings.NewReader(edited))
+}
+
+// sameEdit はエディタでの編集結果が元のタスクから変わっていないかを判定します（期限は日付の単位で比べる）
+func sameEdit(req apiclient.TodoRequest, todo *domain.Todo) bool {
+	if req.Title != todo.Title || req.Description != strings.TrimSpace(todo.Description) || req.Priority != todo.Priority {
+		return false
+	}
+	if req.DueDate == nil || todo.DueDate == nil {
+		return req.DueDate == nil && todo.DueDate == nil
+	}
+	return apiclient.FormatDate(req.DueDate) == apiclient.FormatDate(todo.DueDate)
+}
+
+// parseID は ID を1つだけ受け取るコマンドの引数を解釈します
+func (a *app) parseID(fs *flag.FlagSet, args []string) (int, error) {
+	pos, err := a.parse(fs, args)
+	if err != nil {
+		return 0, err
+	}
+	if len(pos) != 1 {
+		return 0, fmt.Errorf("%w: exactly one ID is required", errUsage)
+	}
+	ids, err := parseIDs(pos)
+	if err != nil {
+		return 0, err
+	}
+	return ids[0], nil
+}
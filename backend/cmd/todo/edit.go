@@ -0,0 +1,131 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"todo_app_golang/internal/apiclient"
+	"todo_app_golang/internal/domain"
+)
+
+// editTemplate はエディタで開く内容の末尾に付ける説明です
+const editTemplate = `
+# 1行目からの "Title:" / "Priority:" / "Due:" を編集し、空行の後に詳細説明を書いてください。
+# Priority は low / medium / high、Due は YYYY-MM-DD です（空にすると期限を外します）。
+# '#' で始まる行は無視します。タイトルを空にすると編集を取りやめます。
+`
+
+// formatForEdit はタスクをエディタで編集する形式にします（メールのヘッダーと本文のような形式）
+func formatForEdit(t *domain.Todo) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Title: %s\n", t.Title)
+	fmt.Fprintf(&b, "Priority: %s\n", t.Priority)
+	due := ""
+	if t.DueDate != nil {
+		due = apiclient.FormatDate(t.DueDate)
+	}
+	fmt.Fprintf(&b, "Due: %s\n", due)
+	b.WriteString("\n")
+	if t.Description != "" {
+		b.WriteString(t.Description + "\n")
+	}
+	b.WriteString(editTemplate)
+	return b.String()
+}
+
+// errEditAborted はタイトルが空にされ、編集を取りやめたことを表します
+var errEditAborted = errors.New("title is empty, edit aborted")
+
+// parseEdited はエディタで編集した内容を更新のリクエストにします
+func parseEdited(r io.Reader) (apiclient.TodoRequest, error) {
+	var req apiclient.TodoRequest
+	var body []string
+	inHeader := true
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		line := sc.Text()
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		if !inHeader {
+			body = append(body, line)
+			continue
+		}
+		if strings.TrimSpace(line) == "" {
+			inHeader = false
+			continue
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return req, fmt.Errorf("invalid line %q: expected \"Key: value\"", line)
+		}
+		value = strings.TrimSpace(value)
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "title":
+			req.Title = value
+		case "priority":
+			req.Priority = strings.ToLower(value)
+		case "due":
+			if value == "" {
+				continue
+			}
+			due, err := apiclient.ParseDate(value)
+			if err != nil {
+				return req, err
+			}
+			req.DueDate = &due
+		default:
+			return req, fmt.Errorf("unknown field %q", key)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return req, err
+	}
+	if req.Title == "" {
+		return req, errEditAborted
+	}
+	req.Description = strings.TrimSpace(strings.Join(body, "\n"))
+	return req, nil
+}
+
+// editInEditor は content を一時ファイルに書き、VISUAL / EDITOR（無ければ vi）で開いて、編集後の内容を返します
+func (a *app) editInEditor(content string) (string, error) {
+	f, err := os.CreateTemp("", "todo-*.txt")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.WriteString(content); err != nil {
+		f.Close()
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		return "", err
+	}
+
+	editor := a.getenv("VISUAL")
+	if editor == "" {
+		editor = a.getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+	// "code --wait" のように引数を含めて指定できる
+	fields := strings.Fields(editor)
+	cmd := exec.Command(fields[0], append(fields[1:], f.Name())...)
+	// 標準出力をパイプにしている場合（-o json | jq など）もエディタが端末に表示できるよう、標準エラーに出す
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = a.stdin, a.stderr, a.stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("editor %q: %w", editor, err)
+	}
+
+	edited, err := os.ReadFile(f.Name())
+	if err != nil {
+		return "", err
+	}
+	return string(edited), nil
+}
@@ -0,0 +1,185 @@
+// Command todo は REST API を使ってターミナルからタスクを管理する CLI です。
+//
+//	todo add "資料作成 +仕事" --priority high --due 2026-11-01
+//	todo ls --open
+//	todo done 12
+//
+// API の URL とトークンは設定ファイル（~/.config/todo/config.yaml など）・環境変数・引数で指定します
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"todo_app_golang/internal/apiclient"
+	"todo_app_golang/internal/domain"
+)
+
+const usage = `usage: todo [global flags] <command> [flags] [args]
+
+commands:
+  add TITLE       タスクを作成する（--priority low|medium|high、--due YYYY-MM-DD、--description TEXT）
+  ls              タスクの一覧を表示する（--open / --done、--priority、--query、--due-before YYYY-MM-DD）
+  show ID         タスクの詳細を表示する
+  done ID...      タスクを完了にする（--undo で未完了に戻す）
+  rm ID...        タスクを削除する
+  edit ID         タスクを編集する（--title / --priority / --due / --description。指定しない場合はエディタで開く）
+
+global flags:
+  -config PATH    設定ファイル（既定: TODO_CONFIG、無ければ ~/.config/todo/config.yaml）
+  -url URL        API の URL（TODO_API_URL、設定ファイルの base_url。既定: http://localhost:8080）
+  -token TOKEN    Authorization: Bearer で送るトークン（TODO_TOKEN、設定ファイルの token）
+  -o FORMAT       出力形式 table / json / plain（TODO_OUTPUT、設定ファイルの output。各コマンドの後にも指定できる）`
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	os.Exit(run(ctx, os.Args[1:], os.Stdin, os.Stdout, os.Stderr, os.Getenv))
+}
+
+// app は1回のコマンドの実行に必要なものです
+type app struct {
+	name   string // 実行中のコマンド
+	cfg    apiclient.Config
+	client *apiclient.Client
+	out    *printer
+	stdin  io.Reader
+	stderr io.Writer
+	getenv func(string) string
+}
+
+// errUsage は引数の誤りを表します（使い方を表示して終了コード 2 で終わる）
+var errUsage = errors.New("usage error")
+
+// run はコマンドを実行し、終了コードを返します
+func run(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer, getenv func(string) string) int {
+	global := flag.NewFlagSet("todo", flag.ContinueOnError)
+	global.SetOutput(io.Discard)
+	configPath := global.String("config", "", "")
+	baseURL := global.String("url", "", "")
+	token := global.String("token", "", "")
+	output := global.String("o", "", "")
+	if err := global.Parse(args); err != nil || global.NArg() == 0 {
+		if !errors.Is(err, flag.ErrHelp) && err != nil {
+			fmt.Fprintln(stderr, "todo:", err)
+		}
+		fmt.Fprintln(stderr, usage)
+		return 2
+	}
+
+	cfg, err := apiclient.LoadConfig(*configPath, getenv)
+	if err != nil {
+		fmt.Fprintln(stderr, "todo: config:", err)
+		return 2
+	}
+	for _, f := range []struct {
+		value string
+		dst   *string
+	}{{*baseURL, &cfg.BaseURL}, {*token, &cfg.Token}, {*output, &cfg.Output}} {
+		if f.value != "" {
+			*f.dst = f.value
+		}
+	}
+
+	a := &app{cfg: cfg, stdin: stdin, stderr: stderr, getenv: getenv}
+	a.out = &printer{w: stdout}
+	commands := map[string]func(context.Context, []string) error{
+		"add":  a.add,
+		"ls":   a.list,
+		"show": a.show,
+		"done": a.done,
+		"rm":   a.remove,
+		"edit": a.edit,
+	}
+	a.name = global.Arg(0)
+	cmd, ok := commands[a.name]
+	if !ok {
+		fmt.Fprintf(stderr, "todo: unknown command %q\n%s\n", a.name, usage)
+		return 2
+	}
+
+	switch err := cmd(ctx, global.Args()[1:]); {
+	case err == nil:
+		return 0
+	case errors.Is(err, errUsage), errors.Is(err, flag.ErrHelp):
+		fmt.Fprintf(stderr, "todo %s: %v\n%s\n", a.name, err, usage)
+		return 2
+	default:
+		fmt.Fprintf(stderr, "todo %s: %v\n", a.name, err)
+		return 1
+	}
+}
+
+// flags はコマンドのフラグを登録する FlagSet を返します（-o は全コマンド共通）
+func (a *app) flags() *flag.FlagSet {
+	fs := flag.NewFlagSet("todo "+a.name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.StringVar(&a.cfg.Output, "o", a.cfg.Output, "")
+	return fs
+}
+
+// parse はフラグと位置引数が混ざった引数を解釈し（todo add "タイトル" --priority high）、設定を検証してクライアントを用意します。
+// 位置引数を返します
+func (a *app) parse(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, fmt.Errorf("%w: %v", errUsage, err)
+		}
+		rest := fs.Args()
+		if n := len(args) - len(rest); n > 0 && args[n-1] == "--" {
+			positional = append(positional, rest...) // -- の後はすべて位置引数
+			break
+		}
+		args = rest
+		if len(args) == 0 {
+			break
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+
+	if err := a.cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", errUsage, err)
+	}
+	switch a.cfg.Output {
+	case formatTable, formatJSON, formatPlain:
+	default:
+		return nil, fmt.Errorf("%w: output must be one of table, json, plain (got %q)", errUsage, a.cfg.Output)
+	}
+	a.client = apiclient.New(a.cfg)
+	a.out.format = a.cfg.Output
+	return positional, nil
+}
+
+// parseIDs は位置引数をタスクの ID として解釈します
+func parseIDs(args []string) ([]int, error) {
+	if len(args) == 0 {
+		return nil, fmt.Errorf("%w: ID is required", errUsage)
+	}
+	ids := make([]int, len(args))
+	for i, arg := range args {
+		id, err := strconv.Atoi(strings.TrimPrefix(arg, "#"))
+		if err != nil || id <= 0 {
+			return nil, fmt.Errorf("%w: invalid ID %q", errUsage, arg)
+		}
+		ids[i] = id
+	}
+	return ids, nil
+}
+
+// priorityFlag は --priority の値を検証します（空の場合は指定なし）
+func priorityFlag(p string) (string, error) {
+	p = strings.ToLower(p)
+	switch p {
+	case "", domain.PriorityLow, domain.PriorityMedium, domain.PriorityHigh:
+		return p, nil
+	}
+	return "", fmt.Errorf("%w: priority must be low, medium or high", errUsage)
+}
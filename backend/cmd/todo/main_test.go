@@ -0,0 +1,271 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"todo_app_golang/internal/apiclient"
+	"todo_app_golang/internal/domain"
+	"todo_app_golang/internal/infrastructure"
+	"todo_app_golang/internal/interface/handler"
+	"todo_app_golang/internal/interface/openapi"
+	"todo_app_golang/internal/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestServer はメモリ上のリポジトリを使った REST API のサーバーを起動します（リクエストは OpenAPI 仕様で検証する）。
+// 受け取った Authorization ヘッダーを返す関数も返します
+func newTestServer(t *testing.T) (*httptest.Server, func() string) {
+	t.Helper()
+	spec, err := openapi.Load()
+	require.NoError(t, err)
+	h := handler.NewTodoHandler(usecase.NewTodoUseCase(infrastructure.NewMemoryTodoRepository()))
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /todos", h.CreateTodoHandler)
+	mux.HandleFunc("GET /todos", h.GetAllTodosHandler)
+	mux.HandleFunc("GET /todos/{id}", h.GetTodoByIDHandler)
+	mux.HandleFunc("PUT /todos/{id}", h.UpdateTodoHandler)
+	mux.HandleFunc("PATCH /todos/{id}", h.UpdateTodoStatusHandler)
+	mux.HandleFunc("DELETE /todos/{id}", h.DeleteTodoHandler)
+
+	var auth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		spec.Middleware(mux).ServeHTTP(w, r)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, func() string { return auth }
+}
+
+type result struct {
+	code   int
+	stdout string
+	stderr string
+}
+
+// runCLI は env を環境変数としてコマンドを実行します（実際の環境変数や設定ファイルは読まない）
+func runCLI(t *testing.T, env map[string]string, args ...string) result {
+	t.Helper()
+	if _, ok := env["TODO_CONFIG"]; !ok {
+		env["TODO_CONFIG"] = filepath.Join(t.TempDir(), "none.yaml")
+		os.WriteFile(env["TODO_CONFIG"], nil, 0o600)
+	}
+	var stdout, stderr bytes.Buffer
+	code := run(context.Background(), args, strings.NewReader(""), &stdout, &stderr, func(key string) string { return env[key] })
+	return result{code, stdout.String(), stderr.String()}
+}
+
+func decodeTodo(t *testing.T, s string) *domain.Todo {
+	t.Helper()
+	var todo domain.Todo
+	require.NoError(t, json.Unmarshal([]byte(s), &todo), s)
+	return &todo
+}
+
+func TestRun(t *testing.T) {
+	srv, _ := newTestServer(t)
+	env := func() map[string]string { return map[string]string{"TODO_API_URL": srv.URL} }
+
+	t.Run("成功：作成・一覧・完了・削除を行えること", func(t *testing.T) {
+		res := runCLI(t, env(), "-o", "json", "add", "資料作成", "+仕事", "--priority", "high", "--due", "2026-11-01")
+		require.Equal(t, 0, res.code, res.stderr)
+		todo := decodeTodo(t, res.stdout)
+		assert.Equal(t, "資料作成 +仕事", todo.Title)
+		assert.Equal(t, domain.PriorityHigh, todo.Priority)
+		require.NotNil(t, todo.DueDate)
+		assert.Equal(t, "2026-11-01", todo.DueDate.Local().Format(apiclient.DateLayout))
+		id := strconv.Itoa(todo.ID)
+
+		res = runCLI(t, env(), "ls", "--open")
+		assert.Equal(t, 0, res.code, res.stderr)
+		assert.Contains(t, res.stdout, "ID")
+		assert.Contains(t, res.stdout, "資料作成 +仕事")
+
+		res = runCLI(t, env(), "done", id, "-o", "plain")
+		assert.Equal(t, 0, res.code, res.stderr)
+		assert.Equal(t, id+"\tdone\thigh\t2026-11-01\t資料作成 +仕事\n", res.stdout)
+
+		res = runCLI(t, env(), "ls", "--open", "-o", "plain")
+		assert.Equal(t, 0, res.code, res.stderr)
+		assert.Empty(t, res.stdout)
+
+		res = runCLI(t, env(), "rm", "#"+id)
+		assert.Equal(t, 0, res.code, res.stderr)
+		assert.Equal(t, "Deleted "+id+"\n", res.stdout)
+
+		res = runCLI(t, env(), "show", id)
+		assert.Equal(t, 1, res.code)
+		assert.Contains(t, res.stderr, "HTTP 404")
+	})
+
+	t.Run("成功：一覧を優先度と期限で絞り込めること", func(t *testing.T) {
+		for _, args := range [][]string{
+			{"add", "filter-low", "--priority", "low"},
+			{"add", "filter-due", "--due", "2026-01-01"},
+		} {
+			require.Equal(t, 0, runCLI(t, env(), args...).code)
+		}
+
+		res := runCLI(t, env(), "ls", "--priority", "low", "--query", "filter-", "-o", "json")
+		assert.Equal(t, 0, res.code, res.stderr)
+		var todos []*domain.Todo
+		require.NoError(t, json.Unmarshal([]byte(res.stdout), &todos))
+		require.Len(t, todos, 1)
+		assert.Equal(t, "filter-low", todos[0].Title)
+
+		res = runCLI(t, env(), "ls", "--due-before", "2026-02-01", "--query", "filter-", "-o", "plain")
+		assert.Equal(t, 0, res.code, res.stderr)
+		assert.Contains(t, res.stdout, "filter-due")
+		assert.NotContains(t, res.stdout, "filter-low")
+	})
+
+	t.Run("成功：フラグで指定した項目だけを編集できること", func(t *testing.T) {
+		res := runCLI(t, env(), "-o", "json", "add", "編集前", "--description", "詳細", "--due", "2026-11-01")
+		require.Equal(t, 0, res.code, res.stderr)
+		id := strconv.Itoa(decodeTodo(t, res.stdout).ID)
+
+		res = runCLI(t, env(), "-o", "json", "edit", id, "--title", "編集後", "--due", "")
+		require.Equal(t, 0, res.code, res.stderr)
+		todo := decodeTodo(t, res.stdout)
+		assert.Equal(t, "編集後", todo.Title)
+		assert.Equal(t, "詳細", todo.Description)
+		assert.Nil(t, todo.DueDate)
+	})
+
+	t.Run("成功：エディタで編集できること", func(t *testing.T) {
+		if runtime.GOOS == "windows" {
+			t.Skip("the test editor is a shell script")
+		}
+		res := runCLI(t, env(), "-o", "json", "add", "エディタ", "--priority", "low")
+		require.Equal(t, 0, res.code, res.stderr)
+		id := strconv.Itoa(decodeTodo(t, res.stdout).ID)
+
+		editor := filepath.Join(t.TempDir(), "editor.sh")
+		script := "#!/bin/sh\nsed -i -e 's/^Title: .*/Title: エディタで編集/' -e 's/^Priority: .*/Priority: high/' -e 's/^Due: .*/Due: 2026-12-24/' \"$1\"\nprintf '\\n2行目\\n' >> \"$1\"\n"
+		require.NoError(t, os.WriteFile(editor, []byte(script), 0o755))
+
+		e := env()
+		e["EDITOR"] = editor
+		res = runCLI(t, e, "-o", "json", "edit", id)
+		require.Equal(t, 0, res.code, res.stderr)
+		todo := decodeTodo(t, res.stdout)
+		assert.Equal(t, "エディタで編集", todo.Title)
+		assert.Equal(t, domain.PriorityHigh, todo.Priority)
+		assert.Equal(t, "2026-12-24", apiclient.FormatDate(todo.DueDate))
+		assert.Equal(t, "2行目", todo.Description)
+
+		e["EDITOR"] = "true" // 何も変更しない
+		res = runCLI(t, e, "edit", id)
+		assert.Equal(t, 0, res.code, res.stderr)
+		assert.Empty(t, res.stdout)
+		assert.Contains(t, res.stderr, "no changes")
+	})
+
+	t.Run("失敗：API のエラーを表示し、終了コード 1 になること", func(t *testing.T) {
+		res := runCLI(t, env(), "done", "999")
+		assert.Equal(t, 1, res.code)
+		assert.Contains(t, res.stderr, "#999")
+		assert.Contains(t, res.stderr, "HTTP 404")
+	})
+
+	t.Run("失敗：引数の誤りは終了コード 2 になること", func(t *testing.T) {
+		for _, args := range [][]string{
+			{},
+			{"unknown"},
+			{"add"},
+			{"add", "x", "--priority", "urgent"},
+			{"add", "x", "--due", "11/01"},
+			{"ls", "--open", "--done"},
+			{"done"},
+			{"done", "abc"},
+			{"edit", "1", "2"},
+			{"ls", "-o", "yaml"},
+		} {
+			res := runCLI(t, env(), args...)
+			assert.Equal(t, 2, res.code, "%v: %s", args, res.stderr)
+			assert.Contains(t, res.stderr, "usage:", args)
+		}
+	})
+}
+
+func TestRun_Config(t *testing.T) {
+	srv, auth := newTestServer(t)
+
+	t.Run("成功：設定ファイルの URL・トークン・出力形式を使うこと", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		require.NoError(t, os.WriteFile(path, []byte("base_url: "+srv.URL+"/\ntoken: secret\noutput: plain\n"), 0o600))
+
+		res := runCLI(t, map[string]string{"TODO_CONFIG": path}, "add", "設定ファイル")
+		assert.Equal(t, 0, res.code, res.stderr)
+		assert.Contains(t, res.stdout, "\topen\tmedium\t-\t設定ファイル\n")
+		assert.Equal(t, "Bearer secret", auth())
+
+		// 環境変数と引数は設定ファイルより優先する
+		res = runCLI(t, map[string]string{"TODO_CONFIG": path, "TODO_TOKEN": "from-env"}, "ls", "-o", "json")
+		assert.Equal(t, 0, res.code, res.stderr)
+		assert.True(t, strings.HasPrefix(res.stdout, "["))
+		assert.Equal(t, "Bearer from-env", auth())
+
+		res = runCLI(t, map[string]string{"TODO_CONFIG": path}, "-token", "from-flag", "ls")
+		assert.Equal(t, 0, res.code, res.stderr)
+		assert.Equal(t, "Bearer from-flag", auth())
+	})
+
+	t.Run("失敗：設定ファイルの誤りは終了コード 2 になること", func(t *testing.T) {
+		dir := t.TempDir()
+		unknown := filepath.Join(dir, "unknown.yaml")
+		require.NoError(t, os.WriteFile(unknown, []byte("base-url: http://localhost\n"), 0o600))
+		invalidURL := filepath.Join(dir, "invalid.yaml")
+		require.NoError(t, os.WriteFile(invalidURL, []byte("base_url: localhost:8080\n"), 0o600))
+
+		for _, args := range [][]string{
+			{"-config", filepath.Join(dir, "missing.yaml"), "ls"},
+			{"-config", unknown, "ls"},
+			{"-config", invalidURL, "ls"},
+		} {
+			res := runCLI(t, map[string]string{}, args...)
+			assert.Equal(t, 2, res.code, "%v: %s", args, res.stderr)
+		}
+	})
+}
+
+func TestParseEdited(t *testing.T) {
+	due := time.Date(2026, 11, 1, 0, 0, 0, 0, time.Local)
+	todo := &domain.Todo{Title: "資料作成", Description: "1行目\n2行目", Priority: domain.PriorityHigh, DueDate: &due}
+
+	t.Run("成功：編集用の形式から元の内容に戻せること", func(t *testing.T) {
+		req, err := parseEdited(strings.NewReader(formatForEdit(todo)))
+		assert.NoError(t, err)
+		assert.True(t, sameEdit(req, todo), "%+v", req)
+	})
+
+	t.Run("成功：期限を空にすると外すこと", func(t *testing.T) {
+		req, err := parseEdited(strings.NewReader("Title: 資料作成\nDue:\n"))
+		assert.NoError(t, err)
+		assert.Nil(t, req.DueDate)
+	})
+
+	t.Run("失敗：タイトルが空の場合は編集を取りやめること", func(t *testing.T) {
+		_, err := parseEdited(strings.NewReader("Title:\nPriority: high\n"))
+		assert.ErrorIs(t, err, errEditAborted)
+	})
+
+	t.Run("失敗：不明な項目や期限の形式の誤りはエラーになること", func(t *testing.T) {
+		for _, s := range []string{"Title: a\nOwner: me\n", "Title: a\nDue: tomorrow\n", "Title a\n"} {
+			_, err := parseEdited(strings.NewReader(s))
+			assert.Error(t, err, s)
+		}
+	})
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+	"todo_app_golang/internal/apiclient"
+	"todo_app_golang/internal/domain"
+)
+
+// 出力形式
+const (
+	formatTable = "table" // 見出し付きの表（既定）
+	formatJSON  = "json"  // API のレスポンスと同じ JSON
+	formatPlain = "plain" // タブ区切りで1行に1件（cut や awk で扱いやすい）
+)
+
+// printer は format に合わせてタスクを出力します
+type printer struct {
+	w      io.Writer
+	format string
+}
+
+// List は一覧を出力します
+func (p *printer) List(todos []*domain.Todo) error {
+	switch p.format {
+	case formatJSON:
+		if todos == nil {
+			todos = []*domain.Todo{}
+		}
+		return p.json(todos)
+	case formatPlain:
+		for _, t := range todos {
+			fmt.Fprintln(p.w, strings.Join(plainFields(t), "\t"))
+		}
+		return nil
+	default:
+		tw := tabwriter.NewWriter(p.w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "ID\tSTATUS\tPRIORITY\tDUE\tTITLE")
+		for _, t := range todos {
+			fmt.Fprintln(tw, strings.Join(plainFields(t), "\t"))
+		}
+		return tw.Flush()
+	}
+}
+
+// Todo は1件を出力します。table の場合は項目ごとに1行で詳細を表示します
+func (p *printer) Todo(t *domain.Todo) error {
+	switch p.format {
+	case formatJSON:
+		return p.json(t)
+	case formatPlain:
+		return p.List([]*domain.Todo{t})
+	default:
+		tw := tabwriter.NewWriter(p.w, 0, 0, 2, ' ', 0)
+		fmt.Fprintf(tw, "ID:\t%d\n", t.ID)
+		fmt.Fprintf(tw, "Title:\t%s\n", t.Title)
+		fmt.Fprintf(tw, "Status:\t%s\n", status(t))
+		fmt.Fprintf(tw, "Priority:\t%s\n", t.Priority)
+		fmt.Fprintf(tw, "Due:\t%s\n", apiclient.FormatDate(t.DueDate))
+		fmt.Fprintf(tw, "Created:\t%s\n", t.CreatedAt.Local().Format(time.DateTime))
+		if t.CompletedAt != nil {
+			fmt.Fprintf(tw, "Completed:\t%s\n", t.CompletedAt.Local().Format(time.DateTime))
+		}
+		if t.Description != "" {
+			fmt.Fprintf(tw, "Description:\t%s\n", strings.ReplaceAll(t.Description, "\n", "\n\t"))
+		}
+		return tw.Flush()
+	}
+}
+
+// Deleted は削除した ID を出力します
+func (p *printer) Deleted(ids []int) error {
+	switch p.format {
+	case formatJSON:
+		return p.json(map[string][]int{"deleted": ids})
+	case formatPlain:
+		for _, id := range ids {
+			fmt.Fprintln(p.w, id)
+		}
+		return nil
+	default:
+		for _, id := range ids {
+			fmt.Fprintf(p.w, "Deleted %d\n", id)
+		}
+		return nil
+	}
+}
+
+func (p *printer) json(v any) error {
+	enc := json.NewEncoder(p.w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// plainFields は一覧の1行分の項目です（ID / 状態 / 優先度 / 期限 / タイトル）
+func plainFields(t *domain.Todo) []string {
+	return []string{strconv.Itoa(t.ID), status(t), t.Priority, apiclient.FormatDate(t.DueDate), t.Title}
+}
+
+func status(t *domain.Todo) string {
+	if t.IsCompleted {
+		return "done"
+	}
+	return "open"
+}
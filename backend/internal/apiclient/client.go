@@ -0,0 +1,167 @@
+// Package apiclient は REST API のクライアントです。CLI (cmd/todo) と TUI (cmd/todo-tui) で共有します
+package apiclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"todo_app_golang/internal/domain"
+	"todo_app_golang/internal/interface/middleware"
+)
+
+// requestTimeout は1回のリクエストを待つ時間です
+const requestTimeout = 10 * time.Second
+
+// Client は REST API のクライアントです
+type Client struct {
+	baseURL string
+	token   string
+	http    *http.Client
+}
+
+func New(cfg Config) *Client {
+	return &Client{baseURL: cfg.BaseURL, token: cfg.Token, http: &http.Client{Timeout: requestTimeout}}
+}
+
+// APIError は API がエラーのステータスを返したことを表します
+type APIError struct {
+	Status  int
+	Message string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%s (HTTP %d)", e.Message, e.Status)
+}
+
+// TodoRequest は作成・更新のリクエストボディです（PUT は省略した項目も空の値で置き換える）
+type TodoRequest struct {
+	Title       string     `json:"title"`
+	Description string     `json:"description,omitempty"`
+	Priority    string     `json:"priority,omitempty"`
+	DueDate     *time.Time `json:"due_date"`
+}
+
+// Create はタスクを作成し、作成したタスクを返します
+func (c *Client) Create(ctx context.Context, req TodoRequest) (*domain.Todo, error) {
+	var todo domain.Todo
+	return &todo, c.do(ctx, http.MethodPost, "/todos", req, &todo)
+}
+
+// List は filter で絞り込んだ一覧を返します（作成日時の新しい順）
+func (c *Client) List(ctx context.Context, filter domain.TodoFilter) ([]*domain.Todo, error) {
+	query := url.Values{}
+	if filter.IsCompleted != nil {
+		query.Set("completed", strconv.FormatBool(*filter.IsCompleted))
+	}
+	if filter.Priority != "" {
+		query.Set("priority", filter.Priority)
+	}
+	if filter.Query != "" {
+		query.Set("q", filter.Query)
+	}
+	if filter.DueBefore != nil {
+		query.Set("due_before", filter.DueBefore.Format(time.RFC3339))
+	}
+	path := "/todos"
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	var todos []*domain.Todo
+	return todos, c.do(ctx, http.MethodGet, path, nil, &todos)
+}
+
+// Get は ID のタスクを返します
+func (c *Client) Get(ctx context.Context, id int) (*domain.Todo, error) {
+	var todo domain.Todo
+	return &todo, c.do(ctx, http.MethodGet, todoPath(id), nil, &todo)
+}
+
+// Update はタスクの内容を req で置き換えます（完了状態は変えない）
+func (c *Client) Update(ctx context.Context, id int, req TodoRequest) (*domain.Todo, error) {
+	var todo domain.Todo
+	return &todo, c.do(ctx, http.MethodPut, todoPath(id), req, &todo)
+}
+
+// SetCompleted は完了状態を変更します
+func (c *Client) SetCompleted(ctx context.Context, id int, completed bool) error {
+	return c.do(ctx, http.MethodPatch, todoPath(id), map[string]bool{"is_completed": completed}, nil)
+}
+
+// Delete はタスクを削除します
+func (c *Client) Delete(ctx context.Context, id int) error {
+	return c.do(ctx, http.MethodDelete, todoPath(id), nil, nil)
+}
+
+func todoPath(id int) string {
+	return "/todos/" + strconv.Itoa(id)
+}
+
+// do はリクエストを送り、成功した場合はレスポンスの JSON を out に読み込みます（out が nil の場合は読まない）
+func (c *Client) do(ctx context.Context, method, path string, body, out any) error {
+	var reqBody io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reqBody)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "todo-cli")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	res, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode >= 300 {
+		return readError(res)
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(res.Body).Decode(out); err != nil {
+		return fmt.Errorf("%s %s: invalid response: %w", method, path, err)
+	}
+	return nil
+}
+
+// readError はエラーのレスポンスから表示するメッセージを取り出します（problem の場合は detail、それ以外は本文）
+func readError(res *http.Response) error {
+	b, _ := io.ReadAll(io.LimitReader(res.Body, 4096))
+	msg := strings.TrimSpace(string(b))
+
+	if mediaType, _, _ := mime.ParseMediaType(res.Header.Get("Content-Type")); mediaType == "application/problem+json" {
+		var p middleware.Problem
+		if json.Unmarshal(b, &p) == nil {
+			msg = p.Detail
+			if msg == "" {
+				msg = p.Title
+			}
+		}
+	}
+	if msg == "" {
+		msg = http.StatusText(res.StatusCode)
+	}
+	return &APIError{Status: res.StatusCode, Message: msg}
+}
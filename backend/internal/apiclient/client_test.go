@@ -0,0 +1,72 @@
+package apiclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"todo_app_golang/internal/domain"
+	"todo_app_golang/internal/interface/middleware"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_Errors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/todos/1":
+			http.Error(w, "指定されたタスクが見つかりません", http.StatusNotFound)
+		case "/todos/2":
+			middleware.WriteProblem(w, http.StatusBadRequest, "path parameter id: invalid")
+		default:
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer srv.Close()
+	c := New(Config{BaseURL: srv.URL})
+
+	for _, tt := range []struct {
+		name string
+		id   int
+		want APIError
+	}{
+		{"失敗：テキストの本文をメッセージにすること", 1, APIError{Status: http.StatusNotFound, Message: "指定されたタスクが見つかりません"}},
+		{"失敗：problem の場合は detail をメッセージにすること", 2, APIError{Status: http.StatusBadRequest, Message: "path parameter id: invalid"}},
+		{"失敗：本文が無い場合はステータスの説明をメッセージにすること", 3, APIError{Status: http.StatusBadGateway, Message: "Bad Gateway"}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := c.Get(context.Background(), tt.id)
+			var apiErr *APIError
+			if assert.ErrorAs(t, err, &apiErr) {
+				assert.Equal(t, tt.want, *apiErr)
+			}
+		})
+	}
+}
+
+func TestClient_List(t *testing.T) {
+	t.Run("成功：絞り込みの条件をクエリパラメータで送ること", func(t *testing.T) {
+		var query string
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			query = r.URL.RawQuery
+			w.Write([]byte("[]"))
+		}))
+		defer srv.Close()
+
+		open := false
+		todos, err := New(Config{BaseURL: srv.URL}).List(context.Background(), domain.TodoFilter{IsCompleted: &open, Priority: domain.PriorityHigh, Query: "資料"})
+		assert.NoError(t, err)
+		assert.Empty(t, todos)
+		assert.Equal(t, "completed=false&priority=high&q=%E8%B3%87%E6%96%99", query)
+	})
+}
+
+func TestConfig_Validate(t *testing.T) {
+	cfg := Config{BaseURL: "https://todo.example.com/"}
+	assert.NoError(t, cfg.Validate())
+	assert.Equal(t, "https://todo.example.com", cfg.BaseURL)
+
+	cfg = Config{BaseURL: "todo.example.com"}
+	assert.Error(t, cfg.Validate())
+}
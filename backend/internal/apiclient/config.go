@@ -0,0 +1,88 @@
+package apiclient
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config はクライアント（todo / todo-tui）の設定です。既定値 < 設定ファイル < 環境変数 < コマンドライン引数 の順に優先されます
+type Config struct {
+	BaseURL string `yaml:"base_url"` // API の URL
+	Token   string `yaml:"token"`    // Authorization: Bearer で送るトークン（空の場合は送らない）
+	Output  string `yaml:"output"`   // todo の出力形式（table / json / plain）
+}
+
+func DefaultConfig() Config {
+	return Config{BaseURL: "http://localhost:8080", Output: "table"}
+}
+
+// DefaultConfigPath は設定ファイルの既定の場所です（Linux では ~/.config/todo/config.yaml）
+func DefaultConfigPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "todo", "config.yaml")
+}
+
+// LoadConfig は設定ファイルと環境変数を読み込みます。
+// path が空の場合は TODO_CONFIG、それも空の場合は既定の場所を読み、既定の場所にファイルが無い場合は既定値のままにします
+func LoadConfig(path string, getenv func(string) string) (Config, error) {
+	cfg := DefaultConfig()
+
+	explicit := path != ""
+	if !explicit {
+		path = getenv("TODO_CONFIG")
+		explicit = path != ""
+	}
+	if !explicit {
+		path = DefaultConfigPath()
+	}
+	if path != "" {
+		if err := readConfigFile(path, &cfg); err != nil && (explicit || !errors.Is(err, fs.ErrNotExist)) {
+			return cfg, err
+		}
+	}
+
+	for key, dst := range map[string]*string{
+		"TODO_API_URL": &cfg.BaseURL,
+		"TODO_TOKEN":   &cfg.Token,
+		"TODO_OUTPUT":  &cfg.Output,
+	} {
+		if v := getenv(key); v != "" {
+			*dst = v
+		}
+	}
+	return cfg, nil
+}
+
+func readConfigFile(path string, cfg *Config) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	dec := yaml.NewDecoder(f)
+	dec.KnownFields(true) // キーの書き間違いに気付けるようにする
+	if err := dec.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+// Validate は接続先を検証し、URL の末尾の / を取り除きます（Output は使う側で検証する）
+func (c *Config) Validate() error {
+	c.BaseURL = strings.TrimRight(c.BaseURL, "/")
+	if !strings.HasPrefix(c.BaseURL, "http://") && !strings.HasPrefix(c.BaseURL, "https://") {
+		return fmt.Errorf("base_url must start with http:// or https:// (got %q)", c.BaseURL)
+	}
+	return nil
+}
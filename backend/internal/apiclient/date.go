@@ -0,0 +1,29 @@
+package apiclient
+
+import (
+	"fmt"
+	"time"
+)
+
+// DateLayout は期限を表示・入力する形式です（期限は todo.txt の due: と同じくローカルの日付として扱う）
+const DateLayout = "2006-01-02"
+
+// FormatDate は期限をローカルの日付にします（期限が無い場合は "-"）
+func FormatDate(t *time.Time) string {
+	if t == nil {
+		return "-"
+	}
+	return t.Local().Format(DateLayout)
+}
+
+// ParseDate は YYYY-MM-DD（ローカルの日付の 0 時）または RFC 3339 の日時を解釈します
+func ParseDate(s string) (time.Time, error) {
+	if t, err := time.ParseInLocation(DateLayout, s, time.Local); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q: use YYYY-MM-DD", s)
+	}
+	return t, nil
+}
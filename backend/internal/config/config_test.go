@@ -0,0 +1,312 @@
+package config
+
+import (
+	"bytes"
+	"flag"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// envMap は os.LookupEnv の代わりに使うテスト用の環境変数です
+func envMap(m map[string]string) func(string) (string, bool) {
+	return func(key string) (string, bool) {
+		v, ok := m[key]
+		return v, ok
+	}
+}
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	t.Run("何も指定しない場合は既定値になること", func(t *testing.T) {
+		cfg, opts, err := Load(nil, envMap(nil), io.Discard)
+
+		assert.NoError(t, err)
+		assert.Equal(t, Default(), cfg)
+		assert.Empty(t, opts.File)
+	})
+
+	t.Run("既定値 < ファイル < 環境変数 < 引数 の順に優先されること", func(t *testing.T) {
+		path := writeFile(t, "config.yaml", `
+server:
+  addr: ":9000"
+log:
+  level: warn
+tracing:
+  exporter: stdout
+  service_name: from-file
+`)
+		env := envMap(map[string]string{
+			"LOG_LEVEL":         "ERROR",
+			"OTEL_SERVICE_NAME": "from-env",
+		})
+
+		cfg, opts, err := Load([]string{"-config", path, "-service-name", "from-flag"}, env, io.Discard)
+
+		assert.NoError(t, err)
+		assert.Equal(t, path, opts.File)
+		assert.Equal(t, ":9000", cfg.Server.Addr)             // ファイル
+		assert.Equal(t, "error", cfg.Log.Level)               // 環境変数（小文字に正規化）
+		assert.Equal(t, "stdout", cfg.Tracing.Exporter)       // ファイル
+		assert.Equal(t, "from-flag", cfg.Tracing.ServiceName) // 引数
+		assert.Equal(t, Default().Database, cfg.Database)     // 既定値
+		assert.Equal(t, Default().CORS, cfg.CORS)             // 既定値
+	})
+
+	t.Run("CONFIG_FILE で指定した TOML ファイルを読み込めること", func(t *testing.T) {
+		path := writeFile(t, "config.toml", `
+[cors]
+allowed_origins = ["https://todo.example.com", "http://localhost:5173"]
+
+[calendar]
+feed_token = "secret"
+`)
+
+		cfg, _, err := Load(nil, envMap(map[string]string{"CONFIG_FILE": path}), io.Discard)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"https://todo.example.com", "http://localhost:5173"}, cfg.CORS.AllowedOrigins)
+		assert.Equal(t, "secret", cfg.Calendar.FeedToken)
+	})
+
+	t.Run("カンマ区切りの CORS オリジンを分割すること", func(t *testing.T) {
+		env := envMap(map[string]string{"CORS_ALLOWED_ORIGINS": "https://a.example.com, https://b.example.com"})
+
+		cfg, _, err := Load(nil, env, io.Discard)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"https://a.example.com", "https://b.example.com"}, cfg.CORS.AllowedOrigins)
+	})
+
+	t.Run("タイムアウトを期間の文字列で指定できること", func(t *testing.T) {
+		path := writeFile(t, "config.yaml", "server:\n  read_timeout: 10s\n  idle_timeout: 1m\n")
+		env := envMap(map[string]string{"HTTP_WRITE_TIMEOUT": "45s"})
+
+		cfg, _, err := Load([]string{"-config", path, "-shutdown-timeout", "3s"}, env, io.Discard)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 10*time.Second, cfg.Server.ReadTimeout)
+		assert.Equal(t, time.Minute, cfg.Server.IdleTimeout)
+		assert.Equal(t, 45*time.Second, cfg.Server.WriteTimeout)
+		assert.Equal(t, 3*time.Second, cfg.Server.ShutdownTimeout)
+	})
+
+	t.Run("失敗：環境変数の期間が解釈できない場合はエラーになること", func(t *testing.T) {
+		_, _, err := Load(nil, envMap(map[string]string{"SHUTDOWN_TIMEOUT": "10"}), io.Discard)
+		assert.ErrorContains(t, err, "SHUTDOWN_TIMEOUT")
+
+		_, _, err = Load([]string{"-read-timeout", "0s"}, envMap(nil), io.Discard)
+		assert.ErrorContains(t, err, "server.read_timeout")
+	})
+
+	t.Run("空の環境変数でも設定されていれば既定値やファイルの値を上書きすること", func(t *testing.T) {
+		path := writeFile(t, "config.yaml", "calendar:\n  feed_token: secret\n")
+		env := envMap(map[string]string{"CONFIG_FILE": path, "GRPC_ADDR": "", "CALENDAR_FEED_TOKEN": ""})
+
+		cfg, _, err := Load(nil, env, io.Discard)
+
+		assert.NoError(t, err)
+		assert.Empty(t, cfg.GRPC.Addr)
+		assert.Empty(t, cfg.Calendar.FeedToken)
+
+		// 数値などは空の値を解釈できないため、黙って無視せずにエラーにする
+		_, _, err = Load(nil, envMap(map[string]string{"CACHE_SIZE": ""}), io.Discard)
+		assert.ErrorContains(t, err, "CACHE_SIZE")
+	})
+
+	t.Run("フラグの後に続く引数を Options.Args に返すこと", func(t *testing.T) {
+		env := envMap(map[string]string{"DB_MIGRATE_ON_START": "true"})
+
+		cfg, opts, err := Load([]string{"-log-level", "debug", "down", "2"}, env, io.Discard)
+
+		assert.NoError(t, err)
+		assert.True(t, cfg.Database.MigrateOnStart)
+		assert.Equal(t, []string{"down", "2"}, opts.Args)
+		assert.False(t, opts.Demo)
+	})
+
+	t.Run("--demo を指定できること", func(t *testing.T) {
+		_, opts, err := Load([]string{"--demo"}, envMap(nil), io.Discard)
+
+		assert.NoError(t, err)
+		assert.True(t, opts.Demo)
+	})
+
+	t.Run("sqlite の場合、接続文字列を省略するとファイルの既定値になること", func(t *testing.T) {
+		cfg, _, err := Load([]string{"-db-driver", "SQLite"}, envMap(nil), io.Discard)
+
+		assert.NoError(t, err)
+		assert.Equal(t, DriverSQLite, cfg.Database.Driver)
+		assert.Equal(t, "todo.db", cfg.Database.Source)
+		assert.Equal(t, "todo.db", cfg.Redacted().Database.Source)
+
+		_, _, err = Load([]string{"-db-driver", "mysql"}, envMap(nil), io.Discard)
+		assert.ErrorContains(t, err, "database.driver")
+	})
+
+	t.Run("キャッシュの設定を環境変数と引数で指定できること", func(t *testing.T) {
+		env := envMap(map[string]string{"CACHE_BACKEND": "Redis", "CACHE_LIST_TTL": "2s", "CACHE_REDIS_URL": "redis://:pass@cache:6379/1"})
+
+		cfg, _, err := Load([]string{"-cache-item-ttl", "30s"}, env, io.Discard)
+
+		assert.NoError(t, err)
+		assert.Equal(t, CacheRedis, cfg.Cache.Backend)
+		assert.Equal(t, 2*time.Second, cfg.Cache.ListTTL)
+		assert.Equal(t, 30*time.Second, cfg.Cache.ItemTTL)
+		assert.Equal(t, "redis://:xxxxx@cache:6379/1", cfg.Redacted().Cache.RedisURL)
+
+		_, _, err = Load([]string{"-cache-backend", "memory", "-cache-size", "0"}, envMap(nil), io.Discard)
+		assert.ErrorContains(t, err, "cache.size")
+		_, _, err = Load([]string{"-cache-backend", "redis", "-cache-redis-url", "localhost:6379"}, envMap(nil), io.Discard)
+		assert.ErrorContains(t, err, "cache.redis_url")
+	})
+
+	t.Run("レート制限の設定を環境変数と引数で指定できること", func(t *testing.T) {
+		env := envMap(map[string]string{"RATE_LIMIT_BACKEND": "Postgres", "RATE_LIMIT_WRITE_RATE": "10", "RATE_LIMIT_TRUST_PROXY": "true"})
+
+		cfg, _, err := Load([]string{"-rate-limit-write-burst", "5"}, env, io.Discard)
+
+		assert.NoError(t, err)
+		assert.Equal(t, RateLimitConfig{
+			Backend: RateLimitPostgres, ReadRate: 300, ReadBurst: 60, WriteRate: 10, WriteBurst: 5, TrustProxy: true,
+		}, cfg.RateLimit)
+
+		_, _, err = Load([]string{"-rate-limit-backend", "postgres", "-db-driver", "sqlite"}, envMap(nil), io.Discard)
+		assert.ErrorContains(t, err, "rate_limit.backend")
+		_, _, err = Load([]string{"-rate-limit-read-burst", "0"}, envMap(nil), io.Discard)
+		assert.ErrorContains(t, err, "rate_limit.read_burst")
+		// 無効にする場合は回数を検証しない
+		_, _, err = Load([]string{"-rate-limit-backend", "none", "-rate-limit-read-burst", "0"}, envMap(nil), io.Discard)
+		assert.NoError(t, err)
+	})
+
+	t.Run("gRPC の待ち受けアドレスを指定でき、空の場合は無効になること", func(t *testing.T) {
+		cfg, _, err := Load(nil, envMap(map[string]string{"GRPC_ADDR": ":50051"}), io.Discard)
+		assert.NoError(t, err)
+		assert.Equal(t, ":50051", cfg.GRPC.Addr)
+
+		cfg, _, err = Load([]string{"-grpc-addr", ""}, envMap(map[string]string{"GRPC_ADDR": ":50051"}), io.Discard)
+		assert.NoError(t, err)
+		assert.Empty(t, cfg.GRPC.Addr)
+
+		_, _, err = Load([]string{"-grpc-addr", "9090"}, envMap(nil), io.Discard)
+		assert.ErrorContains(t, err, "grpc.addr")
+		_, _, err = Load([]string{"-grpc-addr", ":8080"}, envMap(nil), io.Discard)
+		assert.ErrorContains(t, err, "must differ from server.addr")
+	})
+
+	t.Run("添付ファイルの保存先を S3 互換のストレージにできること", func(t *testing.T) {
+		path := writeFile(t, "config.yaml", `
+attachments:
+  store: S3
+  max_size: 1048576
+  s3:
+    endpoint: "http://localhost:9000"
+    bucket: todo
+`)
+		env := envMap(map[string]string{
+			"S3_ACCESS_KEY_ID":          "minio",
+			"S3_SECRET_ACCESS_KEY":      "minio-secret",
+			"ATTACHMENTS_ALLOWED_TYPES": "image/png, application/pdf",
+		})
+
+		cfg, _, err := Load([]string{"-config", path}, env, io.Discard)
+
+		assert.NoError(t, err)
+		assert.Equal(t, AttachmentsConfig{
+			Store:        BlobStoreS3,
+			Dir:          "attachments",
+			MaxSize:      1 << 20,
+			AllowedTypes: []string{"image/png", "application/pdf"},
+			S3: S3Config{
+				Endpoint: "http://localhost:9000", Region: "us-east-1", Bucket: "todo",
+				AccessKeyID: "minio", SecretAccessKey: "minio-secret", PathStyle: true,
+			},
+		}, cfg.Attachments)
+
+		_, _, err = Load([]string{"-attachments-store", "s3"}, envMap(nil), io.Discard)
+		assert.ErrorContains(t, err, "attachments.s3.endpoint")
+		assert.ErrorContains(t, err, "attachments.s3.bucket")
+		_, _, err = Load([]string{"-attachments-max-size", "0"}, envMap(map[string]string{"ATTACHMENTS_ALLOWED_TYPES": "image/*;q=1"}), io.Discard)
+		assert.ErrorContains(t, err, "attachments.max_size")
+		assert.ErrorContains(t, err, "attachments.allowed_types")
+	})
+
+	t.Run("認証プロキシのヘッダーを指定でき、空の場合は認証しないこと", func(t *testing.T) {
+		cfg, _, err := Load(nil, envMap(map[string]string{"AUTH_USER_HEADER": "X-Forwarded-User"}), io.Discard)
+		assert.NoError(t, err)
+		assert.Equal(t, "X-Forwarded-User", cfg.Auth.UserHeader)
+
+		cfg, _, err = Load([]string{"-auth-user-header", ""}, envMap(map[string]string{"AUTH_USER_HEADER": "X-Forwarded-User"}), io.Discard)
+		assert.NoError(t, err)
+		assert.Empty(t, cfg.Auth.UserHeader)
+
+		_, _, err = Load([]string{"-auth-user-header", "X-Forwarded-User:"}, envMap(nil), io.Discard)
+		assert.ErrorContains(t, err, "auth.user_header")
+	})
+
+	t.Run("失敗：設定ファイルに未知のキーがある場合はエラーになること", func(t *testing.T) {
+		yamlPath := writeFile(t, "config.yaml", "server:\n  adr: \":9000\"\n")
+		_, _, err := Load([]string{"-config", yamlPath}, envMap(nil), io.Discard)
+		assert.Error(t, err)
+
+		tomlPath := writeFile(t, "config.toml", "[server]\nadr = \":9000\"\n")
+		_, _, err = Load([]string{"-config", tomlPath}, envMap(nil), io.Discard)
+		assert.ErrorContains(t, err, "server.adr")
+	})
+
+	t.Run("失敗：不正な値はまとめてエラーになること", func(t *testing.T) {
+		args := []string{"-addr", "8080", "-log-level", "verbose", "-cors-origins", "localhost"}
+
+		_, _, err := Load(args, envMap(nil), io.Discard)
+
+		assert.ErrorContains(t, err, "server.addr")
+		assert.ErrorContains(t, err, "log.level")
+		assert.ErrorContains(t, err, "cors.allowed_origins")
+	})
+
+	t.Run("-h の場合は flag.ErrHelp を返すこと", func(t *testing.T) {
+		_, _, err := Load([]string{"-h"}, envMap(nil), io.Discard)
+		assert.ErrorIs(t, err, flag.ErrHelp)
+	})
+}
+
+func TestRedacted(t *testing.T) {
+	cfg := Default()
+	cfg.Database.Source = "postgresql://user:password@db:5432/todo?sslmode=disable"
+	cfg.Calendar.FeedToken = "feed-token-value"
+	cfg.Attachments.S3.SecretAccessKey = "s3-secret-value"
+
+	r := cfg.Redacted()
+	assert.Equal(t, "postgresql://user:xxxxx@db:5432/todo?sslmode=disable", r.Database.Source)
+	assert.Equal(t, "***", r.Calendar.FeedToken)
+	assert.Equal(t, "***", r.Attachments.S3.SecretAccessKey)
+	// 元の設定は変更されない
+	assert.Equal(t, "feed-token-value", cfg.Calendar.FeedToken)
+
+	t.Run("URL 形式でない接続文字列は全体を伏せること", func(t *testing.T) {
+		cfg.Database.Source = "host=db user=user password=password"
+		assert.Equal(t, "***", cfg.Redacted().Database.Source)
+	})
+
+	t.Run("YAML やログの出力にも秘匿情報が含まれないこと", func(t *testing.T) {
+		var buf bytes.Buffer
+		assert.NoError(t, cfg.WriteYAML(&buf))
+		slog.New(slog.NewJSONHandler(&buf, nil)).Info("config", slog.Any("config", cfg))
+
+		assert.NotContains(t, buf.String(), "password=password")
+		assert.NotContains(t, buf.String(), "feed-token-value")
+		assert.NotContains(t, buf.String(), "s3-secret-value")
+	})
+}
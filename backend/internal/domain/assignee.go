@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	ErrTodoForbidden   = errors.New("このタスクを操作する権限がありません")
+	ErrUnauthenticated = errors.New("ログインが必要です")
+	ErrUserIDEmpty     = errors.New("ユーザーを指定してください")
+)
+
+type userIDKey struct{}
+
+// WithUserID は認証済みのユーザーを Context に格納します。
+// ユースケースは格納されたユーザーでタスクの権限を確認します（格納されていない場合は確認しない）
+func WithUserID(ctx context.Context, userID string) context.Context {
+	return context.WithValue(ctx, userIDKey{}, userID)
+}
+
+// UserIDFromContext は WithUserID で格納したユーザーを返します
+func UserIDFromContext(ctx context.Context) (string, bool) {
+	userID, ok := ctx.Value(userIDKey{}).(string)
+	return userID, ok && userID != ""
+}
+
+// TodoAction はタスクに対する操作の種類です（権限の確認に使う）
+type TodoAction int
+
+const (
+	TodoActionEdit         TodoAction = iota // タイトル・詳細説明・優先度・期限の変更
+	TodoActionUpdateStatus                   // 完了状態の変更
+	TodoActionDelete                         // 削除
+	TodoActionAssign                         // 担当者・ほかのユーザーのウォッチの変更
+)
+
+// Permits は user が todo に対して action を行えるかを返します。
+//   - 所有者のいないタスク（認証せずに作成したもの）は誰でも操作できる
+//   - 所有者はすべての操作ができる
+//   - 担当者は完了状態の変更だけができる（編集や削除はできない）
+func (t *Todo) Permits(user string, action TodoAction) bool {
+	if t.OwnerID == nil || *t.OwnerID == user {
+		return true
+	}
+	return action == TodoActionUpdateStatus && t.IsAssignedTo(user)
+}
+
+// IsAssignedTo は user が担当者かを返します
+func (t *Todo) IsAssignedTo(user string) bool {
+	return t.AssigneeID != nil && *t.AssigneeID == user
+}
@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+)
+
+// Attachment はタスクに添付したファイルの情報です。
+// 中身は BlobStore に SHA-256 をキーにして保存し、同じ内容のファイルは（別のタスクでも）1つだけ保存します
+type Attachment struct {
+	ID          int       `json:"id"`
+	TodoID      int       `json:"todo_id"`
+	Filename    string    `json:"filename"`
+	ContentType string    `json:"content_type"` // 内容から判定した種類（クライアントが送った Content-Type は使わない）
+	Size        int64     `json:"size"`
+	SHA256      string    `json:"sha256"` // 中身の SHA-256（16進数の小文字）
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// AttachmentRepository は添付ファイルの情報のデータ操作に関するインターフェースです。
+// タスクを削除すると、そのタスクの添付ファイルの情報も削除されます（中身は BlobStore に残る）
+type AttachmentRepository interface {
+	// Create は attachment を保存して ID を採番します
+	Create(ctx context.Context, attachment *Attachment) error
+	// ListByTodo はタスクの添付ファイルを古い順に返します
+	ListByTodo(ctx context.Context, todoID int) ([]*Attachment, error)
+	// GetByID は添付ファイルの情報を返します。見つからない場合は ErrAttachmentNotFound を返します
+	GetByID(ctx context.Context, id int) (*Attachment, error)
+	// Delete は添付ファイルの情報を削除します（存在しない ID を指定してもエラーにしない）
+	Delete(ctx context.Context, id int) error
+	// CountBySHA256 は同じ中身を参照している添付ファイルの数を返します（中身を削除してよいかの判断に使う）
+	CountBySHA256(ctx context.Context, sha256 string) (int, error)
+}
+
+// BlobStore は添付ファイルの中身の保存先です。キーは中身の SHA-256（16進数の小文字）です
+type BlobStore interface {
+	// Put は r から size バイトを読んで key に保存します。既にある場合は上書きします
+	Put(ctx context.Context, key string, r io.Reader, size int64) error
+	// Open は key の中身を返します。範囲指定で読めるよう Seek できます。無い場合は ErrBlobNotFound を返します
+	Open(ctx context.Context, key string) (io.ReadSeekCloser, error)
+	// Exists は key が保存されているかを返します
+	Exists(ctx context.Context, key string) (bool, error)
+	// Delete は key を削除します（無い場合もエラーにしない）
+	Delete(ctx context.Context, key string) error
+}
+
+var (
+	ErrAttachmentNotFound       = errors.New("指定された添付ファイルが見つかりません")
+	ErrAttachmentTooLarge       = errors.New("添付ファイルが大きすぎます")
+	ErrAttachmentEmpty          = errors.New("添付ファイルが空です")
+	ErrAttachmentTypeNotAllowed = errors.New("この種類のファイルは添付できません")
+	ErrBlobNotFound             = errors.New("blob not found")
+)
@@ -0,0 +1,82 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Comment はタスクに付けるコメントです。本文は Markdown で、HTML への変換（とサニタイズ）は表示する側で行います
+type Comment struct {
+	ID        int               `json:"id"`
+	TodoID    int               `json:"todo_id"`
+	Author    string            `json:"author"`
+	Body      string            `json:"body"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"` // 編集していない場合は CreatedAt と同じ
+	History   []CommentRevision `json:"history"`    // 編集前の本文（古い順）。編集していない場合は空
+}
+
+// CommentRevision は編集で置き換えられる前の本文です
+type CommentRevision struct {
+	Body     string    `json:"body"`
+	EditedAt time.Time `json:"edited_at"` // この本文が置き換えられた日時
+}
+
+// CommentRepository はコメントのデータ操作に関するインターフェースです。
+// タスクを削除すると、そのタスクのコメントと編集履歴も削除されます
+type CommentRepository interface {
+	// Create は comment を保存して ID を採番します（作成日時・更新日時は comment.CreatedAt）
+	Create(ctx context.Context, comment *Comment) error
+	// ListByTodo はタスクのコメントを古い順に、編集履歴を含めて返します
+	ListByTodo(ctx context.Context, todoID int) ([]*Comment, error)
+	// GetByID は編集履歴を含めてコメントを返します。見つからない場合は ErrCommentNotFound を返します
+	GetByID(ctx context.Context, id int) (*Comment, error)
+	// UpdateBody は本文を body に置き換え、元の本文を履歴に追加します。見つからない場合は ErrCommentNotFound を返します
+	UpdateBody(ctx context.Context, id int, body string, editedAt time.Time) error
+	// Delete はコメントと編集履歴を削除します（存在しない ID を指定してもエラーにしない）
+	Delete(ctx context.Context, id int) error
+}
+
+// MaxCommentLength はコメントの本文の最大文字数です
+const MaxCommentLength = 10000
+
+var (
+	ErrCommentNotFound    = errors.New("指定されたコメントが見つかりません")
+	ErrCommentBodyEmpty   = errors.New("コメントを入力してください")
+	ErrCommentTooLong     = errors.New("コメントは 10000 文字以内で入力してください")
+	ErrCommentAuthorEmpty = errors.New("投稿者を指定してください")
+	ErrCommentForbidden   = errors.New("ほかの人のコメントは編集・削除できません")
+)
+
+// ValidateCommentBody はコメントの本文がビジネスルールを満たすかを検証します
+func ValidateCommentBody(body string) error {
+	if strings.TrimSpace(body) == "" {
+		return ErrCommentBodyEmpty
+	}
+	if utf8.RuneCountInString(body) > MaxCommentLength {
+		return ErrCommentTooLong
+	}
+	return nil
+}
+
+// NewComment は新しいコメントを生成する際のビジネスルールを適用します
+func NewComment(todoID int, author, body string, now time.Time) (*Comment, error) {
+	author = strings.TrimSpace(author)
+	if author == "" {
+		return nil, ErrCommentAuthorEmpty
+	}
+	if err := ValidateCommentBody(body); err != nil {
+		return nil, err
+	}
+	return &Comment{
+		TodoID:    todoID,
+		Author:    author,
+		Body:      body,
+		CreatedAt: now,
+		UpdatedAt: now,
+		History:   []CommentRevision{},
+	}, nil
+}
@@ -0,0 +1,17 @@
+package domain
+
+// TodoEventType はタスクの変更の種類です
+type TodoEventType string
+
+const (
+	TodoCreated TodoEventType = "created"
+	TodoUpdated TodoEventType = "updated"
+	TodoDeleted TodoEventType = "deleted"
+)
+
+// TodoEvent はタスクの変更の通知です
+type TodoEvent struct {
+	Type TodoEventType
+	ID   int
+	Todo *Todo // 変更後のタスク（削除の場合は nil）
+}
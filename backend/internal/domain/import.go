@@ -0,0 +1,16 @@
+package domain
+
+// ImportResult は外部フォーマットからのインポート結果です
+type ImportResult struct {
+	Created int             `json:"created"`
+	Updated int             `json:"updated"`
+	Skipped []ImportSkipped `json:"skipped"`
+}
+
+// ImportSkipped は取り込まれなかった要素とその理由です
+type ImportSkipped struct {
+	Component string `json:"component,omitempty"` // 例: VTODO, VJOURNAL
+	UID       string `json:"uid,omitempty"`       // 判明している場合のみ
+	Line      int    `json:"line,omitempty"`      // 行単位のフォーマットの場合の行番号
+	Reason    string `json:"reason"`
+}
@@ -0,0 +1,142 @@
+package repositorytest
+
+import (
+	"context"
+	"testing"
+	"time"
+	"todo_app_golang/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// AttachmentRepositoryContract は newRepos が返すリポジトリに対して添付ファイルの共通のシナリオを実行します。
+// newRepos はシナリオごとに呼ばれ、同じデータストアを使う空のタスクと添付ファイルのリポジトリを返す必要があります
+func AttachmentRepositoryContract(t *testing.T, newRepos func(t *testing.T) (domain.TodoRepository, domain.AttachmentRepository)) {
+	base := time.Now().Truncate(time.Microsecond)
+	ctx := context.Background()
+	const (
+		shaA = "a665a45920422f9d417e4867efdc4fb8a04a1f3fff1fa07e998e86f7f7a27ae3"
+		shaB = "b3a8e0e1f9ab1bfe3a36f231f676f78bb30a519d2b21e6c530c0eee8ebb4a5d0"
+	)
+
+	createTodo := func(t *testing.T, todos domain.TodoRepository) int {
+		t.Helper()
+		todo := &domain.Todo{Title: "添付対象", Priority: "medium", CreatedAt: base}
+		if err := todos.Create(ctx, todo); err != nil {
+			t.Fatalf("Failed to create todo: %v", err)
+		}
+		return todo.ID
+	}
+	createAttachment := func(t *testing.T, attachments domain.AttachmentRepository, todoID int, filename, sha string, at time.Time) *domain.Attachment {
+		t.Helper()
+		a := &domain.Attachment{TodoID: todoID, Filename: filename, ContentType: "image/png", Size: 3, SHA256: sha, CreatedAt: at}
+		if err := attachments.Create(ctx, a); err != nil {
+			t.Fatalf("Failed to create attachment: %v", err)
+		}
+		return a
+	}
+
+	t.Run("作成すると ID が採番され、全てのフィールドを取得できること", func(t *testing.T) {
+		todos, attachments := newRepos(t)
+		todoID := createTodo(t, todos)
+		a := &domain.Attachment{TodoID: todoID, Filename: "スクリーンショット 1.png", ContentType: "application/pdf", Size: 12345, SHA256: shaA, CreatedAt: base}
+
+		assert.NoError(t, attachments.Create(ctx, a))
+		assert.NotZero(t, a.ID)
+
+		got, err := attachments.GetByID(ctx, a.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, a.ID, got.ID)
+		assert.Equal(t, todoID, got.TodoID)
+		assert.Equal(t, "スクリーンショット 1.png", got.Filename)
+		assert.Equal(t, "application/pdf", got.ContentType)
+		assert.Equal(t, int64(12345), got.Size)
+		assert.Equal(t, shaA, got.SHA256)
+		assert.True(t, base.Equal(got.CreatedAt))
+	})
+
+	t.Run("一覧はタスクごとに古い順で取得できること", func(t *testing.T) {
+		todos, attachments := newRepos(t)
+		todoID := createTodo(t, todos)
+		other := createTodo(t, todos)
+		createAttachment(t, attachments, todoID, "new.png", shaA, base)
+		createAttachment(t, attachments, todoID, "old.png", shaB, base.Add(-time.Minute))
+		createAttachment(t, attachments, other, "other.png", shaA, base)
+
+		got, err := attachments.ListByTodo(ctx, todoID)
+		assert.NoError(t, err)
+		if assert.Len(t, got, 2) {
+			assert.Equal(t, "old.png", got[0].Filename)
+			assert.Equal(t, "new.png", got[1].Filename)
+		}
+
+		got, err = attachments.ListByTodo(ctx, other+1000)
+		assert.NoError(t, err)
+		assert.NotNil(t, got, "JSON で null にならないよう空のスライスを返すこと")
+		assert.Empty(t, got)
+	})
+
+	t.Run("同じ中身を参照している数を数えられること", func(t *testing.T) {
+		todos, attachments := newRepos(t)
+		todoID := createTodo(t, todos)
+		other := createTodo(t, todos)
+		first := createAttachment(t, attachments, todoID, "a.png", shaA, base)
+		createAttachment(t, attachments, other, "a-copy.png", shaA, base)
+
+		n, err := attachments.CountBySHA256(ctx, shaA)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, n)
+
+		assert.NoError(t, attachments.Delete(ctx, first.ID))
+		n, err = attachments.CountBySHA256(ctx, shaA)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, n)
+
+		n, err = attachments.CountBySHA256(ctx, shaB)
+		assert.NoError(t, err)
+		assert.Zero(t, n)
+	})
+
+	t.Run("削除すると取得できなくなること", func(t *testing.T) {
+		todos, attachments := newRepos(t)
+		a := createAttachment(t, attachments, createTodo(t, todos), "消す.pdf", shaA, base)
+
+		assert.NoError(t, attachments.Delete(ctx, a.ID))
+
+		_, err := attachments.GetByID(ctx, a.ID)
+		assert.ErrorIs(t, err, domain.ErrAttachmentNotFound)
+		assert.NoError(t, attachments.Delete(ctx, a.ID), "存在しない場合もエラーにしないこと")
+	})
+
+	t.Run("タスクを削除すると添付ファイルの情報も削除されること", func(t *testing.T) {
+		todos, attachments := newRepos(t)
+		todoID := createTodo(t, todos)
+		kept := createTodo(t, todos)
+		a := createAttachment(t, attachments, todoID, "一緒に消える.png", shaA, base)
+		k := createAttachment(t, attachments, kept, "残る.png", shaB, base)
+
+		assert.NoError(t, todos.Delete(ctx, todoID))
+
+		_, err := attachments.GetByID(ctx, a.ID)
+		assert.ErrorIs(t, err, domain.ErrAttachmentNotFound)
+		n, err := attachments.CountBySHA256(ctx, shaA)
+		assert.NoError(t, err)
+		assert.Zero(t, n, "削除したタスクの添付ファイルは数えないこと")
+		_, err = attachments.GetByID(ctx, k.ID)
+		assert.NoError(t, err)
+	})
+
+	t.Run("存在しない場合は実装によらず同じエラーになること", func(t *testing.T) {
+		_, attachments := newRepos(t)
+
+		_, err := attachments.GetByID(ctx, 999999)
+		assert.ErrorIs(t, err, domain.ErrAttachmentNotFound)
+	})
+
+	t.Run("存在しないタスクには添付できないこと", func(t *testing.T) {
+		_, attachments := newRepos(t)
+
+		err := attachments.Create(ctx, &domain.Attachment{TodoID: 999999, Filename: "a.png", ContentType: "image/png", Size: 1, SHA256: shaA, CreatedAt: base})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,123 @@
+package repositorytest
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"strings"
+	"testing"
+	"todo_app_golang/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// BlobStoreContract は newStore が返す BlobStore に対して共通のシナリオを実行します。
+// newStore はシナリオごとに呼ばれ、空の BlobStore を返す必要があります
+func BlobStoreContract(t *testing.T, newStore func(t *testing.T) domain.BlobStore) {
+	ctx := context.Background()
+	keyOf := func(content string) string {
+		sum := sha256.Sum256([]byte(content))
+		return hex.EncodeToString(sum[:])
+	}
+	put := func(t *testing.T, store domain.BlobStore, content string) string {
+		t.Helper()
+		key := keyOf(content)
+		if err := store.Put(ctx, key, strings.NewReader(content), int64(len(content))); err != nil {
+			t.Fatalf("Failed to put blob: %v", err)
+		}
+		return key
+	}
+	const content = "%PDF-1.4 0123456789 abcdefghijklmnopqrstuvwxyz"
+
+	t.Run("保存した中身を読み込めること", func(t *testing.T) {
+		store := newStore(t)
+		key := put(t, store, content)
+
+		ok, err := store.Exists(ctx, key)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+
+		r, err := store.Open(ctx, key)
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer r.Close()
+		got, err := io.ReadAll(r)
+		assert.NoError(t, err)
+		assert.Equal(t, content, string(got))
+	})
+
+	t.Run("途中から読み込めること", func(t *testing.T) {
+		store := newStore(t)
+		key := put(t, store, content)
+
+		r, err := store.Open(ctx, key)
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer r.Close()
+
+		// http.ServeContent と同じ順序で Seek する
+		size, err := r.Seek(0, io.SeekEnd)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(len(content)), size)
+		_, err = r.Seek(9, io.SeekStart)
+		assert.NoError(t, err)
+		buf := make([]byte, 10)
+		_, err = io.ReadFull(r, buf)
+		assert.NoError(t, err)
+		assert.Equal(t, "0123456789", string(buf))
+
+		_, err = r.Seek(-3, io.SeekEnd)
+		assert.NoError(t, err)
+		rest, err := io.ReadAll(r)
+		assert.NoError(t, err)
+		assert.Equal(t, "xyz", string(rest))
+	})
+
+	t.Run("同じキーに保存し直しても中身が変わらないこと", func(t *testing.T) {
+		store := newStore(t)
+		key := put(t, store, content)
+		put(t, store, content)
+
+		r, err := store.Open(ctx, key)
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer r.Close()
+		got, _ := io.ReadAll(r)
+		assert.Equal(t, content, string(got))
+	})
+
+	t.Run("削除すると読み込めなくなること", func(t *testing.T) {
+		store := newStore(t)
+		key := put(t, store, content)
+
+		assert.NoError(t, store.Delete(ctx, key))
+
+		ok, err := store.Exists(ctx, key)
+		assert.NoError(t, err)
+		assert.False(t, ok)
+		_, err = store.Open(ctx, key)
+		assert.ErrorIs(t, err, domain.ErrBlobNotFound)
+		assert.NoError(t, store.Delete(ctx, key), "無い場合もエラーにしないこと")
+	})
+
+	t.Run("大きさが足りない場合は保存しないこと", func(t *testing.T) {
+		store := newStore(t)
+		key := keyOf(content)
+
+		err := store.Put(ctx, key, strings.NewReader("short"), int64(len(content)))
+		assert.Error(t, err)
+		ok, _ := store.Exists(ctx, key)
+		assert.False(t, ok)
+	})
+
+	t.Run("SHA-256 の形式ではないキーは受け付けないこと", func(t *testing.T) {
+		store := newStore(t)
+
+		err := store.Put(ctx, "../../etc/passwd", strings.NewReader("x"), 1)
+		assert.Error(t, err)
+	})
+}
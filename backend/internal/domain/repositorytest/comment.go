@@ -0,0 +1,165 @@
+package repositorytest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+	"todo_app_golang/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// CommentRepositoryContract は newRepos が返すリポジトリに対してコメントの共通のシナリオを実行します。
+// newRepos はシナリオごとに呼ばれ、同じデータストアを使う空のタスクとコメントのリポジトリを返す必要があります
+func CommentRepositoryContract(t *testing.T, newRepos func(t *testing.T) (domain.TodoRepository, domain.CommentRepository)) {
+	base := time.Now().Truncate(time.Microsecond)
+	ctx := context.Background()
+
+	createTodo := func(t *testing.T, todos domain.TodoRepository) int {
+		t.Helper()
+		todo := &domain.Todo{Title: "コメント対象", Priority: "medium", CreatedAt: base}
+		if err := todos.Create(ctx, todo); err != nil {
+			t.Fatalf("Failed to create todo: %v", err)
+		}
+		return todo.ID
+	}
+	createComment := func(t *testing.T, comments domain.CommentRepository, todoID int, body string, at time.Time) *domain.Comment {
+		t.Helper()
+		c := &domain.Comment{TodoID: todoID, Author: "alice", Body: body, CreatedAt: at}
+		if err := comments.Create(ctx, c); err != nil {
+			t.Fatalf("Failed to create comment: %v", err)
+		}
+		return c
+	}
+
+	t.Run("作成すると ID が採番され、全てのフィールドを取得できること", func(t *testing.T) {
+		todos, comments := newRepos(t)
+		todoID := createTodo(t, todos)
+		c := &domain.Comment{TodoID: todoID, Author: "alice", Body: "**太字** と `code`\n\n- 箇条書き", CreatedAt: base}
+
+		assert.NoError(t, comments.Create(ctx, c))
+		assert.NotZero(t, c.ID)
+
+		got, err := comments.GetByID(ctx, c.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, c.ID, got.ID)
+		assert.Equal(t, todoID, got.TodoID)
+		assert.Equal(t, "alice", got.Author)
+		assert.Equal(t, "**太字** と `code`\n\n- 箇条書き", got.Body)
+		assert.True(t, base.Equal(got.CreatedAt))
+		assert.True(t, base.Equal(got.UpdatedAt))
+		assert.Empty(t, got.History)
+		assert.NotNil(t, got.History, "JSON で null にならないよう空のスライスを返すこと")
+	})
+
+	t.Run("一覧はタスクごとに古い順で取得できること", func(t *testing.T) {
+		todos, comments := newRepos(t)
+		todoID := createTodo(t, todos)
+		other := createTodo(t, todos)
+		for i := range 3 {
+			// 作成日時が逆順でも古い順に並ぶこと
+			createComment(t, comments, todoID, fmt.Sprintf("コメント %d", i), base.Add(-time.Duration(i)*time.Minute))
+		}
+		createComment(t, comments, other, "別のタスク", base)
+
+		got, err := comments.ListByTodo(ctx, todoID)
+		assert.NoError(t, err)
+		if assert.Len(t, got, 3) {
+			assert.Equal(t, "コメント 2", got[0].Body)
+			assert.Equal(t, "コメント 1", got[1].Body)
+			assert.Equal(t, "コメント 0", got[2].Body)
+		}
+
+		got, err = comments.ListByTodo(ctx, other+1000)
+		assert.NoError(t, err)
+		assert.Empty(t, got)
+	})
+
+	t.Run("本文を更新すると元の本文が履歴に残ること", func(t *testing.T) {
+		todos, comments := newRepos(t)
+		c := createComment(t, comments, createTodo(t, todos), "v1", base)
+		first, second := base.Add(time.Minute), base.Add(2*time.Minute)
+
+		assert.NoError(t, comments.UpdateBody(ctx, c.ID, "v2", first))
+		assert.NoError(t, comments.UpdateBody(ctx, c.ID, "v3", second))
+
+		got, err := comments.GetByID(ctx, c.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, "v3", got.Body)
+		assert.True(t, base.Equal(got.CreatedAt))
+		assert.True(t, second.Equal(got.UpdatedAt))
+		if assert.Len(t, got.History, 2) {
+			assert.Equal(t, "v1", got.History[0].Body)
+			assert.True(t, first.Equal(got.History[0].EditedAt))
+			assert.Equal(t, "v2", got.History[1].Body)
+			assert.True(t, second.Equal(got.History[1].EditedAt))
+		}
+
+		list, err := comments.ListByTodo(ctx, c.TodoID)
+		assert.NoError(t, err)
+		if assert.Len(t, list, 1) {
+			assert.Equal(t, got, list[0], "一覧にも編集履歴が含まれること")
+		}
+	})
+
+	t.Run("削除すると取得できなくなること", func(t *testing.T) {
+		todos, comments := newRepos(t)
+		c := createComment(t, comments, createTodo(t, todos), "消す", base)
+
+		assert.NoError(t, comments.UpdateBody(ctx, c.ID, "消す（編集）", base))
+		assert.NoError(t, comments.Delete(ctx, c.ID))
+
+		_, err := comments.GetByID(ctx, c.ID)
+		assert.ErrorIs(t, err, domain.ErrCommentNotFound)
+		assert.NoError(t, comments.Delete(ctx, c.ID), "存在しない場合もエラーにしないこと")
+	})
+
+	t.Run("タスクを削除するとコメントも削除されること", func(t *testing.T) {
+		todos, comments := newRepos(t)
+		todoID := createTodo(t, todos)
+		kept := createTodo(t, todos)
+		c := createComment(t, comments, todoID, "一緒に消える", base)
+		assert.NoError(t, comments.UpdateBody(ctx, c.ID, "編集済み", base))
+		k := createComment(t, comments, kept, "残る", base)
+
+		assert.NoError(t, todos.Delete(ctx, todoID))
+
+		_, err := comments.GetByID(ctx, c.ID)
+		assert.ErrorIs(t, err, domain.ErrCommentNotFound)
+		list, err := comments.ListByTodo(ctx, todoID)
+		assert.NoError(t, err)
+		assert.Empty(t, list)
+		_, err = comments.GetByID(ctx, k.ID)
+		assert.NoError(t, err)
+	})
+
+	t.Run("存在しない場合は実装によらず同じエラーになること", func(t *testing.T) {
+		_, comments := newRepos(t)
+
+		_, err := comments.GetByID(ctx, 999999)
+		assert.ErrorIs(t, err, domain.ErrCommentNotFound)
+		assert.ErrorIs(t, comments.UpdateBody(ctx, 999999, "body", base), domain.ErrCommentNotFound)
+	})
+
+	t.Run("存在しないタスクにはコメントできないこと", func(t *testing.T) {
+		_, comments := newRepos(t)
+
+		err := comments.Create(ctx, &domain.Comment{TodoID: 999999, Author: "alice", Body: "body", CreatedAt: base})
+		assert.Error(t, err)
+	})
+
+	t.Run("取得したコメントを書き換えても保存済みのデータに影響しないこと", func(t *testing.T) {
+		todos, comments := newRepos(t)
+		c := createComment(t, comments, createTodo(t, todos), "元の本文", base)
+		assert.NoError(t, comments.UpdateBody(ctx, c.ID, "編集後", base))
+
+		got, _ := comments.GetByID(ctx, c.ID)
+		got.Body = "取得後に変更"
+		got.History[0].Body = "履歴を変更"
+
+		got, _ = comments.GetByID(ctx, c.ID)
+		assert.Equal(t, "編集後", got.Body)
+		assert.Equal(t, "元の本文", got.History[0].Body)
+	})
+}
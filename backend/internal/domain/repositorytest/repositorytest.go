@@ -0,0 +1,364 @@
+// Package repositorytest は domain.TodoRepository・domain.CommentRepository・domain.AttachmentRepository・domain.BlobStore の実装が満たすべき振る舞いをまとめたテストです。
+// バックエンド（Postgres / SQLite / メモリ）ごとのテストから TodoRepositoryContract・CommentRepositoryContract・AttachmentRepositoryContract・BlobStoreContract を呼び出し、すべてが同じ結果になることを確かめます
+package repositorytest
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+	"todo_app_golang/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TodoRepositoryContract は newRepo が返すリポジトリに対して共通のシナリオを実行します。
+// newRepo はシナリオごとに呼ばれ、空のリポジトリを返す必要があります
+func TodoRepositoryContract(t *testing.T, newRepo func(t *testing.T) domain.TodoRepository) {
+	// DB によっては マイクロ秒までしか保存できないため、比較に使う日時はあらかじめ丸めておく
+	base := time.Now().Truncate(time.Microsecond)
+	ctx := context.Background()
+
+	t.Run("作成すると ID が採番され、全てのフィールドを取得できること", func(t *testing.T) {
+		repo := newRepo(t)
+		due := base.Add(24 * time.Hour)
+		uid := "contract@example.com"
+		todo := &domain.Todo{
+			Title:       "Detail Test",
+			Description: "Description here",
+			Priority:    "low",
+			DueDate:     &due,
+			CreatedAt:   base,
+			ICalUID:     &uid,
+		}
+
+		assert.NoError(t, repo.Create(ctx, todo))
+		assert.NotZero(t, todo.ID)
+
+		got, err := repo.GetByID(ctx, todo.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, todo.ID, got.ID)
+		assert.Equal(t, "Detail Test", got.Title)
+		assert.Equal(t, "Description here", got.Description)
+		assert.Equal(t, "low", got.Priority)
+		assert.False(t, got.IsCompleted)
+		// Timeの比較は .Equal を使用（タイムゾーンの差異を許容）
+		assert.True(t, due.Equal(*got.DueDate))
+		assert.True(t, base.Equal(got.CreatedAt))
+		assert.False(t, got.UpdatedAt.IsZero())
+		assert.Nil(t, got.CompletedAt)
+		assert.Equal(t, uid, *got.ICalUID)
+	})
+
+	t.Run("ID は作成するたびに大きくなること", func(t *testing.T) {
+		repo := newRepo(t)
+		var prev int
+		for i := range 3 {
+			todo := &domain.Todo{Title: fmt.Sprintf("Task %d", i), Priority: "medium", CreatedAt: base}
+			assert.NoError(t, repo.Create(ctx, todo))
+			assert.Greater(t, todo.ID, prev)
+			prev = todo.ID
+		}
+	})
+
+	t.Run("未設定の任意項目は空のまま取得できること", func(t *testing.T) {
+		repo := newRepo(t)
+		todo := &domain.Todo{Title: "最小限", Priority: "medium", CreatedAt: base}
+		assert.NoError(t, repo.Create(ctx, todo))
+
+		got, err := repo.GetByID(ctx, todo.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, "", got.Description)
+		assert.Nil(t, got.DueDate)
+		assert.Nil(t, got.CompletedAt)
+		assert.Nil(t, got.ICalUID)
+		assert.Nil(t, got.OwnerID)
+		assert.Nil(t, got.AssigneeID)
+		assert.NotNil(t, got.Watchers, "ウォッチャーがいない場合も空の配列で返すこと")
+		assert.Empty(t, got.Watchers)
+	})
+
+	t.Run("完了済みで作成すると完了日時が記録されること", func(t *testing.T) {
+		repo := newRepo(t)
+		done := base.Add(-time.Hour)
+
+		withTime := &domain.Todo{Title: "完了日時あり", Priority: "high", IsCompleted: true, CompletedAt: &done, CreatedAt: base}
+		assert.NoError(t, repo.Create(ctx, withTime))
+		withoutTime := &domain.Todo{Title: "完了日時なし", Priority: "high", IsCompleted: true, CreatedAt: base}
+		assert.NoError(t, repo.Create(ctx, withoutTime))
+
+		got, _ := repo.GetByID(ctx, withTime.ID)
+		assert.True(t, got.IsCompleted)
+		assert.True(t, done.Equal(*got.CompletedAt))
+		got, _ = repo.GetByID(ctx, withoutTime.ID)
+		assert.NotNil(t, got.CompletedAt)
+		assert.NotNil(t, withoutTime.CompletedAt, "作成した Todo にも完了日時が反映されること")
+
+		// 未完了で作成した場合は完了日時を指定しても記録しない
+		notDone := &domain.Todo{Title: "未完了", Priority: "high", CompletedAt: &done, CreatedAt: base}
+		assert.NoError(t, repo.Create(ctx, notDone))
+		got, _ = repo.GetByID(ctx, notDone.ID)
+		assert.Nil(t, got.CompletedAt)
+	})
+
+	t.Run("一覧は作成日時の新しい順に取得できること", func(t *testing.T) {
+		repo := newRepo(t)
+
+		todos, err := repo.FetchAll(ctx)
+		assert.NoError(t, err)
+		assert.Empty(t, todos)
+
+		for i, title := range []string{"Task 1", "Task 3", "Task 2"} {
+			created := base.Add(time.Duration([]int{1, 3, 2}[i]) * time.Minute)
+			assert.NoError(t, repo.Create(ctx, &domain.Todo{Title: title, Priority: "medium", CreatedAt: created}))
+		}
+
+		todos, err = repo.FetchAll(ctx)
+		assert.NoError(t, err)
+		assert.Len(t, todos, 3)
+		assert.Equal(t, "Task 3", todos[0].Title)
+		assert.Equal(t, "Task 2", todos[1].Title)
+		assert.Equal(t, "Task 1", todos[2].Title)
+	})
+
+	t.Run("完了状態を切り替えると完了日時が記録・クリアされること", func(t *testing.T) {
+		repo := newRepo(t)
+		todo := &domain.Todo{Title: "Update Test Task", Priority: "high", CreatedAt: base}
+		assert.NoError(t, repo.Create(ctx, todo))
+
+		assert.NoError(t, repo.UpdateStatus(ctx, todo.ID, true))
+		first, _ := repo.GetByID(ctx, todo.ID)
+		assert.True(t, first.IsCompleted)
+		assert.NotNil(t, first.CompletedAt)
+
+		// 完了済みのものを再度完了にしても完了日時は変わらない
+		assert.NoError(t, repo.UpdateStatus(ctx, todo.ID, true))
+		again, _ := repo.GetByID(ctx, todo.ID)
+		assert.True(t, first.CompletedAt.Equal(*again.CompletedAt))
+
+		// 未完了に戻すと完了日時はクリアされる
+		assert.NoError(t, repo.UpdateStatus(ctx, todo.ID, false))
+		got, _ := repo.GetByID(ctx, todo.ID)
+		assert.False(t, got.IsCompleted)
+		assert.Nil(t, got.CompletedAt)
+	})
+
+	t.Run("更新すると作成日時以外のフィールドが書き換わること", func(t *testing.T) {
+		repo := newRepo(t)
+		todo := &domain.Todo{Title: "更新前", Priority: "low", CreatedAt: base}
+		assert.NoError(t, repo.Create(ctx, todo))
+
+		due := base.Add(48 * time.Hour)
+		uid := "updated@example.com"
+		assert.NoError(t, repo.Update(ctx, &domain.Todo{
+			ID: todo.ID, Title: "更新後", Description: "詳細", Priority: "high", DueDate: &due, ICalUID: &uid,
+			IsCompleted: true, CreatedAt: base.Add(time.Hour),
+		}))
+
+		got, err := repo.GetByID(ctx, todo.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, "更新後", got.Title)
+		assert.Equal(t, "詳細", got.Description)
+		assert.Equal(t, "high", got.Priority)
+		assert.True(t, due.Equal(*got.DueDate))
+		assert.Equal(t, uid, *got.ICalUID)
+		assert.True(t, got.IsCompleted)
+		assert.NotNil(t, got.CompletedAt)
+		assert.True(t, base.Equal(got.CreatedAt))
+
+		// 完了日時を指定せずに更新しても、既存の完了日時は保たれる
+		completedAt := *got.CompletedAt
+		got.Title = "再更新"
+		got.CompletedAt = nil
+		assert.NoError(t, repo.Update(ctx, got))
+		got, _ = repo.GetByID(ctx, todo.ID)
+		assert.True(t, completedAt.Equal(*got.CompletedAt))
+
+		// 未完了にすると期限・UID と完了日時がクリアされる
+		assert.NoError(t, repo.Update(ctx, &domain.Todo{ID: todo.ID, Title: "未完了", Priority: "medium", CreatedAt: base}))
+		got, _ = repo.GetByID(ctx, todo.ID)
+		assert.False(t, got.IsCompleted)
+		assert.Nil(t, got.CompletedAt)
+		assert.Nil(t, got.DueDate)
+		assert.Nil(t, got.ICalUID)
+	})
+
+	t.Run("所有者と担当者を保存でき、担当者は設定・解除できること", func(t *testing.T) {
+		repo := newRepo(t)
+		owner, assignee := "alice", "bob"
+		todo := &domain.Todo{Title: "担当あり", Priority: "medium", CreatedAt: base, OwnerID: &owner, AssigneeID: &assignee}
+		assert.NoError(t, repo.Create(ctx, todo))
+
+		got, err := repo.GetByID(ctx, todo.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, "alice", *got.OwnerID)
+		assert.Equal(t, "bob", *got.AssigneeID)
+
+		carol := "carol"
+		assert.NoError(t, repo.SetAssignee(ctx, todo.ID, &carol))
+		got, _ = repo.GetByID(ctx, todo.ID)
+		assert.Equal(t, "carol", *got.AssigneeID)
+		assert.Equal(t, "alice", *got.OwnerID)
+
+		// 担当者で絞り込めるよう、一覧にも含まれる
+		todos, _ := repo.FetchAll(ctx)
+		assert.Equal(t, "carol", *todos[0].AssigneeID)
+
+		assert.NoError(t, repo.SetAssignee(ctx, todo.ID, nil))
+		got, _ = repo.GetByID(ctx, todo.ID)
+		assert.Nil(t, got.AssigneeID)
+	})
+
+	t.Run("ウォッチャーは重複せず ID 順に取得でき、追加・削除は冪等であること", func(t *testing.T) {
+		repo := newRepo(t)
+		todo := &domain.Todo{Title: "ウォッチ", Priority: "medium", CreatedAt: base}
+		assert.NoError(t, repo.Create(ctx, todo))
+		other := &domain.Todo{Title: "ほかのタスク", Priority: "medium", CreatedAt: base}
+		assert.NoError(t, repo.Create(ctx, other))
+
+		for _, user := range []string{"carol", "alice", "bob", "alice"} {
+			assert.NoError(t, repo.AddWatcher(ctx, todo.ID, user))
+		}
+		got, _ := repo.GetByID(ctx, todo.ID)
+		assert.Equal(t, []string{"alice", "bob", "carol"}, got.Watchers)
+		got, _ = repo.GetByID(ctx, other.ID)
+		assert.Empty(t, got.Watchers, "ほかのタスクには影響しないこと")
+
+		assert.NoError(t, repo.RemoveWatcher(ctx, todo.ID, "bob"))
+		assert.NoError(t, repo.RemoveWatcher(ctx, todo.ID, "bob"))
+		got, _ = repo.GetByID(ctx, todo.ID)
+		assert.Equal(t, []string{"alice", "carol"}, got.Watchers)
+
+		// 更新しても所有者・担当者・ウォッチャーは変わらない
+		dave := "dave"
+		assert.NoError(t, repo.SetAssignee(ctx, todo.ID, &dave))
+		assert.NoError(t, repo.Update(ctx, &domain.Todo{ID: todo.ID, Title: "更新後", Priority: "high", CreatedAt: base}))
+		got, _ = repo.GetByID(ctx, todo.ID)
+		assert.Equal(t, "更新後", got.Title)
+		assert.Equal(t, []string{"alice", "carol"}, got.Watchers)
+		assert.Equal(t, "dave", *got.AssigneeID)
+		assert.Nil(t, got.OwnerID)
+
+		// タスクを削除した後は追加できない
+		assert.NoError(t, repo.Delete(ctx, todo.ID))
+		assert.Equal(t, domain.ErrTodoNotFound, repo.AddWatcher(ctx, todo.ID, "alice"))
+	})
+
+	t.Run("削除すると取得できなくなること", func(t *testing.T) {
+		repo := newRepo(t)
+		todo := &domain.Todo{Title: "Test Delete", Priority: "high", CreatedAt: base}
+		assert.NoError(t, repo.Create(ctx, todo))
+
+		assert.NoError(t, repo.Delete(ctx, todo.ID))
+
+		_, err := repo.GetByID(ctx, todo.ID)
+		assert.Equal(t, domain.ErrTodoNotFound, err)
+		todos, _ := repo.FetchAll(ctx)
+		assert.Empty(t, todos)
+	})
+
+	t.Run("iCal UID を指定して取得できること", func(t *testing.T) {
+		repo := newRepo(t)
+		uid := "import-test@example.com"
+		todo := &domain.Todo{Title: "Imported", Priority: "medium", CreatedAt: base, ICalUID: &uid}
+		assert.NoError(t, repo.Create(ctx, todo))
+
+		found, err := repo.GetByICalUID(ctx, uid)
+		assert.NoError(t, err)
+		assert.Equal(t, todo.ID, found.ID)
+		assert.Equal(t, uid, *found.ICalUID)
+
+		// UID は重複して登録できない
+		assert.Error(t, repo.Create(ctx, &domain.Todo{Title: "Duplicate", Priority: "medium", CreatedAt: base, ICalUID: &uid}))
+	})
+
+	t.Run("ID を指定してまとめて取得できること", func(t *testing.T) {
+		repo := newRepo(t)
+		var ids []int
+		for _, title := range []string{"一件目", "二件目", "三件目"} {
+			todo := &domain.Todo{Title: title, Priority: "medium", CreatedAt: base}
+			assert.NoError(t, repo.Create(ctx, todo))
+			ids = append(ids, todo.ID)
+		}
+
+		// 見つからない ID は含めず、重複した ID も1件として返す
+		todos, err := repo.GetByIDs(ctx, []int{ids[2], ids[0], 99999, ids[0]})
+		assert.NoError(t, err)
+		var titles []string
+		for _, todo := range todos {
+			titles = append(titles, todo.Title)
+		}
+		assert.ElementsMatch(t, []string{"一件目", "三件目"}, titles)
+
+		todos, err = repo.GetByIDs(ctx, nil)
+		assert.NoError(t, err)
+		assert.Empty(t, todos)
+	})
+
+	t.Run("存在しない場合は実装によらず同じエラーになること", func(t *testing.T) {
+		repo := newRepo(t)
+
+		_, err := repo.GetByID(ctx, 99999)
+		assert.Equal(t, domain.ErrTodoNotFound, err)
+		_, err = repo.GetByICalUID(ctx, "missing@example.com")
+		assert.Equal(t, domain.ErrTodoNotFound, err)
+		assert.ErrorIs(t, repo.UpdateStatus(ctx, 99999, true), sql.ErrNoRows)
+		assert.ErrorIs(t, repo.Update(ctx, &domain.Todo{ID: 99999, Title: "なし", Priority: "medium", CreatedAt: base}), sql.ErrNoRows)
+		assert.Equal(t, domain.ErrTodoNotFound, repo.SetAssignee(ctx, 99999, nil))
+		assert.Equal(t, domain.ErrTodoNotFound, repo.AddWatcher(ctx, 99999, "alice"))
+		assert.Equal(t, domain.ErrTodoNotFound, repo.RemoveWatcher(ctx, 99999, "alice"))
+		// 削除は冪等で、存在しない ID でもエラーにしない
+		assert.NoError(t, repo.Delete(ctx, 99999))
+	})
+
+	t.Run("並行して書き込んでも ID が重複せず、すべて保存されること", func(t *testing.T) {
+		repo := newRepo(t)
+		const n = 20
+
+		var wg sync.WaitGroup
+		errs := make([]error, n)
+		for i := range n {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				todo := &domain.Todo{Title: fmt.Sprintf("並行 %d", i), Priority: "medium", CreatedAt: base}
+				if errs[i] = repo.Create(ctx, todo); errs[i] == nil {
+					errs[i] = repo.UpdateStatus(ctx, todo.ID, i%2 == 0)
+				}
+			}()
+		}
+		wg.Wait()
+
+		for _, err := range errs {
+			assert.NoError(t, err)
+		}
+		todos, err := repo.FetchAll(ctx)
+		assert.NoError(t, err)
+		ids := map[int]bool{}
+		completed := 0
+		for _, todo := range todos {
+			ids[todo.ID] = true
+			if todo.IsCompleted {
+				completed++
+			}
+		}
+		assert.Len(t, ids, n)
+		assert.Equal(t, n/2, completed)
+	})
+
+	t.Run("取得したタスクを書き換えても保存済みのデータに影響しないこと", func(t *testing.T) {
+		repo := newRepo(t)
+		todo := &domain.Todo{Title: "元のタイトル", Priority: "medium", CreatedAt: base}
+		assert.NoError(t, repo.Create(ctx, todo))
+
+		todo.Title = "作成後に変更"
+		got, _ := repo.GetByID(ctx, todo.ID)
+		got.Title = "取得後に変更"
+
+		got, _ = repo.GetByID(ctx, todo.ID)
+		assert.Equal(t, "元のタイトル", got.Title)
+	})
+
+}
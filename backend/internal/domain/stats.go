@@ -0,0 +1,86 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Stats はタスクの集計結果です
+type Stats struct {
+	Total           int            `json:"total"`
+	Completed       int            `json:"completed"`
+	Active          int            `json:"active"`
+	CompletionRate  float64        `json:"completion_rate"` // 0〜100 (%)
+	ByPriority      map[string]int `json:"by_priority"`
+	Overdue         int            `json:"overdue"`       // 未完了で期限を過ぎたもの
+	DueToday        int            `json:"due_today"`     // 未完了で今日中に期限を迎えるもの
+	DueThisWeek     int            `json:"due_this_week"` // 未完了で今週中に期限を迎えるもの（今日分を含む）
+	CompletedPerDay []DailyCount   `json:"completed_per_day"`
+}
+
+// DailyCount は1日あたりの件数です
+type DailyCount struct {
+	Date  string `json:"date"` // YYYY-MM-DD
+	Count int    `json:"count"`
+}
+
+// StatsQuery は集計条件です。日付の境界はすべて Location を基準に計算します
+type StatsQuery struct {
+	Now      time.Time
+	From     time.Time // 日別完了件数の開始日（この日を含む）
+	To       time.Time // 日別完了件数の終了日（この日を含む）
+	Location *time.Location
+}
+
+// LeadTime は作成から完了までにかかった時間の要約です（単位: 時間）
+type LeadTime struct {
+	Count       int     `json:"count"`
+	MedianHours float64 `json:"median_hours"`
+	P90Hours    float64 `json:"p90_hours"`
+}
+
+// LeadTimeStats は期間内に完了したタスクのリードタイムです。
+// プロジェクトは todo.txt と同じくタイトル中の +project 表記から判定します。
+type LeadTimeStats struct {
+	Overall    LeadTime            `json:"overall"`
+	ByPriority map[string]LeadTime `json:"by_priority"`
+	ByProject  map[string]LeadTime `json:"by_project"`
+}
+
+// WeeklyCount は週（月曜始まり）ごとの件数です
+type WeeklyCount struct {
+	WeekStart string `json:"week_start"` // YYYY-MM-DD
+	Count     int    `json:"count"`
+}
+
+// StatsRepository は集計をデータストア側で行うためのインターフェースです
+type StatsRepository interface {
+	Stats(ctx context.Context, q StatsQuery) (*Stats, error)
+	// LeadTimes は From〜To に完了したタスクのリードタイムを集計します
+	LeadTimes(ctx context.Context, q StatsQuery) (*LeadTimeStats, error)
+	// WeeklyThroughput は From〜To を含む各週の完了件数を返します（0件の週も含む）
+	WeeklyThroughput(ctx context.Context, q StatsQuery) ([]WeeklyCount, error)
+}
+
+// TodayRange は Now が属する日の [開始, 翌日開始) を返します
+func (q StatsQuery) TodayRange() (time.Time, time.Time) {
+	start := startOfDay(q.Now.In(q.Location))
+	return start, start.AddDate(0, 0, 1)
+}
+
+// WeekRange は Now が属する週（月曜始まり）の [開始, 翌週開始) を返します
+func (q StatsQuery) WeekRange() (time.Time, time.Time) {
+	today, _ := q.TodayRange()
+	offset := (int(today.Weekday()) + 6) % 7 // 月曜日を0とする
+	start := today.AddDate(0, 0, -offset)
+	return start, start.AddDate(0, 0, 7)
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// CompletedRange は From の開始から To の翌日開始までの [開始, 終了) を返します
+func (q StatsQuery) CompletedRange() (time.Time, time.Time) {
+	return startOfDay(q.From.In(q.Location)), startOfDay(q.To.In(q.Location)).AddDate(0, 0, 1)
+}
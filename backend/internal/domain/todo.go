@@ -3,6 +3,8 @@ package domain
 import (
 	"context"
 	"errors"
+	"slices"
+	"strings"
 	"time"
 )
 
@@ -15,7 +17,12 @@ type Todo struct {
 	Priority    string     `json:"priority" db:"priority"` // 'low', 'medium', 'high'
 	DueDate     *time.Time `json:"due_date" db:"due_date"` // 期限（未設定を許容するためポインタ）
 	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
-	UpdatedAt   time.Time  `json:"updated_at" db:"updated_at"` // 更新日時も持っておくと便利です
+	UpdatedAt   time.Time  `json:"updated_at" db:"updated_at"`       // 更新日時も持っておくと便利です
+	CompletedAt *time.Time `json:"completed_at" db:"completed_at"`   // 完了日時（未完了の場合は nil）
+	ICalUID     *string    `json:"ical_uid,omitempty" db:"ical_uid"` // iCalendar からインポートした場合の UID
+	OwnerID     *string    `json:"owner_id" db:"owner_id"`           // 作成したユーザー（認証せずに作成した場合は nil）
+	AssigneeID  *string    `json:"assignee_id" db:"assignee_id"`     // 担当者（未設定の場合は nil）
+	Watchers    []string   `json:"watchers" db:"-"`                  // ウォッチしているユーザー（ID 順。AddWatcher / RemoveWatcher でだけ変わる）
 }
 
 // TodoRepository はデータ操作に関するインターフェースです
@@ -25,13 +32,32 @@ type TodoRepository interface {
 	Delete(ctx context.Context, id int) error
 	UpdateStatus(ctx context.Context, id int, isCompleted bool) error
 	GetByID(ctx context.Context, id int) (*Todo, error)
+	// GetByIDs は ids のタスクをまとめて取得します。見つからない ID は結果に含めません（順序は不定）
+	GetByIDs(ctx context.Context, ids []int) ([]*Todo, error)
 	Update(ctx context.Context, todo *Todo) error
+	GetByICalUID(ctx context.Context, uid string) (*Todo, error)
+	// SetAssignee は担当者を設定します（nil の場合は解除）。見つからない場合は ErrTodoNotFound を返します
+	SetAssignee(ctx context.Context, id int, assigneeID *string) error
+	// AddWatcher はウォッチャーを追加します（追加済みの場合は何もしない）。見つからない場合は ErrTodoNotFound を返します
+	AddWatcher(ctx context.Context, id int, userID string) error
+	// RemoveWatcher はウォッチャーを外します（いない場合は何もしない）。見つからない場合は ErrTodoNotFound を返します
+	RemoveWatcher(ctx context.Context, id int, userID string) error
 }
 
 // カスタムエラーの定義
 var (
 	ErrTitleEmpty   = errors.New("タイトルを入力してください")
 	ErrTodoNotFound = errors.New("指定されたタスクが見つかりません")
+	ErrInvalidRange = errors.New("期間の指定が不正です")
+
+	ErrInvalidPriority = errors.New("優先度は low / medium / high のいずれかを指定してください")
+)
+
+// 優先度
+const (
+	PriorityLow    = "low"
+	PriorityMedium = "medium"
+	PriorityHigh   = "high"
 )
 
 // NewTodo は新しいTodoを生成する際のビジネスルールを適用します
@@ -42,6 +68,83 @@ func NewTodo(title string) (*Todo, error) {
 	return &Todo{
 		Title:       title,
 		IsCompleted: false,
+		Priority:    PriorityMedium, // DB の既定値と同じ
 		CreatedAt:   time.Now(),
+		Watchers:    []string{},
 	}, nil
 }
+
+// TodoInput は作成・更新時に利用者が指定できる項目です
+type TodoInput struct {
+	Title       string
+	Description string
+	Priority    string // 空の場合は medium
+	DueDate     *time.Time
+}
+
+// Validate は入力がビジネスルールを満たすかを検証し、優先度の既定値を補います
+func (in *TodoInput) Validate() error {
+	if in.Title == "" {
+		return ErrTitleEmpty
+	}
+	switch in.Priority {
+	case "":
+		in.Priority = PriorityMedium
+	case PriorityLow, PriorityMedium, PriorityHigh:
+	default:
+		return ErrInvalidPriority
+	}
+	return nil
+}
+
+// TodoFilter は一覧を絞り込む条件です。ゼロ値の項目では絞り込みません
+type TodoFilter struct {
+	IsCompleted *bool
+	Priority    string
+	Query       string     // タイトル・詳細説明の部分一致（大文字小文字を区別しない）
+	DueBefore   *time.Time // 期限がこの日時より前のもの（期限未設定のタスクは含まない）
+	AssigneeID  string     // 担当者
+}
+
+// Match は todo が条件をすべて満たすかを判定します
+func (f TodoFilter) Match(todo *Todo) bool {
+	if f.IsCompleted != nil && todo.IsCompleted != *f.IsCompleted {
+		return false
+	}
+	if f.Priority != "" && todo.Priority != f.Priority {
+		return false
+	}
+	if f.Query != "" {
+		q := strings.ToLower(f.Query)
+		if !strings.Contains(strings.ToLower(todo.Title), q) && !strings.Contains(strings.ToLower(todo.Description), q) {
+			return false
+		}
+	}
+	if f.DueBefore != nil && (todo.DueDate == nil || !todo.DueDate.Before(*f.DueBefore)) {
+		return false
+	}
+	if f.AssigneeID != "" && (todo.AssigneeID == nil || *todo.AssigneeID != f.AssigneeID) {
+		return false
+	}
+	return true
+}
+
+// Projects はタイトル中の +project 表記を重複を除いて返します（todo.txt と同じ規則で、統計のプロジェクト別集計とも同じ）
+func (t *Todo) Projects() []string {
+	return prefixedWords(t.Title, '+')
+}
+
+// Contexts はタイトル中の @context 表記を重複を除いて返します（todo.txt と同じ規則）
+func (t *Todo) Contexts() []string {
+	return prefixedWords(t.Title, '@')
+}
+
+func prefixedWords(s string, prefix byte) []string {
+	words := []string{}
+	for _, word := range strings.Fields(s) {
+		if len(word) > 1 && word[0] == prefix && !slices.Contains(words, word[1:]) {
+			words = append(words, word[1:])
+		}
+	}
+	return words
+}
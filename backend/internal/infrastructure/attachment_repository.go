@@ -0,0 +1,122 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"todo_app_golang/internal/domain"
+
+	semconv "go.opentelemetry.io/otel/semconv/v1.40.0"
+)
+
+type postgresAttachmentRepository struct {
+	db *sql.DB
+}
+
+// NewAttachmentRepository は Postgres 版の添付ファイルのリポジトリを生成します
+func NewAttachmentRepository(db *sql.DB) domain.AttachmentRepository {
+	return &postgresAttachmentRepository{db: db}
+}
+
+// attachmentColumns は SELECT するカラムの一覧です（scanAttachment の順序と合わせる）
+const attachmentColumns = `id, todo_id, filename, content_type, size, sha256, created_at`
+
+func scanAttachment(row rowScanner) (*domain.Attachment, error) {
+	a := &domain.Attachment{}
+	if err := row.Scan(&a.ID, &a.TodoID, &a.Filename, &a.ContentType, &a.Size, &a.SHA256, &a.CreatedAt); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// scanAttachments は rows をすべて読み込みます
+func scanAttachments(rows *sql.Rows) ([]*domain.Attachment, error) {
+	defer rows.Close()
+	attachments := []*domain.Attachment{}
+	for rows.Next() {
+		a, err := scanAttachment(rows)
+		if err != nil {
+			return nil, err
+		}
+		attachments = append(attachments, a)
+	}
+	return attachments, rows.Err()
+}
+
+func (r *postgresAttachmentRepository) Create(ctx context.Context, attachment *domain.Attachment) error {
+	query := `
+		INSERT INTO attachments (todo_id, filename, content_type, size, sha256, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id`
+
+	ctx, span := startQuerySpan(ctx, "attachments.Create", query)
+	defer span.End()
+
+	err := r.db.QueryRowContext(ctx, query,
+		attachment.TodoID, attachment.Filename, attachment.ContentType, attachment.Size, attachment.SHA256, attachment.CreatedAt,
+	).Scan(&attachment.ID)
+	if err == nil {
+		span.SetAttributes(rowsAffectedKey.Int64(1))
+	}
+	return logQueryError(ctx, "attachments.Create", err)
+}
+
+func (r *postgresAttachmentRepository) ListByTodo(ctx context.Context, todoID int) ([]*domain.Attachment, error) {
+	query := `SELECT ` + attachmentColumns + ` FROM attachments WHERE todo_id = $1 ORDER BY created_at, id`
+
+	ctx, span := startQuerySpan(ctx, "attachments.ListByTodo", query)
+	defer span.End()
+
+	rows, err := r.db.QueryContext(ctx, query, todoID)
+	if err != nil {
+		return nil, logQueryError(ctx, "attachments.ListByTodo", err)
+	}
+	attachments, err := scanAttachments(rows)
+	if err != nil {
+		return nil, logQueryError(ctx, "attachments.ListByTodo", err)
+	}
+	span.SetAttributes(semconv.DBResponseReturnedRows(len(attachments)))
+	return attachments, nil
+}
+
+func (r *postgresAttachmentRepository) GetByID(ctx context.Context, id int) (*domain.Attachment, error) {
+	query := `SELECT ` + attachmentColumns + ` FROM attachments WHERE id = $1`
+
+	ctx, span := startQuerySpan(ctx, "attachments.GetByID", query)
+	defer span.End()
+
+	a, err := scanAttachment(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrAttachmentNotFound
+		}
+		return nil, logQueryError(ctx, "attachments.GetByID", err)
+	}
+	return a, nil
+}
+
+func (r *postgresAttachmentRepository) Delete(ctx context.Context, id int) error {
+	query := `DELETE FROM attachments WHERE id = $1`
+
+	ctx, span := startQuerySpan(ctx, "attachments.Delete", query)
+	defer span.End()
+
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return logQueryError(ctx, "attachments.Delete", err)
+	}
+	if rows, err := result.RowsAffected(); err == nil {
+		span.SetAttributes(rowsAffectedKey.Int64(rows))
+	}
+	return nil
+}
+
+func (r *postgresAttachmentRepository) CountBySHA256(ctx context.Context, sha256 string) (int, error) {
+	query := `SELECT COUNT(*) FROM attachments WHERE sha256 = $1`
+
+	ctx, span := startQuerySpan(ctx, "attachments.CountBySHA256", query)
+	defer span.End()
+
+	var n int
+	err := r.db.QueryRowContext(ctx, query, sha256).Scan(&n)
+	return n, logQueryError(ctx, "attachments.CountBySHA256", err)
+}
@@ -0,0 +1,210 @@
+package infrastructure
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+	"todo_app_golang/internal/domain"
+	"todo_app_golang/internal/logging"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// キャッシュのキー
+const cacheKeyTodoList = "todos:all"
+
+func cacheKeyTodo(id int) string {
+	return "todos:" + strconv.Itoa(id)
+}
+
+// CacheStats はキャッシュのヒット・ミスの累計です
+type CacheStats struct {
+	Hits   uint64
+	Misses uint64
+}
+
+// CachedTodoRepository は一覧と ID 指定の取得結果をキャッシュする domain.TodoRepository のデコレーターです。
+//
+//   - 書き込み (Create / Update / UpdateStatus / Delete) が成功すると、影響する一覧と該当 ID のキャッシュだけを消す
+//   - 同じキーの取得が同時に来た場合は single-flight で元のリポジトリへの問い合わせを1回にまとめる
+//   - 取得中に書き込みがあった場合、取得した（古いかもしれない）結果はキャッシュしない
+//
+// 同じプロセス内の書き込みは直後の読み込みに必ず反映されます。
+// 複数インスタンスで Redis を共有する場合、他のインスタンスの取得と書き込みが競合したときに限り TTL の間だけ古い値が残ることがあります
+type CachedTodoRepository struct {
+	repo    domain.TodoRepository
+	store   CacheStore
+	listTTL time.Duration
+	itemTTL time.Duration
+
+	group singleflight.Group
+	// mu と generation で「取得を始めてから書き込みがあったか」を判定する。
+	// generation の更新とキャッシュの削除、generation の確認とキャッシュへの保存をそれぞれ不可分にする
+	mu         sync.RWMutex
+	generation uint64
+
+	hits   atomic.Uint64
+	misses atomic.Uint64
+}
+
+// NewCachedTodoRepository は repo の取得結果を store にキャッシュするリポジトリを生成します。
+// 一覧は listTTL、ID 指定の取得結果は itemTTL の間保持します
+func NewCachedTodoRepository(repo domain.TodoRepository, store CacheStore, listTTL, itemTTL time.Duration) *CachedTodoRepository {
+	return &CachedTodoRepository{repo: repo, store: store, listTTL: listTTL, itemTTL: itemTTL}
+}
+
+// Stats はキャッシュのヒット・ミスの累計を返します
+func (r *CachedTodoRepository) Stats() CacheStats {
+	return CacheStats{Hits: r.hits.Load(), Misses: r.misses.Load()}
+}
+
+func (r *CachedTodoRepository) FetchAll(ctx context.Context) ([]*domain.Todo, error) {
+	var todos []*domain.Todo
+	err := r.readThrough(ctx, cacheKeyTodoList, r.listTTL, &todos, func(ctx context.Context) (any, error) {
+		return r.repo.FetchAll(ctx)
+	})
+	return todos, err
+}
+
+func (r *CachedTodoRepository) GetByID(ctx context.Context, id int) (*domain.Todo, error) {
+	var todo *domain.Todo
+	// 見つからない場合などのエラーはキャッシュせず、そのまま返す
+	err := r.readThrough(ctx, cacheKeyTodo(id), r.itemTTL, &todo, func(ctx context.Context) (any, error) {
+		return r.repo.GetByID(ctx, id)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return todo, nil
+}
+
+// GetByIDs は GraphQL 等でまとめて取得するための問い合わせで、組み合わせごとにキャッシュしても再利用されにくいためキャッシュしません
+func (r *CachedTodoRepository) GetByIDs(ctx context.Context, ids []int) ([]*domain.Todo, error) {
+	return r.repo.GetByIDs(ctx, ids)
+}
+
+// GetByICalUID はインポート時にしか使わないためキャッシュしません
+func (r *CachedTodoRepository) GetByICalUID(ctx context.Context, uid string) (*domain.Todo, error) {
+	return r.repo.GetByICalUID(ctx, uid)
+}
+
+func (r *CachedTodoRepository) Create(ctx context.Context, todo *domain.Todo) error {
+	if err := r.repo.Create(ctx, todo); err != nil {
+		return err
+	}
+	r.invalidate(ctx, cacheKeyTodoList)
+	return nil
+}
+
+func (r *CachedTodoRepository) Update(ctx context.Context, todo *domain.Todo) error {
+	if err := r.repo.Update(ctx, todo); err != nil {
+		return err
+	}
+	r.invalidate(ctx, cacheKeyTodoList, cacheKeyTodo(todo.ID))
+	return nil
+}
+
+func (r *CachedTodoRepository) UpdateStatus(ctx context.Context, id int, isCompleted bool) error {
+	if err := r.repo.UpdateStatus(ctx, id, isCompleted); err != nil {
+		return err
+	}
+	r.invalidate(ctx, cacheKeyTodoList, cacheKeyTodo(id))
+	return nil
+}
+
+func (r *CachedTodoRepository) Delete(ctx context.Context, id int) error {
+	if err := r.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+	r.invalidate(ctx, cacheKeyTodoList, cacheKeyTodo(id))
+	return nil
+}
+
+func (r *CachedTodoRepository) SetAssignee(ctx context.Context, id int, assigneeID *string) error {
+	if err := r.repo.SetAssignee(ctx, id, assigneeID); err != nil {
+		return err
+	}
+	r.invalidate(ctx, cacheKeyTodoList, cacheKeyTodo(id))
+	return nil
+}
+
+func (r *CachedTodoRepository) AddWatcher(ctx context.Context, id int, userID string) error {
+	if err := r.repo.AddWatcher(ctx, id, userID); err != nil {
+		return err
+	}
+	r.invalidate(ctx, cacheKeyTodoList, cacheKeyTodo(id))
+	return nil
+}
+
+func (r *CachedTodoRepository) RemoveWatcher(ctx context.Context, id int, userID string) error {
+	if err := r.repo.RemoveWatcher(ctx, id, userID); err != nil {
+		return err
+	}
+	r.invalidate(ctx, cacheKeyTodoList, cacheKeyTodo(id))
+	return nil
+}
+
+// readThrough はキャッシュに key があれば dest に復元し、無ければ load の結果をキャッシュしてから dest に設定します。
+// キャッシュの障害時はエラーにせず、元のリポジトリの結果を返します
+func (r *CachedTodoRepository) readThrough(ctx context.Context, key string, ttl time.Duration, dest any, load func(ctx context.Context) (any, error)) error {
+	value, ok, err := r.store.Get(ctx, key)
+	if err != nil {
+		logging.FromContext(ctx).WarnContext(ctx, "cache get failed", slog.String("key", key), slog.Any("error", err))
+	}
+	if ok && json.Unmarshal(value, dest) == nil {
+		r.hits.Add(1)
+		return nil
+	}
+	r.misses.Add(1)
+
+	// 最初の呼び出し元がキャンセルしても、相乗りしている他の呼び出し元には結果を返せるようにする
+	shared, err, _ := r.group.Do(key, func() (any, error) {
+		loadCtx := context.WithoutCancel(ctx)
+
+		r.mu.RLock()
+		generation := r.generation
+		r.mu.RUnlock()
+
+		result, err := load(loadCtx)
+		if err != nil {
+			return nil, err
+		}
+		data, err := json.Marshal(result)
+		if err != nil {
+			return nil, err
+		}
+
+		r.mu.RLock()
+		defer r.mu.RUnlock()
+		if r.generation == generation {
+			if err := r.store.Set(loadCtx, key, data, ttl); err != nil {
+				logging.FromContext(ctx).WarnContext(ctx, "cache set failed", slog.String("key", key), slog.Any("error", err))
+			}
+		}
+		return data, nil
+	})
+	if err != nil {
+		return err
+	}
+	// 相乗りした呼び出し元同士で同じ値を共有しないよう、シリアライズした値からそれぞれ復元する
+	return json.Unmarshal(shared.([]byte), dest)
+}
+
+// invalidate は書き込みの影響を受けるキーを削除します。
+// 取得中の single-flight からも切り離し、以降の取得は書き込み後のデータを読み込みます
+func (r *CachedTodoRepository) invalidate(ctx context.Context, keys ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.generation++
+	for _, key := range keys {
+		r.group.Forget(key)
+	}
+	if err := r.store.Delete(context.WithoutCancel(ctx), keys...); err != nil {
+		logging.FromContext(ctx).ErrorContext(ctx, "cache invalidation failed", slog.Any("keys", keys), slog.Any("error", err))
+	}
+}
@@ -0,0 +1,208 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+	"todo_app_golang/internal/domain"
+	"todo_app_golang/internal/domain/repositorytest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLRUCache(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("上限を超えると最も長く使われていないものから追い出すこと", func(t *testing.T) {
+		cache := NewLRUCache(2)
+		cache.Set(ctx, "a", []byte("1"), time.Minute)
+		cache.Set(ctx, "b", []byte("2"), time.Minute)
+		cache.Get(ctx, "a") // a を最近使ったことにする
+		cache.Set(ctx, "c", []byte("3"), time.Minute)
+
+		_, ok, _ := cache.Get(ctx, "b")
+		assert.False(t, ok)
+		_, ok, _ = cache.Get(ctx, "a")
+		assert.True(t, ok)
+		assert.Equal(t, 2, cache.Len())
+	})
+
+	t.Run("TTL を過ぎた値はミスになること", func(t *testing.T) {
+		cache := NewLRUCache(10)
+		now := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+		cache.now = func() time.Time { return now }
+		cache.Set(ctx, "a", []byte("1"), time.Second)
+
+		_, ok, _ := cache.Get(ctx, "a")
+		assert.True(t, ok)
+
+		now = now.Add(time.Second)
+		_, ok, _ = cache.Get(ctx, "a")
+		assert.False(t, ok)
+		assert.Equal(t, 0, cache.Len())
+	})
+}
+
+func TestCachedTodoRepository(t *testing.T) {
+	t.Run("LRU", func(t *testing.T) {
+		repositorytest.TodoRepositoryContract(t, func(t *testing.T) domain.TodoRepository {
+			return NewCachedTodoRepository(NewMemoryTodoRepository(), NewLRUCache(100), time.Minute, time.Minute)
+		})
+	})
+	t.Run("Redis", func(t *testing.T) {
+		repositorytest.TodoRepositoryContract(t, func(t *testing.T) domain.TodoRepository {
+			_, url := startFakeRedis(t)
+			cache := NewRedisCache(url)
+			t.Cleanup(func() { cache.Close() })
+			return NewCachedTodoRepository(NewMemoryTodoRepository(), cache, time.Minute, time.Minute)
+		})
+	})
+}
+
+// countingRepository は FetchAll / GetByID の呼び出し回数を数えます。block を設定すると閉じられるまで FetchAll を待たせます
+type countingRepository struct {
+	domain.TodoRepository
+	fetchAll atomic.Int32
+	getByID  atomic.Int32
+	block    chan struct{}
+}
+
+func (r *countingRepository) FetchAll(ctx context.Context) ([]*domain.Todo, error) {
+	r.fetchAll.Add(1)
+	if r.block != nil {
+		<-r.block
+	}
+	return r.TodoRepository.FetchAll(ctx)
+}
+
+func (r *countingRepository) GetByID(ctx context.Context, id int) (*domain.Todo, error) {
+	r.getByID.Add(1)
+	return r.TodoRepository.GetByID(ctx, id)
+}
+
+func TestCachedTodoRepository_Caching(t *testing.T) {
+	ctx := context.Background()
+	setup := func(t *testing.T) (*CachedTodoRepository, *countingRepository, *domain.Todo, *domain.Todo) {
+		inner := &countingRepository{TodoRepository: NewMemoryTodoRepository()}
+		first := &domain.Todo{Title: "1件目", Priority: "medium", CreatedAt: time.Now()}
+		second := &domain.Todo{Title: "2件目", Priority: "medium", CreatedAt: time.Now()}
+		inner.Create(ctx, first)
+		inner.Create(ctx, second)
+		return NewCachedTodoRepository(inner, NewLRUCache(100), time.Minute, time.Minute), inner, first, second
+	}
+
+	t.Run("2回目以降の取得はキャッシュから返し、ヒット・ミスを数えること", func(t *testing.T) {
+		repo, inner, first, _ := setup(t)
+
+		for range 3 {
+			todos, err := repo.FetchAll(ctx)
+			assert.NoError(t, err)
+			assert.Len(t, todos, 2)
+			_, err = repo.GetByID(ctx, first.ID)
+			assert.NoError(t, err)
+		}
+
+		assert.Equal(t, int32(1), inner.fetchAll.Load())
+		assert.Equal(t, int32(1), inner.getByID.Load())
+		assert.Equal(t, CacheStats{Hits: 4, Misses: 2}, repo.Stats())
+	})
+
+	t.Run("書き込みで影響する一覧と該当 ID のキャッシュだけが消えること", func(t *testing.T) {
+		repo, inner, first, second := setup(t)
+		repo.FetchAll(ctx)
+		repo.GetByID(ctx, first.ID)
+		repo.GetByID(ctx, second.ID)
+
+		assert.NoError(t, repo.UpdateStatus(ctx, first.ID, true))
+
+		got, _ := repo.GetByID(ctx, first.ID)
+		assert.True(t, got.IsCompleted)
+		repo.GetByID(ctx, second.ID)
+		todos, _ := repo.FetchAll(ctx)
+		assert.Len(t, todos, 2)
+
+		assert.Equal(t, int32(2), inner.fetchAll.Load())
+		assert.Equal(t, int32(3), inner.getByID.Load(), "2件目は再取得しないこと")
+
+		// 作成は一覧だけ、削除は一覧と該当 ID を消す
+		assert.NoError(t, repo.Create(ctx, &domain.Todo{Title: "3件目", Priority: "low", CreatedAt: time.Now()}))
+		todos, _ = repo.FetchAll(ctx)
+		assert.Len(t, todos, 3)
+		assert.NoError(t, repo.Delete(ctx, second.ID))
+		_, err := repo.GetByID(ctx, second.ID)
+		assert.Equal(t, domain.ErrTodoNotFound, err)
+		todos, _ = repo.FetchAll(ctx)
+		assert.Len(t, todos, 2)
+	})
+
+	t.Run("同時に来た取得は元のリポジトリへの問い合わせを1回にまとめること", func(t *testing.T) {
+		repo, inner, _, _ := setup(t)
+		inner.block = make(chan struct{})
+
+		var wg sync.WaitGroup
+		for range 10 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				todos, err := repo.FetchAll(ctx)
+				assert.NoError(t, err)
+				assert.Len(t, todos, 2)
+			}()
+		}
+		// すべての呼び出しがミスしてから問い合わせを終わらせる
+		// （ミスを数えてから single-flight に入るまでの僅かな間を待つ）
+		assert.Eventually(t, func() bool { return repo.Stats().Misses == 10 }, time.Second, time.Millisecond)
+		time.Sleep(20 * time.Millisecond)
+		close(inner.block)
+		wg.Wait()
+
+		assert.Equal(t, int32(1), inner.fetchAll.Load())
+	})
+
+	t.Run("取得中に書き込みがあった場合、取得した結果はキャッシュしないこと", func(t *testing.T) {
+		repo, inner, first, _ := setup(t)
+		inner.block = make(chan struct{})
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			repo.FetchAll(ctx) // 書き込み前の一覧を読み込む
+		}()
+		assert.Eventually(t, func() bool { return inner.fetchAll.Load() == 1 }, time.Second, time.Millisecond)
+		assert.NoError(t, repo.Delete(ctx, first.ID))
+		close(inner.block)
+		<-done
+
+		todos, _ := repo.FetchAll(ctx)
+		assert.Len(t, todos, 1)
+		assert.Equal(t, int32(2), inner.fetchAll.Load())
+	})
+
+	t.Run("キャッシュに接続できない場合も元のリポジトリの結果を返すこと", func(t *testing.T) {
+		ln, _ := net.Listen("tcp", "127.0.0.1:0")
+		addr := ln.Addr().String()
+		ln.Close()
+		inner := &countingRepository{TodoRepository: NewMemoryTodoRepository()}
+		inner.Create(ctx, &domain.Todo{Title: "1件目", Priority: "medium", CreatedAt: time.Now()})
+		repo := NewCachedTodoRepository(inner, NewRedisCache("redis://"+addr), time.Minute, time.Minute)
+
+		todos, err := repo.FetchAll(ctx)
+		assert.NoError(t, err)
+		assert.Len(t, todos, 1)
+		assert.NoError(t, repo.UpdateStatus(ctx, todos[0].ID, true))
+	})
+
+	t.Run("元のリポジトリのエラーはキャッシュせずに返すこと", func(t *testing.T) {
+		repo, inner, _, _ := setup(t)
+
+		for range 2 {
+			_, err := repo.GetByID(ctx, 99999)
+			assert.True(t, errors.Is(err, domain.ErrTodoNotFound))
+		}
+		assert.Equal(t, int32(2), inner.getByID.Load())
+	})
+}
@@ -0,0 +1,173 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"time"
+	"todo_app_golang/internal/domain"
+
+	semconv "go.opentelemetry.io/otel/semconv/v1.40.0"
+)
+
+type postgresCommentRepository struct {
+	db *sql.DB
+}
+
+// NewCommentRepository は Postgres 版のコメントのリポジトリを生成します
+func NewCommentRepository(db *sql.DB) domain.CommentRepository {
+	return &postgresCommentRepository{db: db}
+}
+
+// commentColumns は SELECT するカラムの一覧です（scanComment の順序と合わせる）
+const commentColumns = `id, todo_id, author, body, created_at, updated_at`
+
+func scanComment(row rowScanner) (*domain.Comment, error) {
+	c := &domain.Comment{History: []domain.CommentRevision{}}
+	if err := row.Scan(&c.ID, &c.TodoID, &c.Author, &c.Body, &c.CreatedAt, &c.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// scanRevisions は comment_id, body, edited_at の順の行を読み、comments の該当するコメントの履歴に追加します
+func scanRevisions(rows *sql.Rows, comments map[int]*domain.Comment) error {
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			commentID int
+			rev       domain.CommentRevision
+		)
+		if err := rows.Scan(&commentID, &rev.Body, &rev.EditedAt); err != nil {
+			return err
+		}
+		if c, ok := comments[commentID]; ok {
+			c.History = append(c.History, rev)
+		}
+	}
+	return rows.Err()
+}
+
+func (r *postgresCommentRepository) Create(ctx context.Context, comment *domain.Comment) error {
+	query := `
+		INSERT INTO comments (todo_id, author, body, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $4)
+		RETURNING id`
+
+	ctx, span := startQuerySpan(ctx, "comments.Create", query)
+	defer span.End()
+
+	err := r.db.QueryRowContext(ctx, query, comment.TodoID, comment.Author, comment.Body, comment.CreatedAt).Scan(&comment.ID)
+	if err == nil {
+		span.SetAttributes(rowsAffectedKey.Int64(1))
+	}
+	return logQueryError(ctx, "comments.Create", err)
+}
+
+func (r *postgresCommentRepository) ListByTodo(ctx context.Context, todoID int) ([]*domain.Comment, error) {
+	query := `SELECT ` + commentColumns + ` FROM comments WHERE todo_id = $1 ORDER BY created_at, id`
+
+	ctx, span := startQuerySpan(ctx, "comments.ListByTodo", query)
+	defer span.End()
+
+	rows, err := r.db.QueryContext(ctx, query, todoID)
+	if err != nil {
+		return nil, logQueryError(ctx, "comments.ListByTodo", err)
+	}
+	defer rows.Close()
+
+	comments := []*domain.Comment{}
+	byID := map[int]*domain.Comment{}
+	for rows.Next() {
+		c, err := scanComment(rows)
+		if err != nil {
+			return nil, logQueryError(ctx, "comments.ListByTodo", err)
+		}
+		comments = append(comments, c)
+		byID[c.ID] = c
+	}
+	if err := rows.Err(); err != nil {
+		return nil, logQueryError(ctx, "comments.ListByTodo", err)
+	}
+	span.SetAttributes(semconv.DBResponseReturnedRows(len(comments)))
+	if len(comments) == 0 {
+		return comments, nil
+	}
+
+	revisionsQuery := `
+		SELECT r.comment_id, r.body, r.edited_at
+		FROM comment_revisions r JOIN comments c ON c.id = r.comment_id
+		WHERE c.todo_id = $1
+		ORDER BY r.id`
+	revisions, err := r.db.QueryContext(ctx, revisionsQuery, todoID)
+	if err != nil {
+		return nil, logQueryError(ctx, "comments.ListByTodo", err)
+	}
+	return comments, logQueryError(ctx, "comments.ListByTodo", scanRevisions(revisions, byID))
+}
+
+func (r *postgresCommentRepository) GetByID(ctx context.Context, id int) (*domain.Comment, error) {
+	query := `SELECT ` + commentColumns + ` FROM comments WHERE id = $1`
+
+	ctx, span := startQuerySpan(ctx, "comments.GetByID", query)
+	defer span.End()
+
+	c, err := scanComment(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrCommentNotFound
+		}
+		return nil, logQueryError(ctx, "comments.GetByID", err)
+	}
+
+	revisions, err := r.db.QueryContext(ctx,
+		`SELECT comment_id, body, edited_at FROM comment_revisions WHERE comment_id = $1 ORDER BY id`, id)
+	if err != nil {
+		return nil, logQueryError(ctx, "comments.GetByID", err)
+	}
+	if err := scanRevisions(revisions, map[int]*domain.Comment{id: c}); err != nil {
+		return nil, logQueryError(ctx, "comments.GetByID", err)
+	}
+	return c, nil
+}
+
+func (r *postgresCommentRepository) UpdateBody(ctx context.Context, id int, body string, editedAt time.Time) error {
+	// 元の本文を履歴に追加してから書き換える。CTE 内の文はすべて同じスナップショットを見るため、old は更新前の本文になる
+	query := `
+		WITH old AS (
+			SELECT id, body FROM comments WHERE id = $1 FOR UPDATE
+		), revision AS (
+			INSERT INTO comment_revisions (comment_id, body, edited_at) SELECT id, body, $3 FROM old
+		)
+		UPDATE comments SET body = $2, updated_at = $3 WHERE id = (SELECT id FROM old)`
+
+	ctx, span := startQuerySpan(ctx, "comments.UpdateBody", query)
+	defer span.End()
+
+	result, err := r.db.ExecContext(ctx, query, id, body, editedAt)
+	if err != nil {
+		return logQueryError(ctx, "comments.UpdateBody", err)
+	}
+	rows, err := result.RowsAffected()
+	span.SetAttributes(rowsAffectedKey.Int64(rows))
+	if err != nil || rows == 0 {
+		return domain.ErrCommentNotFound
+	}
+	return nil
+}
+
+func (r *postgresCommentRepository) Delete(ctx context.Context, id int) error {
+	// 編集履歴は外部キーの ON DELETE CASCADE で削除される
+	query := `DELETE FROM comments WHERE id = $1`
+
+	ctx, span := startQuerySpan(ctx, "comments.Delete", query)
+	defer span.End()
+
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return logQueryError(ctx, "comments.Delete", err)
+	}
+	if rows, err := result.RowsAffected(); err == nil {
+		span.SetAttributes(rowsAffectedKey.Int64(rows))
+	}
+	return nil
+}
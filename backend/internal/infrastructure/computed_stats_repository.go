@@ -0,0 +1,165 @@
+package infrastructure
+
+import (
+	"context"
+	"math"
+	"regexp"
+	"slices"
+	"time"
+	"todo_app_golang/internal/domain"
+)
+
+// projectPattern は Postgres 版の leadTimesByProject と同じく、タイトル中の +project を取り出します
+var projectPattern = regexp.MustCompile(`(?:^|\s)\+(\S+)`)
+
+type computedStatsRepository struct {
+	todos domain.TodoRepository
+}
+
+// NewComputedStatsRepository は全件を取得して Go 側で集計する StatsRepository を生成します。
+// 集計用の SQL を持たない SQLite 版などで使います（件数が多い場合は Postgres 版を使ってください）
+func NewComputedStatsRepository(todos domain.TodoRepository) domain.StatsRepository {
+	return &computedStatsRepository{todos: todos}
+}
+
+func (r *computedStatsRepository) Stats(ctx context.Context, q domain.StatsQuery) (*domain.Stats, error) {
+	todos, err := r.todos.FetchAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	_, todayEnd := q.TodayRange()
+	_, weekEnd := q.WeekRange()
+
+	stats := &domain.Stats{ByPriority: map[string]int{"low": 0, "medium": 0, "high": 0}}
+	for _, t := range todos {
+		stats.Total++
+		if t.IsCompleted {
+			stats.Completed++
+		}
+		if _, ok := stats.ByPriority[t.Priority]; ok {
+			stats.ByPriority[t.Priority]++
+		}
+		if t.IsCompleted || t.DueDate == nil {
+			continue
+		}
+		// 今日・今週が期限の件数は、期限切れと重複しないよう現在時刻以降のものを数える
+		due := *t.DueDate
+		switch {
+		case due.Before(q.Now):
+			stats.Overdue++
+		default:
+			if due.Before(todayEnd) {
+				stats.DueToday++
+			}
+			if due.Before(weekEnd) {
+				stats.DueThisWeek++
+			}
+		}
+	}
+
+	perDay := map[string]int{}
+	for _, t := range todos {
+		if t.CompletedAt != nil {
+			perDay[t.CompletedAt.In(q.Location).Format(time.DateOnly)]++
+		}
+	}
+	stats.CompletedPerDay = []domain.DailyCount{}
+	for d := dateOf(q.From); !d.After(dateOf(q.To)); d = d.AddDate(0, 0, 1) {
+		date := d.Format(time.DateOnly)
+		stats.CompletedPerDay = append(stats.CompletedPerDay, domain.DailyCount{Date: date, Count: perDay[date]})
+	}
+	return stats, nil
+}
+
+func (r *computedStatsRepository) LeadTimes(ctx context.Context, q domain.StatsQuery) (*domain.LeadTimeStats, error) {
+	todos, err := r.todos.FetchAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	start, end := q.CompletedRange()
+
+	var overall []float64
+	byPriority := map[string][]float64{}
+	byProject := map[string][]float64{}
+	for _, t := range todos {
+		if t.CompletedAt == nil || t.CompletedAt.Before(start) || !t.CompletedAt.Before(end) {
+			continue
+		}
+		hours := t.CompletedAt.Sub(t.CreatedAt).Hours()
+		overall = append(overall, hours)
+		byPriority[t.Priority] = append(byPriority[t.Priority], hours)
+
+		// 同じプロジェクトが複数回書かれていても1件として数える
+		seen := map[string]bool{}
+		for _, m := range projectPattern.FindAllStringSubmatch(t.Title, -1) {
+			if !seen[m[1]] {
+				seen[m[1]] = true
+				byProject[m[1]] = append(byProject[m[1]], hours)
+			}
+		}
+	}
+
+	result := &domain.LeadTimeStats{
+		Overall:    leadTime(overall),
+		ByPriority: map[string]domain.LeadTime{},
+		ByProject:  map[string]domain.LeadTime{},
+	}
+	for k, v := range byPriority {
+		result.ByPriority[k] = leadTime(v)
+	}
+	for k, v := range byProject {
+		result.ByProject[k] = leadTime(v)
+	}
+	return result, nil
+}
+
+func (r *computedStatsRepository) WeeklyThroughput(ctx context.Context, q domain.StatsQuery) ([]domain.WeeklyCount, error) {
+	todos, err := r.todos.FetchAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	perWeek := map[string]int{}
+	for _, t := range todos {
+		if t.CompletedAt != nil {
+			perWeek[weekStart(dateOf(t.CompletedAt.In(q.Location))).Format(time.DateOnly)]++
+		}
+	}
+
+	counts := []domain.WeeklyCount{}
+	for w := weekStart(dateOf(q.From)); !w.After(weekStart(dateOf(q.To))); w = w.AddDate(0, 0, 7) {
+		date := w.Format(time.DateOnly)
+		counts = append(counts, domain.WeeklyCount{WeekStart: date, Count: perWeek[date]})
+	}
+	return counts, nil
+}
+
+// dateOf は日時の暦日だけを取り出します（Postgres の ::date と同じく、時刻とタイムゾーンは捨てる）
+func dateOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// weekStart はその日が属する週の月曜日を返します
+func weekStart(d time.Time) time.Time {
+	return d.AddDate(0, 0, -((int(d.Weekday()) + 6) % 7))
+}
+
+func leadTime(hours []float64) domain.LeadTime {
+	if len(hours) == 0 {
+		return domain.LeadTime{}
+	}
+	slices.Sort(hours)
+	return domain.LeadTime{
+		Count:       len(hours),
+		MedianHours: percentileCont(hours, 0.5),
+		P90Hours:    percentileCont(hours, 0.9),
+	}
+}
+
+// percentileCont は Postgres の percentile_cont と同じく線形補間したパーセンタイルを返します（sorted は昇順）
+func percentileCont(sorted []float64, p float64) float64 {
+	pos := p * float64(len(sorted)-1)
+	lower := math.Floor(pos)
+	upper := math.Ceil(pos)
+	return sorted[int(lower)] + (sorted[int(upper)]-sorted[int(lower)])*(pos-lower)
+}
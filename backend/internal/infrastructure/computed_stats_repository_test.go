@@ -0,0 +1,116 @@
+package infrastructure
+
+import (
+	"context"
+	"testing"
+	"time"
+	"todo_app_golang/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fetchAllRepository は FetchAll だけを実装したテスト用のリポジトリです
+type fetchAllRepository struct {
+	domain.TodoRepository
+	todos []*domain.Todo
+}
+
+func (r *fetchAllRepository) FetchAll(ctx context.Context) ([]*domain.Todo, error) {
+	return r.todos, nil
+}
+
+// computedTodo は集計用のテストデータを作成します（completedAt が nil の場合は未完了）
+func computedTodo(title, priority string, due *time.Time, createdAt time.Time, completedAt *time.Time) *domain.Todo {
+	return &domain.Todo{
+		Title: title, Priority: priority, DueDate: due, CreatedAt: createdAt,
+		IsCompleted: completedAt != nil, CompletedAt: completedAt,
+	}
+}
+
+func TestComputedStatsRepository_Stats(t *testing.T) {
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	ptr := func(t time.Time) *time.Time { return &t }
+
+	repo := NewComputedStatsRepository(&fetchAllRepository{todos: []*domain.Todo{
+		computedTodo("期限切れ", "high", ptr(now.Add(-48*time.Hour)), now, nil),
+		computedTodo("今日が期限", "medium", ptr(now.Add(2*time.Hour)), now, nil),
+		computedTodo("今週が期限", "low", ptr(now.Add(72*time.Hour)), now, nil),
+		computedTodo("完了済み", "high", ptr(now.Add(-48*time.Hour)), now, ptr(now.Add(-24*time.Hour))),
+	}})
+
+	stats, err := repo.Stats(context.Background(), domain.StatsQuery{
+		Now:      now,
+		From:     now.AddDate(0, 0, -2),
+		To:       now,
+		Location: time.UTC,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 4, stats.Total)
+	assert.Equal(t, 1, stats.Completed)
+	assert.Equal(t, map[string]int{"low": 1, "medium": 1, "high": 2}, stats.ByPriority)
+	assert.Equal(t, 1, stats.Overdue)
+	assert.Equal(t, 1, stats.DueToday)
+	assert.Equal(t, 2, stats.DueThisWeek)
+	assert.Equal(t, []domain.DailyCount{
+		{Date: "2026-10-13", Count: 0},
+		{Date: "2026-10-14", Count: 1},
+		{Date: "2026-10-15", Count: 0},
+	}, stats.CompletedPerDay)
+}
+
+func TestComputedStatsRepository_LeadTimes(t *testing.T) {
+	created := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	ptr := func(t time.Time) *time.Time { return &t }
+
+	repo := NewComputedStatsRepository(&fetchAllRepository{todos: []*domain.Todo{
+		computedTodo("A +仕事", "high", nil, created, ptr(created.Add(10*time.Hour))),
+		computedTodo("B +仕事 +家 +仕事", "high", nil, created, ptr(created.Add(20*time.Hour))),
+		computedTodo("C", "low", nil, created, ptr(created.Add(30*time.Hour))),
+		computedTodo("未完了 +仕事", "low", nil, created, nil),
+		// 期間外に完了したものは含まれない
+		computedTodo("D", "low", nil, created, ptr(created.AddDate(0, 1, 0))),
+	}})
+
+	stats, err := repo.LeadTimes(context.Background(), domain.StatsQuery{
+		From:     created,
+		To:       created.AddDate(0, 0, 7),
+		Location: time.UTC,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, stats.Overall.Count)
+	assert.InDelta(t, 20, stats.Overall.MedianHours, 0.01)
+	assert.InDelta(t, 28, stats.Overall.P90Hours, 0.01)
+	assert.Equal(t, 2, stats.ByPriority["high"].Count)
+	assert.InDelta(t, 15, stats.ByPriority["high"].MedianHours, 0.01)
+	assert.Equal(t, 1, stats.ByPriority["low"].Count)
+	// 同じタイトルに同じプロジェクトが複数回あっても1件として数える
+	assert.Equal(t, 2, stats.ByProject["仕事"].Count)
+	assert.Equal(t, 1, stats.ByProject["家"].Count)
+}
+
+func TestComputedStatsRepository_WeeklyThroughput(t *testing.T) {
+	created := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+	ptr := func(t time.Time) *time.Time { return &t }
+
+	// 2026-10-05, 2026-10-12 はいずれも月曜日
+	repo := NewComputedStatsRepository(&fetchAllRepository{todos: []*domain.Todo{
+		computedTodo("A", "low", nil, created, ptr(time.Date(2026, 10, 6, 12, 0, 0, 0, time.UTC))),
+		computedTodo("B", "low", nil, created, ptr(time.Date(2026, 10, 11, 12, 0, 0, 0, time.UTC))),
+		computedTodo("C", "low", nil, created, ptr(time.Date(2026, 10, 20, 12, 0, 0, 0, time.UTC))),
+	}})
+
+	counts, err := repo.WeeklyThroughput(context.Background(), domain.StatsQuery{
+		From:     time.Date(2026, 10, 7, 0, 0, 0, 0, time.UTC),
+		To:       time.Date(2026, 10, 21, 0, 0, 0, 0, time.UTC),
+		Location: time.UTC,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.WeeklyCount{
+		{WeekStart: "2026-10-05", Count: 2},
+		{WeekStart: "2026-10-12", Count: 0},
+		{WeekStart: "2026-10-19", Count: 1},
+	}, counts)
+}
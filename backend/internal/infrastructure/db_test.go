@@ -0,0 +1,19 @@
+package infrastructure
+
+import (
+	"context"
+	"testing"
+	"todo_app_golang/migrations"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchemaVersion(t *testing.T) {
+	requirePostgres(t)
+	// TestMain ですべてのマイグレーションを適用済み
+	version, dirty, err := SchemaVersion(context.Background(), testDB)
+
+	assert.NoError(t, err)
+	assert.False(t, dirty)
+	assert.Equal(t, migrations.LatestVersion(), version)
+}
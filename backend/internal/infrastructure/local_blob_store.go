@@ -0,0 +1,120 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"todo_app_golang/internal/domain"
+)
+
+// validateBlobKey はキーが SHA-256 の16進数（小文字）であることを確かめます。
+// キーはファイルパスや URL に使うため、それ以外の文字（/ や .. など）を含むキーは受け付けない
+func validateBlobKey(key string) error {
+	if len(key) != 64 {
+		return fmt.Errorf("invalid blob key %q", key)
+	}
+	for _, c := range key {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return fmt.Errorf("invalid blob key %q", key)
+		}
+	}
+	return nil
+}
+
+// LocalBlobStore はローカルのディレクトリに添付ファイルの中身を保存する BlobStore です。
+// 1つのディレクトリにファイルが集中しないよう、キーの先頭2文字のサブディレクトリに分けて保存します
+type LocalBlobStore struct {
+	dir string
+}
+
+// NewLocalBlobStore は dir に保存する LocalBlobStore を生成します。dir が無い場合は作成します
+func NewLocalBlobStore(dir string) (*LocalBlobStore, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("create blob directory: %w", err)
+	}
+	return &LocalBlobStore{dir: dir}, nil
+}
+
+func (s *LocalBlobStore) path(key string) (string, error) {
+	if err := validateBlobKey(key); err != nil {
+		return "", err
+	}
+	return filepath.Join(s.dir, key[:2], key), nil
+}
+
+func (s *LocalBlobStore) Put(ctx context.Context, key string, r io.Reader, size int64) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	dst, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0o750); err != nil {
+		return err
+	}
+
+	// 書き込み途中のファイルを読まれないよう、一時ファイルに書いてから名前を変える
+	tmp, err := os.CreateTemp(filepath.Dir(dst), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	n, err := io.Copy(tmp, io.LimitReader(r, size))
+	if err == nil && n != size {
+		err = fmt.Errorf("blob %s: wrote %d of %d bytes", key, n, size)
+	}
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), dst)
+}
+
+func (s *LocalBlobStore) Open(ctx context.Context, key string) (io.ReadSeekCloser, error) {
+	p, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(p)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, domain.ErrBlobNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (s *LocalBlobStore) Exists(ctx context.Context, key string) (bool, error) {
+	p, err := s.path(key)
+	if err != nil {
+		return false, err
+	}
+	_, err = os.Stat(p)
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (s *LocalBlobStore) Delete(ctx context.Context, key string) error {
+	p, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(p); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package infrastructure
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"todo_app_golang/internal/domain"
+)
+
+type memoryAttachmentRepository struct {
+	mu          sync.Mutex
+	todos       domain.TodoRepository
+	attachments map[int]*domain.Attachment
+	nextID      int
+}
+
+// NewMemoryAttachmentRepository はメモリ上に添付ファイルの情報を保持するリポジトリを生成します（テストやデモ用）。
+// todos から削除されたタスクの添付ファイルは、次に参照したときに削除します（Postgres 版の ON DELETE CASCADE に相当）
+func NewMemoryAttachmentRepository(todos domain.TodoRepository) domain.AttachmentRepository {
+	return &memoryAttachmentRepository{todos: todos, attachments: map[int]*domain.Attachment{}, nextID: 1}
+}
+
+// todoExists はタスクが存在するかを返し、存在しない場合はそのタスクの添付ファイルを削除します。r.mu を取得した状態で呼んでください
+func (r *memoryAttachmentRepository) todoExists(ctx context.Context, todoID int) (bool, error) {
+	_, err := r.todos.GetByID(ctx, todoID)
+	switch {
+	case err == nil:
+		return true, nil
+	case errors.Is(err, domain.ErrTodoNotFound):
+		for id, a := range r.attachments {
+			if a.TodoID == todoID {
+				delete(r.attachments, id)
+			}
+		}
+		return false, nil
+	default:
+		return false, err
+	}
+}
+
+func (r *memoryAttachmentRepository) Create(ctx context.Context, attachment *domain.Attachment) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// 外部キー制約の違反を再現する
+	ok, err := r.todoExists(ctx, attachment.TodoID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("attachments: todo %d does not exist", attachment.TodoID)
+	}
+
+	stored := *attachment
+	stored.ID = r.nextID
+	r.attachments[stored.ID] = &stored
+	r.nextID++
+
+	attachment.ID = stored.ID
+	return nil
+}
+
+func (r *memoryAttachmentRepository) ListByTodo(ctx context.Context, todoID int) ([]*domain.Attachment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	attachments := []*domain.Attachment{}
+	if ok, err := r.todoExists(ctx, todoID); !ok {
+		return attachments, err
+	}
+	for _, a := range r.attachments {
+		if a.TodoID == todoID {
+			copied := *a
+			attachments = append(attachments, &copied)
+		}
+	}
+	slices.SortFunc(attachments, func(a, b *domain.Attachment) int {
+		if c := a.CreatedAt.Compare(b.CreatedAt); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.ID, b.ID)
+	})
+	return attachments, nil
+}
+
+func (r *memoryAttachmentRepository) GetByID(ctx context.Context, id int) (*domain.Attachment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	a, ok := r.attachments[id]
+	if !ok {
+		return nil, domain.ErrAttachmentNotFound
+	}
+	exists, err := r.todoExists(ctx, a.TodoID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, domain.ErrAttachmentNotFound
+	}
+	copied := *a
+	return &copied, nil
+}
+
+func (r *memoryAttachmentRepository) Delete(ctx context.Context, id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Postgres 版と同じく、存在しない ID を指定してもエラーにしない
+	delete(r.attachments, id)
+	return nil
+}
+
+func (r *memoryAttachmentRepository) CountBySHA256(ctx context.Context, sha256 string) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	todoIDs := map[int]bool{}
+	for _, a := range r.attachments {
+		if a.SHA256 == sha256 {
+			todoIDs[a.TodoID] = true
+		}
+	}
+	// 削除済みのタスクの添付ファイルは数えない
+	for todoID := range todoIDs {
+		if _, err := r.todoExists(ctx, todoID); err != nil {
+			return 0, err
+		}
+	}
+	n := 0
+	for _, a := range r.attachments {
+		if a.SHA256 == sha256 {
+			n++
+		}
+	}
+	return n, nil
+}
@@ -0,0 +1,73 @@
+package infrastructure
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"todo_app_golang/internal/domain"
+)
+
+type memoryBlobStore struct {
+	mu    sync.Mutex
+	blobs map[string][]byte
+}
+
+// NewMemoryBlobStore はメモリ上に添付ファイルの中身を保持する BlobStore を生成します（テストやデモ用）
+func NewMemoryBlobStore() domain.BlobStore {
+	return &memoryBlobStore{blobs: map[string][]byte{}}
+}
+
+func (s *memoryBlobStore) Put(ctx context.Context, key string, r io.Reader, size int64) error {
+	if err := validateBlobKey(key); err != nil {
+		return err
+	}
+	data, err := io.ReadAll(io.LimitReader(r, size))
+	if err != nil {
+		return err
+	}
+	if int64(len(data)) != size {
+		return fmt.Errorf("blob %s: read %d of %d bytes", key, len(data), size)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.blobs[key] = data
+	return nil
+}
+
+// nopSeekCloser は bytes.Reader に何もしない Close を付けたものです
+type nopSeekCloser struct {
+	*bytes.Reader
+}
+
+func (nopSeekCloser) Close() error { return nil }
+
+func (s *memoryBlobStore) Open(ctx context.Context, key string) (io.ReadSeekCloser, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, ok := s.blobs[key]
+	if !ok {
+		return nil, domain.ErrBlobNotFound
+	}
+	// 保存済みのスライスは書き換えないため、コピーせずに返してよい
+	return nopSeekCloser{bytes.NewReader(data)}, nil
+}
+
+func (s *memoryBlobStore) Exists(ctx context.Context, key string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, ok := s.blobs[key]
+	return ok, nil
+}
+
+func (s *memoryBlobStore) Delete(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.blobs, key)
+	return nil
+}
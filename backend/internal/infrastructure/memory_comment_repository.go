@@ -0,0 +1,146 @@
+package infrastructure
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+	"todo_app_golang/internal/domain"
+)
+
+type memoryCommentRepository struct {
+	mu       sync.Mutex
+	todos    domain.TodoRepository
+	comments map[int]*domain.Comment
+	nextID   int
+}
+
+// NewMemoryCommentRepository はメモリ上にコメントを保持するリポジトリを生成します（テストやデモ用）。
+// todos から削除されたタスクのコメントは、次に参照したときに削除します（Postgres 版の ON DELETE CASCADE に相当）
+func NewMemoryCommentRepository(todos domain.TodoRepository) domain.CommentRepository {
+	return &memoryCommentRepository{todos: todos, comments: map[int]*domain.Comment{}, nextID: 1}
+}
+
+// cloneComment は呼び出し側が書き換えても保存済みのデータに影響しないようにコピーを返します
+func cloneComment(c *domain.Comment) *domain.Comment {
+	cc := *c
+	cc.History = append([]domain.CommentRevision{}, c.History...)
+	return &cc
+}
+
+// todoExists はタスクが存在するかを返し、存在しない場合はそのタスクのコメントを削除します。r.mu を取得した状態で呼んでください
+func (r *memoryCommentRepository) todoExists(ctx context.Context, todoID int) (bool, error) {
+	_, err := r.todos.GetByID(ctx, todoID)
+	switch {
+	case err == nil:
+		return true, nil
+	case errors.Is(err, domain.ErrTodoNotFound):
+		for id, c := range r.comments {
+			if c.TodoID == todoID {
+				delete(r.comments, id)
+			}
+		}
+		return false, nil
+	default:
+		return false, err
+	}
+}
+
+func (r *memoryCommentRepository) Create(ctx context.Context, comment *domain.Comment) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// 外部キー制約の違反を再現する
+	ok, err := r.todoExists(ctx, comment.TodoID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("comments: todo %d does not exist", comment.TodoID)
+	}
+
+	stored := cloneComment(comment)
+	stored.ID = r.nextID
+	stored.UpdatedAt = stored.CreatedAt
+	stored.History = []domain.CommentRevision{}
+	r.comments[stored.ID] = stored
+	r.nextID++
+
+	comment.ID = stored.ID
+	return nil
+}
+
+func (r *memoryCommentRepository) ListByTodo(ctx context.Context, todoID int) ([]*domain.Comment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	comments := []*domain.Comment{}
+	if ok, err := r.todoExists(ctx, todoID); !ok {
+		return comments, err
+	}
+	for _, c := range r.comments {
+		if c.TodoID == todoID {
+			comments = append(comments, cloneComment(c))
+		}
+	}
+	slices.SortFunc(comments, func(a, b *domain.Comment) int {
+		if c := a.CreatedAt.Compare(b.CreatedAt); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.ID, b.ID)
+	})
+	return comments, nil
+}
+
+// get は id のコメントを返します。タスクが削除されている場合は見つからないものとして扱います
+func (r *memoryCommentRepository) get(ctx context.Context, id int) (*domain.Comment, error) {
+	c, ok := r.comments[id]
+	if !ok {
+		return nil, domain.ErrCommentNotFound
+	}
+	exists, err := r.todoExists(ctx, c.TodoID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, domain.ErrCommentNotFound
+	}
+	return c, nil
+}
+
+func (r *memoryCommentRepository) GetByID(ctx context.Context, id int) (*domain.Comment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	c, err := r.get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return cloneComment(c), nil
+}
+
+func (r *memoryCommentRepository) UpdateBody(ctx context.Context, id int, body string, editedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	c, err := r.get(ctx, id)
+	if err != nil {
+		return err
+	}
+	c.History = append(c.History, domain.CommentRevision{Body: c.Body, EditedAt: editedAt})
+	c.Body = body
+	c.UpdatedAt = editedAt
+	return nil
+}
+
+func (r *memoryCommentRepository) Delete(ctx context.Context, id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Postgres 版と同じく、存在しない ID を指定してもエラーにしない
+	delete(r.comments, id)
+	return nil
+}
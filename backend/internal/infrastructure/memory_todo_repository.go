@@ -0,0 +1,260 @@
+package infrastructure
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+	"todo_app_golang/internal/domain"
+)
+
+type memoryTodoRepository struct {
+	mu     sync.RWMutex
+	todos  map[int]*domain.Todo
+	nextID int
+	now    func() time.Time
+}
+
+// NewMemoryTodoRepository はメモリ上にタスクを保持するリポジトリを生成します。
+// テストやデモ用で、ID の採番・並び順・エラーは Postgres 版と同じ振る舞いをします（プロセスを終了すると消えます）
+func NewMemoryTodoRepository() domain.TodoRepository {
+	return &memoryTodoRepository{todos: map[int]*domain.Todo{}, nextID: 1, now: time.Now}
+}
+
+// clone は呼び出し側が書き換えても保存済みのデータに影響しないようにコピーを返します
+func clone(t *domain.Todo) *domain.Todo {
+	c := *t
+	if t.DueDate != nil {
+		d := *t.DueDate
+		c.DueDate = &d
+	}
+	if t.CompletedAt != nil {
+		d := *t.CompletedAt
+		c.CompletedAt = &d
+	}
+	if t.ICalUID != nil {
+		uid := *t.ICalUID
+		c.ICalUID = &uid
+	}
+	if t.OwnerID != nil {
+		owner := *t.OwnerID
+		c.OwnerID = &owner
+	}
+	if t.AssigneeID != nil {
+		assignee := *t.AssigneeID
+		c.AssigneeID = &assignee
+	}
+	c.Watchers = append([]string{}, t.Watchers...)
+	return &c
+}
+
+// checkICalUID は ical_uid のユニーク制約を再現します（id 自身は除く）
+func (r *memoryTodoRepository) checkICalUID(uid *string, id int) error {
+	if uid == nil {
+		return nil
+	}
+	for _, t := range r.todos {
+		if t.ID != id && t.ICalUID != nil && *t.ICalUID == *uid {
+			return fmt.Errorf("todos: duplicate ical_uid %q", *uid)
+		}
+	}
+	return nil
+}
+
+func (r *memoryTodoRepository) Create(ctx context.Context, todo *domain.Todo) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.checkICalUID(todo.ICalUID, 0); err != nil {
+		return err
+	}
+
+	// 完了済みで作成する場合（インポート等）、完了日時が無ければ現在時刻を記録する
+	now := r.now()
+	stored := clone(todo)
+	stored.ID = r.nextID
+	stored.UpdatedAt = now
+	stored.CompletedAt = nil
+	stored.Watchers = []string{} // Postgres 版と同じく、ウォッチャーは AddWatcher でだけ追加する
+	if todo.IsCompleted {
+		stored.CompletedAt = todo.CompletedAt
+		if stored.CompletedAt == nil {
+			stored.CompletedAt = &now
+		}
+	}
+	r.todos[stored.ID] = stored
+	r.nextID++
+
+	todo.ID = stored.ID
+	todo.CompletedAt = clone(stored).CompletedAt
+	return nil
+}
+
+func (r *memoryTodoRepository) FetchAll(ctx context.Context) ([]*domain.Todo, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var todos []*domain.Todo
+	for _, t := range r.todos {
+		todos = append(todos, clone(t))
+	}
+	// 作成日時の新しい順（同時刻の場合は後から作成したものを先にする）
+	slices.SortFunc(todos, func(a, b *domain.Todo) int {
+		if c := b.CreatedAt.Compare(a.CreatedAt); c != 0 {
+			return c
+		}
+		return cmp.Compare(b.ID, a.ID)
+	})
+	return todos, nil
+}
+
+func (r *memoryTodoRepository) Delete(ctx context.Context, id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Postgres 版と同じく、存在しない ID を指定してもエラーにしない
+	delete(r.todos, id)
+	return nil
+}
+
+func (r *memoryTodoRepository) UpdateStatus(ctx context.Context, id int, isCompleted bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	t, ok := r.todos[id]
+	if !ok {
+		return sql.ErrNoRows
+	}
+
+	// 完了にした時点の日時を記録し、未完了に戻した場合はクリアする（完了済みを再度完了にしても上書きしない）
+	now := r.now()
+	t.IsCompleted = isCompleted
+	switch {
+	case !isCompleted:
+		t.CompletedAt = nil
+	case t.CompletedAt == nil:
+		t.CompletedAt = &now
+	}
+	t.UpdatedAt = now
+	return nil
+}
+
+func (r *memoryTodoRepository) GetByID(ctx context.Context, id int) (*domain.Todo, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	t, ok := r.todos[id]
+	if !ok {
+		return nil, domain.ErrTodoNotFound
+	}
+	return clone(t), nil
+}
+
+func (r *memoryTodoRepository) GetByIDs(ctx context.Context, ids []int) ([]*domain.Todo, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	todos := make([]*domain.Todo, 0, len(ids))
+	for _, id := range slices.Compact(slices.Sorted(slices.Values(ids))) {
+		if t, ok := r.todos[id]; ok {
+			todos = append(todos, clone(t))
+		}
+	}
+	return todos, nil
+}
+
+func (r *memoryTodoRepository) Update(ctx context.Context, todo *domain.Todo) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, ok := r.todos[todo.ID]
+	if !ok {
+		return sql.ErrNoRows
+	}
+	if err := r.checkICalUID(todo.ICalUID, todo.ID); err != nil {
+		return err
+	}
+
+	// 作成日時・所有者・担当者・ウォッチャーは更新しない。完了日時は指定された値 → 既存の値 → 現在時刻の順で決める
+	now := r.now()
+	updated := clone(todo)
+	updated.CreatedAt = existing.CreatedAt
+	updated.OwnerID = existing.OwnerID
+	updated.AssigneeID = existing.AssigneeID
+	updated.Watchers = existing.Watchers
+	updated.UpdatedAt = now
+	updated.CompletedAt = nil
+	if todo.IsCompleted {
+		switch {
+		case todo.CompletedAt != nil:
+			updated.CompletedAt = clone(todo).CompletedAt
+		case existing.CompletedAt != nil:
+			updated.CompletedAt = existing.CompletedAt
+		default:
+			updated.CompletedAt = &now
+		}
+	}
+	r.todos[todo.ID] = updated
+	return nil
+}
+
+func (r *memoryTodoRepository) GetByICalUID(ctx context.Context, uid string) (*domain.Todo, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, t := range r.todos {
+		if t.ICalUID != nil && *t.ICalUID == uid {
+			return clone(t), nil
+		}
+	}
+	return nil, domain.ErrTodoNotFound
+}
+
+func (r *memoryTodoRepository) SetAssignee(ctx context.Context, id int, assigneeID *string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	t, ok := r.todos[id]
+	if !ok {
+		return domain.ErrTodoNotFound
+	}
+	t.AssigneeID = nil
+	if assigneeID != nil {
+		assignee := *assigneeID
+		t.AssigneeID = &assignee
+	}
+	t.UpdatedAt = r.now()
+	return nil
+}
+
+func (r *memoryTodoRepository) AddWatcher(ctx context.Context, id int, userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	t, ok := r.todos[id]
+	if !ok {
+		return domain.ErrTodoNotFound
+	}
+	// ID 順を保って挿入する（追加済みの場合は何もしない）
+	if i, found := slices.BinarySearch(t.Watchers, userID); !found {
+		t.Watchers = slices.Insert(t.Watchers, i, userID)
+	}
+	return nil
+}
+
+func (r *memoryTodoRepository) RemoveWatcher(ctx context.Context, id int, userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	t, ok := r.todos[id]
+	if !ok {
+		return domain.ErrTodoNotFound
+	}
+	if i, found := slices.BinarySearch(t.Watchers, userID); found {
+		t.Watchers = slices.Delete(t.Watchers, i, i+1)
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+package infrastructure
+
+import (
+	"testing"
+	"todo_app_golang/internal/domain"
+	"todo_app_golang/internal/domain/repositorytest"
+)
+
+func TestMemoryTodoRepository(t *testing.T) {
+	repositorytest.TodoRepositoryContract(t, func(t *testing.T) domain.TodoRepository {
+		return NewMemoryTodoRepository()
+	})
+}
+
+func TestMemoryCommentRepository(t *testing.T) {
+	repositorytest.CommentRepositoryContract(t, func(t *testing.T) (domain.TodoRepository, domain.CommentRepository) {
+		todos := NewMemoryTodoRepository()
+		return todos, NewMemoryCommentRepository(todos)
+	})
+}
+
+func TestMemoryAttachmentRepository(t *testing.T) {
+	repositorytest.AttachmentRepositoryContract(t, func(t *testing.T) (domain.TodoRepository, domain.AttachmentRepository) {
+		todos := NewMemoryTodoRepository()
+		return todos, NewMemoryAttachmentRepository(todos)
+	})
+}
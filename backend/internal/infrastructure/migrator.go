@@ -0,0 +1,147 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"strings"
+	"todo_app_golang/internal/logging"
+	"todo_app_golang/migrations"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database"
+	"github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+)
+
+// migrationLockID はマイグレーション中に取得する Postgres のアドバイザリロックの ID です。
+// 複数のインスタンスが同時に起動しても、マイグレーションは1つずつ実行されます
+const migrationLockID int64 = 0x746f646f6d6967 // "todomig"
+
+// Migrator はバイナリに同梱したマイグレーションを適用します
+type Migrator struct {
+	db     *sql.DB
+	source fs.FS
+	// lock は他のインスタンスと同時に実行しないためのロックを取得し、解放する関数を返します
+	lock func(ctx context.Context, conn *sql.Conn) (unlock func(), err error)
+	// driver は conn を使う golang-migrate のドライバーを生成します
+	driver func(ctx context.Context, conn *sql.Conn) (database.Driver, error)
+}
+
+// NewMigrator は Postgres 用の Migrator を生成します
+func NewMigrator(db *sql.DB) *Migrator {
+	return &Migrator{
+		db:     db,
+		source: migrations.FS,
+		lock:   advisoryLock,
+		driver: func(ctx context.Context, conn *sql.Conn) (database.Driver, error) {
+			return postgres.WithConnection(ctx, conn, &postgres.Config{})
+		},
+	}
+}
+
+// Up は未適用のマイグレーションをすべて適用します。適用済みの場合は何もしません
+func (m *Migrator) Up(ctx context.Context) error {
+	return m.run(ctx, "up", func(mig *migrate.Migrate) error {
+		return ignoreNoChange(mig.Up())
+	})
+}
+
+// Down は直近のマイグレーションから steps 個を取り消します。steps が 0 以下の場合はすべて取り消します
+func (m *Migrator) Down(ctx context.Context, steps int) error {
+	return m.run(ctx, "down", func(mig *migrate.Migrate) error {
+		if steps <= 0 {
+			return ignoreNoChange(mig.Down())
+		}
+		return ignoreNoChange(mig.Steps(-steps))
+	})
+}
+
+// Force は失敗して dirty になったバージョンを、マイグレーションを実行せずに指定のバージョンとして記録します
+func (m *Migrator) Force(ctx context.Context, version int) error {
+	return m.run(ctx, "force", func(mig *migrate.Migrate) error {
+		return mig.Force(version)
+	})
+}
+
+// Version は現在のスキーマのバージョンを返します。未適用の場合は 0 を返します
+func (m *Migrator) Version(ctx context.Context) (version uint, dirty bool, err error) {
+	err = m.run(ctx, "version", func(mig *migrate.Migrate) error {
+		version, dirty, err = mig.Version()
+		if errors.Is(err, migrate.ErrNilVersion) {
+			return nil
+		}
+		return err
+	})
+	return version, dirty, err
+}
+
+// run はロックを取得した専用のコネクションで migrate を実行します
+func (m *Migrator) run(ctx context.Context, op string, fn func(mig *migrate.Migrate) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	unlock, err := m.lock(ctx, conn)
+	if err != nil {
+		return fmt.Errorf("acquire migration lock: %w", err)
+	}
+	defer unlock()
+
+	src, err := iofs.New(m.source, ".")
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	// driver.Close はコネクションを閉じてしまうため呼ばない（上の defer で解放する）
+	driver, err := m.driver(ctx, conn)
+	if err != nil {
+		return err
+	}
+	mig, err := migrate.NewWithInstance("iofs", src, "postgres", driver)
+	if err != nil {
+		return err
+	}
+	mig.Log = &migrateLogger{ctx: ctx, logger: logging.FromContext(ctx).With(slog.String("op", "migrate."+op))}
+
+	return fn(mig)
+}
+
+// advisoryLock は Postgres のアドバイザリロックを取得します。
+// 他のインスタンスがマイグレーション中の場合は終わるまで待ちます
+func advisoryLock(ctx context.Context, conn *sql.Conn) (func(), error) {
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, migrationLockID); err != nil {
+		return nil, err
+	}
+	// ロックはセッションに紐づくため、コネクションをプールに戻す前に必ず解放する
+	return func() {
+		conn.ExecContext(context.WithoutCancel(ctx), `SELECT pg_advisory_unlock($1)`, migrationLockID)
+	}, nil
+}
+
+func ignoreNoChange(err error) error {
+	if errors.Is(err, migrate.ErrNoChange) {
+		return nil
+	}
+	return err
+}
+
+// migrateLogger は golang-migrate の進捗を slog に出力します
+type migrateLogger struct {
+	ctx    context.Context
+	logger *slog.Logger
+}
+
+func (l *migrateLogger) Printf(format string, v ...any) {
+	l.logger.InfoContext(l.ctx, strings.TrimSpace(fmt.Sprintf(format, v...)))
+}
+
+func (l *migrateLogger) Verbose() bool {
+	return false
+}
@@ -0,0 +1,60 @@
+package infrastructure
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"todo_app_golang/migrations"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMigrator(t *testing.T) {
+	requirePostgres(t)
+	ctx := context.Background()
+	migrator := NewMigrator(testDB)
+	latest := migrations.LatestVersion()
+
+	t.Run("TestMain で最新バージョンまで適用されていること", func(t *testing.T) {
+		version, dirty, err := migrator.Version(ctx)
+
+		assert.NoError(t, err)
+		assert.False(t, dirty)
+		assert.Equal(t, latest, version)
+	})
+
+	t.Run("1つ取り消してから再適用できること", func(t *testing.T) {
+		assert.NoError(t, migrator.Down(ctx, 1))
+		version, _, err := migrator.Version(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, latest-1, version)
+
+		assert.NoError(t, migrator.Up(ctx))
+		version, _, err = migrator.Version(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, latest, version)
+	})
+
+	t.Run("複数のインスタンスが同時に Up してもエラーにならないこと", func(t *testing.T) {
+		assert.NoError(t, migrator.Down(ctx, 2))
+
+		var wg sync.WaitGroup
+		errs := make([]error, 5)
+		for i := range errs {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				errs[i] = NewMigrator(testDB).Up(ctx)
+			}()
+		}
+		wg.Wait()
+
+		for _, err := range errs {
+			assert.NoError(t, err)
+		}
+		version, dirty, err := migrator.Version(ctx)
+		assert.NoError(t, err)
+		assert.False(t, dirty)
+		assert.Equal(t, latest, version)
+	})
+}
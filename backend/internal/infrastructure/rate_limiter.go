@@ -0,0 +1,78 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+	"time"
+	"todo_app_golang/internal/ratelimit"
+)
+
+// rateLimitPruneInterval は満杯に戻ったバケットの行を片付ける間隔です
+const rateLimitPruneInterval = time.Minute
+
+// PostgresRateLimiter は rate_limits テーブルにバケットを保持する ratelimit.Limiter です。
+// 複数インスタンスで同じ DB を使えば、制限もインスタンス全体で共有されます。
+// 時刻は DB の now() を使うため、インスタンス間の時計のずれは影響しません
+type PostgresRateLimiter struct {
+	db *sql.DB
+
+	mu         sync.Mutex
+	lastPruned time.Time
+}
+
+// NewPostgresRateLimiter は Postgres 版の ratelimit.Limiter を生成します
+func NewPostgresRateLimiter(db *sql.DB) *PostgresRateLimiter {
+	return &PostgresRateLimiter{db: db}
+}
+
+func (l *PostgresRateLimiter) Allow(ctx context.Context, key string, budget ratelimit.Budget) (ratelimit.Result, error) {
+	l.prune(ctx)
+
+	// ratelimit.Budget.Take と同じ判定を1文で行う。
+	// 受け付ける場合だけ行を更新して新しい TAT を返し、拒否する場合は行を返さない
+	query := `
+		INSERT INTO rate_limits AS l (key, tat) VALUES ($1, now() + $2::bigint * interval '1 microsecond')
+		ON CONFLICT (key) DO UPDATE SET tat = GREATEST(l.tat, now()) + $2::bigint * interval '1 microsecond'
+		WHERE GREATEST(l.tat, now()) + $2::bigint * interval '1 microsecond' <= now() + $3::bigint * interval '1 microsecond'
+		RETURNING tat, now()`
+
+	ctx, span := startQuerySpan(ctx, "rate_limits.Allow", query)
+	defer span.End()
+
+	var tat, now time.Time
+	err := l.db.QueryRowContext(ctx, query, key, budget.Interval().Microseconds(), budget.Tolerance().Microseconds()).Scan(&tat, &now)
+	if err == nil {
+		return budget.Result(true, tat, now), nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return ratelimit.Result{}, logQueryError(ctx, "rate_limits.Allow", err)
+	}
+
+	// 拒否した場合は再試行までの時間を求めるために現在の TAT を読む
+	query = `SELECT tat, now() FROM rate_limits WHERE key = $1`
+	if err := l.db.QueryRowContext(ctx, query, key).Scan(&tat, &now); err != nil {
+		return ratelimit.Result{}, logQueryError(ctx, "rate_limits.Allow", err)
+	}
+	return budget.Result(false, tat, now), nil
+}
+
+// prune は満杯に戻ったバケットの行を削除します。失敗しても判定には影響しないため、エラーは記録するだけです
+func (l *PostgresRateLimiter) prune(ctx context.Context) {
+	l.mu.Lock()
+	if time.Since(l.lastPruned) < rateLimitPruneInterval {
+		l.mu.Unlock()
+		return
+	}
+	l.lastPruned = time.Now()
+	l.mu.Unlock()
+
+	query := `DELETE FROM rate_limits WHERE tat < now()`
+
+	ctx, span := startQuerySpan(ctx, "rate_limits.Prune", query)
+	defer span.End()
+
+	_, err := l.db.ExecContext(ctx, query)
+	logQueryError(ctx, "rate_limits.Prune", err)
+}
@@ -0,0 +1,69 @@
+package infrastructure
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+	"todo_app_golang/internal/ratelimit"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPostgresRateLimiter(t *testing.T) {
+	requirePostgres(t)
+	ctx := context.Background()
+	budget := ratelimit.Budget{Rate: 60, Burst: 3}
+
+	setup := func(t *testing.T) *PostgresRateLimiter {
+		if _, err := testDB.Exec("DELETE FROM rate_limits"); err != nil {
+			t.Fatalf("Failed to clean table: %v", err)
+		}
+		return NewPostgresRateLimiter(testDB)
+	}
+
+	t.Run("容量までは受け付け、超えると再試行までの時間とともに拒否すること", func(t *testing.T) {
+		limiter := setup(t)
+
+		for _, remaining := range []int{2, 1, 0} {
+			r, err := limiter.Allow(ctx, "ip:192.0.2.1", budget)
+			assert.NoError(t, err)
+			assert.True(t, r.Allowed)
+			assert.Equal(t, remaining, r.Remaining)
+		}
+
+		r, err := limiter.Allow(ctx, "ip:192.0.2.1", budget)
+		assert.NoError(t, err)
+		assert.False(t, r.Allowed)
+		assert.Greater(t, r.RetryAfter, time.Duration(0))
+		assert.LessOrEqual(t, r.RetryAfter, time.Second)
+
+		r, _ = limiter.Allow(ctx, "ip:192.0.2.2", budget)
+		assert.True(t, r.Allowed, "別のキーは制限されないこと")
+	})
+
+	t.Run("複数のインスタンスから同時に呼んでも容量を超えて受け付けないこと", func(t *testing.T) {
+		setup(t)
+		limiters := []*PostgresRateLimiter{NewPostgresRateLimiter(testDB), NewPostgresRateLimiter(testDB)}
+
+		var mu sync.Mutex
+		allowed := 0
+		var wg sync.WaitGroup
+		for i := range 10 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				r, err := limiters[i%2].Allow(ctx, "user:1", ratelimit.Budget{Rate: 1, Burst: 3})
+				assert.NoError(t, err)
+				if r.Allowed {
+					mu.Lock()
+					allowed++
+					mu.Unlock()
+				}
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, 3, allowed)
+	})
+}
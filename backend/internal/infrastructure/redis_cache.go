@@ -0,0 +1,90 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// redisTimeout はキャッシュへの1回の問い合わせを待つ最大時間です。
+// キャッシュが遅い場合は DB に問い合わせた方が早いため、短くしておく
+const redisTimeout = 500 * time.Millisecond
+
+// RedisCache は Redis プロトコルのサーバーを使う CacheStore です。
+// 複数のインスタンスでキャッシュを共有でき、書き込み時の無効化も全インスタンスに反映されます
+type RedisCache struct {
+	pool   *redis.Pool
+	prefix string // 他の用途とキーが衝突しないよう、すべてのキーの先頭に付ける
+}
+
+// NewRedisCache は url (例: redis://localhost:6379/0) のサーバーを使う RedisCache を生成します。
+// 接続は最初に使うときに確立します
+func NewRedisCache(url string) *RedisCache {
+	return &RedisCache{
+		prefix: "todo:",
+		pool: &redis.Pool{
+			DialContext: func(ctx context.Context) (redis.Conn, error) {
+				return redis.DialURLContext(ctx, url,
+					redis.DialConnectTimeout(redisTimeout),
+					redis.DialReadTimeout(redisTimeout),
+					redis.DialWriteTimeout(redisTimeout),
+				)
+			},
+			MaxIdle:     10,
+			IdleTimeout: 5 * time.Minute,
+		},
+	}
+}
+
+func (c *RedisCache) do(ctx context.Context, cmd string, args ...any) (any, error) {
+	ctx, cancel := context.WithTimeout(ctx, redisTimeout)
+	defer cancel()
+
+	conn, err := c.pool.GetContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	return redis.DoContext(conn, ctx, cmd, args...)
+}
+
+func (c *RedisCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	value, err := redis.Bytes(c.do(ctx, "GET", c.prefix+key))
+	if errors.Is(err, redis.ErrNil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+func (c *RedisCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	_, err := c.do(ctx, "SET", c.prefix+key, value, "PX", ttl.Milliseconds())
+	return err
+}
+
+func (c *RedisCache) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	args := make([]any, len(keys))
+	for i, key := range keys {
+		args[i] = c.prefix + key
+	}
+	_, err := c.do(ctx, "DEL", args...)
+	return err
+}
+
+// Ping はサーバーに接続できるかを確認します（readiness チェック用）
+func (c *RedisCache) Ping(ctx context.Context) error {
+	_, err := c.do(ctx, "PING")
+	return err
+}
+
+// Close は接続をすべて閉じます
+func (c *RedisCache) Close() error {
+	return c.pool.Close()
+}
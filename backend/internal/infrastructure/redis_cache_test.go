@@ -0,0 +1,189 @@
+package infrastructure
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeRedis はテスト用に GET / SET (PX) / DEL / PING だけを実装した Redis プロトコルのサーバーです
+type fakeRedis struct {
+	mu      sync.Mutex
+	values  map[string]string
+	expires map[string]time.Time
+	ln      net.Listener
+}
+
+// startFakeRedis はローカルで fakeRedis を起動し、接続先の URL を返します
+func startFakeRedis(t *testing.T) (*fakeRedis, string) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	s := &fakeRedis{values: map[string]string{}, expires: map[string]time.Time{}, ln: ln}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	t.Cleanup(func() { ln.Close() })
+	return s, "redis://" + ln.Addr().String()
+}
+
+func (s *fakeRedis) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	for {
+		args, err := readCommand(r)
+		if err != nil {
+			return
+		}
+		io.WriteString(conn, s.handle(args))
+	}
+}
+
+// readCommand は RESP の配列（*N\r\n$len\r\narg\r\n...）を1つ読み込みます
+func readCommand(r *bufio.Reader) ([]string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(line, "*")))
+	if err != nil {
+		return nil, err
+	}
+	args := make([]string, n)
+	for i := range args {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		size, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(line, "$")))
+		if err != nil {
+			return nil, err
+		}
+		buf := make([]byte, size+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		args[i] = string(buf[:size])
+	}
+	return args, nil
+}
+
+func (s *fakeRedis) handle(args []string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch strings.ToUpper(args[0]) {
+	case "PING":
+		return "+PONG\r\n"
+	case "GET":
+		if exp, ok := s.expires[args[1]]; ok && !time.Now().Before(exp) {
+			delete(s.values, args[1])
+			delete(s.expires, args[1])
+		}
+		v, ok := s.values[args[1]]
+		if !ok {
+			return "$-1\r\n"
+		}
+		return fmt.Sprintf("$%d\r\n%s\r\n", len(v), v)
+	case "SET":
+		s.values[args[1]] = args[2]
+		delete(s.expires, args[1])
+		if len(args) == 5 && strings.ToUpper(args[3]) == "PX" {
+			ms, _ := strconv.Atoi(args[4])
+			s.expires[args[1]] = time.Now().Add(time.Duration(ms) * time.Millisecond)
+		}
+		return "+OK\r\n"
+	case "DEL":
+		deleted := 0
+		for _, key := range args[1:] {
+			if _, ok := s.values[key]; ok {
+				deleted++
+			}
+			delete(s.values, key)
+			delete(s.expires, key)
+		}
+		return fmt.Sprintf(":%d\r\n", deleted)
+	default:
+		return "-ERR unknown command\r\n"
+	}
+}
+
+// keys は保存されているキーの一覧です
+func (s *fakeRedis) keys() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var keys []string
+	for k := range s.values {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+func TestRedisCache(t *testing.T) {
+	server, url := startFakeRedis(t)
+	cache := NewRedisCache(url)
+	t.Cleanup(func() { cache.Close() })
+	ctx := context.Background()
+
+	t.Run("保存した値を取得でき、キーには接頭辞が付くこと", func(t *testing.T) {
+		assert.NoError(t, cache.Ping(ctx))
+		assert.NoError(t, cache.Set(ctx, "a", []byte(`{"id":1}`), time.Minute))
+
+		value, ok, err := cache.Get(ctx, "a")
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, `{"id":1}`, string(value))
+		assert.Contains(t, server.keys(), "todo:a")
+	})
+
+	t.Run("存在しないキーと期限切れのキーはミスになること", func(t *testing.T) {
+		_, ok, err := cache.Get(ctx, "missing")
+		assert.NoError(t, err)
+		assert.False(t, ok)
+
+		assert.NoError(t, cache.Set(ctx, "short", []byte("x"), 10*time.Millisecond))
+		time.Sleep(20 * time.Millisecond)
+		_, ok, _ = cache.Get(ctx, "short")
+		assert.False(t, ok)
+	})
+
+	t.Run("複数のキーをまとめて削除できること", func(t *testing.T) {
+		cache.Set(ctx, "b", []byte("1"), time.Minute)
+		cache.Set(ctx, "c", []byte("2"), time.Minute)
+
+		assert.NoError(t, cache.Delete(ctx, "b", "c"))
+
+		_, ok, _ := cache.Get(ctx, "b")
+		assert.False(t, ok)
+		_, ok, _ = cache.Get(ctx, "c")
+		assert.False(t, ok)
+	})
+
+	t.Run("接続できない場合はエラーになること", func(t *testing.T) {
+		ln, _ := net.Listen("tcp", "127.0.0.1:0")
+		addr := ln.Addr().String()
+		ln.Close()
+		down := NewRedisCache("redis://" + addr)
+
+		_, _, err := down.Get(ctx, "a")
+		assert.Error(t, err)
+		assert.Error(t, down.Ping(ctx))
+	})
+}
@@ -0,0 +1,285 @@
+package infrastructure
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"slices"
+	"strings"
+	"time"
+	"todo_app_golang/internal/domain"
+)
+
+const (
+	// emptyPayloadSHA256 は本文が空のリクエストの x-amz-content-sha256 です
+	emptyPayloadSHA256 = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+	// unsignedPayload は本文を署名に含めないことを表します（本文を2回読まずにアップロードするため）
+	unsignedPayload = "UNSIGNED-PAYLOAD"
+)
+
+// S3BlobStoreOptions は S3BlobStore の接続先です
+type S3BlobStoreOptions struct {
+	Endpoint        string // 例: https://s3.ap-northeast-1.amazonaws.com、http://localhost:9000
+	Region          string
+	Bucket          string
+	AccessKeyID     string
+	SecretAccessKey string
+	// PathStyle が true の場合は {endpoint}/{bucket}/{key}、false の場合は {bucket}.{endpoint のホスト}/{key} にアクセスする
+	PathStyle bool
+}
+
+// S3BlobStore は S3 互換のオブジェクトストレージ（AWS S3、MinIO など）に添付ファイルの中身を保存する BlobStore です。
+// SDK は使わず、必要な4つの操作（PUT / GET / HEAD / DELETE）を署名バージョン4で直接呼び出します
+type S3BlobStore struct {
+	client   *http.Client
+	endpoint *url.URL
+	opts     S3BlobStoreOptions
+	now      func() time.Time // テストで署名の日時を固定するために差し替える
+}
+
+// NewS3BlobStore は opts のバケットに保存する S3BlobStore を生成します。接続は最初に使うときに確立します
+func NewS3BlobStore(opts S3BlobStoreOptions) (*S3BlobStore, error) {
+	endpoint, err := url.Parse(opts.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("s3 endpoint: %w", err)
+	}
+	if endpoint.Scheme != "http" && endpoint.Scheme != "https" || endpoint.Host == "" {
+		return nil, fmt.Errorf("s3 endpoint must be an http(s) URL: %q", opts.Endpoint)
+	}
+	// ダウンロードは大きくなりうるため全体のタイムアウトは設けず、リクエストの context で打ち切る
+	return &S3BlobStore{client: &http.Client{}, endpoint: endpoint, opts: opts, now: time.Now}, nil
+}
+
+func (s *S3BlobStore) objectURL(key string) *url.URL {
+	u := *s.endpoint
+	if s.opts.PathStyle {
+		u.Path = path.Join("/", u.Path, s.opts.Bucket, key)
+	} else {
+		u.Host = s.opts.Bucket + "." + u.Host
+		u.Path = path.Join("/", u.Path, key)
+	}
+	return &u
+}
+
+// do は key に対して署名したリクエストを送ります。header は署名の前に設定します
+func (s *S3BlobStore) do(ctx context.Context, method, key string, body io.Reader, size int64, header http.Header) (*http.Response, error) {
+	if err := validateBlobKey(key); err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, method, s.objectURL(key).String(), body)
+	if err != nil {
+		return nil, err
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	payloadHash := emptyPayloadSHA256
+	if body != nil {
+		req.ContentLength = size
+		payloadHash = unsignedPayload
+	}
+	s.sign(req, payloadHash, s.now().UTC())
+	return s.client.Do(req)
+}
+
+// sign はリクエストに署名バージョン4の Authorization ヘッダーを付けます。
+// 署名するヘッダーは host・range・x-amz-* です
+func (s *S3BlobStore) sign(req *http.Request, payloadHash string, t time.Time) {
+	amzDate := t.Format("20060102T150405Z")
+	date := t.Format("20060102")
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		lower := strings.ToLower(name)
+		if lower == "range" || strings.HasPrefix(lower, "x-amz-") {
+			headers[lower] = strings.TrimSpace(strings.Join(values, ","))
+		}
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.Query().Encode(),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	scope := date + "/" + s.opts.Region + "/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hexSHA256(canonicalRequest)
+
+	key := hmacSHA256([]byte("AWS4"+s.opts.SecretAccessKey), date)
+	key = hmacSHA256(key, s.opts.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.opts.AccessKeyID, scope, signedHeaders, signature))
+}
+
+func hexSHA256(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+// s3Error はステータスコードが想定外のレスポンスをエラーにします（本文は先頭だけ含める）
+func s3Error(resp *http.Response, method, key string) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	return fmt.Errorf("s3 %s %s: %s: %s", method, key, resp.Status, strings.TrimSpace(string(body)))
+}
+
+func (s *S3BlobStore) Put(ctx context.Context, key string, r io.Reader, size int64) error {
+	// 本文が空でも Content-Length: 0 を送るため、nil ではない Reader を渡す
+	resp, err := s.do(ctx, http.MethodPut, key, io.LimitReader(r, size), size, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return s3Error(resp, http.MethodPut, key)
+	}
+	return nil
+}
+
+// head は key の大きさを返します
+func (s *S3BlobStore) head(ctx context.Context, key string) (int64, error) {
+	resp, err := s.do(ctx, http.MethodHead, key, nil, 0, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return resp.ContentLength, nil
+	case http.StatusNotFound:
+		return 0, domain.ErrBlobNotFound
+	default:
+		return 0, s3Error(resp, http.MethodHead, key)
+	}
+}
+
+func (s *S3BlobStore) Open(ctx context.Context, key string) (io.ReadSeekCloser, error) {
+	size, err := s.head(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	return &s3Object{ctx: ctx, store: s, key: key, size: size}, nil
+}
+
+func (s *S3BlobStore) Exists(ctx context.Context, key string) (bool, error) {
+	_, err := s.head(ctx, key)
+	if errors.Is(err, domain.ErrBlobNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (s *S3BlobStore) Delete(ctx context.Context, key string) error {
+	resp, err := s.do(ctx, http.MethodDelete, key, nil, 0, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusNoContent, http.StatusNotFound:
+		return nil
+	default:
+		return s3Error(resp, http.MethodDelete, key)
+	}
+}
+
+// s3Object はオブジェクトを必要になった位置から範囲指定の GET で読み込む io.ReadSeekCloser です。
+// http.ServeContent が Range リクエストに応えるとき、必要な範囲だけを取得できます
+type s3Object struct {
+	ctx    context.Context
+	store  *S3BlobStore
+	key    string
+	size   int64
+	offset int64
+	body   io.ReadCloser // offset から読み込み中のレスポンス。Seek すると閉じる
+}
+
+func (o *s3Object) Read(p []byte) (int, error) {
+	if o.offset >= o.size {
+		return 0, io.EOF
+	}
+	if o.body == nil {
+		header := http.Header{"Range": {fmt.Sprintf("bytes=%d-", o.offset)}}
+		resp, err := o.store.do(o.ctx, http.MethodGet, o.key, nil, 0, header)
+		if err != nil {
+			return 0, err
+		}
+		if resp.StatusCode != http.StatusPartialContent && resp.StatusCode != http.StatusOK {
+			defer resp.Body.Close()
+			return 0, s3Error(resp, http.MethodGet, o.key)
+		}
+		// Range に対応していないサーバーは先頭から返すため、読み込み済みの分を読み飛ばす
+		if resp.StatusCode == http.StatusOK && o.offset > 0 {
+			if _, err := io.CopyN(io.Discard, resp.Body, o.offset); err != nil {
+				resp.Body.Close()
+				return 0, err
+			}
+		}
+		o.body = resp.Body
+	}
+	n, err := o.body.Read(p)
+	o.offset += int64(n)
+	return n, err
+}
+
+func (o *s3Object) Seek(offset int64, whence int) (int64, error) {
+	var abs int64
+	switch whence {
+	case io.SeekStart:
+		abs = offset
+	case io.SeekCurrent:
+		abs = o.offset + offset
+	case io.SeekEnd:
+		abs = o.size + offset
+	default:
+		return 0, errors.New("s3Object.Seek: invalid whence")
+	}
+	if abs < 0 {
+		return 0, errors.New("s3Object.Seek: negative position")
+	}
+	if abs != o.offset && o.body != nil {
+		o.body.Close()
+		o.body = nil
+	}
+	o.offset = abs
+	return abs, nil
+}
+
+func (o *s3Object) Close() error {
+	if o.body == nil {
+		return nil
+	}
+	err := o.body.Close()
+	o.body = nil
+	return err
+}
@@ -0,0 +1,150 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"todo_app_golang/migrations"
+
+	"github.com/golang-migrate/migrate/v4/database"
+	_ "modernc.org/sqlite" // 直接使わないが、"sqlite" ドライバーを登録するために必要（cgo 不要）
+)
+
+// sqliteDriverName は modernc.org/sqlite が database/sql に登録するドライバーの名前です
+const sqliteDriverName = "sqlite"
+
+// NewSQLiteDB は SQLite のデータベースファイルを開きます（存在しない場合は作成されます）
+func NewSQLiteDB(path string) (*sql.DB, error) {
+	db, err := sql.Open(sqliteDriverName, path)
+	if err != nil {
+		return nil, err
+	}
+	// SQLite は書き込みが1つずつしか行えず、PRAGMA もコネクション単位のため、コネクションは1本に絞る
+	db.SetMaxOpenConns(1)
+
+	for _, pragma := range []string{
+		`PRAGMA journal_mode = WAL`,
+		`PRAGMA busy_timeout = 5000`,
+		`PRAGMA foreign_keys = ON`,
+	} {
+		if _, err := db.Exec(pragma); err != nil {
+			db.Close()
+			return nil, fmt.Errorf("%s: %w", pragma, err)
+		}
+	}
+	return db, nil
+}
+
+// NewSQLiteMigrator は SQLite 用の Migrator を生成します。
+// 単一プロセスでの利用を想定しているため、インスタンス間のロックは取りません
+func NewSQLiteMigrator(db *sql.DB) *Migrator {
+	return &Migrator{
+		db:     db,
+		source: migrations.SQLite,
+		lock: func(context.Context, *sql.Conn) (func(), error) {
+			return func() {}, nil
+		},
+		driver: func(ctx context.Context, conn *sql.Conn) (database.Driver, error) {
+			d := &sqliteMigrateDriver{ctx: ctx, conn: conn}
+			return d, d.ensureVersionTable()
+		},
+	}
+}
+
+// sqliteMigrateDriver は golang-migrate の database.Driver を database/sql の SQLite 上に実装したものです。
+// バージョンは Postgres と同じ形の schema_migrations テーブルに記録します（SchemaVersion で読み取れる）
+type sqliteMigrateDriver struct {
+	ctx  context.Context
+	conn *sql.Conn
+}
+
+func (d *sqliteMigrateDriver) ensureVersionTable() error {
+	_, err := d.conn.ExecContext(d.ctx,
+		`CREATE TABLE IF NOT EXISTS schema_migrations (version INTEGER NOT NULL, dirty BOOLEAN NOT NULL)`)
+	return err
+}
+
+func (d *sqliteMigrateDriver) Open(string) (database.Driver, error) {
+	return nil, errors.New("sqlite migrate driver must be created with NewSQLiteMigrator")
+}
+
+func (d *sqliteMigrateDriver) Close() error  { return nil }
+func (d *sqliteMigrateDriver) Lock() error   { return nil }
+func (d *sqliteMigrateDriver) Unlock() error { return nil }
+
+// Run は1つのマイグレーションファイルをトランザクション内で実行します
+func (d *sqliteMigrateDriver) Run(migration io.Reader) error {
+	query, err := io.ReadAll(migration)
+	if err != nil {
+		return err
+	}
+
+	tx, err := d.conn.BeginTx(d.ctx, nil)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(d.ctx, string(query)); err != nil {
+		tx.Rollback()
+		return &database.Error{OrigErr: err, Err: "migration failed", Query: query}
+	}
+	return tx.Commit()
+}
+
+func (d *sqliteMigrateDriver) SetVersion(version int, dirty bool) error {
+	tx, err := d.conn.BeginTx(d.ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(d.ctx, `DELETE FROM schema_migrations`); err != nil {
+		return err
+	}
+	// golang-migrate と同様に、すべて取り消した（NilVersion）場合で dirty でなければ行を残さない
+	if version >= 0 || (version == database.NilVersion && dirty) {
+		if _, err := tx.ExecContext(d.ctx,
+			`INSERT INTO schema_migrations (version, dirty) VALUES (?, ?)`, version, dirty); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (d *sqliteMigrateDriver) Version() (int, bool, error) {
+	var (
+		version int
+		dirty   bool
+	)
+	err := d.conn.QueryRowContext(d.ctx, `SELECT version, dirty FROM schema_migrations LIMIT 1`).Scan(&version, &dirty)
+	if errors.Is(err, sql.ErrNoRows) {
+		return database.NilVersion, false, nil
+	}
+	return version, dirty, err
+}
+
+// Drop はすべてのテーブルを削除します
+func (d *sqliteMigrateDriver) Drop() error {
+	rows, err := d.conn.QueryContext(d.ctx, `SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%'`)
+	if err != nil {
+		return err
+	}
+	var tables []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			rows.Close()
+			return err
+		}
+		tables = append(tables, name)
+	}
+	rows.Close()
+
+	for _, t := range tables {
+		if _, err := d.conn.ExecContext(d.ctx, fmt.Sprintf(`DROP TABLE IF EXISTS %q`, t)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"todo_app_golang/internal/domain"
+
+	semconv "go.opentelemetry.io/otel/semconv/v1.40.0"
+)
+
+type sqliteAttachmentRepository struct {
+	db *sql.DB
+}
+
+// NewSQLiteAttachmentRepository は SQLite 版の添付ファイルのリポジトリを生成します
+func NewSQLiteAttachmentRepository(db *sql.DB) domain.AttachmentRepository {
+	return &sqliteAttachmentRepository{db: db}
+}
+
+func (r *sqliteAttachmentRepository) Create(ctx context.Context, attachment *domain.Attachment) error {
+	query := `
+		INSERT INTO attachments (todo_id, filename, content_type, size, sha256, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		RETURNING id`
+
+	ctx, span := startSQLiteQuerySpan(ctx, "attachments.Create", query)
+	defer span.End()
+
+	err := r.db.QueryRowContext(ctx, query,
+		attachment.TodoID, attachment.Filename, attachment.ContentType, attachment.Size, attachment.SHA256, attachment.CreatedAt.UTC(),
+	).Scan(&attachment.ID)
+	if err == nil {
+		span.SetAttributes(rowsAffectedKey.Int64(1))
+	}
+	return logQueryError(ctx, "attachments.Create", err)
+}
+
+func (r *sqliteAttachmentRepository) ListByTodo(ctx context.Context, todoID int) ([]*domain.Attachment, error) {
+	query := `SELECT ` + attachmentColumns + ` FROM attachments WHERE todo_id = ? ORDER BY created_at, id`
+
+	ctx, span := startSQLiteQuerySpan(ctx, "attachments.ListByTodo", query)
+	defer span.End()
+
+	rows, err := r.db.QueryContext(ctx, query, todoID)
+	if err != nil {
+		return nil, logQueryError(ctx, "attachments.ListByTodo", err)
+	}
+	attachments, err := scanAttachments(rows)
+	if err != nil {
+		return nil, logQueryError(ctx, "attachments.ListByTodo", err)
+	}
+	span.SetAttributes(semconv.DBResponseReturnedRows(len(attachments)))
+	return attachments, nil
+}
+
+func (r *sqliteAttachmentRepository) GetByID(ctx context.Context, id int) (*domain.Attachment, error) {
+	query := `SELECT ` + attachmentColumns + ` FROM attachments WHERE id = ?`
+
+	ctx, span := startSQLiteQuerySpan(ctx, "attachments.GetByID", query)
+	defer span.End()
+
+	a, err := scanAttachment(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrAttachmentNotFound
+		}
+		return nil, logQueryError(ctx, "attachments.GetByID", err)
+	}
+	return a, nil
+}
+
+func (r *sqliteAttachmentRepository) Delete(ctx context.Context, id int) error {
+	query := `DELETE FROM attachments WHERE id = ?`
+
+	ctx, span := startSQLiteQuerySpan(ctx, "attachments.Delete", query)
+	defer span.End()
+
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return logQueryError(ctx, "attachments.Delete", err)
+	}
+	if rows, err := result.RowsAffected(); err == nil {
+		span.SetAttributes(rowsAffectedKey.Int64(rows))
+	}
+	return nil
+}
+
+func (r *sqliteAttachmentRepository) CountBySHA256(ctx context.Context, sha256 string) (int, error) {
+	query := `SELECT COUNT(*) FROM attachments WHERE sha256 = ?`
+
+	ctx, span := startSQLiteQuerySpan(ctx, "attachments.CountBySHA256", query)
+	defer span.End()
+
+	var n int
+	err := r.db.QueryRowContext(ctx, query, sha256).Scan(&n)
+	return n, logQueryError(ctx, "attachments.CountBySHA256", err)
+}
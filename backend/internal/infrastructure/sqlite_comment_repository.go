@@ -0,0 +1,154 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+	"todo_app_golang/internal/domain"
+
+	semconv "go.opentelemetry.io/otel/semconv/v1.40.0"
+)
+
+type sqliteCommentRepository struct {
+	db *sql.DB
+}
+
+// NewSQLiteCommentRepository は SQLite 版のコメントのリポジトリを生成します。
+// タスクの削除に合わせたコメントの削除には外部キー（NewSQLiteDB で有効にしている）を使います
+func NewSQLiteCommentRepository(db *sql.DB) domain.CommentRepository {
+	return &sqliteCommentRepository{db: db}
+}
+
+func (r *sqliteCommentRepository) Create(ctx context.Context, comment *domain.Comment) error {
+	query := `
+		INSERT INTO comments (todo_id, author, body, created_at, updated_at)
+		VALUES (?1, ?2, ?3, ?4, ?4)
+		RETURNING id`
+
+	ctx, span := startSQLiteQuerySpan(ctx, "comments.Create", query)
+	defer span.End()
+
+	err := r.db.QueryRowContext(ctx, query, comment.TodoID, comment.Author, comment.Body, comment.CreatedAt.UTC()).Scan(&comment.ID)
+	if err == nil {
+		span.SetAttributes(rowsAffectedKey.Int64(1))
+	}
+	return logQueryError(ctx, "comments.Create", err)
+}
+
+func (r *sqliteCommentRepository) ListByTodo(ctx context.Context, todoID int) ([]*domain.Comment, error) {
+	query := `SELECT ` + commentColumns + ` FROM comments WHERE todo_id = ? ORDER BY created_at, id`
+
+	ctx, span := startSQLiteQuerySpan(ctx, "comments.ListByTodo", query)
+	defer span.End()
+
+	rows, err := r.db.QueryContext(ctx, query, todoID)
+	if err != nil {
+		return nil, logQueryError(ctx, "comments.ListByTodo", err)
+	}
+	defer rows.Close()
+
+	comments := []*domain.Comment{}
+	byID := map[int]*domain.Comment{}
+	for rows.Next() {
+		c, err := scanComment(rows)
+		if err != nil {
+			return nil, logQueryError(ctx, "comments.ListByTodo", err)
+		}
+		comments = append(comments, c)
+		byID[c.ID] = c
+	}
+	if err := rows.Err(); err != nil {
+		return nil, logQueryError(ctx, "comments.ListByTodo", err)
+	}
+	// コネクションは1本のため、次のクエリの前に読み終えておく
+	rows.Close()
+	span.SetAttributes(semconv.DBResponseReturnedRows(len(comments)))
+	if len(comments) == 0 {
+		return comments, nil
+	}
+
+	revisionsQuery := `
+		SELECT r.comment_id, r.body, r.edited_at
+		FROM comment_revisions r JOIN comments c ON c.id = r.comment_id
+		WHERE c.todo_id = ?
+		ORDER BY r.id`
+	revisions, err := r.db.QueryContext(ctx, revisionsQuery, todoID)
+	if err != nil {
+		return nil, logQueryError(ctx, "comments.ListByTodo", err)
+	}
+	return comments, logQueryError(ctx, "comments.ListByTodo", scanRevisions(revisions, byID))
+}
+
+func (r *sqliteCommentRepository) GetByID(ctx context.Context, id int) (*domain.Comment, error) {
+	query := `SELECT ` + commentColumns + ` FROM comments WHERE id = ?`
+
+	ctx, span := startSQLiteQuerySpan(ctx, "comments.GetByID", query)
+	defer span.End()
+
+	c, err := scanComment(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrCommentNotFound
+		}
+		return nil, logQueryError(ctx, "comments.GetByID", err)
+	}
+
+	revisions, err := r.db.QueryContext(ctx,
+		`SELECT comment_id, body, edited_at FROM comment_revisions WHERE comment_id = ? ORDER BY id`, id)
+	if err != nil {
+		return nil, logQueryError(ctx, "comments.GetByID", err)
+	}
+	if err := scanRevisions(revisions, map[int]*domain.Comment{id: c}); err != nil {
+		return nil, logQueryError(ctx, "comments.GetByID", err)
+	}
+	return c, nil
+}
+
+func (r *sqliteCommentRepository) UpdateBody(ctx context.Context, id int, body string, editedAt time.Time) error {
+	// SQLite は CTE の中で INSERT できないため、トランザクションで元の本文を履歴に追加してから書き換える
+	query := `UPDATE comments SET body = ?, updated_at = ? WHERE id = ?`
+
+	ctx, span := startSQLiteQuerySpan(ctx, "comments.UpdateBody", query)
+	defer span.End()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return logQueryError(ctx, "comments.UpdateBody", err)
+	}
+	defer tx.Rollback()
+
+	editedAt = editedAt.UTC()
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO comment_revisions (comment_id, body, edited_at) SELECT id, body, ? FROM comments WHERE id = ?`,
+		editedAt, id); err != nil {
+		return logQueryError(ctx, "comments.UpdateBody", err)
+	}
+	result, err := tx.ExecContext(ctx, query, body, editedAt, id)
+	if err != nil {
+		return logQueryError(ctx, "comments.UpdateBody", err)
+	}
+	rows, err := result.RowsAffected()
+	span.SetAttributes(rowsAffectedKey.Int64(rows))
+	if err != nil || rows == 0 {
+		return domain.ErrCommentNotFound
+	}
+	return logQueryError(ctx, "comments.UpdateBody", tx.Commit())
+}
+
+func (r *sqliteCommentRepository) Delete(ctx context.Context, id int) error {
+	// 編集履歴は外部キーの ON DELETE CASCADE で削除される
+	query := `DELETE FROM comments WHERE id = ?`
+
+	ctx, span := startSQLiteQuerySpan(ctx, "comments.Delete", query)
+	defer span.End()
+
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return logQueryError(ctx, "comments.Delete", err)
+	}
+	if rows, err := result.RowsAffected(); err == nil {
+		span.SetAttributes(rowsAffectedKey.Int64(rows))
+	}
+	return nil
+}
@@ -0,0 +1,331 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+	"todo_app_golang/internal/domain"
+
+	semconv "go.opentelemetry.io/otel/semconv/v1.40.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type sqliteTodoRepository struct {
+	db  *sql.DB
+	now func() time.Time
+}
+
+// NewSQLiteTodoRepository は SQLite 版のリポジトリを生成します
+func NewSQLiteTodoRepository(db *sql.DB) domain.TodoRepository {
+	return &sqliteTodoRepository{db: db, now: time.Now}
+}
+
+// sqliteTodoColumns は SELECT するカラムの一覧です（scanSQLiteTodo の順序と合わせる）。
+// COALESCE を使うと日時の型情報が失われ文字列で返るため、updated_at の補完は Go 側で行う。
+// ウォッチャーは JSON の配列にまとめて取得する（並び順は Go 側で揃える）
+const sqliteTodoColumns = `id, title, description, is_completed, priority, due_date, created_at, updated_at, completed_at, ical_uid,
+	owner_id, assignee_id,
+	(SELECT json_group_array(w.user_id) FROM todo_watchers w WHERE w.todo_id = todos.id)`
+
+func scanSQLiteTodo(row rowScanner) (*domain.Todo, error) {
+	t := &domain.Todo{}
+	var priority sql.NullString
+	var updatedAt sql.NullTime
+	var watchers string
+	err := row.Scan(
+		&t.ID, &t.Title, &t.Description, &t.IsCompleted, &priority, &t.DueDate,
+		&t.CreatedAt, &updatedAt, &t.CompletedAt, &t.ICalUID,
+		&t.OwnerID, &t.AssigneeID, &watchers,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(watchers), &t.Watchers); err != nil {
+		return nil, err
+	}
+	slices.Sort(t.Watchers)
+	t.Priority = priority.String
+	t.UpdatedAt = t.CreatedAt
+	if updatedAt.Valid {
+		t.UpdatedAt = updatedAt.Time
+	}
+	return t, nil
+}
+
+// utc は日時を UTC に揃えます。SQLite は日時を文字列で保存するため、並び順を保つにはタイムゾーンを統一する必要がある
+func utc(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	u := t.UTC()
+	return &u
+}
+
+// startSQLiteQuerySpan は SQL 1回分のスパンを開始します。呼び出し側で span.End() してください
+func startSQLiteQuerySpan(ctx context.Context, op, query string) (context.Context, trace.Span) {
+	return startDBSpan(ctx, semconv.DBSystemNameSQLite, op, query)
+}
+
+func (r *sqliteTodoRepository) Create(ctx context.Context, todo *domain.Todo) error {
+	query := `
+		INSERT INTO todos (title, description, is_completed, priority, due_date, created_at, updated_at, ical_uid, completed_at, owner_id, assignee_id)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		RETURNING id`
+
+	ctx, span := startSQLiteQuerySpan(ctx, "todos.Create", query)
+	defer span.End()
+
+	// 完了済みで作成する場合（インポート等）、完了日時が無ければ現在時刻を記録する
+	now := r.now().UTC()
+	createdAt := todo.CreatedAt.UTC()
+	if todo.CreatedAt.IsZero() {
+		createdAt = now
+	}
+	var completedAt *time.Time
+	if todo.IsCompleted {
+		completedAt = utc(todo.CompletedAt)
+		if completedAt == nil {
+			completedAt = &now
+		}
+	}
+
+	err := r.db.QueryRowContext(ctx, query,
+		todo.Title, todo.Description, todo.IsCompleted, priorityOrDefault(todo.Priority), utc(todo.DueDate),
+		createdAt, createdAt, todo.ICalUID, completedAt, todo.OwnerID, todo.AssigneeID,
+	).Scan(&todo.ID)
+	if err != nil {
+		return logQueryError(ctx, "todos.Create", err)
+	}
+	todo.CompletedAt = completedAt
+	span.SetAttributes(rowsAffectedKey.Int64(1))
+	return nil
+}
+
+func (r *sqliteTodoRepository) FetchAll(ctx context.Context) ([]*domain.Todo, error) {
+	query := `SELECT ` + sqliteTodoColumns + ` FROM todos ORDER BY created_at DESC, id DESC`
+
+	ctx, span := startSQLiteQuerySpan(ctx, "todos.FetchAll", query)
+	defer span.End()
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, logQueryError(ctx, "todos.FetchAll", err)
+	}
+	defer rows.Close()
+
+	var todos []*domain.Todo
+	for rows.Next() {
+		t, err := scanSQLiteTodo(rows)
+		if err != nil {
+			return nil, logQueryError(ctx, "todos.FetchAll", err)
+		}
+		todos = append(todos, t)
+	}
+	span.SetAttributes(semconv.DBResponseReturnedRows(len(todos)))
+	return todos, logQueryError(ctx, "todos.FetchAll", rows.Err())
+}
+
+func (r *sqliteTodoRepository) Delete(ctx context.Context, id int) error {
+	query := `DELETE FROM todos WHERE id = ?`
+
+	ctx, span := startSQLiteQuerySpan(ctx, "todos.Delete", query)
+	defer span.End()
+
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return logQueryError(ctx, "todos.Delete", err)
+	}
+	if rows, err := result.RowsAffected(); err == nil {
+		span.SetAttributes(rowsAffectedKey.Int64(rows))
+	}
+	return nil
+}
+
+func (r *sqliteTodoRepository) UpdateStatus(ctx context.Context, id int, isCompleted bool) error {
+	// 完了にした時点の日時を記録し、未完了に戻した場合はクリアする（完了済みを再度完了にしても上書きしない）
+	query := `
+		UPDATE todos
+		SET is_completed = ?1,
+			completed_at = CASE WHEN ?1 THEN COALESCE(completed_at, ?2) END,
+			updated_at = ?2
+		WHERE id = ?3`
+
+	ctx, span := startSQLiteQuerySpan(ctx, "todos.UpdateStatus", query)
+	defer span.End()
+
+	result, err := r.db.ExecContext(ctx, query, isCompleted, r.now().UTC(), id)
+	if err != nil {
+		return logQueryError(ctx, "todos.UpdateStatus", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return logQueryError(ctx, "todos.UpdateStatus", err)
+	}
+	span.SetAttributes(rowsAffectedKey.Int64(rows))
+	if rows == 0 {
+		return sql.ErrNoRows // Postgres 版と同じく、1行も更新されなかったらエラーとする
+	}
+	return nil
+}
+
+func (r *sqliteTodoRepository) GetByID(ctx context.Context, id int) (*domain.Todo, error) {
+	query := `SELECT ` + sqliteTodoColumns + ` FROM todos WHERE id = ?`
+
+	ctx, span := startSQLiteQuerySpan(ctx, "todos.GetByID", query)
+	defer span.End()
+
+	t, err := scanSQLiteTodo(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrTodoNotFound
+		}
+		return nil, logQueryError(ctx, "todos.GetByID", err)
+	}
+	return t, nil
+}
+
+func (r *sqliteTodoRepository) GetByIDs(ctx context.Context, ids []int) ([]*domain.Todo, error) {
+	if len(ids) == 0 {
+		return []*domain.Todo{}, nil
+	}
+	query := `SELECT ` + sqliteTodoColumns + ` FROM todos WHERE id IN (?` + strings.Repeat(", ?", len(ids)-1) + `)`
+
+	ctx, span := startSQLiteQuerySpan(ctx, "todos.GetByIDs", query)
+	defer span.End()
+
+	args := make([]any, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, logQueryError(ctx, "todos.GetByIDs", err)
+	}
+	defer rows.Close()
+
+	todos := make([]*domain.Todo, 0, len(ids))
+	for rows.Next() {
+		t, err := scanSQLiteTodo(rows)
+		if err != nil {
+			return nil, logQueryError(ctx, "todos.GetByIDs", err)
+		}
+		todos = append(todos, t)
+	}
+	span.SetAttributes(semconv.DBResponseReturnedRows(len(todos)))
+	return todos, logQueryError(ctx, "todos.GetByIDs", rows.Err())
+}
+
+func (r *sqliteTodoRepository) Update(ctx context.Context, todo *domain.Todo) error {
+	query := `
+		UPDATE todos
+		SET title = ?1, description = ?2, is_completed = ?3, priority = ?4, due_date = ?5, ical_uid = ?6,
+			completed_at = CASE WHEN ?3 THEN COALESCE(?8, completed_at, ?9) END,
+			updated_at = ?9
+		WHERE id = ?7`
+
+	ctx, span := startSQLiteQuerySpan(ctx, "todos.Update", query)
+	defer span.End()
+
+	result, err := r.db.ExecContext(ctx, query,
+		todo.Title, todo.Description, todo.IsCompleted, priorityOrDefault(todo.Priority), utc(todo.DueDate), todo.ICalUID,
+		todo.ID, utc(todo.CompletedAt), r.now().UTC(),
+	)
+	if err != nil {
+		return logQueryError(ctx, "todos.Update", err)
+	}
+
+	rows, err := result.RowsAffected()
+	span.SetAttributes(rowsAffectedKey.Int64(rows))
+	if err != nil || rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *sqliteTodoRepository) GetByICalUID(ctx context.Context, uid string) (*domain.Todo, error) {
+	query := `SELECT ` + sqliteTodoColumns + ` FROM todos WHERE ical_uid = ?`
+
+	ctx, span := startSQLiteQuerySpan(ctx, "todos.GetByICalUID", query)
+	defer span.End()
+
+	t, err := scanSQLiteTodo(r.db.QueryRowContext(ctx, query, uid))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrTodoNotFound
+		}
+		return nil, logQueryError(ctx, "todos.GetByICalUID", err)
+	}
+	return t, nil
+}
+
+func (r *sqliteTodoRepository) SetAssignee(ctx context.Context, id int, assigneeID *string) error {
+	query := `UPDATE todos SET assignee_id = ?, updated_at = ? WHERE id = ?`
+
+	ctx, span := startSQLiteQuerySpan(ctx, "todos.SetAssignee", query)
+	defer span.End()
+
+	result, err := r.db.ExecContext(ctx, query, assigneeID, r.now().UTC(), id)
+	if err != nil {
+		return logQueryError(ctx, "todos.SetAssignee", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return logQueryError(ctx, "todos.SetAssignee", err)
+	}
+	span.SetAttributes(rowsAffectedKey.Int64(rows))
+	if rows == 0 {
+		return domain.ErrTodoNotFound
+	}
+	return nil
+}
+
+func (r *sqliteTodoRepository) AddWatcher(ctx context.Context, id int, userID string) error {
+	query := `INSERT OR IGNORE INTO todo_watchers (todo_id, user_id) SELECT id, ?2 FROM todos WHERE id = ?1`
+	return r.changeWatcher(ctx, "todos.AddWatcher", query, id, userID)
+}
+
+func (r *sqliteTodoRepository) RemoveWatcher(ctx context.Context, id int, userID string) error {
+	query := `DELETE FROM todo_watchers WHERE todo_id = ?1 AND user_id = ?2`
+	return r.changeWatcher(ctx, "todos.RemoveWatcher", query, id, userID)
+}
+
+// changeWatcher はウォッチャーを追加・削除する query を実行します。
+// 1行も変わらなかった場合は、追加済み（いない）のかタスクが無いのかをタスクの有無で見分けます
+func (r *sqliteTodoRepository) changeWatcher(ctx context.Context, op, query string, id int, userID string) error {
+	ctx, span := startSQLiteQuerySpan(ctx, op, query)
+	defer span.End()
+
+	result, err := r.db.ExecContext(ctx, query, id, userID)
+	if err != nil {
+		return logQueryError(ctx, op, err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return logQueryError(ctx, op, err)
+	}
+	span.SetAttributes(rowsAffectedKey.Int64(rows))
+	if rows > 0 {
+		return nil
+	}
+
+	var exists bool
+	if err := r.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM todos WHERE id = ?)`, id).Scan(&exists); err != nil {
+		return logQueryError(ctx, op, err)
+	}
+	if !exists {
+		return domain.ErrTodoNotFound
+	}
+	return nil
+}
+
+// priorityOrDefault は優先度が未設定の場合にカラムの既定値（medium）を返します
+func priorityOrDefault(priority string) string {
+	if priority == "" {
+		return "medium"
+	}
+	return priority
+}
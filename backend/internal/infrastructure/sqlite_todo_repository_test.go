@@ -0,0 +1,75 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"todo_app_golang/internal/domain"
+	"todo_app_golang/internal/domain/repositorytest"
+	"todo_app_golang/migrations"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// setupSQLiteRepository は一時ディレクトリに SQLite のデータベースを作成し、マイグレーション済みのリポジトリを返します
+func setupSQLiteRepository(t *testing.T) (domain.TodoRepository, *Migrator) {
+	t.Helper()
+	db, migrator := setupSQLiteDB(t)
+	return NewSQLiteTodoRepository(db), migrator
+}
+
+// setupSQLiteDB は一時ディレクトリにマイグレーション済みの SQLite のデータベースを作成します
+func setupSQLiteDB(t *testing.T) (*sql.DB, *Migrator) {
+	t.Helper()
+	db, err := NewSQLiteDB(filepath.Join(t.TempDir(), "todo.db"))
+	if err != nil {
+		t.Fatalf("Failed to open SQLite: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	migrator := NewSQLiteMigrator(db)
+	if err := migrator.Up(context.Background()); err != nil {
+		t.Fatalf("Failed to run migrations: %v", err)
+	}
+	return db, migrator
+}
+
+func TestSQLiteMigrator(t *testing.T) {
+	_, migrator := setupSQLiteRepository(t)
+	ctx := context.Background()
+
+	version, dirty, err := migrator.Version(ctx)
+	assert.NoError(t, err)
+	assert.False(t, dirty)
+	assert.Equal(t, migrations.LatestSQLiteVersion(), version)
+
+	assert.NoError(t, migrator.Down(ctx, 0))
+	version, _, err = migrator.Version(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(0), version)
+
+	assert.NoError(t, migrator.Up(ctx))
+	assert.NoError(t, migrator.Up(ctx), "適用済みの場合は何もしないこと")
+}
+
+func TestSQLiteTodoRepository(t *testing.T) {
+	repositorytest.TodoRepositoryContract(t, func(t *testing.T) domain.TodoRepository {
+		repo, _ := setupSQLiteRepository(t)
+		return repo
+	})
+}
+
+func TestSQLiteCommentRepository(t *testing.T) {
+	repositorytest.CommentRepositoryContract(t, func(t *testing.T) (domain.TodoRepository, domain.CommentRepository) {
+		db, _ := setupSQLiteDB(t)
+		return NewSQLiteTodoRepository(db), NewSQLiteCommentRepository(db)
+	})
+}
+
+func TestSQLiteAttachmentRepository(t *testing.T) {
+	repositorytest.AttachmentRepositoryContract(t, func(t *testing.T) (domain.TodoRepository, domain.AttachmentRepository) {
+		db, _ := setupSQLiteDB(t)
+		return NewSQLiteTodoRepository(db), NewSQLiteAttachmentRepository(db)
+	})
+}
@@ -0,0 +1,203 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"time"
+	"todo_app_golang/internal/domain"
+)
+
+type postgresStatsRepository struct {
+	db *sql.DB
+}
+
+// NewStatsRepository は Postgres 版の集計リポジトリを生成します
+func NewStatsRepository(db *sql.DB) domain.StatsRepository {
+	return &postgresStatsRepository{db: db}
+}
+
+func (r *postgresStatsRepository) Stats(ctx context.Context, q domain.StatsQuery) (*domain.Stats, error) {
+	_, todayEnd := q.TodayRange()
+	_, weekEnd := q.WeekRange()
+
+	// is_completed は NULL を許容するため IS TRUE / IS NOT TRUE で判定する。
+	// 今日・今週が期限の件数は、期限切れと重複しないよう現在時刻以降のものを数える
+	query := `
+		SELECT
+			COUNT(*),
+			COUNT(*) FILTER (WHERE is_completed IS TRUE),
+			COUNT(*) FILTER (WHERE priority = 'low'),
+			COUNT(*) FILTER (WHERE priority = 'medium'),
+			COUNT(*) FILTER (WHERE priority = 'high'),
+			COUNT(*) FILTER (WHERE is_completed IS NOT TRUE AND due_date < $1),
+			COUNT(*) FILTER (WHERE is_completed IS NOT TRUE AND due_date >= $1 AND due_date < $2),
+			COUNT(*) FILTER (WHERE is_completed IS NOT TRUE AND due_date >= $1 AND due_date < $3)
+		FROM todos`
+
+	stats := &domain.Stats{}
+	var low, medium, high int
+	err := r.db.QueryRowContext(ctx, query, q.Now, todayEnd, weekEnd).Scan(
+		&stats.Total, &stats.Completed, &low, &medium, &high,
+		&stats.Overdue, &stats.DueToday, &stats.DueThisWeek,
+	)
+	if err != nil {
+		return nil, logQueryError(ctx, "stats.Stats", err)
+	}
+	stats.ByPriority = map[string]int{"low": low, "medium": medium, "high": high}
+
+	stats.CompletedPerDay, err = r.completedPerDay(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// completedPerDay は期間内の日別完了件数を返します（完了が0件の日も含む）
+func (r *postgresStatsRepository) completedPerDay(ctx context.Context, q domain.StatsQuery) ([]domain.DailyCount, error) {
+	query := `
+		SELECT to_char(d, 'YYYY-MM-DD'), COUNT(t.id)
+		FROM generate_series($1::date, $2::date, interval '1 day') AS d
+		LEFT JOIN todos t
+			ON (t.completed_at AT TIME ZONE $3)::date = d::date
+		GROUP BY d
+		ORDER BY d`
+
+	rows, err := r.db.QueryContext(ctx, query,
+		q.From.Format("2006-01-02"), q.To.Format("2006-01-02"), q.Location.String(),
+	)
+	if err != nil {
+		return nil, logQueryError(ctx, "stats.CompletedPerDay", err)
+	}
+	defer rows.Close()
+
+	counts := []domain.DailyCount{}
+	for rows.Next() {
+		var c domain.DailyCount
+		if err := rows.Scan(&c.Date, &c.Count); err != nil {
+			return nil, err
+		}
+		counts = append(counts, c)
+	}
+	return counts, rows.Err()
+}
+
+func (r *postgresStatsRepository) LeadTimes(ctx context.Context, q domain.StatsQuery) (*domain.LeadTimeStats, error) {
+	start, end := q.CompletedRange()
+	result := &domain.LeadTimeStats{
+		ByPriority: map[string]domain.LeadTime{},
+		ByProject:  map[string]domain.LeadTime{},
+	}
+
+	// 全体と優先度別を GROUPING SETS でまとめて集計する（全体の行は priority が NULL）
+	query := `
+		WITH completed AS (
+			SELECT priority, title, EXTRACT(EPOCH FROM completed_at - created_at) / 3600 AS hours
+			FROM todos
+			WHERE completed_at >= $1 AND completed_at < $2
+		)
+		SELECT GROUPING(priority) = 1, COALESCE(priority, ''), COUNT(*),
+			COALESCE(percentile_cont(0.5) WITHIN GROUP (ORDER BY hours), 0),
+			COALESCE(percentile_cont(0.9) WITHIN GROUP (ORDER BY hours), 0)
+		FROM completed
+		GROUP BY GROUPING SETS ((), (priority))`
+
+	rows, err := r.db.QueryContext(ctx, query, start, end)
+	if err != nil {
+		return nil, logQueryError(ctx, "stats.LeadTimes", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			overall  bool
+			priority string
+			lt       domain.LeadTime
+		)
+		if err := rows.Scan(&overall, &priority, &lt.Count, &lt.MedianHours, &lt.P90Hours); err != nil {
+			return nil, err
+		}
+		if overall {
+			result.Overall = lt
+		} else {
+			result.ByPriority[priority] = lt
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	result.ByProject, err = r.leadTimesByProject(ctx, start, end)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// leadTimesByProject はタイトル中の +project ごとにリードタイムを集計します
+func (r *postgresStatsRepository) leadTimesByProject(ctx context.Context, start, end time.Time) (map[string]domain.LeadTime, error) {
+	query := `
+		WITH completed AS (
+			SELECT id, title, EXTRACT(EPOCH FROM completed_at - created_at) / 3600 AS hours
+			FROM todos
+			WHERE completed_at >= $1 AND completed_at < $2
+		)
+		SELECT p.project, COUNT(*),
+			percentile_cont(0.5) WITHIN GROUP (ORDER BY c.hours),
+			percentile_cont(0.9) WITHIN GROUP (ORDER BY c.hours)
+		FROM completed c
+		CROSS JOIN LATERAL (
+			SELECT DISTINCT m[1] AS project
+			FROM regexp_matches(c.title, '(?:^|\s)\+(\S+)', 'g') AS m
+		) p
+		GROUP BY p.project`
+
+	rows, err := r.db.QueryContext(ctx, query, start, end)
+	if err != nil {
+		return nil, logQueryError(ctx, "stats.LeadTimesByProject", err)
+	}
+	defer rows.Close()
+
+	byProject := map[string]domain.LeadTime{}
+	for rows.Next() {
+		var (
+			project string
+			lt      domain.LeadTime
+		)
+		if err := rows.Scan(&project, &lt.Count, &lt.MedianHours, &lt.P90Hours); err != nil {
+			return nil, err
+		}
+		byProject[project] = lt
+	}
+	return byProject, rows.Err()
+}
+
+func (r *postgresStatsRepository) WeeklyThroughput(ctx context.Context, q domain.StatsQuery) ([]domain.WeeklyCount, error) {
+	// date_trunc('week', ...) は月曜始まりの週を返す
+	query := `
+		SELECT to_char(w, 'YYYY-MM-DD'), COUNT(t.id)
+		FROM generate_series(
+			date_trunc('week', $1::date), date_trunc('week', $2::date), interval '1 week'
+		) AS w
+		LEFT JOIN todos t
+			ON date_trunc('week', t.completed_at AT TIME ZONE $3) = w
+		GROUP BY w
+		ORDER BY w`
+
+	rows, err := r.db.QueryContext(ctx, query,
+		q.From.Format("2006-01-02"), q.To.Format("2006-01-02"), q.Location.String(),
+	)
+	if err != nil {
+		return nil, logQueryError(ctx, "stats.WeeklyThroughput", err)
+	}
+	defer rows.Close()
+
+	counts := []domain.WeeklyCount{}
+	for rows.Next() {
+		var c domain.WeeklyCount
+		if err := rows.Scan(&c.WeekStart, &c.Count); err != nil {
+			return nil, err
+		}
+		counts = append(counts, c)
+	}
+	return counts, rows.Err()
+}
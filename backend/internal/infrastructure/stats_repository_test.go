@@ -0,0 +1,113 @@
+package infrastructure
+
+import (
+	"context"
+	"testing"
+	"time"
+	"todo_app_golang/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatsRepository_Stats(t *testing.T) {
+	setupRepository(t)
+	repo := NewStatsRepository(testDB)
+	ctx := context.Background()
+
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	ptr := func(t time.Time) *time.Time { return &t }
+
+	insertTodo(t, "期限切れ", "high", ptr(now.Add(-48*time.Hour)), now, nil)
+	insertTodo(t, "今日が期限", "medium", ptr(now.Add(2*time.Hour)), now, nil)
+	insertTodo(t, "今週が期限", "low", ptr(now.Add(72*time.Hour)), now, nil)
+	insertTodo(t, "完了済み", "high", ptr(now.Add(-48*time.Hour)), now, ptr(now.Add(-24*time.Hour)))
+
+	stats, err := repo.Stats(ctx, domain.StatsQuery{
+		Now:      now,
+		From:     now.AddDate(0, 0, -2),
+		To:       now,
+		Location: time.UTC,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 4, stats.Total)
+	assert.Equal(t, 1, stats.Completed)
+	assert.Equal(t, map[string]int{"low": 1, "medium": 1, "high": 2}, stats.ByPriority)
+	assert.Equal(t, 1, stats.Overdue)
+	assert.Equal(t, 1, stats.DueToday)
+	assert.Equal(t, 2, stats.DueThisWeek)
+	assert.Equal(t, []domain.DailyCount{
+		{Date: "2026-10-13", Count: 0},
+		{Date: "2026-10-14", Count: 1},
+		{Date: "2026-10-15", Count: 0},
+	}, stats.CompletedPerDay)
+}
+
+func TestStatsRepository_LeadTimes(t *testing.T) {
+	setupRepository(t)
+	repo := NewStatsRepository(testDB)
+	ctx := context.Background()
+
+	created := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	ptr := func(t time.Time) *time.Time { return &t }
+
+	insertTodo(t, "A +仕事", "high", nil, created, ptr(created.Add(10*time.Hour)))
+	insertTodo(t, "B +仕事 +家", "high", nil, created, ptr(created.Add(20*time.Hour)))
+	insertTodo(t, "C", "low", nil, created, ptr(created.Add(30*time.Hour)))
+	insertTodo(t, "未完了 +仕事", "low", nil, created, nil)
+	// 期間外に完了したものは含まれない
+	insertTodo(t, "D", "low", nil, created, ptr(created.AddDate(0, 1, 0)))
+
+	stats, err := repo.LeadTimes(ctx, domain.StatsQuery{
+		From:     created,
+		To:       created.AddDate(0, 0, 7),
+		Location: time.UTC,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, stats.Overall.Count)
+	assert.InDelta(t, 20, stats.Overall.MedianHours, 0.01)
+	assert.InDelta(t, 28, stats.Overall.P90Hours, 0.01)
+	assert.Equal(t, 2, stats.ByPriority["high"].Count)
+	assert.InDelta(t, 15, stats.ByPriority["high"].MedianHours, 0.01)
+	assert.Equal(t, 1, stats.ByPriority["low"].Count)
+	assert.Equal(t, 2, stats.ByProject["仕事"].Count)
+	assert.Equal(t, 1, stats.ByProject["家"].Count)
+}
+
+func TestStatsRepository_WeeklyThroughput(t *testing.T) {
+	setupRepository(t)
+	repo := NewStatsRepository(testDB)
+	ctx := context.Background()
+
+	created := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+	ptr := func(t time.Time) *time.Time { return &t }
+
+	// 2026-10-05, 2026-10-12 はいずれも月曜日
+	insertTodo(t, "A", "low", nil, created, ptr(time.Date(2026, 10, 6, 12, 0, 0, 0, time.UTC)))
+	insertTodo(t, "B", "low", nil, created, ptr(time.Date(2026, 10, 11, 12, 0, 0, 0, time.UTC)))
+	insertTodo(t, "C", "low", nil, created, ptr(time.Date(2026, 10, 20, 12, 0, 0, 0, time.UTC)))
+
+	counts, err := repo.WeeklyThroughput(ctx, domain.StatsQuery{
+		From:     time.Date(2026, 10, 7, 0, 0, 0, 0, time.UTC),
+		To:       time.Date(2026, 10, 21, 0, 0, 0, 0, time.UTC),
+		Location: time.UTC,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.WeeklyCount{
+		{WeekStart: "2026-10-05", Count: 2},
+		{WeekStart: "2026-10-12", Count: 0},
+		{WeekStart: "2026-10-19", Count: 1},
+	}, counts)
+}
+
+// insertTodo はテストデータを直接 INSERT します（completedAt が nil の場合は未完了）
+func insertTodo(t *testing.T, title, priority string, due *time.Time, createdAt time.Time, completedAt *time.Time) {
+	t.Helper()
+	_, err := testDB.Exec(`INSERT INTO todos (title, description, is_completed, priority, due_date, created_at, completed_at)
+		VALUES ($1, '', $2, $3, $4, $5, $6)`, title, completedAt != nil, priority, due, createdAt, completedAt)
+	if err != nil {
+		t.Fatalf("テストデータの作成に失敗しました: %v", err)
+	}
+}
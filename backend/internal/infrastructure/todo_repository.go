@@ -4,6 +4,9 @@ import (
 	"context"
 	"database/sql"
 	"todo_app_golang/internal/domain"
+
+	"github.com/lib/pq"
+	semconv "go.opentelemetry.io/otel/semconv/v1.40.0"
 )
 
 type postgresTodoRepository struct {
@@ -15,61 +18,116 @@ func NewTodoRepository(db *sql.DB) domain.TodoRepository {
 	return &postgresTodoRepository{db: db}
 }
 
+// todoColumns は SELECT するカラムの一覧です（scanTodo の順序と合わせる）。ウォッチャーは ID 順の配列にまとめて取得する
+const todoColumns = `id, title, description, is_completed, priority, due_date, created_at, COALESCE(updated_at, created_at), completed_at, ical_uid,
+	owner_id, assignee_id,
+	COALESCE((SELECT array_agg(w.user_id ORDER BY w.user_id) FROM todo_watchers w WHERE w.todo_id = todos.id), '{}')`
+
+// rowScanner は *sql.Row と *sql.Rows の共通部分です
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanTodo(row rowScanner) (*domain.Todo, error) {
+	t := &domain.Todo{}
+	var watchers pq.StringArray
+	err := row.Scan(
+		&t.ID, &t.Title, &t.Description, &t.IsCompleted, &t.Priority, &t.DueDate,
+		&t.CreatedAt, &t.UpdatedAt, &t.CompletedAt, &t.ICalUID,
+		&t.OwnerID, &t.AssigneeID, &watchers,
+	)
+	if err != nil {
+		return nil, err
+	}
+	t.Watchers = append([]string{}, watchers...)
+	return t, nil
+}
+
 func (r *postgresTodoRepository) Create(ctx context.Context, todo *domain.Todo) error {
-	// $1~$5 を使用し、RETURNING で ID と時間情報を取得
+	// 完了済みで作成する場合（インポート等）、完了日時が無ければ現在時刻を記録する
+	// RETURNING で ID と完了日時を取得
 	query := `
-		INSERT INTO todos (title, description, is_completed, priority, due_date, created_at) 
-		VALUES ($1, $2, $3, $4, $5, $6) 
-		RETURNING id`
+		INSERT INTO todos (title, description, is_completed, priority, due_date, created_at, ical_uid, completed_at, owner_id, assignee_id) 
+		VALUES ($1, $2, $3, $4, $5, $6, $7, CASE WHEN $3 THEN COALESCE($8, CURRENT_TIMESTAMP) END, $9, $10) 
+		RETURNING id, completed_at`
 
-	err := r.db.QueryRowContext(ctx, query,
-		todo.Title, todo.Description, todo.IsCompleted, todo.Priority, todo.DueDate, todo.CreatedAt,
-	).Scan(&todo.ID)
+	ctx, span := startQuerySpan(ctx, "todos.Create", query)
+	defer span.End()
 
-	return err
+	err := r.db.QueryRowContext(ctx, query,
+		todo.Title, todo.Description, todo.IsCompleted, todo.Priority, todo.DueDate, todo.CreatedAt, todo.ICalUID, todo.CompletedAt,
+		todo.OwnerID, todo.AssigneeID,
+	).Scan(&todo.ID, &todo.CompletedAt)
+	if err == nil {
+		span.SetAttributes(rowsAffectedKey.Int64(1))
+	}
+	return logQueryError(ctx, "todos.Create", err)
 }
 
 func (r *postgresTodoRepository) FetchAll(ctx context.Context) ([]*domain.Todo, error) {
-	query := `SELECT id, title, description, is_completed, priority, due_date, created_at FROM todos ORDER BY created_at DESC`
+	query := `SELECT ` + todoColumns + ` FROM todos ORDER BY created_at DESC`
+
+	ctx, span := startQuerySpan(ctx, "todos.FetchAll", query)
+	defer span.End()
+
 	rows, err := r.db.QueryContext(ctx, query)
 	if err != nil {
-		return nil, err
+		return nil, logQueryError(ctx, "todos.FetchAll", err)
 	}
 	defer rows.Close()
 
 	var todos []*domain.Todo
 	for rows.Next() {
-		t := &domain.Todo{}
-		// Scanの順序をSELECTと合わせる
-		err := rows.Scan(&t.ID, &t.Title, &t.Description, &t.IsCompleted, &t.Priority, &t.DueDate, &t.CreatedAt)
+		t, err := scanTodo(rows)
 		if err != nil {
-			return nil, err
+			return nil, logQueryError(ctx, "todos.FetchAll", err)
 		}
 		todos = append(todos, t)
 	}
+	span.SetAttributes(semconv.DBResponseReturnedRows(len(todos)))
 	return todos, nil
 }
 
 func (r *postgresTodoRepository) Delete(ctx context.Context, id int) error {
 	query := `DELETE FROM todos WHERE id = $1`
-	_, err := r.db.ExecContext(ctx, query, id) // Exec ではなく ExecContext を使うのがベスト
-	return err
+
+	ctx, span := startQuerySpan(ctx, "todos.Delete", query)
+	defer span.End()
+
+	result, err := r.db.ExecContext(ctx, query, id) // Exec ではなく ExecContext を使うのがベスト
+	if err != nil {
+		return logQueryError(ctx, "todos.Delete", err)
+	}
+	if rows, err := result.RowsAffected(); err == nil {
+		span.SetAttributes(rowsAffectedKey.Int64(rows))
+	}
+	return nil
 }
 
 func (r *postgresTodoRepository) UpdateStatus(ctx context.Context, id int, isCompleted bool) error {
-	query := `UPDATE todos SET is_completed = $1 WHERE id = $2`
+	// 完了にした時点の日時を記録し、未完了に戻した場合はクリアする（完了済みを再度完了にしても上書きしない）
+	query := `
+		UPDATE todos 
+		SET is_completed = $1,
+			completed_at = CASE WHEN $1 THEN COALESCE(completed_at, CURRENT_TIMESTAMP) END,
+			updated_at = CURRENT_TIMESTAMP 
+		WHERE id = $2`
+
+	ctx, span := startQuerySpan(ctx, "todos.UpdateStatus", query)
+	defer span.End()
 
 	// ExecContext を使用してクエリを実行
 	result, err := r.db.ExecContext(ctx, query, isCompleted, id)
 	if err != nil {
-		return err
+		return logQueryError(ctx, "todos.UpdateStatus", err)
 	}
 
 	// 念のため、更新された行数を確認（存在しないIDが指定された場合のケア）
 	rows, err := result.RowsAffected()
 	if err != nil {
-		return err
+		return logQueryError(ctx, "todos.UpdateStatus", err)
 	}
+	span.SetAttributes(rowsAffectedKey.Int64(rows))
 	if rows == 0 {
 		return sql.ErrNoRows // 1行も更新されなかったらエラーとする
 	}
@@ -78,38 +136,156 @@ func (r *postgresTodoRepository) UpdateStatus(ctx context.Context, id int, isCom
 }
 
 func (r *postgresTodoRepository) GetByID(ctx context.Context, id int) (*domain.Todo, error) {
-	t := &domain.Todo{}
-	query := `SELECT id, title, description, is_completed, priority, due_date, created_at FROM todos WHERE id = $1`
+	query := `SELECT ` + todoColumns + ` FROM todos WHERE id = $1`
 
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&t.ID, &t.Title, &t.Description, &t.IsCompleted, &t.Priority, &t.DueDate, &t.CreatedAt,
-	)
+	ctx, span := startQuerySpan(ctx, "todos.GetByID", query)
+	defer span.End()
+
+	t, err := scanTodo(r.db.QueryRowContext(ctx, query, id))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			// DB固有のエラーをドメインエラーに変換して返す
 			return nil, domain.ErrTodoNotFound
 		}
-		return nil, err
+		return nil, logQueryError(ctx, "todos.GetByID", err)
 	}
 	return t, nil
 }
 
+func (r *postgresTodoRepository) GetByIDs(ctx context.Context, ids []int) ([]*domain.Todo, error) {
+	if len(ids) == 0 {
+		return []*domain.Todo{}, nil
+	}
+	query := `SELECT ` + todoColumns + ` FROM todos WHERE id = ANY($1)`
+
+	ctx, span := startQuerySpan(ctx, "todos.GetByIDs", query)
+	defer span.End()
+
+	params := make(pq.Int64Array, len(ids))
+	for i, id := range ids {
+		params[i] = int64(id)
+	}
+	rows, err := r.db.QueryContext(ctx, query, params)
+	if err != nil {
+		return nil, logQueryError(ctx, "todos.GetByIDs", err)
+	}
+	defer rows.Close()
+
+	todos := make([]*domain.Todo, 0, len(ids))
+	for rows.Next() {
+		t, err := scanTodo(rows)
+		if err != nil {
+			return nil, logQueryError(ctx, "todos.GetByIDs", err)
+		}
+		todos = append(todos, t)
+	}
+	span.SetAttributes(semconv.DBResponseReturnedRows(len(todos)))
+	return todos, logQueryError(ctx, "todos.GetByIDs", rows.Err())
+}
+
 func (r *postgresTodoRepository) Update(ctx context.Context, todo *domain.Todo) error {
 	query := `
 		UPDATE todos 
-		SET title = $1, description = $2, is_completed = $3, priority = $4, due_date = $5 
-		WHERE id = $6`
+		SET title = $1, description = $2, is_completed = $3, priority = $4, due_date = $5, ical_uid = $6,
+			completed_at = CASE WHEN $3 THEN COALESCE($8, completed_at, CURRENT_TIMESTAMP) END,
+			updated_at = CURRENT_TIMESTAMP 
+		WHERE id = $7`
+
+	ctx, span := startQuerySpan(ctx, "todos.Update", query)
+	defer span.End()
 
 	result, err := r.db.ExecContext(ctx, query,
-		todo.Title, todo.Description, todo.IsCompleted, todo.Priority, todo.DueDate, todo.ID,
+		todo.Title, todo.Description, todo.IsCompleted, todo.Priority, todo.DueDate, todo.ICalUID, todo.ID, todo.CompletedAt,
 	)
 	if err != nil {
-		return err
+		return logQueryError(ctx, "todos.Update", err)
 	}
 
 	rows, err := result.RowsAffected()
+	span.SetAttributes(rowsAffectedKey.Int64(rows))
 	if err != nil || rows == 0 {
 		return sql.ErrNoRows
 	}
 	return nil
 }
+
+func (r *postgresTodoRepository) GetByICalUID(ctx context.Context, uid string) (*domain.Todo, error) {
+	query := `SELECT ` + todoColumns + ` FROM todos WHERE ical_uid = $1`
+
+	ctx, span := startQuerySpan(ctx, "todos.GetByICalUID", query)
+	defer span.End()
+
+	t, err := scanTodo(r.db.QueryRowContext(ctx, query, uid))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrTodoNotFound
+		}
+		return nil, logQueryError(ctx, "todos.GetByICalUID", err)
+	}
+	return t, nil
+}
+
+func (r *postgresTodoRepository) SetAssignee(ctx context.Context, id int, assigneeID *string) error {
+	query := `UPDATE todos SET assignee_id = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2`
+
+	ctx, span := startQuerySpan(ctx, "todos.SetAssignee", query)
+	defer span.End()
+
+	result, err := r.db.ExecContext(ctx, query, assigneeID, id)
+	if err != nil {
+		return logQueryError(ctx, "todos.SetAssignee", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return logQueryError(ctx, "todos.SetAssignee", err)
+	}
+	span.SetAttributes(rowsAffectedKey.Int64(rows))
+	if rows == 0 {
+		return domain.ErrTodoNotFound
+	}
+	return nil
+}
+
+func (r *postgresTodoRepository) AddWatcher(ctx context.Context, id int, userID string) error {
+	// タスクが無い場合は何も追加せず 0 を返す（追加済みの場合と区別するため、タスクの件数を返す）
+	query := `
+		WITH todo AS (SELECT id FROM todos WHERE id = $1),
+		added AS (
+			INSERT INTO todo_watchers (todo_id, user_id) SELECT id, $2 FROM todo
+			ON CONFLICT DO NOTHING
+		)
+		SELECT COUNT(*) FROM todo`
+
+	ctx, span := startQuerySpan(ctx, "todos.AddWatcher", query)
+	defer span.End()
+
+	var n int
+	if err := r.db.QueryRowContext(ctx, query, id, userID).Scan(&n); err != nil {
+		return logQueryError(ctx, "todos.AddWatcher", err)
+	}
+	if n == 0 {
+		return domain.ErrTodoNotFound
+	}
+	return nil
+}
+
+func (r *postgresTodoRepository) RemoveWatcher(ctx context.Context, id int, userID string) error {
+	query := `
+		WITH todo AS (SELECT id FROM todos WHERE id = $1),
+		removed AS (
+			DELETE FROM todo_watchers WHERE todo_id = $1 AND user_id = $2
+		)
+		SELECT COUNT(*) FROM todo`
+
+	ctx, span := startQuerySpan(ctx, "todos.RemoveWatcher", query)
+	defer span.End()
+
+	var n int
+	if err := r.db.QueryRowContext(ctx, query, id, userID).Scan(&n); err != nil {
+		return logQueryError(ctx, "todos.RemoveWatcher", err)
+	}
+	if n == 0 {
+		return domain.ErrTodoNotFound
+	}
+	return nil
+}
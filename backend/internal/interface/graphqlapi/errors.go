@@ -0,0 +1,63 @@
+package graphqlapi
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"todo_app_golang/internal/domain"
+	"todo_app_golang/internal/logging"
+)
+
+// エラーレスポンスの extensions.code に入れる値です（Apollo Server などと同じ名前を使う）
+const (
+	codeBadUserInput    = "BAD_USER_INPUT"
+	codeNotFound        = "NOT_FOUND"
+	codeUnauthenticated = "UNAUTHENTICATED"
+	codeForbidden       = "FORBIDDEN"
+	codeInternal        = "INTERNAL_SERVER_ERROR"
+)
+
+var (
+	errMutationOverGET = errors.New("mutations must be sent with POST")
+	errInternal        = errors.New("internal server error")
+)
+
+// resolverError は extensions.code を付けて返すエラーです
+type resolverError struct {
+	err  error
+	code string
+}
+
+func (e *resolverError) Error() string { return e.err.Error() }
+func (e *resolverError) Unwrap() error { return e.err }
+
+// Extensions は graphql-go がエラーの extensions に入れる値です
+func (e *resolverError) Extensions() map[string]any {
+	return map[string]any{"code": e.code}
+}
+
+func badUserInput(err error) error {
+	return &resolverError{err: err, code: codeBadUserInput}
+}
+
+// toError はユースケースのエラーを extensions.code 付きのエラーに変換します。
+// 想定外のエラーはログに書き、クライアントには内容を伏せて返します
+func toError(ctx context.Context, err error) error {
+	var code string
+	switch {
+	case errors.Is(err, domain.ErrTitleEmpty), errors.Is(err, domain.ErrInvalidPriority), errors.Is(err, domain.ErrInvalidRange):
+		code = codeBadUserInput
+	case errors.Is(err, domain.ErrTodoNotFound):
+		code = codeNotFound
+	case errors.Is(err, domain.ErrUnauthenticated):
+		code = codeUnauthenticated
+	case errors.Is(err, errMutationOverGET), errors.Is(err, domain.ErrTodoForbidden):
+		code = codeForbidden
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return err
+	default:
+		logging.FromContext(ctx).Error("GraphQL resolver failed", slog.Any("error", err))
+		return &resolverError{err: errInternal, code: codeInternal}
+	}
+	return &resolverError{err: err, code: code}
+}
@@ -0,0 +1,128 @@
+// Package graphqlapi は Todo の GraphQL API (/graphql) を提供します。
+// REST API と同じユースケースの上に実装し、スキーマは schema.graphql に記載しています。
+// クエリとミューテーションは HTTP（GET / POST）で、サブスクリプションは WebSocket（graphql-transport-ws プロトコル）で受け付けます
+package graphqlapi
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"todo_app_golang/internal/interface/middleware"
+
+	"github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/trace/otel"
+)
+
+//go:embed schema.graphql
+var schemaSDL string
+
+const (
+	// maxRequestBytes は POST のボディ・WebSocket のメッセージの最大サイズです
+	maxRequestBytes = 1 << 20
+	// maxDepth はクエリのネストの上限です（深いクエリで負荷をかけられないようにする）
+	maxDepth = 10
+	// maxParallelism は1リクエストで同時に実行するリゾルバーの上限です
+	maxParallelism = 10
+)
+
+// Options は Handler の設定です
+type Options struct {
+	// AllowedOrigins は WebSocket の接続を受け付ける Origin です（CORS と同じ値を指定する）。
+	// API と同じホストからの接続は常に受け付けます
+	AllowedOrigins []string
+}
+
+// Handler は /graphql のハンドラーです
+type Handler struct {
+	schema *graphql.Schema
+	todos  TodoUseCaseInterface
+	opts   Options
+
+	shutdownCtx context.Context // Shutdown で終わり、WebSocket の接続を閉じる
+	shutdown    context.CancelFunc
+	conns       sync.WaitGroup
+}
+
+// NewHandler はハンドラーを生成します。スキーマは同梱しているもので、解釈に失敗した場合は panic します
+func NewHandler(todos TodoUseCaseInterface, stats StatsUseCaseInterface, opts Options) *Handler {
+	schema := graphql.MustParseSchema(schemaSDL, &resolver{todos: todos, stats: stats},
+		graphql.UseFieldResolvers(),
+		graphql.MaxDepth(maxDepth),
+		graphql.MaxParallelism(maxParallelism),
+		graphql.Tracer(otel.DefaultTracer()),
+	)
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Handler{schema: schema, todos: todos, opts: opts, shutdownCtx: ctx, shutdown: cancel}
+}
+
+// Shutdown は WebSocket の接続をすべて閉じ、閉じ終わるまで待ちます。
+// http.Server.Shutdown はハイジャックした接続を待たないため、RegisterOnShutdown で登録してください
+func (h *Handler) Shutdown() {
+	h.shutdown()
+	h.conns.Wait()
+}
+
+// request は GraphQL over HTTP のリクエストです
+type request struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName"`
+	Variables     map[string]any `json:"variables"`
+}
+
+// ServeHTTP: GET /graphql?query=...、POST /graphql {"query": "..."}、または WebSocket へのアップグレード
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodGet && strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+		h.serveWebSocket(w, r)
+		return
+	}
+
+	ctx := r.Context()
+	var req request
+	switch r.Method {
+	case http.MethodGet:
+		query := r.URL.Query()
+		req.Query = query.Get("query")
+		req.OperationName = query.Get("operationName")
+		if v := query.Get("variables"); v != "" {
+			if err := json.Unmarshal([]byte(v), &req.Variables); err != nil {
+				middleware.WriteProblem(w, http.StatusBadRequest, "variables must be a JSON object")
+				return
+			}
+		}
+		// GET はフォームやリンクからも送れるため、ミューテーションは受け付けない
+		ctx = withReadOnly(ctx)
+	case http.MethodPost:
+		body := http.MaxBytesReader(w, r.Body, maxRequestBytes)
+		if err := json.NewDecoder(body).Decode(&req); err != nil {
+			var maxErr *http.MaxBytesError
+			if errors.As(err, &maxErr) {
+				middleware.WriteProblem(w, http.StatusRequestEntityTooLarge, "request body is too large")
+				return
+			}
+			if errors.Is(err, io.EOF) {
+				err = errors.New("request body is empty")
+			}
+			middleware.WriteProblem(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+			return
+		}
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		middleware.WriteProblem(w, http.StatusMethodNotAllowed, "use GET or POST")
+		return
+	}
+	if req.Query == "" {
+		middleware.WriteProblem(w, http.StatusBadRequest, "query is required")
+		return
+	}
+
+	ctx = withLoader(ctx, newTodoLoader(h.todos.GetTodosByIDs))
+	res := h.schema.Exec(ctx, req.Query, req.OperationName, req.Variables)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(res)
+}
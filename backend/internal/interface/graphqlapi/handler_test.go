@@ -0,0 +1,383 @@
+package graphqlapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+	"todo_app_golang/internal/domain"
+	"todo_app_golang/internal/infrastructure"
+	"todo_app_golang/internal/usecase"
+
+	"github.com/coder/websocket"
+	"github.com/stretchr/testify/assert"
+)
+
+// countingUseCase は GetTodosByIDs の呼び出し回数を数えます（N+1 になっていないかの確認用）
+type countingUseCase struct {
+	*usecase.TodoUseCase
+	batches atomic.Int32
+}
+
+func (u *countingUseCase) GetTodosByIDs(ctx context.Context, ids []int) ([]*domain.Todo, error) {
+	u.batches.Add(1)
+	return u.TodoUseCase.GetTodosByIDs(ctx, ids)
+}
+
+func newTestHandler(t *testing.T) (*Handler, *countingUseCase, *httptest.Server) {
+	t.Helper()
+	repo := infrastructure.NewMemoryTodoRepository()
+	uc := &countingUseCase{TodoUseCase: usecase.NewTodoUseCase(repo)}
+	h := NewHandler(uc, usecase.NewStatsUseCase(infrastructure.NewComputedStatsRepository(repo)), Options{})
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+	return h, uc, srv
+}
+
+type gqlResponse struct {
+	Data   map[string]json.RawMessage `json:"data"`
+	Errors []struct {
+		Message    string         `json:"message"`
+		Extensions map[string]any `json:"extensions"`
+	} `json:"errors"`
+}
+
+func post(t *testing.T, srv *httptest.Server, query string, variables map[string]any) gqlResponse {
+	t.Helper()
+	body, _ := json.Marshal(map[string]any{"query": query, "variables": variables})
+	res, err := http.Post(srv.URL, "application/json", strings.NewReader(string(body)))
+	if err != nil {
+		t.Fatalf("POST: %v", err)
+	}
+	defer res.Body.Close()
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	var out gqlResponse
+	assert.NoError(t, json.NewDecoder(res.Body).Decode(&out))
+	return out
+}
+
+func TestHandler_HTTP(t *testing.T) {
+	_, _, srv := newTestHandler(t)
+
+	t.Run("ミューテーションで作成したタスクをクエリで取得できること", func(t *testing.T) {
+		res := post(t, srv, `mutation($in: TodoInput!) { createTodo(input: $in) { id title priority dueDate projects tags } }`,
+			map[string]any{"in": map[string]any{"title": "資料作成 +仕事 @PC +仕事", "priority": "HIGH", "dueDate": "2026-10-31T00:00:00Z"}})
+		assert.Empty(t, res.Errors)
+		assert.JSONEq(t, `{"id":"1","title":"資料作成 +仕事 @PC +仕事","priority":"HIGH","dueDate":"2026-10-31T00:00:00Z","projects":["仕事"],"tags":["PC"]}`,
+			string(res.Data["createTodo"]))
+
+		post(t, srv, `mutation { createTodo(input: {title: "買い物"}) { id } }`, nil)
+		res = post(t, srv, `mutation { setTodoCompleted(id: "2", completed: true) { isCompleted completedAt } }`, nil)
+		assert.Empty(t, res.Errors)
+		assert.Contains(t, string(res.Data["setTodoCompleted"]), `"isCompleted":true`)
+
+		res = post(t, srv, `{ todos(filter: {isCompleted: false}) { title } todo(id: "2") { priority } }`, nil)
+		assert.JSONEq(t, `[{"title":"資料作成 +仕事 @PC +仕事"}]`, string(res.Data["todos"]))
+		assert.JSONEq(t, `{"priority":"MEDIUM"}`, string(res.Data["todo"]))
+	})
+
+	t.Run("GET でクエリを実行できること", func(t *testing.T) {
+		res, err := http.Get(srv.URL + "?query=" + url.QueryEscape(`{ todo(id: "1") { title } }`))
+		assert.NoError(t, err)
+		defer res.Body.Close()
+		assert.Equal(t, "application/json", res.Header.Get("Content-Type"))
+		var out gqlResponse
+		assert.NoError(t, json.NewDecoder(res.Body).Decode(&out))
+		assert.JSONEq(t, `{"title":"資料作成 +仕事 @PC +仕事"}`, string(out.Data["todo"]))
+	})
+
+	t.Run("失敗：GET ではミューテーションを実行しないこと", func(t *testing.T) {
+		res, err := http.Get(srv.URL + "?query=" + url.QueryEscape(`mutation { deleteTodo(id: "1") }`))
+		assert.NoError(t, err)
+		defer res.Body.Close()
+		var out gqlResponse
+		assert.NoError(t, json.NewDecoder(res.Body).Decode(&out))
+		assert.Equal(t, codeForbidden, out.Errors[0].Extensions["code"])
+
+		after := post(t, srv, `{ todo(id: "1") { id } }`, nil)
+		assert.JSONEq(t, `{"id":"1"}`, string(after.Data["todo"]))
+	})
+
+	t.Run("存在しないタスクは null になること", func(t *testing.T) {
+		res := post(t, srv, `{ todo(id: "999") { id } }`, nil)
+		assert.Empty(t, res.Errors)
+		assert.Equal(t, "null", string(res.Data["todo"]))
+	})
+
+	t.Run("失敗：入力の誤りは BAD_USER_INPUT、存在しないタスクの更新は NOT_FOUND になること", func(t *testing.T) {
+		for query, code := range map[string]string{
+			`mutation { createTodo(input: {title: ""}) { id } }`:               codeBadUserInput,
+			`{ todo(id: "abc") { id } }`:                                       codeBadUserInput,
+			`{ stats(tz: "Mars/Olympus") { total } }`:                          codeBadUserInput,
+			`mutation { updateTodo(id: "999", input: {title: "a"}) { id } }`:   codeNotFound,
+			`mutation { setTodoCompleted(id: "999", completed: true) { id } }`: codeNotFound,
+		} {
+			res := post(t, srv, query, nil)
+			if assert.Len(t, res.Errors, 1, query) {
+				assert.Equal(t, code, res.Errors[0].Extensions["code"], query)
+			}
+		}
+	})
+
+	t.Run("集計を取得できること", func(t *testing.T) {
+		res := post(t, srv, `{ stats(tz: "Asia/Tokyo") { total completed byPriority { priority count } } }`, nil)
+		assert.Empty(t, res.Errors)
+		assert.JSONEq(t, `{"total":2,"completed":1,"byPriority":[{"priority":"LOW","count":0},{"priority":"MEDIUM","count":1},{"priority":"HIGH","count":1}]}`,
+			string(res.Data["stats"]))
+	})
+
+	t.Run("失敗：リクエストの形式が不正な場合は 400 を返すこと", func(t *testing.T) {
+		for _, body := range []string{``, `not json`, `{"variables":{}}`} {
+			res, err := http.Post(srv.URL, "application/json", strings.NewReader(body))
+			assert.NoError(t, err)
+			res.Body.Close()
+			assert.Equal(t, http.StatusBadRequest, res.StatusCode, body)
+			assert.Equal(t, "application/problem+json", res.Header.Get("Content-Type"))
+		}
+	})
+}
+
+func TestHandler_Assignees(t *testing.T) {
+	_, _, srv := newTestHandler(t)
+	post(t, srv, `mutation { createTodo(input: {title: "タスク"}) { id } }`, nil)
+
+	t.Run("所有者・担当者のいないタスクは null、ウォッチャーは空になること", func(t *testing.T) {
+		res := post(t, srv, `{ todos { ownerId assigneeId watchers } }`, nil)
+		assert.Empty(t, res.Errors)
+		assert.JSONEq(t, `[{"ownerId":null,"assigneeId":null,"watchers":[]}]`, string(res.Data["todos"]))
+	})
+
+	t.Run("失敗：認証していない場合に assignee: \"me\" を指定すると UNAUTHENTICATED になること", func(t *testing.T) {
+		res := post(t, srv, `{ todos(filter: {assignee: "me"}) { id } }`, nil)
+		if assert.Len(t, res.Errors, 1) {
+			assert.Equal(t, codeUnauthenticated, res.Errors[0].Extensions["code"])
+		}
+	})
+}
+
+func TestHandler_Batching(t *testing.T) {
+	_, uc, srv := newTestHandler(t)
+	for _, title := range []string{"a", "b", "c"} {
+		post(t, srv, `mutation($t: String!) { createTodo(input: {title: $t}) { id } }`, map[string]any{"t": title})
+	}
+
+	t.Run("同じリクエスト内の ID 指定の取得を1回の問い合わせにまとめること", func(t *testing.T) {
+		uc.batches.Store(0)
+		res := post(t, srv, `{
+			a: todo(id: "1") { title }
+			b: todo(id: "2") { title }
+			c: todo(id: "999") { title }
+			list: todos(ids: ["3", "1", "998"]) { title }
+		}`, nil)
+
+		assert.Empty(t, res.Errors)
+		assert.JSONEq(t, `{"title":"a"}`, string(res.Data["a"]))
+		assert.JSONEq(t, `{"title":"b"}`, string(res.Data["b"]))
+		assert.Equal(t, "null", string(res.Data["c"]))
+		assert.JSONEq(t, `[{"title":"c"},{"title":"a"}]`, string(res.Data["list"]))
+		assert.Equal(t, int32(1), uc.batches.Load())
+	})
+
+	t.Run("リクエストごとに読み込み直すこと", func(t *testing.T) {
+		uc.batches.Store(0)
+		post(t, srv, `{ todo(id: "1") { title } }`, nil)
+		post(t, srv, `{ todo(id: "1") { title } }`, nil)
+		assert.Equal(t, int32(2), uc.batches.Load())
+	})
+}
+
+// wsClient は graphql-transport-ws のテスト用クライアントです。
+// 受信は別の goroutine で行う（coder/websocket は Read の ctx が終わると接続を閉じるため、タイムアウトは受信側で待つ）
+type wsClient struct {
+	t        *testing.T
+	conn     *websocket.Conn
+	messages chan wsMessage
+	closeErr error // messages が閉じられた後に読める
+}
+
+func dialWS(t *testing.T, srv *httptest.Server) *wsClient {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	conn, _, err := websocket.Dial(ctx, "ws"+strings.TrimPrefix(srv.URL, "http"), &websocket.DialOptions{
+		Subprotocols: []string{wsProtocol},
+	})
+	if err != nil {
+		t.Fatalf("websocket.Dial: %v", err)
+	}
+	t.Cleanup(func() { conn.CloseNow() })
+
+	c := &wsClient{t: t, conn: conn, messages: make(chan wsMessage, 16)}
+	go func() {
+		defer close(c.messages)
+		for {
+			_, data, err := conn.Read(context.Background())
+			if err != nil {
+				c.closeErr = err
+				return
+			}
+			var msg wsMessage
+			if json.Unmarshal(data, &msg) == nil {
+				c.messages <- msg
+			}
+		}
+	}()
+	return c
+}
+
+func (c *wsClient) send(msg string) {
+	c.t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	assert.NoError(c.t, c.conn.Write(ctx, websocket.MessageText, []byte(msg)))
+}
+
+// next は wait の間に届いたメッセージを返します。届かなかった場合や接続が閉じられた場合は false を返します
+func (c *wsClient) next(wait time.Duration) (wsMessage, bool) {
+	select {
+	case msg, ok := <-c.messages:
+		return msg, ok
+	case <-time.After(wait):
+		return wsMessage{}, false
+	}
+}
+
+// expect は次のメッセージが typ であることを確認して返します
+func (c *wsClient) expect(typ string) wsMessage {
+	c.t.Helper()
+	msg, ok := c.next(5 * time.Second)
+	if !ok {
+		c.t.Fatalf("expected %s, but no message arrived (close: %v)", typ, c.closeErr)
+	}
+	assert.Equal(c.t, typ, msg.Type, string(msg.Payload))
+	return msg
+}
+
+// closeStatus は接続が閉じられるまで待ち、サーバーが送ったステータスコードを返します
+func (c *wsClient) closeStatus() websocket.StatusCode {
+	c.t.Helper()
+	for {
+		if _, ok := c.next(5 * time.Second); !ok {
+			return websocket.CloseStatus(c.closeErr)
+		}
+	}
+}
+
+func TestHandler_WebSocket(t *testing.T) {
+	t.Run("購読するとタスクの変更が届き、complete で止められること", func(t *testing.T) {
+		_, uc, srv := newTestHandler(t)
+		c := dialWS(t, srv)
+		c.send(`{"type":"connection_init"}`)
+		c.expect("connection_ack")
+		c.send(`{"type":"ping"}`)
+		c.expect("pong")
+
+		c.send(`{"id":"1","type":"subscribe","payload":{"query":"subscription { todoChanged { type id todo { title } } }"}}`)
+		// 購読の開始は非同期のため、通知が届くまで変更を繰り返す
+		var next wsMessage
+		assert.Eventually(t, func() bool {
+			_, err := uc.AddTodo(context.Background(), domain.TodoInput{Title: "通知"})
+			assert.NoError(t, err)
+			var ok bool
+			next, ok = c.next(50 * time.Millisecond)
+			return ok
+		}, 3*time.Second, time.Millisecond)
+		assert.Equal(t, "next", next.Type)
+		assert.Equal(t, "1", next.ID)
+		assert.Contains(t, string(next.Payload), `"type":"CREATED"`)
+		assert.Contains(t, string(next.Payload), `"title":"通知"`)
+
+		// 同じ接続でクエリも実行でき、結果の後に complete が届く
+		c.send(`{"id":"1","type":"complete"}`)
+		c.send(`{"id":"2","type":"subscribe","payload":{"query":"{ todo(id: \"1\") { title } }"}}`)
+		var got []string
+		for len(got) < 2 {
+			msg, ok := c.next(5 * time.Second)
+			if !ok {
+				t.Fatalf("connection closed: %v", c.closeErr)
+			}
+			if msg.ID == "2" { // 購読 1 の通知が先に届く場合がある
+				got = append(got, msg.Type)
+			}
+		}
+		assert.Equal(t, []string{"next", "complete"}, got)
+	})
+
+	t.Run("失敗：connection_init の前に購読すると 4401 で閉じること", func(t *testing.T) {
+		_, _, srv := newTestHandler(t)
+		c := dialWS(t, srv)
+		c.send(`{"id":"1","type":"subscribe","payload":{"query":"subscription { todoChanged { id } }"}}`)
+		assert.Equal(t, wsStatusUnauthorized, c.closeStatus())
+	})
+
+	t.Run("失敗：不正なクエリは error を返すこと", func(t *testing.T) {
+		_, _, srv := newTestHandler(t)
+		c := dialWS(t, srv)
+		c.send(`{"type":"connection_init"}`)
+		c.expect("connection_ack")
+		c.send(`{"id":"1","type":"subscribe","payload":{"query":"subscription { nothing }"}}`)
+		msg := c.expect("error")
+		assert.Equal(t, "1", msg.ID)
+	})
+
+	t.Run("Shutdown で接続を閉じること", func(t *testing.T) {
+		h, _, srv := newTestHandler(t)
+		c := dialWS(t, srv)
+		c.send(`{"type":"connection_init"}`)
+		c.expect("connection_ack")
+
+		h.Shutdown()
+		assert.Equal(t, websocket.StatusGoingAway, c.closeStatus())
+	})
+}
+
+func TestHandler_Permissions(t *testing.T) {
+	h, uc, _ := newTestHandler(t)
+	// 認証のミドルウェアの代わりに、X-User ヘッダーのユーザーを Context に格納する
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h.ServeHTTP(w, r.WithContext(domain.WithUserID(r.Context(), r.Header.Get("X-User"))))
+	}))
+	t.Cleanup(srv.Close)
+	as := func(user, query string) gqlResponse {
+		t.Helper()
+		body, _ := json.Marshal(map[string]any{"query": query})
+		req, _ := http.NewRequest(http.MethodPost, srv.URL, strings.NewReader(string(body)))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-User", user)
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("POST: %v", err)
+		}
+		defer res.Body.Close()
+		var out gqlResponse
+		assert.NoError(t, json.NewDecoder(res.Body).Decode(&out))
+		return out
+	}
+
+	as("alice", `mutation { createTodo(input: {title: "alice のタスク"}) { id } }`)
+
+	// 担当者・ウォッチャーを変更するミューテーションは無いため、ユースケースで設定する
+	_, err := uc.AssignTodo(domain.WithUserID(context.Background(), "alice"), 1, "bob")
+	assert.NoError(t, err)
+	_, err = uc.AddTodoWatcher(domain.WithUserID(context.Background(), "carol"), 1, "carol")
+	assert.NoError(t, err)
+
+	res := as("bob", `{ todos(filter: {assignee: "me"}) { ownerId assigneeId watchers } }`)
+	assert.Empty(t, res.Errors)
+	assert.JSONEq(t, `[{"ownerId":"alice","assigneeId":"bob","watchers":["carol"]}]`, string(res.Data["todos"]))
+	res = as("carol", `{ todos(filter: {assignee: "me"}) { id } }`)
+	assert.JSONEq(t, `[]`, string(res.Data["todos"]))
+	res = as("carol", `{ todos(ids: ["1"], filter: {assignee: "bob"}) { id } }`)
+	assert.JSONEq(t, `[{"id":"1"}]`, string(res.Data["todos"]))
+
+	res = as("bob", `mutation { deleteTodo(id: "1") }`)
+	if assert.Len(t, res.Errors, 1) {
+		assert.Equal(t, codeForbidden, res.Errors[0].Extensions["code"])
+	}
+	assert.Empty(t, as("alice", `mutation { deleteTodo(id: "1") }`).Errors)
+}
@@ -0,0 +1,130 @@
+package graphqlapi
+
+import (
+	"context"
+	"sync"
+	"time"
+	"todo_app_golang/internal/domain"
+)
+
+const (
+	// loaderWait は最初の読み込み要求から問い合わせるまで、他のフィールドの要求を待つ時間です
+	loaderWait = 2 * time.Millisecond
+	// loaderMaxBatch は1回の問い合わせにまとめる ID の上限です
+	loaderMaxBatch = 100
+)
+
+// todoLoader は同じリクエスト内で ID を指定したタスクの読み込みをまとめ、GetTodosByIDs の1回の問い合わせで取得します（dataloader と同じ方式）。
+// フィールドごとに取得すると N+1 回の問い合わせになるため、リクエストごとに作って使います。読み込んだ結果はリクエストの間使い回します
+type todoLoader struct {
+	fetch func(ctx context.Context, ids []int) ([]*domain.Todo, error)
+
+	mu      sync.Mutex
+	results map[int]*loadResult
+	pending []int // まだ問い合わせていない ID
+}
+
+// loadResult は1件分の読み込み結果です。done が閉じられた後に todo / err を読めます
+type loadResult struct {
+	done chan struct{}
+	todo *domain.Todo // 見つからない場合は nil
+	err  error
+}
+
+func newTodoLoader(fetch func(ctx context.Context, ids []int) ([]*domain.Todo, error)) *todoLoader {
+	return &todoLoader{fetch: fetch, results: map[int]*loadResult{}}
+}
+
+// Load は id のタスクを返します。見つからない場合は nil を返します
+func (l *todoLoader) Load(ctx context.Context, id int) (*domain.Todo, error) {
+	todos, err := l.LoadMany(ctx, []int{id})
+	if err != nil {
+		return nil, err
+	}
+	return todos[0], nil
+}
+
+// LoadMany は ids の順にタスクを返します。見つからない ID の位置は nil になります
+func (l *todoLoader) LoadMany(ctx context.Context, ids []int) ([]*domain.Todo, error) {
+	results := make([]*loadResult, len(ids))
+	l.mu.Lock()
+	for i, id := range ids {
+		res, ok := l.results[id]
+		if !ok {
+			res = &loadResult{done: make(chan struct{})}
+			l.results[id] = res
+			l.enqueue(ctx, id)
+		}
+		results[i] = res
+	}
+	l.mu.Unlock()
+
+	todos := make([]*domain.Todo, len(ids))
+	for i, res := range results {
+		select {
+		case <-res.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		if res.err != nil {
+			return nil, res.err
+		}
+		todos[i] = res.todo
+	}
+	return todos, nil
+}
+
+// enqueue は id を次の問い合わせに加えます。l.mu をロックした状態で呼んでください
+func (l *todoLoader) enqueue(ctx context.Context, id int) {
+	l.pending = append(l.pending, id)
+	switch len(l.pending) {
+	case 1:
+		time.AfterFunc(loaderWait, func() { l.dispatch(ctx, false) })
+	case loaderMaxBatch:
+		go l.dispatch(ctx, true)
+	}
+}
+
+// dispatch は待っている ID をまとめて問い合わせます。
+// full の場合は上限に達した分だけを問い合わせ、タイマーで呼ばれた場合は残りをすべて問い合わせます
+func (l *todoLoader) dispatch(ctx context.Context, full bool) {
+	l.mu.Lock()
+	n := len(l.pending)
+	if full {
+		n = min(n, loaderMaxBatch)
+	}
+	ids := l.pending[:n:n]
+	l.pending = l.pending[n:] // 上限を超えた分は最初の要求のタイマーで問い合わせる
+	results := make([]*loadResult, len(ids))
+	for i, id := range ids {
+		results[i] = l.results[id]
+	}
+	l.mu.Unlock()
+	if len(ids) == 0 {
+		return
+	}
+
+	todos, err := l.fetch(ctx, ids)
+	byID := make(map[int]*domain.Todo, len(todos))
+	for _, t := range todos {
+		byID[t.ID] = t
+	}
+	for i, id := range ids {
+		results[i].todo, results[i].err = byID[id], err
+		close(results[i].done)
+	}
+}
+
+type loaderKey struct{}
+
+func withLoader(ctx context.Context, l *todoLoader) context.Context {
+	return context.WithValue(ctx, loaderKey{}, l)
+}
+
+// loaderFrom は ctx のローダーを返します。無い場合（ハンドラーを通さずに実行した場合）はその呼び出し限りのローダーを返します
+func (r *resolver) loaderFrom(ctx context.Context) *todoLoader {
+	if l, ok := ctx.Value(loaderKey{}).(*todoLoader); ok {
+		return l
+	}
+	return newTodoLoader(r.todos.GetTodosByIDs)
+}
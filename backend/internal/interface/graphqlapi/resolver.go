@@ -0,0 +1,495 @@
+package graphqlapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"todo_app_golang/internal/domain"
+
+	"github.com/graph-gophers/graphql-go"
+)
+
+// TodoUseCaseInterface は GraphQL API が使うタスクのユースケースです
+type TodoUseCaseInterface interface {
+	AddTodo(ctx context.Context, in domain.TodoInput) (*domain.Todo, error)
+	GetTodoByID(ctx context.Context, id int) (*domain.Todo, error)
+	GetTodosByIDs(ctx context.Context, ids []int) ([]*domain.Todo, error)
+	ListTodos(ctx context.Context, filter domain.TodoFilter) ([]*domain.Todo, error)
+	UpdateTodo(ctx context.Context, id int, in domain.TodoInput) (*domain.Todo, error)
+	UpdateTodoStatus(ctx context.Context, id int, isCompleted bool) error
+	DeleteTodo(ctx context.Context, id int) error
+	WatchTodos(ctx context.Context) <-chan domain.TodoEvent
+}
+
+// StatsUseCaseInterface は GraphQL API が使う集計のユースケースです
+type StatsUseCaseInterface interface {
+	GetStats(ctx context.Context, from, to time.Time, loc *time.Location) (*domain.Stats, error)
+	GetLeadTimes(ctx context.Context, from, to time.Time, loc *time.Location) (*domain.LeadTimeStats, error)
+	GetWeeklyThroughput(ctx context.Context, from, to time.Time, loc *time.Location) ([]domain.WeeklyCount, error)
+}
+
+// resolver はスキーマの Query / Mutation / Subscription のルートです
+type resolver struct {
+	todos TodoUseCaseInterface
+	stats StatsUseCaseInterface
+}
+
+// currentUser は TodoFilter.assignee で自分自身を表すユーザー ID の指定です
+const currentUser = "me"
+
+// priorities は優先度を表示する順です
+var priorities = []string{domain.PriorityLow, domain.PriorityMedium, domain.PriorityHigh}
+
+// ---- Query ----
+
+func (r *resolver) Todo(ctx context.Context, args struct{ ID graphql.ID }) (*todoResolver, error) {
+	id, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+	todo, err := r.loaderFrom(ctx).Load(ctx, id)
+	if err != nil {
+		return nil, toError(ctx, err)
+	}
+	if todo == nil {
+		return nil, nil
+	}
+	return &todoResolver{todo}, nil
+}
+
+type todoFilterInput struct {
+	IsCompleted *bool
+	Priority    *string
+	Query       *string
+	DueBefore   *graphql.Time
+	Assignee    *string
+}
+
+// toDomain は絞り込み条件を変換します。assignee の "me" は ctx のユーザーにし、認証していない場合は domain.ErrUnauthenticated を返します
+func (f *todoFilterInput) toDomain(ctx context.Context) (domain.TodoFilter, error) {
+	var filter domain.TodoFilter
+	if f == nil {
+		return filter, nil
+	}
+	filter.IsCompleted = f.IsCompleted
+	if f.Priority != nil {
+		filter.Priority = strings.ToLower(*f.Priority)
+	}
+	if f.Query != nil {
+		filter.Query = *f.Query
+	}
+	if f.DueBefore != nil {
+		filter.DueBefore = &f.DueBefore.Time
+	}
+	if f.Assignee != nil && *f.Assignee != "" {
+		filter.AssigneeID = *f.Assignee
+		if filter.AssigneeID == currentUser {
+			user, ok := domain.UserIDFromContext(ctx)
+			if !ok {
+				return filter, domain.ErrUnauthenticated
+			}
+			filter.AssigneeID = user
+		}
+	}
+	return filter, nil
+}
+
+func (r *resolver) Todos(ctx context.Context, args struct {
+	Filter *todoFilterInput
+	IDs    *[]graphql.ID
+}) ([]*todoResolver, error) {
+	filter, err := args.Filter.toDomain(ctx)
+	if err != nil {
+		return nil, toError(ctx, err)
+	}
+	if args.IDs == nil {
+		todos, err := r.todos.ListTodos(ctx, filter)
+		if err != nil {
+			return nil, toError(ctx, err)
+		}
+		return todoResolvers(todos), nil
+	}
+
+	ids := make([]int, len(*args.IDs))
+	for i, gid := range *args.IDs {
+		id, err := parseID(gid)
+		if err != nil {
+			return nil, err
+		}
+		ids[i] = id
+	}
+	loaded, err := r.loaderFrom(ctx).LoadMany(ctx, ids)
+	if err != nil {
+		return nil, toError(ctx, err)
+	}
+	todos := make([]*domain.Todo, 0, len(loaded))
+	for _, todo := range loaded {
+		if todo != nil && filter.Match(todo) {
+			todos = append(todos, todo)
+		}
+	}
+	return todoResolvers(todos), nil
+}
+
+type statsArgs struct {
+	From *string
+	To   *string
+	TZ   *string
+}
+
+// parse は REST の GET /stats と同じく、from / to を tz の日付として解釈します
+func (a statsArgs) parse() (from, to time.Time, loc *time.Location, err error) {
+	loc = time.UTC
+	if a.TZ != nil && *a.TZ != "" {
+		if loc, err = time.LoadLocation(*a.TZ); err != nil {
+			return time.Time{}, time.Time{}, nil, badUserInput(fmt.Errorf("invalid tz %q", *a.TZ))
+		}
+	}
+	for name, src := range map[string]*string{"from": a.From, "to": a.To} {
+		if src == nil || *src == "" {
+			continue
+		}
+		d, err := time.ParseInLocation("2006-01-02", *src, loc)
+		if err != nil {
+			return time.Time{}, time.Time{}, nil, badUserInput(fmt.Errorf("invalid %s %q: use YYYY-MM-DD", name, *src))
+		}
+		if name == "from" {
+			from = d
+		} else {
+			to = d
+		}
+	}
+	return from, to, loc, nil
+}
+
+func (r *resolver) Stats(ctx context.Context, args statsArgs) (*statsResult, error) {
+	from, to, loc, err := args.parse()
+	if err != nil {
+		return nil, err
+	}
+	stats, err := r.stats.GetStats(ctx, from, to, loc)
+	if err != nil {
+		return nil, toError(ctx, err)
+	}
+
+	res := &statsResult{
+		Total:          int32(stats.Total),
+		Completed:      int32(stats.Completed),
+		Active:         int32(stats.Active),
+		CompletionRate: stats.CompletionRate,
+		Overdue:        int32(stats.Overdue),
+		DueToday:       int32(stats.DueToday),
+		DueThisWeek:    int32(stats.DueThisWeek),
+	}
+	for _, p := range priorities {
+		res.ByPriority = append(res.ByPriority, &priorityCount{Priority: enum(p), Count: int32(stats.ByPriority[p])})
+	}
+	for _, d := range stats.CompletedPerDay {
+		res.CompletedPerDay = append(res.CompletedPerDay, &dailyCount{Date: d.Date, Count: int32(d.Count)})
+	}
+	return res, nil
+}
+
+func (r *resolver) LeadTime(ctx context.Context, args statsArgs) (*leadTimeStats, error) {
+	from, to, loc, err := args.parse()
+	if err != nil {
+		return nil, err
+	}
+	stats, err := r.stats.GetLeadTimes(ctx, from, to, loc)
+	if err != nil {
+		return nil, toError(ctx, err)
+	}
+
+	res := &leadTimeStats{
+		Overall:    newLeadTime(stats.Overall),
+		ByPriority: []*priorityLeadTime{},
+		ByProject:  []*projectLeadTime{},
+	}
+	for _, p := range priorities {
+		if lt, ok := stats.ByPriority[p]; ok {
+			res.ByPriority = append(res.ByPriority, &priorityLeadTime{Priority: enum(p), LeadTime: newLeadTime(lt)})
+		}
+	}
+	for project, lt := range stats.ByProject {
+		res.ByProject = append(res.ByProject, &projectLeadTime{Project: project, LeadTime: newLeadTime(lt)})
+	}
+	sort.Slice(res.ByProject, func(i, j int) bool { return res.ByProject[i].Project < res.ByProject[j].Project })
+	return res, nil
+}
+
+func (r *resolver) Throughput(ctx context.Context, args statsArgs) ([]*weeklyCount, error) {
+	from, to, loc, err := args.parse()
+	if err != nil {
+		return nil, err
+	}
+	counts, err := r.stats.GetWeeklyThroughput(ctx, from, to, loc)
+	if err != nil {
+		return nil, toError(ctx, err)
+	}
+	res := make([]*weeklyCount, len(counts))
+	for i, c := range counts {
+		res[i] = &weeklyCount{WeekStart: c.WeekStart, Count: int32(c.Count)}
+	}
+	return res, nil
+}
+
+// ---- Mutation ----
+
+type todoInput struct {
+	Title       string
+	Description *string
+	Priority    *string
+	DueDate     *graphql.Time
+}
+
+func (in todoInput) toDomain() domain.TodoInput {
+	out := domain.TodoInput{Title: in.Title}
+	if in.Description != nil {
+		out.Description = *in.Description
+	}
+	if in.Priority != nil {
+		out.Priority = strings.ToLower(*in.Priority)
+	}
+	if in.DueDate != nil {
+		out.DueDate = &in.DueDate.Time
+	}
+	return out
+}
+
+func (r *resolver) CreateTodo(ctx context.Context, args struct{ Input todoInput }) (*todoResolver, error) {
+	if err := mutationAllowed(ctx); err != nil {
+		return nil, err
+	}
+	todo, err := r.todos.AddTodo(ctx, args.Input.toDomain())
+	if err != nil {
+		return nil, toError(ctx, err)
+	}
+	return &todoResolver{todo}, nil
+}
+
+func (r *resolver) UpdateTodo(ctx context.Context, args struct {
+	ID    graphql.ID
+	Input todoInput
+}) (*todoResolver, error) {
+	if err := mutationAllowed(ctx); err != nil {
+		return nil, err
+	}
+	id, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+	todo, err := r.todos.UpdateTodo(ctx, id, args.Input.toDomain())
+	if err != nil {
+		return nil, toError(ctx, err)
+	}
+	return &todoResolver{todo}, nil
+}
+
+func (r *resolver) SetTodoCompleted(ctx context.Context, args struct {
+	ID        graphql.ID
+	Completed bool
+}) (*todoResolver, error) {
+	if err := mutationAllowed(ctx); err != nil {
+		return nil, err
+	}
+	id, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.todos.UpdateTodoStatus(ctx, id, args.Completed); err != nil {
+		return nil, toError(ctx, err)
+	}
+	todo, err := r.todos.GetTodoByID(ctx, id)
+	if err != nil {
+		return nil, toError(ctx, err)
+	}
+	return &todoResolver{todo}, nil
+}
+
+func (r *resolver) DeleteTodo(ctx context.Context, args struct{ ID graphql.ID }) (graphql.ID, error) {
+	if err := mutationAllowed(ctx); err != nil {
+		return "", err
+	}
+	id, err := parseID(args.ID)
+	if err != nil {
+		return "", err
+	}
+	if err := r.todos.DeleteTodo(ctx, id); err != nil {
+		return "", toError(ctx, err)
+	}
+	return args.ID, nil
+}
+
+type readOnlyKey struct{}
+
+// withReadOnly はミューテーションを受け付けないリクエストであることを ctx に記録します（GET の場合。CSRF 対策）
+func withReadOnly(ctx context.Context) context.Context {
+	return context.WithValue(ctx, readOnlyKey{}, true)
+}
+
+func mutationAllowed(ctx context.Context) error {
+	if readOnly, _ := ctx.Value(readOnlyKey{}).(bool); readOnly {
+		return toError(ctx, errMutationOverGET)
+	}
+	return nil
+}
+
+// ---- Subscription ----
+
+// TodoChanged は購読を始めた後のタスクの変更を返します。
+// 受信が追いつかずユースケースが購読を打ち切った場合や ctx が終わった場合はチャネルを閉じます（購読が完了する）
+func (r *resolver) TodoChanged(ctx context.Context) <-chan *todoEventResolver {
+	events := r.todos.WatchTodos(ctx)
+	out := make(chan *todoEventResolver)
+	go func() {
+		defer close(out)
+		for ev := range events {
+			select {
+			case out <- &todoEventResolver{ev}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
+
+type todoEventResolver struct {
+	ev domain.TodoEvent
+}
+
+func (r *todoEventResolver) Type() string {
+	switch r.ev.Type {
+	case domain.TodoCreated:
+		return "CREATED"
+	case domain.TodoDeleted:
+		return "DELETED"
+	default:
+		return "UPDATED"
+	}
+}
+
+func (r *todoEventResolver) ID() graphql.ID { return formatID(r.ev.ID) }
+
+func (r *todoEventResolver) Todo() *todoResolver {
+	if r.ev.Todo == nil {
+		return nil
+	}
+	return &todoResolver{r.ev.Todo}
+}
+
+// ---- Todo ----
+
+type todoResolver struct {
+	t *domain.Todo
+}
+
+func todoResolvers(todos []*domain.Todo) []*todoResolver {
+	res := make([]*todoResolver, len(todos))
+	for i, t := range todos {
+		res[i] = &todoResolver{t}
+	}
+	return res
+}
+
+func (r *todoResolver) ID() graphql.ID             { return formatID(r.t.ID) }
+func (r *todoResolver) Title() string              { return r.t.Title }
+func (r *todoResolver) Description() string        { return r.t.Description }
+func (r *todoResolver) IsCompleted() bool          { return r.t.IsCompleted }
+func (r *todoResolver) Priority() string           { return enum(r.t.Priority) }
+func (r *todoResolver) DueDate() *graphql.Time     { return optionalTime(r.t.DueDate) }
+func (r *todoResolver) CreatedAt() graphql.Time    { return graphql.Time{Time: r.t.CreatedAt} }
+func (r *todoResolver) UpdatedAt() graphql.Time    { return graphql.Time{Time: r.t.UpdatedAt} }
+func (r *todoResolver) CompletedAt() *graphql.Time { return optionalTime(r.t.CompletedAt) }
+func (r *todoResolver) Projects() []string         { return r.t.Projects() }
+func (r *todoResolver) Tags() []string             { return r.t.Contexts() }
+func (r *todoResolver) OwnerID() *string           { return r.t.OwnerID }
+func (r *todoResolver) AssigneeID() *string        { return r.t.AssigneeID }
+func (r *todoResolver) Watchers() []string         { return r.t.Watchers }
+
+// ---- 集計 ----
+// 集計結果はフィールドをそのまま返すため、GraphQL の Int に合わせて int32 にした構造体で表します（UseFieldResolvers）
+
+type statsResult struct {
+	Total           int32
+	Completed       int32
+	Active          int32
+	CompletionRate  float64
+	ByPriority      []*priorityCount
+	Overdue         int32
+	DueToday        int32
+	DueThisWeek     int32
+	CompletedPerDay []*dailyCount
+}
+
+type priorityCount struct {
+	Priority string
+	Count    int32
+}
+
+type dailyCount struct {
+	Date  string
+	Count int32
+}
+
+type leadTime struct {
+	Count       int32
+	MedianHours float64
+	P90Hours    float64
+}
+
+func newLeadTime(lt domain.LeadTime) *leadTime {
+	return &leadTime{Count: int32(lt.Count), MedianHours: lt.MedianHours, P90Hours: lt.P90Hours}
+}
+
+type priorityLeadTime struct {
+	Priority string
+	LeadTime *leadTime
+}
+
+type projectLeadTime struct {
+	Project  string
+	LeadTime *leadTime
+}
+
+type leadTimeStats struct {
+	Overall    *leadTime
+	ByPriority []*priorityLeadTime
+	ByProject  []*projectLeadTime
+}
+
+type weeklyCount struct {
+	WeekStart string
+	Count     int32
+}
+
+// ---- 変換 ----
+
+// parseID は ID を正の整数として解釈します
+func parseID(id graphql.ID) (int, error) {
+	n, err := strconv.Atoi(string(id))
+	if err != nil || n <= 0 {
+		return 0, badUserInput(errors.New("id must be a positive integer"))
+	}
+	return n, nil
+}
+
+func formatID(id int) graphql.ID {
+	return graphql.ID(strconv.Itoa(id))
+}
+
+// enum は優先度を GraphQL の列挙値（大文字）にします
+func enum(priority string) string {
+	return strings.ToUpper(priority)
+}
+
+func optionalTime(t *time.Time) *graphql.Time {
+	if t == nil {
+		return nil
+	}
+	return &graphql.Time{Time: *t}
+}
@@ -0,0 +1,208 @@
+package graphqlapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+	"todo_app_golang/internal/logging"
+
+	"github.com/coder/websocket"
+	"github.com/graph-gophers/graphql-go"
+)
+
+// WebSocket では graphql-transport-ws プロトコル (https://github.com/enisdenjo/graphql-ws/blob/master/PROTOCOL.md) を話します
+const (
+	wsProtocol = "graphql-transport-ws"
+	// wsInitTimeout は接続してから connection_init を待つ時間です
+	wsInitTimeout = 10 * time.Second
+	// wsWriteTimeout は1件のメッセージの送信を待つ時間です
+	wsWriteTimeout = 10 * time.Second
+)
+
+// graphql-transport-ws で定められた、接続を閉じる際のステータスコードです
+const (
+	wsStatusBadRequest      websocket.StatusCode = 4400
+	wsStatusUnauthorized    websocket.StatusCode = 4401
+	wsStatusInitTimeout     websocket.StatusCode = 4408
+	wsStatusDuplicateID     websocket.StatusCode = 4409
+	wsStatusTooManyInitReqs websocket.StatusCode = 4429
+)
+
+// wsMessage は graphql-transport-ws のメッセージです
+type wsMessage struct {
+	ID      string          `json:"id,omitempty"`
+	Type    string          `json:"type"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+// wsSession は1つの WebSocket 接続です。接続の中で複数の操作（主にサブスクリプション）を id で区別して実行します
+type wsSession struct {
+	h    *Handler
+	conn *websocket.Conn
+	ctx  context.Context // 接続が終わると終わる
+
+	mu          sync.Mutex
+	initialized bool
+	operations  map[string]context.CancelFunc // 実行中の操作（id ごと）
+}
+
+func (h *Handler) serveWebSocket(w http.ResponseWriter, r *http.Request) {
+	// サーバーの ReadTimeout / WriteTimeout はハイジャックした後の接続にも残るため、接続を引き継ぐ前に解除する
+	rc := http.NewResponseController(w)
+	rc.SetReadDeadline(time.Time{})
+	rc.SetWriteDeadline(time.Time{})
+
+	conn, err := websocket.Accept(w, r, &websocket.AcceptOptions{
+		Subprotocols:   []string{wsProtocol},
+		OriginPatterns: h.opts.AllowedOrigins,
+	})
+	if err != nil {
+		return // Accept がエラーのレスポンスを書いている
+	}
+	if conn.Subprotocol() != wsProtocol {
+		conn.Close(websocket.StatusPolicyViolation, "use the "+wsProtocol+" subprotocol")
+		return
+	}
+	conn.SetReadLimit(maxRequestBytes)
+
+	h.conns.Add(1)
+	defer h.conns.Done()
+	stop := context.AfterFunc(h.shutdownCtx, func() {
+		conn.Close(websocket.StatusGoingAway, "server is shutting down")
+	})
+	defer stop()
+
+	// ハイジャックした後はリクエストの ctx が終わらないため、ロガーやトレースだけを引き継ぐ
+	ctx, cancel := context.WithCancel(context.WithoutCancel(r.Context()))
+	defer cancel()
+	s := &wsSession{h: h, conn: conn, ctx: ctx, operations: map[string]context.CancelFunc{}}
+	s.run()
+}
+
+// run は接続が閉じるまでメッセージを読みます
+func (s *wsSession) run() {
+	defer s.conn.CloseNow()
+	initTimer := time.AfterFunc(wsInitTimeout, func() {
+		s.conn.Close(wsStatusInitTimeout, "Connection initialisation timeout")
+	})
+	defer initTimer.Stop()
+
+	for {
+		_, data, err := s.conn.Read(s.ctx)
+		if err != nil {
+			return // 切断された
+		}
+		var msg wsMessage
+		if err := json.Unmarshal(data, &msg); err != nil {
+			s.conn.Close(wsStatusBadRequest, "Invalid message")
+			return
+		}
+
+		switch msg.Type {
+		case "connection_init":
+			s.mu.Lock()
+			again := s.initialized
+			s.initialized = true
+			s.mu.Unlock()
+			if again {
+				s.conn.Close(wsStatusTooManyInitReqs, "Too many initialisation requests")
+				return
+			}
+			initTimer.Stop()
+			s.write(wsMessage{Type: "connection_ack"})
+		case "ping":
+			s.write(wsMessage{Type: "pong"})
+		case "pong":
+		case "subscribe":
+			if code, reason := s.subscribe(msg); code != 0 {
+				s.conn.Close(code, reason)
+				return
+			}
+		case "complete":
+			s.cancel(msg.ID)
+		default:
+			s.conn.Close(wsStatusBadRequest, fmt.Sprintf("Unknown message type %q", msg.Type))
+			return
+		}
+	}
+}
+
+// subscribe は操作を始めます。プロトコル違反で接続を閉じる場合はそのステータスと理由を返します
+func (s *wsSession) subscribe(msg wsMessage) (websocket.StatusCode, string) {
+	var req request
+	if msg.ID == "" || json.Unmarshal(msg.Payload, &req) != nil || req.Query == "" {
+		return wsStatusBadRequest, "Invalid subscribe message"
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.initialized {
+		return wsStatusUnauthorized, "Unauthorized"
+	}
+	if _, ok := s.operations[msg.ID]; ok {
+		return wsStatusDuplicateID, fmt.Sprintf("Subscriber for %s already exists", msg.ID)
+	}
+	ctx, cancel := context.WithCancel(s.ctx)
+	s.operations[msg.ID] = cancel
+
+	go func() {
+		defer s.cancel(msg.ID)
+		ctx = withLoader(ctx, newTodoLoader(s.h.todos.GetTodosByIDs))
+		responses, err := s.h.schema.Subscribe(ctx, req.Query, req.OperationName, req.Variables)
+		if err != nil {
+			s.sendErrors(msg.ID, err.Error())
+			return
+		}
+
+		first := true
+		for v := range responses {
+			res := v.(*graphql.Response)
+			// 検証エラーなどで実行できなかった場合は next ではなく error を返す（complete は送らない）
+			if first && res.Data == nil && len(res.Errors) > 0 {
+				payload, _ := json.Marshal(res.Errors)
+				s.write(wsMessage{ID: msg.ID, Type: "error", Payload: payload})
+				return
+			}
+			first = false
+			payload, _ := json.Marshal(res)
+			s.write(wsMessage{ID: msg.ID, Type: "next", Payload: payload})
+		}
+		// クライアントが complete を送った場合は返さない
+		if ctx.Err() == nil {
+			s.write(wsMessage{ID: msg.ID, Type: "complete"})
+		}
+	}()
+	return 0, ""
+}
+
+// cancel は id の操作を止めます（終わっている場合は何もしない）
+func (s *wsSession) cancel(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if cancel, ok := s.operations[id]; ok {
+		cancel()
+		delete(s.operations, id)
+	}
+}
+
+func (s *wsSession) sendErrors(id, message string) {
+	payload, _ := json.Marshal([]map[string]string{{"message": message}})
+	s.write(wsMessage{ID: id, Type: "error", Payload: payload})
+}
+
+// write はメッセージを送ります。送れない場合は接続が閉じているため、ログに書くだけにします
+func (s *wsSession) write(msg wsMessage) {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(s.ctx, wsWriteTimeout)
+	defer cancel()
+	if err := s.conn.Write(ctx, websocket.MessageText, data); err != nil {
+		logging.FromContext(s.ctx).Debug("Failed to write GraphQL WebSocket message", slog.String("type", msg.Type), slog.Any("error", err))
+	}
+}
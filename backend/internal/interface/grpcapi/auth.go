@@ -0,0 +1,52 @@
+package grpcapi
+
+import (
+	"context"
+	"strings"
+	"todo_app_golang/internal/domain"
+	todov1 "todo_app_golang/proto/todo/v1"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// unaryAuthenticate は REST API の middleware.Authenticate と同じく、認証プロキシが付けたメタデータからユーザーを取り出し、
+// Context に格納します。メタデータがない TodoService の RPC は UNAUTHENTICATED にします（ヘルスチェック・リフレクションは通す）
+func unaryAuthenticate(key string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := authenticate(ctx, key, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// streamAuthenticate は unaryAuthenticate のストリーミング RPC 版です
+func streamAuthenticate(key string) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authenticate(ss.Context(), key, info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+func authenticate(ctx context.Context, key, fullMethod string) (context.Context, error) {
+	if !strings.HasPrefix(fullMethod, "/"+todov1.TodoService_ServiceDesc.ServiceName+"/") {
+		return ctx, nil
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	var userID string
+	// Get はキーを小文字にして探すため、HTTP のヘッダー名（X-Forwarded-User など）をそのまま使える
+	if values := md.Get(key); len(values) > 0 {
+		userID = strings.TrimSpace(values[0])
+	}
+	if userID == "" {
+		return nil, status.Error(codes.Unauthenticated, "ログインが必要です")
+	}
+	return domain.WithUserID(ctx, userID), nil
+}
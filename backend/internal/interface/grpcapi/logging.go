@@ -0,0 +1,84 @@
+package grpcapi
+
+import (
+	"context"
+	"log/slog"
+	"time"
+	"todo_app_golang/internal/logging"
+
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// unaryLogger はリクエストスコープのロガーを Context に格納し、RPC ごとにアクセスログを出力します。
+// ハンドラーの panic はプロセスを落とさないよう INTERNAL に変換します
+func unaryLogger(logger *slog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (res any, err error) {
+		start := time.Now()
+		ctx, rpcLogger := withLogger(ctx, logger)
+		defer func() {
+			err = recoverPanic(ctx, rpcLogger, recover(), err)
+			logRPC(ctx, rpcLogger, info.FullMethod, start, err)
+		}()
+		return handler(ctx, req)
+	}
+}
+
+// streamLogger は unaryLogger のストリーミング RPC 版です
+func streamLogger(logger *slog.Logger) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		start := time.Now()
+		ctx, rpcLogger := withLogger(ss.Context(), logger)
+		defer func() {
+			err = recoverPanic(ctx, rpcLogger, recover(), err)
+			logRPC(ctx, rpcLogger, info.FullMethod, start, err)
+		}()
+		return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+func withLogger(ctx context.Context, logger *slog.Logger) (context.Context, *slog.Logger) {
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		// トレースと突き合わせられるよう trace_id もログに含める
+		logger = logger.With(slog.String("trace_id", sc.TraceID().String()))
+	}
+	return logging.WithLogger(ctx, logger), logger
+}
+
+func recoverPanic(ctx context.Context, logger *slog.Logger, recovered any, err error) error {
+	if recovered == nil {
+		return err
+	}
+	logger.ErrorContext(ctx, "grpc handler panicked", slog.Any("panic", recovered))
+	return status.Error(codes.Internal, "internal error")
+}
+
+func logRPC(ctx context.Context, logger *slog.Logger, method string, start time.Time, err error) {
+	code := status.Code(err)
+	level := slog.LevelInfo
+	switch code {
+	case codes.Internal, codes.Unknown, codes.DataLoss, codes.Unimplemented:
+		level = slog.LevelError
+	}
+	attrs := []slog.Attr{
+		slog.String("method", method),
+		slog.String("code", code.String()),
+		slog.Duration("duration", time.Since(start)),
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String("error", status.Convert(err).Message()))
+	}
+	logger.LogAttrs(ctx, level, "grpc request", attrs...)
+}
+
+// serverStream は Context をリクエストスコープのロガー入りのものに差し替えます
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
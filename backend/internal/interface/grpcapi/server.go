@@ -0,0 +1,72 @@
+package grpcapi
+
+import (
+	"context"
+	"log/slog"
+	"net"
+	todov1 "todo_app_golang/proto/todo/v1"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+)
+
+// Server は TodoService・ヘルスチェック (grpc.health.v1)・リフレクションを提供する gRPC サーバーです
+type Server struct {
+	grpc   *grpc.Server
+	health *health.Server
+	todos  *TodoServer
+}
+
+// Options は gRPC サーバーの設定です
+type Options struct {
+	// UserMetadataKey は認証プロキシがユーザーを渡すメタデータのキーです（空の場合は認証しない）
+	UserMetadataKey string
+}
+
+// NewServer は RPC ごとのトレースとアクセスログを有効にした gRPC サーバーを生成します
+func NewServer(logger *slog.Logger, uc TodoUseCaseInterface, opts Options) *Server {
+	unary := []grpc.UnaryServerInterceptor{unaryLogger(logger)}
+	stream := []grpc.StreamServerInterceptor{streamLogger(logger)}
+	if opts.UserMetadataKey != "" {
+		unary = append(unary, unaryAuthenticate(opts.UserMetadataKey))
+		stream = append(stream, streamAuthenticate(opts.UserMetadataKey))
+	}
+	s := &Server{
+		grpc: grpc.NewServer(
+			grpc.StatsHandler(otelgrpc.NewServerHandler()),
+			grpc.ChainUnaryInterceptor(unary...),
+			grpc.ChainStreamInterceptor(stream...),
+		),
+		health: health.NewServer(),
+		todos:  NewTodoServer(uc),
+	}
+	todov1.RegisterTodoServiceServer(s.grpc, s.todos)
+	healthpb.RegisterHealthServer(s.grpc, s.health)
+	reflection.Register(s.grpc)
+
+	s.health.SetServingStatus(todov1.TodoService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	return s
+}
+
+// Serve は ctx が終わるまで ln で待ち受けます。
+// 終了時はヘルスチェックを NOT_SERVING にして WatchTodos のストリームを終わらせ、処理中の RPC の完了を待ってから戻ります
+func (s *Server) Serve(ctx context.Context, ln net.Listener) error {
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- s.grpc.Serve(ln)
+	}()
+
+	select {
+	case err := <-serveErr:
+		return err
+	case <-ctx.Done():
+	}
+
+	s.health.Shutdown()
+	s.todos.stop()
+	s.grpc.GracefulStop()
+	return <-serveErr
+}
@@ -0,0 +1,274 @@
+package grpcapi
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net"
+	"testing"
+	"time"
+	"todo_app_golang/internal/domain"
+	"todo_app_golang/internal/infrastructure"
+	"todo_app_golang/internal/usecase"
+	todov1 "todo_app_golang/proto/todo/v1"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// startServer はメモリ上のリポジトリを使ったサーバーを起動し、接続済みのクライアントと停止用の関数を返します
+func startServer(t *testing.T, opts ...Options) (*grpc.ClientConn, func()) {
+	t.Helper()
+	return startServerWith(t, usecase.NewTodoUseCase(infrastructure.NewMemoryTodoRepository()), opts...)
+}
+
+// startServerWith は uc を使ったサーバーを起動します（RPC の無い操作をテストの準備に使う場合）
+func startServerWith(t *testing.T, uc *usecase.TodoUseCase, opts ...Options) (*grpc.ClientConn, func()) {
+	t.Helper()
+	var o Options
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+	srv := NewServer(slog.New(slog.DiscardHandler), uc, o)
+	ln := bufconn.Listen(1 << 20)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- srv.Serve(ctx, ln) }()
+	stop := func() {
+		cancel()
+		assert.NoError(t, <-done)
+	}
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return ln.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("grpc.NewClient: %v", err)
+	}
+	t.Cleanup(func() {
+		conn.Close()
+		select {
+		case <-ctx.Done():
+		default:
+			stop()
+		}
+	})
+	return conn, stop
+}
+
+func TestTodoServer(t *testing.T) {
+	conn, _ := startServer(t)
+	client := todov1.NewTodoServiceClient(conn)
+	ctx := context.Background()
+	due := time.Date(2026, 10, 31, 0, 0, 0, 0, time.UTC)
+
+	t.Run("作成・取得・更新・削除ができること", func(t *testing.T) {
+		created, err := client.CreateTodo(ctx, &todov1.CreateTodoRequest{
+			Title: "請求書を送る", Priority: todov1.Priority_PRIORITY_HIGH, DueDate: timestamppb.New(due),
+		})
+		assert.NoError(t, err)
+		id := created.GetTodo().GetId()
+		assert.Positive(t, id)
+		assert.Equal(t, todov1.Priority_PRIORITY_HIGH, created.GetTodo().GetPriority())
+
+		got, err := client.GetTodo(ctx, &todov1.GetTodoRequest{Id: id})
+		assert.NoError(t, err)
+		assert.Equal(t, "請求書を送る", got.GetTodo().GetTitle())
+		assert.True(t, due.Equal(got.GetTodo().GetDueDate().AsTime()))
+
+		updated, err := client.UpdateTodo(ctx, &todov1.UpdateTodoRequest{Id: id, Title: "請求書を再送する"})
+		assert.NoError(t, err)
+		assert.Equal(t, "請求書を再送する", updated.GetTodo().GetTitle())
+		assert.Equal(t, todov1.Priority_PRIORITY_MEDIUM, updated.GetTodo().GetPriority())
+		assert.Nil(t, updated.GetTodo().GetDueDate())
+
+		completed, err := client.UpdateTodoStatus(ctx, &todov1.UpdateTodoStatusRequest{Id: id, IsCompleted: true})
+		assert.NoError(t, err)
+		assert.True(t, completed.GetTodo().GetIsCompleted())
+		assert.NotNil(t, completed.GetTodo().GetCompletedAt())
+
+		_, err = client.DeleteTodo(ctx, &todov1.DeleteTodoRequest{Id: id})
+		assert.NoError(t, err)
+		_, err = client.GetTodo(ctx, &todov1.GetTodoRequest{Id: id})
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	t.Run("条件を指定して一覧を取得できること", func(t *testing.T) {
+		for _, req := range []*todov1.CreateTodoRequest{
+			{Title: "牛乳を買う", Priority: todov1.Priority_PRIORITY_LOW},
+			{Title: "報告書を書く", Description: "Q3 の売上", Priority: todov1.Priority_PRIORITY_HIGH},
+		} {
+			_, err := client.CreateTodo(ctx, req)
+			assert.NoError(t, err)
+		}
+		open := false
+
+		res, err := client.ListTodos(ctx, &todov1.ListTodosRequest{IsCompleted: &open, Priority: todov1.Priority_PRIORITY_HIGH})
+		assert.NoError(t, err)
+		assert.Len(t, res.GetTodos(), 1)
+		assert.Equal(t, "報告書を書く", res.GetTodos()[0].GetTitle())
+
+		res, err = client.ListTodos(ctx, &todov1.ListTodosRequest{Query: "q3"})
+		assert.NoError(t, err)
+		assert.Len(t, res.GetTodos(), 1)
+
+		res, err = client.ListTodos(ctx, &todov1.ListTodosRequest{})
+		assert.NoError(t, err)
+		assert.Len(t, res.GetTodos(), 2)
+	})
+
+	t.Run("失敗：ドメインのエラーを対応するステータスコードで返すこと", func(t *testing.T) {
+		_, err := client.CreateTodo(ctx, &todov1.CreateTodoRequest{})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		assert.Equal(t, "タイトルを入力してください", status.Convert(err).Message())
+
+		_, err = client.CreateTodo(ctx, &todov1.CreateTodoRequest{Title: "タスク", Priority: todov1.Priority(9)})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+		_, err = client.GetTodo(ctx, &todov1.GetTodoRequest{Id: 0})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+		_, err = client.UpdateTodo(ctx, &todov1.UpdateTodoRequest{Id: 99999, Title: "なし"})
+		assert.Equal(t, codes.NotFound, status.Code(err))
+		_, err = client.UpdateTodoStatus(ctx, &todov1.UpdateTodoStatusRequest{Id: 99999, IsCompleted: true})
+		assert.Equal(t, codes.NotFound, status.Code(err))
+		// 削除は冪等で、存在しない ID でもエラーにしない
+		_, err = client.DeleteTodo(ctx, &todov1.DeleteTodoRequest{Id: 99999})
+		assert.NoError(t, err)
+	})
+
+	t.Run("ヘルスチェックとリフレクションを提供すること", func(t *testing.T) {
+		res, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{Service: "todo.v1.TodoService"})
+		assert.NoError(t, err)
+		assert.Equal(t, healthpb.HealthCheckResponse_SERVING, res.GetStatus())
+
+		res, err = healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{})
+		assert.NoError(t, err)
+		assert.Equal(t, healthpb.HealthCheckResponse_SERVING, res.GetStatus())
+	})
+}
+
+func TestServer_Authenticate(t *testing.T) {
+	uc := usecase.NewTodoUseCase(infrastructure.NewMemoryTodoRepository())
+	conn, _ := startServerWith(t, uc, Options{UserMetadataKey: "X-Forwarded-User"})
+	client := todov1.NewTodoServiceClient(conn)
+	as := func(user string) context.Context {
+		return metadata.AppendToOutgoingContext(context.Background(), "x-forwarded-user", user)
+	}
+
+	t.Run("メタデータのユーザーで所有者・担当者の権限を確認すること", func(t *testing.T) {
+		created, err := client.CreateTodo(as("alice"), &todov1.CreateTodoRequest{Title: "請求書を送る"})
+		assert.NoError(t, err)
+		id := created.GetTodo().GetId()
+
+		_, err = client.DeleteTodo(as("bob"), &todov1.DeleteTodoRequest{Id: id})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+		_, err = client.UpdateTodo(as("bob"), &todov1.UpdateTodoRequest{Id: id, Title: "書き換え"})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+		_, err = client.DeleteTodo(as("alice"), &todov1.DeleteTodoRequest{Id: id})
+		assert.NoError(t, err)
+	})
+
+	t.Run("所有者・担当者・ウォッチャーを返し、担当者で絞り込めること", func(t *testing.T) {
+		created, err := client.CreateTodo(as("alice"), &todov1.CreateTodoRequest{Title: "見積書を確認する"})
+		assert.NoError(t, err)
+		id := int(created.GetTodo().GetId())
+		assert.Equal(t, "alice", created.GetTodo().GetOwnerId())
+		assert.Nil(t, created.GetTodo().AssigneeId)
+
+		// 担当者・ウォッチャーを変更する RPC は無いため、ユースケースで設定する
+		_, err = uc.AssignTodo(domain.WithUserID(context.Background(), "alice"), id, "bob")
+		assert.NoError(t, err)
+		_, err = uc.AddTodoWatcher(domain.WithUserID(context.Background(), "carol"), id, "carol")
+		assert.NoError(t, err)
+
+		res, err := client.ListTodos(as("bob"), &todov1.ListTodosRequest{Assignee: "me"})
+		assert.NoError(t, err)
+		if assert.Len(t, res.GetTodos(), 1) {
+			assert.Equal(t, "bob", res.GetTodos()[0].GetAssigneeId())
+			assert.Equal(t, []string{"carol"}, res.GetTodos()[0].GetWatchers())
+		}
+		res, err = client.ListTodos(as("alice"), &todov1.ListTodosRequest{Assignee: "bob"})
+		assert.NoError(t, err)
+		assert.Len(t, res.GetTodos(), 1)
+		res, err = client.ListTodos(as("carol"), &todov1.ListTodosRequest{Assignee: "me"})
+		assert.NoError(t, err)
+		assert.Empty(t, res.GetTodos())
+	})
+
+	t.Run("メタデータがない場合は UNAUTHENTICATED になること", func(t *testing.T) {
+		_, err := client.ListTodos(context.Background(), &todov1.ListTodosRequest{})
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+		stream, err := client.WatchTodos(context.Background(), &todov1.WatchTodosRequest{})
+		assert.NoError(t, err)
+		_, err = stream.Recv()
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+
+	t.Run("ヘルスチェックは認証しないこと", func(t *testing.T) {
+		_, err := healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{})
+		assert.NoError(t, err)
+	})
+}
+
+func TestServer_Reflection(t *testing.T) {
+	srv := NewServer(slog.New(slog.DiscardHandler), usecase.NewTodoUseCase(infrastructure.NewMemoryTodoRepository()), Options{})
+	services := srv.grpc.GetServiceInfo()
+
+	assert.Contains(t, services, "todo.v1.TodoService")
+	assert.Contains(t, services, "grpc.health.v1.Health")
+	assert.Contains(t, services, "grpc.reflection.v1.ServerReflection")
+}
+
+func TestTodoServer_WatchTodos(t *testing.T) {
+	conn, stop := startServer(t)
+	client := todov1.NewTodoServiceClient(conn)
+	ctx := context.Background()
+
+	stream, err := client.WatchTodos(ctx, &todov1.WatchTodosRequest{})
+	assert.NoError(t, err)
+	// ヘッダーが届いた時点で購読が始まっている
+	_, err = stream.Header()
+	assert.NoError(t, err)
+
+	created, err := client.CreateTodo(ctx, &todov1.CreateTodoRequest{Title: "牛乳を買う"})
+	assert.NoError(t, err)
+	id := created.GetTodo().GetId()
+	_, err = client.UpdateTodoStatus(ctx, &todov1.UpdateTodoStatusRequest{Id: id, IsCompleted: true})
+	assert.NoError(t, err)
+	_, err = client.DeleteTodo(ctx, &todov1.DeleteTodoRequest{Id: id})
+	assert.NoError(t, err)
+
+	for _, want := range []struct {
+		typ       todov1.EventType
+		completed bool
+	}{
+		{todov1.EventType_EVENT_TYPE_CREATED, false},
+		{todov1.EventType_EVENT_TYPE_UPDATED, true},
+		{todov1.EventType_EVENT_TYPE_DELETED, false},
+	} {
+		ev, err := stream.Recv()
+		assert.NoError(t, err)
+		assert.Equal(t, want.typ, ev.GetType())
+		assert.Equal(t, id, ev.GetId())
+		assert.Equal(t, want.completed, ev.GetTodo().GetIsCompleted())
+	}
+
+	t.Run("サーバーを停止するとストリームが UNAVAILABLE で終わること", func(t *testing.T) {
+		stop()
+		_, err := stream.Recv()
+		assert.NotEqual(t, io.EOF, err)
+		assert.Equal(t, codes.Unavailable, status.Code(err))
+	})
+}
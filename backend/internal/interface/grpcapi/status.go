@@ -0,0 +1,32 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"todo_app_golang/internal/domain"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// toStatus はユースケースのエラーを gRPC のステータスに変換します
+func toStatus(err error) error {
+	var code codes.Code
+	switch {
+	case errors.Is(err, domain.ErrTitleEmpty), errors.Is(err, domain.ErrInvalidPriority), errors.Is(err, domain.ErrInvalidRange):
+		code = codes.InvalidArgument
+	case errors.Is(err, domain.ErrUnauthenticated):
+		code = codes.Unauthenticated
+	case errors.Is(err, domain.ErrTodoForbidden):
+		code = codes.PermissionDenied
+	case errors.Is(err, domain.ErrTodoNotFound):
+		code = codes.NotFound
+	case errors.Is(err, context.Canceled):
+		code = codes.Canceled
+	case errors.Is(err, context.DeadlineExceeded):
+		code = codes.DeadlineExceeded
+	default:
+		code = codes.Internal
+	}
+	return status.Error(code, err.Error())
+}
@@ -0,0 +1,264 @@
+// Package grpcapi は Todo の gRPC API (proto/todo/v1) を提供します。
+// REST API と同じユースケースの上に実装し、ドメインのエラーは gRPC のステータスコードに変換して返します
+package grpcapi
+
+import (
+	"context"
+	"sync"
+	"time"
+	"todo_app_golang/internal/domain"
+	todov1 "todo_app_golang/proto/todo/v1"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// currentUser は ListTodosRequest.assignee で自分自身を表すユーザー ID の指定です
+const currentUser = "me"
+
+// TodoUseCaseInterface は TodoServer が使うユースケースです
+type TodoUseCaseInterface interface {
+	AddTodo(ctx context.Context, in domain.TodoInput) (*domain.Todo, error)
+	GetTodoByID(ctx context.Context, id int) (*domain.Todo, error)
+	ListTodos(ctx context.Context, filter domain.TodoFilter) ([]*domain.Todo, error)
+	UpdateTodo(ctx context.Context, id int, in domain.TodoInput) (*domain.Todo, error)
+	UpdateTodoStatus(ctx context.Context, id int, isCompleted bool) error
+	DeleteTodo(ctx context.Context, id int) error
+	WatchTodos(ctx context.Context) <-chan domain.TodoEvent
+}
+
+// TodoServer は todo.v1.TodoService の実装です
+type TodoServer struct {
+	todov1.UnimplementedTodoServiceServer
+	useCase TodoUseCaseInterface
+
+	stopping chan struct{} // 閉じられたら WatchTodos のストリームを終える
+	stopOnce sync.Once
+}
+
+func NewTodoServer(uc TodoUseCaseInterface) *TodoServer {
+	return &TodoServer{useCase: uc, stopping: make(chan struct{})}
+}
+
+// stop は WatchTodos のストリームを終わらせます。
+// ストリームは自分からは終わらないため、GracefulStop の前に呼んでください
+func (s *TodoServer) stop() {
+	s.stopOnce.Do(func() { close(s.stopping) })
+}
+
+func (s *TodoServer) CreateTodo(ctx context.Context, req *todov1.CreateTodoRequest) (*todov1.CreateTodoResponse, error) {
+	todo, err := s.useCase.AddTodo(ctx, domain.TodoInput{
+		Title:       req.GetTitle(),
+		Description: req.GetDescription(),
+		Priority:    priorityFromProto(req.GetPriority()),
+		DueDate:     timeFromProto(req.GetDueDate()),
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &todov1.CreateTodoResponse{Todo: todoToProto(todo)}, nil
+}
+
+func (s *TodoServer) GetTodo(ctx context.Context, req *todov1.GetTodoRequest) (*todov1.GetTodoResponse, error) {
+	id, err := todoID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	todo, err := s.useCase.GetTodoByID(ctx, id)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &todov1.GetTodoResponse{Todo: todoToProto(todo)}, nil
+}
+
+func (s *TodoServer) ListTodos(ctx context.Context, req *todov1.ListTodosRequest) (*todov1.ListTodosResponse, error) {
+	assignee, err := resolveUser(ctx, req.GetAssignee())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	todos, err := s.useCase.ListTodos(ctx, domain.TodoFilter{
+		IsCompleted: req.IsCompleted,
+		Priority:    priorityFromProto(req.GetPriority()),
+		Query:       req.GetQuery(),
+		DueBefore:   timeFromProto(req.GetDueBefore()),
+		AssigneeID:  assignee,
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	res := &todov1.ListTodosResponse{Todos: make([]*todov1.Todo, 0, len(todos))}
+	for _, todo := range todos {
+		res.Todos = append(res.Todos, todoToProto(todo))
+	}
+	return res, nil
+}
+
+func (s *TodoServer) UpdateTodo(ctx context.Context, req *todov1.UpdateTodoRequest) (*todov1.UpdateTodoResponse, error) {
+	id, err := todoID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	todo, err := s.useCase.UpdateTodo(ctx, id, domain.TodoInput{
+		Title:       req.GetTitle(),
+		Description: req.GetDescription(),
+		Priority:    priorityFromProto(req.GetPriority()),
+		DueDate:     timeFromProto(req.GetDueDate()),
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &todov1.UpdateTodoResponse{Todo: todoToProto(todo)}, nil
+}
+
+func (s *TodoServer) UpdateTodoStatus(ctx context.Context, req *todov1.UpdateTodoStatusRequest) (*todov1.UpdateTodoStatusResponse, error) {
+	id, err := todoID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.useCase.UpdateTodoStatus(ctx, id, req.GetIsCompleted()); err != nil {
+		return nil, toStatus(err)
+	}
+	todo, err := s.useCase.GetTodoByID(ctx, id)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &todov1.UpdateTodoStatusResponse{Todo: todoToProto(todo)}, nil
+}
+
+func (s *TodoServer) DeleteTodo(ctx context.Context, req *todov1.DeleteTodoRequest) (*todov1.DeleteTodoResponse, error) {
+	id, err := todoID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.useCase.DeleteTodo(ctx, id); err != nil {
+		return nil, toStatus(err)
+	}
+	return &todov1.DeleteTodoResponse{}, nil
+}
+
+func (s *TodoServer) WatchTodos(_ *todov1.WatchTodosRequest, stream grpc.ServerStreamingServer[todov1.WatchTodosResponse]) error {
+	ctx := stream.Context()
+	events := s.useCase.WatchTodos(ctx)
+	// 購読を始めたことをクライアントが確認できるよう、最初の変更を待たずにヘッダーを送る
+	if err := stream.SendHeader(metadata.MD{}); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case ev, ok := <-events:
+			if !ok {
+				if err := ctx.Err(); err != nil {
+					return toStatus(err)
+				}
+				return status.Error(codes.ResourceExhausted, "too many pending changes: list todos again and re-subscribe")
+			}
+			if err := stream.Send(eventToProto(ev)); err != nil {
+				return err
+			}
+		case <-s.stopping:
+			return status.Error(codes.Unavailable, "server is shutting down")
+		}
+	}
+}
+
+// resolveUser は REST API の assignee=me と同じく、"me" を認証済みのユーザーに置き換えます
+func resolveUser(ctx context.Context, userID string) (string, error) {
+	if userID != currentUser {
+		return userID, nil
+	}
+	user, ok := domain.UserIDFromContext(ctx)
+	if !ok {
+		return "", domain.ErrUnauthenticated
+	}
+	return user, nil
+}
+
+// todoID は ID が正の値であることを確かめてから int に変換します
+func todoID(id int64) (int, error) {
+	if id <= 0 {
+		return 0, status.Error(codes.InvalidArgument, "id must be a positive integer")
+	}
+	return int(id), nil
+}
+
+func todoToProto(todo *domain.Todo) *todov1.Todo {
+	return &todov1.Todo{
+		Id:          int64(todo.ID),
+		Title:       todo.Title,
+		Description: todo.Description,
+		IsCompleted: todo.IsCompleted,
+		Priority:    priorityToProto(todo.Priority),
+		DueDate:     timeToProto(todo.DueDate),
+		CreatedAt:   timestamppb.New(todo.CreatedAt),
+		UpdatedAt:   timestamppb.New(todo.UpdatedAt),
+		CompletedAt: timeToProto(todo.CompletedAt),
+		OwnerId:     todo.OwnerID,
+		AssigneeId:  todo.AssigneeID,
+		Watchers:    todo.Watchers,
+	}
+}
+
+func eventToProto(ev domain.TodoEvent) *todov1.WatchTodosResponse {
+	res := &todov1.WatchTodosResponse{Id: int64(ev.ID)}
+	switch ev.Type {
+	case domain.TodoCreated:
+		res.Type = todov1.EventType_EVENT_TYPE_CREATED
+	case domain.TodoUpdated:
+		res.Type = todov1.EventType_EVENT_TYPE_UPDATED
+	case domain.TodoDeleted:
+		res.Type = todov1.EventType_EVENT_TYPE_DELETED
+	}
+	if ev.Todo != nil {
+		res.Todo = todoToProto(ev.Todo)
+	}
+	return res
+}
+
+func priorityToProto(priority string) todov1.Priority {
+	switch priority {
+	case domain.PriorityLow:
+		return todov1.Priority_PRIORITY_LOW
+	case domain.PriorityMedium:
+		return todov1.Priority_PRIORITY_MEDIUM
+	case domain.PriorityHigh:
+		return todov1.Priority_PRIORITY_HIGH
+	default:
+		return todov1.Priority_PRIORITY_UNSPECIFIED
+	}
+}
+
+// priorityFromProto は優先度を文字列に変換します。
+// UNSPECIFIED は未指定 ("")、未知の値はそのまま数値の文字列にしてユースケースの検証でエラーにします
+func priorityFromProto(p todov1.Priority) string {
+	switch p {
+	case todov1.Priority_PRIORITY_UNSPECIFIED:
+		return ""
+	case todov1.Priority_PRIORITY_LOW:
+		return domain.PriorityLow
+	case todov1.Priority_PRIORITY_MEDIUM:
+		return domain.PriorityMedium
+	case todov1.Priority_PRIORITY_HIGH:
+		return domain.PriorityHigh
+	default:
+		return p.String()
+	}
+}
+
+func timeToProto(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
+
+func timeFromProto(ts *timestamppb.Timestamp) *time.Time {
+	if ts == nil {
+		return nil
+	}
+	t := ts.AsTime()
+	return &t
+}
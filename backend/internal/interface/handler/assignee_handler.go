@@ -0,0 +1,125 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"todo_app_golang/internal/domain"
+	"todo_app_golang/internal/interface/middleware"
+)
+
+// AssigneeHandler が必要とする機能をインターフェースとして定義
+type AssigneeUseCaseInterface interface {
+	AssignTodo(ctx context.Context, id int, assigneeID string) (*domain.Todo, error)
+	UnassignTodo(ctx context.Context, id int) (*domain.Todo, error)
+	AddTodoWatcher(ctx context.Context, id int, userID string) (*domain.Todo, error)
+	RemoveTodoWatcher(ctx context.Context, id int, userID string) (*domain.Todo, error)
+}
+
+type AssigneeHandler struct {
+	useCase AssigneeUseCaseInterface
+}
+
+func NewAssigneeHandler(uc AssigneeUseCaseInterface) *AssigneeHandler {
+	return &AssigneeHandler{useCase: uc}
+}
+
+// currentUser は自分自身を表すユーザー ID の指定です
+const currentUser = "me"
+
+// resolveUser は me を認証済みのユーザーに置き換えます。認証していない場合は domain.ErrUnauthenticated を返します
+func resolveUser(r *http.Request, userID string) (string, error) {
+	if userID != currentUser {
+		return userID, nil
+	}
+	user, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		return "", domain.ErrUnauthenticated
+	}
+	return user, nil
+}
+
+// AssignTodoHandler: PUT /todos/{id}/assignee。{"assignee_id": "bob"}（"me" は自分）で担当者を設定し、更新後のタスクを返します
+func (h *AssigneeHandler) AssignTodoHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	var req struct {
+		AssigneeID string `json:"assignee_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	assignee, err := resolveUser(r, req.AssigneeID)
+	if err != nil {
+		http.Error(w, err.Error(), inputErrorStatus(err))
+		return
+	}
+
+	todo, err := h.useCase.AssignTodo(r.Context(), id, assignee)
+	writeTodoResult(w, todo, err)
+}
+
+// UnassignTodoHandler: DELETE /todos/{id}/assignee。担当者を解除し、更新後のタスクを返します
+func (h *AssigneeHandler) UnassignTodoHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	todo, err := h.useCase.UnassignTodo(r.Context(), id)
+	writeTodoResult(w, todo, err)
+}
+
+// AddWatcherHandler: PUT /todos/{id}/watchers/{user}。ウォッチャーに追加し（"me" は自分）、更新後のタスクを返します
+func (h *AssigneeHandler) AddWatcherHandler(w http.ResponseWriter, r *http.Request) {
+	id, user, ok := watcherPath(w, r)
+	if !ok {
+		return
+	}
+
+	todo, err := h.useCase.AddTodoWatcher(r.Context(), id, user)
+	writeTodoResult(w, todo, err)
+}
+
+// RemoveWatcherHandler: DELETE /todos/{id}/watchers/{user}。ウォッチャーから外し（"me" は自分）、更新後のタスクを返します
+func (h *AssigneeHandler) RemoveWatcherHandler(w http.ResponseWriter, r *http.Request) {
+	id, user, ok := watcherPath(w, r)
+	if !ok {
+		return
+	}
+
+	todo, err := h.useCase.RemoveTodoWatcher(r.Context(), id, user)
+	writeTodoResult(w, todo, err)
+}
+
+// watcherPath はパスからタスクの ID とユーザーを読み取ります。読み取れない場合はエラーを書き込んで false を返します
+func watcherPath(w http.ResponseWriter, r *http.Request) (int, string, bool) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return 0, "", false
+	}
+	user, err := resolveUser(r, r.PathValue("user"))
+	if err != nil {
+		http.Error(w, err.Error(), inputErrorStatus(err))
+		return 0, "", false
+	}
+	return id, user, true
+}
+
+// writeTodoResult は更新後のタスク、またはエラーをレスポンスに書き込みます
+func writeTodoResult(w http.ResponseWriter, todo *domain.Todo, err error) {
+	if err != nil {
+		http.Error(w, err.Error(), inputErrorStatus(err))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(todo)
+}
@@ -0,0 +1,157 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"todo_app_golang/internal/domain"
+	"todo_app_golang/internal/interface/middleware"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockAssigneeUseCase struct {
+	mock.Mock
+}
+
+func (m *mockAssigneeUseCase) AssignTodo(ctx context.Context, id int, assigneeID string) (*domain.Todo, error) {
+	args := m.Called(ctx, id, assigneeID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Todo), args.Error(1)
+}
+
+func (m *mockAssigneeUseCase) UnassignTodo(ctx context.Context, id int) (*domain.Todo, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Todo), args.Error(1)
+}
+
+func (m *mockAssigneeUseCase) AddTodoWatcher(ctx context.Context, id int, userID string) (*domain.Todo, error) {
+	args := m.Called(ctx, id, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Todo), args.Error(1)
+}
+
+func (m *mockAssigneeUseCase) RemoveTodoWatcher(ctx context.Context, id int, userID string) (*domain.Todo, error) {
+	args := m.Called(ctx, id, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Todo), args.Error(1)
+}
+
+func TestAssigneeHandler_AssignTodoHandler(t *testing.T) {
+	t.Run("成功：me は認証済みのユーザーに置き換えて担当者を設定すること", func(t *testing.T) {
+		mockUC := new(mockAssigneeUseCase)
+		h := NewAssigneeHandler(mockUC)
+		assignee := "alice"
+		mockUC.On("AssignTodo", mock.Anything, 1, "alice").Return(&domain.Todo{ID: 1, Title: "タスク", AssigneeID: &assignee}, nil)
+
+		req := httptest.NewRequest(http.MethodPut, "/todos/1/assignee", bytes.NewBufferString(`{"assignee_id":"me"}`))
+		req.SetPathValue("id", "1")
+		req = req.WithContext(middleware.WithUserID(req.Context(), "alice"))
+		rr := httptest.NewRecorder()
+		h.AssignTodoHandler(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Contains(t, rr.Body.String(), `"assignee_id":"alice"`)
+		mockUC.AssertExpectations(t)
+	})
+
+	t.Run("失敗：認証していない場合に me を指定すると401を返すこと", func(t *testing.T) {
+		mockUC := new(mockAssigneeUseCase)
+		h := NewAssigneeHandler(mockUC)
+
+		req := httptest.NewRequest(http.MethodPut, "/todos/1/assignee", bytes.NewBufferString(`{"assignee_id":"me"}`))
+		req.SetPathValue("id", "1")
+		rr := httptest.NewRecorder()
+		h.AssignTodoHandler(rr, req)
+
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+		mockUC.AssertNotCalled(t, "AssignTodo", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("失敗：ユースケースのエラーに応じたステータスを返すこと", func(t *testing.T) {
+		for _, tc := range []struct {
+			err    error
+			status int
+		}{
+			{domain.ErrUserIDEmpty, http.StatusBadRequest},
+			{domain.ErrTodoForbidden, http.StatusForbidden},
+			{domain.ErrTodoNotFound, http.StatusNotFound},
+		} {
+			mockUC := new(mockAssigneeUseCase)
+			h := NewAssigneeHandler(mockUC)
+			mockUC.On("AssignTodo", mock.Anything, 1, mock.Anything).Return(nil, tc.err)
+
+			req := httptest.NewRequest(http.MethodPut, "/todos/1/assignee", bytes.NewBufferString(`{"assignee_id":"bob"}`))
+			req.SetPathValue("id", "1")
+			rr := httptest.NewRecorder()
+			h.AssignTodoHandler(rr, req)
+
+			assert.Equal(t, tc.status, rr.Code, tc.err.Error())
+		}
+	})
+}
+
+func TestAssigneeHandler_UnassignTodoHandler(t *testing.T) {
+	mockUC := new(mockAssigneeUseCase)
+	h := NewAssigneeHandler(mockUC)
+	mockUC.On("UnassignTodo", mock.Anything, 1).Return(&domain.Todo{ID: 1, Title: "タスク"}, nil)
+
+	req := httptest.NewRequest(http.MethodDelete, "/todos/1/assignee", nil)
+	req.SetPathValue("id", "1")
+	rr := httptest.NewRecorder()
+	h.UnassignTodoHandler(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"assignee_id":null`)
+	mockUC.AssertExpectations(t)
+}
+
+func TestAssigneeHandler_WatcherHandlers(t *testing.T) {
+	t.Run("成功：パスのユーザーをウォッチャーに追加・削除すること", func(t *testing.T) {
+		mockUC := new(mockAssigneeUseCase)
+		h := NewAssigneeHandler(mockUC)
+		mockUC.On("AddTodoWatcher", mock.Anything, 1, "bob").Return(&domain.Todo{ID: 1, Watchers: []string{"bob"}}, nil)
+		mockUC.On("RemoveTodoWatcher", mock.Anything, 1, "alice").Return(&domain.Todo{ID: 1, Watchers: []string{}}, nil)
+
+		req := httptest.NewRequest(http.MethodPut, "/todos/1/watchers/bob", nil)
+		req.SetPathValue("id", "1")
+		req.SetPathValue("user", "bob")
+		rr := httptest.NewRecorder()
+		h.AddWatcherHandler(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Contains(t, rr.Body.String(), `"watchers":["bob"]`)
+
+		req = httptest.NewRequest(http.MethodDelete, "/todos/1/watchers/me", nil)
+		req.SetPathValue("id", "1")
+		req.SetPathValue("user", "me")
+		req = req.WithContext(middleware.WithUserID(req.Context(), "alice"))
+		rr = httptest.NewRecorder()
+		h.RemoveWatcherHandler(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		mockUC.AssertExpectations(t)
+	})
+
+	t.Run("失敗：ID が数値でない場合は400を返すこと", func(t *testing.T) {
+		h := NewAssigneeHandler(new(mockAssigneeUseCase))
+
+		req := httptest.NewRequest(http.MethodPut, "/todos/abc/watchers/bob", nil)
+		req.SetPathValue("id", "abc")
+		req.SetPathValue("user", "bob")
+		rr := httptest.NewRecorder()
+		h.AddWatcherHandler(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+}
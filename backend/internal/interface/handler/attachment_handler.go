@@ -0,0 +1,168 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"todo_app_golang/internal/domain"
+)
+
+// multipartOverhead はファイル本体以外（境界やパートのヘッダー）に見込むリクエストボディの大きさです
+const multipartOverhead = 64 << 10
+
+// AttachmentHandler が必要とする機能をインターフェースとして定義
+type AttachmentUseCaseInterface interface {
+	MaxSize() int64
+	ListAttachments(ctx context.Context, todoID int) ([]*domain.Attachment, error)
+	Upload(ctx context.Context, todoID int, filename string, r io.Reader) (*domain.Attachment, error)
+	OpenAttachment(ctx context.Context, id int) (*domain.Attachment, io.ReadSeekCloser, error)
+	DeleteAttachment(ctx context.Context, id int) error
+}
+
+type AttachmentHandler struct {
+	useCase AttachmentUseCaseInterface
+}
+
+func NewAttachmentHandler(uc AttachmentUseCaseInterface) *AttachmentHandler {
+	return &AttachmentHandler{useCase: uc}
+}
+
+// ListAttachmentsHandler: GET /todos/{id}/attachments。添付ファイルの情報を古い順に返します
+func (h *AttachmentHandler) ListAttachmentsHandler(w http.ResponseWriter, r *http.Request) {
+	todoID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	attachments, err := h.useCase.ListAttachments(r.Context(), todoID)
+	if err != nil {
+		http.Error(w, err.Error(), attachmentErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(attachments)
+}
+
+// UploadAttachmentHandler: POST /todos/{id}/attachments
+// multipart/form-data の file フィールドを、メモリに溜めずにそのままユースケースに渡します
+func (h *AttachmentHandler) UploadAttachmentHandler(w http.ResponseWriter, r *http.Request) {
+	todoID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, h.useCase.MaxSize()+multipartOverhead)
+
+	file, filename, err := multipartFile(r, "file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			http.Error(w, domain.ErrAttachmentTooLarge.Error(), http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	attachment, err := h.useCase.Upload(r.Context(), todoID, filename, file)
+	if err != nil {
+		http.Error(w, err.Error(), attachmentErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Location", "/attachments/"+strconv.Itoa(attachment.ID))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(attachment)
+}
+
+// multipartFile は multipart/form-data のボディから name フィールドのパートを探して返します
+func multipartFile(r *http.Request, name string) (io.Reader, string, error) {
+	mr, err := r.MultipartReader()
+	if err != nil {
+		return nil, "", errors.New("multipart/form-data の file フィールドで送ってください")
+	}
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			return nil, "", errors.New("file フィールドがありません")
+		}
+		if err != nil {
+			return nil, "", err
+		}
+		if part.FormName() == name {
+			return part, part.FileName(), nil
+		}
+	}
+}
+
+// DownloadAttachmentHandler: GET /attachments/{id}
+// 保存時に判定した Content-Type で返し、Range リクエスト（途中からのダウンロードや PDF の部分読み込み）に対応します
+func (h *AttachmentHandler) DownloadAttachmentHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	attachment, content, err := h.useCase.OpenAttachment(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), attachmentErrorStatus(err))
+		return
+	}
+	defer content.Close()
+
+	header := w.Header()
+	header.Set("Content-Type", attachment.ContentType)
+	// 中身が同じなら ETag も同じになるため、If-None-Match / If-Range にそのまま使える
+	header.Set("ETag", `"`+attachment.SHA256+`"`)
+	header.Set("Cache-Control", "private, max-age=31536000, immutable")
+	// 判定した種類以外として解釈させず、万一スクリプトを含んでいても同じオリジンで実行させない
+	header.Set("X-Content-Type-Options", "nosniff")
+	header.Set("Content-Security-Policy", "sandbox")
+	header.Set("Content-Disposition", mime.FormatMediaType("inline", map[string]string{"filename": attachment.Filename}))
+	http.ServeContent(w, r, "", attachment.CreatedAt, content)
+}
+
+// DeleteAttachmentHandler: DELETE /attachments/{id}
+func (h *AttachmentHandler) DeleteAttachmentHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.useCase.DeleteAttachment(r.Context(), id); err != nil {
+		http.Error(w, err.Error(), attachmentErrorStatus(err))
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// attachmentErrorStatus は添付ファイルの操作のエラーに対応するステータスコードを返します
+func attachmentErrorStatus(err error) int {
+	var tooLarge *http.MaxBytesError
+	switch {
+	case errors.Is(err, domain.ErrAttachmentTooLarge), errors.As(err, &tooLarge):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, domain.ErrAttachmentTypeNotAllowed):
+		return http.StatusUnsupportedMediaType
+	case errors.Is(err, domain.ErrTodoForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, domain.ErrTodoNotFound), errors.Is(err, domain.ErrAttachmentNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrBlobNotFound):
+		// 情報はあるのに中身が無いのはサーバー側の不整合
+		return http.StatusInternalServerError
+	case errors.Is(err, domain.ErrAttachmentEmpty):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,193 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"todo_app_golang/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockAttachmentUseCase struct {
+	mock.Mock
+}
+
+func (m *mockAttachmentUseCase) MaxSize() int64 {
+	return 1 << 20
+}
+
+func (m *mockAttachmentUseCase) ListAttachments(ctx context.Context, todoID int) ([]*domain.Attachment, error) {
+	args := m.Called(ctx, todoID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Attachment), args.Error(1)
+}
+
+// Upload は受け取った中身を文字列にして記録します
+func (m *mockAttachmentUseCase) Upload(ctx context.Context, todoID int, filename string, r io.Reader) (*domain.Attachment, error) {
+	content, _ := io.ReadAll(r)
+	args := m.Called(ctx, todoID, filename, string(content))
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Attachment), args.Error(1)
+}
+
+func (m *mockAttachmentUseCase) OpenAttachment(ctx context.Context, id int) (*domain.Attachment, io.ReadSeekCloser, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, nil, args.Error(2)
+	}
+	return args.Get(0).(*domain.Attachment), args.Get(1).(io.ReadSeekCloser), args.Error(2)
+}
+
+func (m *mockAttachmentUseCase) DeleteAttachment(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+// newUploadRequest は file フィールドに content を入れた multipart/form-data のリクエストを作ります
+func newUploadRequest(t *testing.T, todoID, filename, content string) *http.Request {
+	t.Helper()
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	mw.WriteField("note", "ファイルより前のフィールドは読み飛ばす")
+	fw, _ := mw.CreateFormFile("file", filename)
+	io.WriteString(fw, content)
+	mw.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/todos/"+todoID+"/attachments", &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	req.SetPathValue("id", todoID)
+	return req
+}
+
+func TestAttachmentHandler_UploadAttachmentHandler(t *testing.T) {
+	t.Run("成功：file フィールドの中身とファイル名を渡すこと", func(t *testing.T) {
+		mockUC := new(mockAttachmentUseCase)
+		mockUC.On("Upload", mock.Anything, 1, "画面.png", "PNGDATA").
+			Return(&domain.Attachment{ID: 7, TodoID: 1, Filename: "画面.png"}, nil)
+
+		rr := httptest.NewRecorder()
+		NewAttachmentHandler(mockUC).UploadAttachmentHandler(rr, newUploadRequest(t, "1", "画面.png", "PNGDATA"))
+
+		assert.Equal(t, http.StatusCreated, rr.Code)
+		assert.Equal(t, "/attachments/7", rr.Header().Get("Location"))
+		mockUC.AssertExpectations(t)
+	})
+
+	t.Run("失敗：エラーに応じたステータスを返すこと", func(t *testing.T) {
+		for err, want := range map[error]int{
+			domain.ErrAttachmentTooLarge:       http.StatusRequestEntityTooLarge,
+			domain.ErrAttachmentTypeNotAllowed: http.StatusUnsupportedMediaType,
+			domain.ErrAttachmentEmpty:          http.StatusBadRequest,
+			domain.ErrTodoNotFound:             http.StatusNotFound,
+		} {
+			mockUC := new(mockAttachmentUseCase)
+			mockUC.On("Upload", mock.Anything, 1, "a.png", "data").Return(nil, err)
+
+			rr := httptest.NewRecorder()
+			NewAttachmentHandler(mockUC).UploadAttachmentHandler(rr, newUploadRequest(t, "1", "a.png", "data"))
+
+			assert.Equal(t, want, rr.Code, err.Error())
+		}
+	})
+
+	t.Run("失敗：multipart/form-data ではない場合は400を返すこと", func(t *testing.T) {
+		mockUC := new(mockAttachmentUseCase)
+
+		req := httptest.NewRequest(http.MethodPost, "/todos/1/attachments", strings.NewReader("PNGDATA"))
+		req.Header.Set("Content-Type", "image/png")
+		req.SetPathValue("id", "1")
+		rr := httptest.NewRecorder()
+		NewAttachmentHandler(mockUC).UploadAttachmentHandler(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		mockUC.AssertNotCalled(t, "Upload", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+// nopReadSeekCloser はテスト用に strings.Reader に何もしない Close を付けたものです
+type nopReadSeekCloser struct {
+	*strings.Reader
+}
+
+func (nopReadSeekCloser) Close() error { return nil }
+
+func TestAttachmentHandler_DownloadAttachmentHandler(t *testing.T) {
+	attachment := &domain.Attachment{
+		ID:          7,
+		Filename:    "見積書.pdf",
+		ContentType: "application/pdf",
+		Size:        10,
+		SHA256:      strings.Repeat("ab", 32),
+		CreatedAt:   time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC),
+	}
+
+	t.Run("成功：保存時の種類とファイル名で返すこと", func(t *testing.T) {
+		mockUC := new(mockAttachmentUseCase)
+		mockUC.On("OpenAttachment", mock.Anything, 7).Return(attachment, nopReadSeekCloser{strings.NewReader("0123456789")}, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/attachments/7", nil)
+		req.SetPathValue("id", "7")
+		rr := httptest.NewRecorder()
+		NewAttachmentHandler(mockUC).DownloadAttachmentHandler(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "application/pdf", rr.Header().Get("Content-Type"))
+		assert.Equal(t, `"`+attachment.SHA256+`"`, rr.Header().Get("ETag"))
+		assert.Equal(t, "nosniff", rr.Header().Get("X-Content-Type-Options"))
+		assert.Equal(t, "inline; filename*=utf-8''%E8%A6%8B%E7%A9%8D%E6%9B%B8.pdf", rr.Header().Get("Content-Disposition"))
+		assert.Equal(t, "bytes", rr.Header().Get("Accept-Ranges"))
+		assert.Equal(t, "0123456789", rr.Body.String())
+	})
+
+	t.Run("成功：Range を指定した場合は206で一部を返すこと", func(t *testing.T) {
+		mockUC := new(mockAttachmentUseCase)
+		mockUC.On("OpenAttachment", mock.Anything, 7).Return(attachment, nopReadSeekCloser{strings.NewReader("0123456789")}, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/attachments/7", nil)
+		req.Header.Set("Range", "bytes=2-5")
+		req.SetPathValue("id", "7")
+		rr := httptest.NewRecorder()
+		NewAttachmentHandler(mockUC).DownloadAttachmentHandler(rr, req)
+
+		assert.Equal(t, http.StatusPartialContent, rr.Code)
+		assert.Equal(t, "bytes 2-5/10", rr.Header().Get("Content-Range"))
+		assert.Equal(t, "2345", rr.Body.String())
+	})
+
+	t.Run("成功：ETag が一致する場合は304を返すこと", func(t *testing.T) {
+		mockUC := new(mockAttachmentUseCase)
+		mockUC.On("OpenAttachment", mock.Anything, 7).Return(attachment, nopReadSeekCloser{strings.NewReader("0123456789")}, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/attachments/7", nil)
+		req.Header.Set("If-None-Match", `"`+attachment.SHA256+`"`)
+		req.SetPathValue("id", "7")
+		rr := httptest.NewRecorder()
+		NewAttachmentHandler(mockUC).DownloadAttachmentHandler(rr, req)
+
+		assert.Equal(t, http.StatusNotModified, rr.Code)
+	})
+
+	t.Run("失敗：存在しない場合は404を返すこと", func(t *testing.T) {
+		mockUC := new(mockAttachmentUseCase)
+		mockUC.On("OpenAttachment", mock.Anything, 99).Return(nil, nil, domain.ErrAttachmentNotFound)
+
+		req := httptest.NewRequest(http.MethodGet, "/attachments/99", nil)
+		req.SetPathValue("id", "99")
+		rr := httptest.NewRecorder()
+		NewAttachmentHandler(mockUC).DownloadAttachmentHandler(rr, req)
+
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+}
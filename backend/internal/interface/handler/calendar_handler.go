@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strings"
+	"todo_app_golang/internal/interface/ical"
+	"todo_app_golang/internal/logging"
+)
+
+// maxImportBytes はインポートで受け付けるファイルサイズの上限です
+const maxImportBytes = 5 << 20
+
+type CalendarHandler struct {
+	useCase TodoUseCaseInterface
+	// feedToken はフィード購読用のトークン（カレンダーアプリはヘッダーを送れないためクエリでも受け付ける）
+	feedToken string
+}
+
+func NewCalendarHandler(uc TodoUseCaseInterface, feedToken string) *CalendarHandler {
+	return &CalendarHandler{useCase: uc, feedToken: feedToken}
+}
+
+// CalendarFeedHandler: GET /calendar.ics
+func (h *CalendarHandler) CalendarFeedHandler(w http.ResponseWriter, r *http.Request) {
+	if !h.authorized(r) {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="calendar"`)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	todos, err := h.useCase.GetAllTodos(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	opts := ical.EncodeOptions{
+		WithEvents: r.URL.Query().Get("events") == "true",
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", `inline; filename="todos.ics"`)
+	if err := ical.Encode(w, todos, opts); err != nil {
+		// ヘッダーは送信済みのためステータスは変えられない（クライアントの切断など）
+		logging.FromContext(r.Context()).WarnContext(r.Context(), "Failed to write calendar feed", slog.Any("error", err))
+	}
+}
+
+// authorized はクエリの token または Authorization ヘッダーのトークンを検証します
+func (h *CalendarHandler) authorized(r *http.Request) bool {
+	if h.feedToken == "" {
+		// トークン未設定の場合はフィードを公開しない
+		return false
+	}
+
+	token := r.URL.Query().Get("token")
+	if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+		token = strings.TrimPrefix(auth, "Bearer ")
+	}
+	return subtle.ConstantTimeCompare([]byte(token), []byte(h.feedToken)) == 1
+}
+
+// ImportICSHandler: POST /todos/import/ics
+// multipart/form-data の file フィールド、またはリクエストボディそのものを .ics として受け付けます
+func (h *CalendarHandler) ImportICSHandler(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportBytes)
+
+	body, err := importFile(r)
+	if err != nil {
+		http.Error(w, "ファイルを読み込めません", http.StatusBadRequest)
+		return
+	}
+	defer body.Close()
+
+	decoded, err := ical.Decode(body)
+	if err != nil {
+		if errors.Is(err, ical.ErrNotCalendar) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, "ファイルを読み込めません", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.useCase.ImportTodos(r.Context(), decoded.Todos)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	// 解析時に除外した要素も結果に含める
+	result.Skipped = append(decoded.Skipped, result.Skipped...)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// importFile はアップロードされたファイルの内容を返します
+func importFile(r *http.Request) (io.ReadCloser, error) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != "multipart/form-data" {
+		return r.Body, nil
+	}
+
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		return nil, err
+	}
+	return file, nil
+}
@@ -0,0 +1,162 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"todo_app_golang/internal/domain"
+	"todo_app_golang/internal/logging"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// failingWriter はクライアントが切断した場合のように、本文の書き込みに失敗する ResponseWriter です
+type failingWriter struct {
+	*httptest.ResponseRecorder
+}
+
+func (w *failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("connection reset by peer")
+}
+
+func (w *failingWriter) WriteString(string) (int, error) {
+	return 0, errors.New("connection reset by peer")
+}
+
+func TestCalendarHandler_CalendarFeedHandler(t *testing.T) {
+	t.Run("成功：正しいトークンで VCALENDAR が返ること", func(t *testing.T) {
+		mockUC := new(mockTodoUseCase)
+		h := NewCalendarHandler(mockUC, "secret")
+		mockUC.On("GetAllTodos", mock.Anything).Return([]*domain.Todo{{ID: 1, Title: "タスク"}}, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/calendar.ics?token=secret", nil)
+		rr := httptest.NewRecorder()
+
+		h.CalendarFeedHandler(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "text/calendar; charset=utf-8", rr.Header().Get("Content-Type"))
+		assert.Contains(t, rr.Body.String(), "BEGIN:VTODO")
+		mockUC.AssertExpectations(t)
+	})
+
+	t.Run("成功：Authorization ヘッダーのトークンでも取得できること", func(t *testing.T) {
+		mockUC := new(mockTodoUseCase)
+		h := NewCalendarHandler(mockUC, "secret")
+		mockUC.On("GetAllTodos", mock.Anything).Return([]*domain.Todo{}, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/calendar.ics", nil)
+		req.Header.Set("Authorization", "Bearer secret")
+		rr := httptest.NewRecorder()
+
+		h.CalendarFeedHandler(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+	})
+
+	t.Run("失敗：書き込めない場合はエラーをログに出力すること", func(t *testing.T) {
+		mockUC := new(mockTodoUseCase)
+		h := NewCalendarHandler(mockUC, "secret")
+		mockUC.On("GetAllTodos", mock.Anything).Return([]*domain.Todo{{ID: 1, Title: "タスク"}}, nil)
+
+		var logs bytes.Buffer
+		req := httptest.NewRequest(http.MethodGet, "/calendar.ics?token=secret", nil)
+		req = req.WithContext(logging.WithLogger(req.Context(), slog.New(slog.NewTextHandler(&logs, nil))))
+
+		h.CalendarFeedHandler(&failingWriter{ResponseRecorder: httptest.NewRecorder()}, req)
+
+		assert.Contains(t, logs.String(), "Failed to write calendar feed")
+	})
+
+	t.Run("失敗：トークンが誤っている場合に401を返すこと", func(t *testing.T) {
+		mockUC := new(mockTodoUseCase)
+		h := NewCalendarHandler(mockUC, "secret")
+
+		req := httptest.NewRequest(http.MethodGet, "/calendar.ics?token=wrong", nil)
+		rr := httptest.NewRecorder()
+
+		h.CalendarFeedHandler(rr, req)
+
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+		mockUC.AssertNotCalled(t, "GetAllTodos", mock.Anything)
+	})
+
+	t.Run("失敗：トークンが未設定の場合はフィードが無効であること", func(t *testing.T) {
+		mockUC := new(mockTodoUseCase)
+		h := NewCalendarHandler(mockUC, "")
+
+		req := httptest.NewRequest(http.MethodGet, "/calendar.ics?token=", nil)
+		rr := httptest.NewRecorder()
+
+		h.CalendarFeedHandler(rr, req)
+
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	})
+}
+
+func TestCalendarHandler_ImportICSHandler(t *testing.T) {
+	ics := "BEGIN:VCALENDAR\r\nBEGIN:VTODO\r\nUID:a@example.com\r\nSUMMARY:インポート\r\nEND:VTODO\r\n" +
+		"BEGIN:VJOURNAL\r\nUID:j@example.com\r\nEND:VJOURNAL\r\nEND:VCALENDAR\r\n"
+
+	t.Run("成功：multipart でアップロードした .ics が取り込まれること", func(t *testing.T) {
+		mockUC := new(mockTodoUseCase)
+		h := NewCalendarHandler(mockUC, "")
+		mockUC.On("ImportTodos", mock.Anything, mock.MatchedBy(func(todos []*domain.Todo) bool {
+			return len(todos) == 1 && todos[0].Title == "インポート"
+		})).Return(&domain.ImportResult{Created: 1, Skipped: []domain.ImportSkipped{}}, nil)
+
+		var body bytes.Buffer
+		mw := multipart.NewWriter(&body)
+		fw, _ := mw.CreateFormFile("file", "todos.ics")
+		fw.Write([]byte(ics))
+		mw.Close()
+
+		req := httptest.NewRequest(http.MethodPost, "/todos/import/ics", &body)
+		req.Header.Set("Content-Type", mw.FormDataContentType())
+		rr := httptest.NewRecorder()
+
+		h.ImportICSHandler(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		var result domain.ImportResult
+		assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &result))
+		assert.Equal(t, 1, result.Created)
+		assert.Len(t, result.Skipped, 1)
+		assert.Equal(t, "VJOURNAL", result.Skipped[0].Component)
+		mockUC.AssertExpectations(t)
+	})
+
+	t.Run("成功：ボディに直接 .ics を送った場合も取り込まれること", func(t *testing.T) {
+		mockUC := new(mockTodoUseCase)
+		h := NewCalendarHandler(mockUC, "")
+		mockUC.On("ImportTodos", mock.Anything, mock.Anything).Return(&domain.ImportResult{Created: 1, Skipped: []domain.ImportSkipped{}}, nil)
+
+		req := httptest.NewRequest(http.MethodPost, "/todos/import/ics", bytes.NewBufferString(ics))
+		req.Header.Set("Content-Type", "text/calendar")
+		rr := httptest.NewRecorder()
+
+		h.ImportICSHandler(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		mockUC.AssertExpectations(t)
+	})
+
+	t.Run("失敗：iCalendar 形式でない場合に400を返すこと", func(t *testing.T) {
+		mockUC := new(mockTodoUseCase)
+		h := NewCalendarHandler(mockUC, "")
+
+		req := httptest.NewRequest(http.MethodPost, "/todos/import/ics", bytes.NewBufferString("not a calendar"))
+		rr := httptest.NewRecorder()
+
+		h.ImportICSHandler(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		mockUC.AssertNotCalled(t, "ImportTodos", mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"todo_app_golang/internal/domain"
+	"todo_app_golang/internal/interface/middleware"
+)
+
+// CommentHandler が必要とする機能をインターフェースとして定義
+type CommentUseCaseInterface interface {
+	ListComments(ctx context.Context, todoID int) ([]*domain.Comment, error)
+	AddComment(ctx context.Context, todoID int, author, body string) (*domain.Comment, error)
+	EditComment(ctx context.Context, id int, user, body string) (*domain.Comment, error)
+	DeleteComment(ctx context.Context, id int, user string) error
+}
+
+type CommentHandler struct {
+	useCase CommentUseCaseInterface
+}
+
+func NewCommentHandler(uc CommentUseCaseInterface) *CommentHandler {
+	return &CommentHandler{useCase: uc}
+}
+
+// commentRequest は作成・編集のリクエストボディです。
+// author は認証していない場合に使う投稿者名で、認証済みの場合は無視してユーザー ID を使います
+type commentRequest struct {
+	Body   string `json:"body"`
+	Author string `json:"author"`
+}
+
+// ListCommentsHandler: GET /todos/{id}/comments。コメントを古い順に返します
+func (h *CommentHandler) ListCommentsHandler(w http.ResponseWriter, r *http.Request) {
+	todoID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	comments, err := h.useCase.ListComments(r.Context(), todoID)
+	if err != nil {
+		http.Error(w, err.Error(), commentErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(comments)
+}
+
+// CreateCommentHandler: POST /todos/{id}/comments。作成したコメントを返します
+func (h *CommentHandler) CreateCommentHandler(w http.ResponseWriter, r *http.Request) {
+	todoID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	var req commentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	author := req.Author
+	if user, ok := middleware.UserIDFromContext(r.Context()); ok {
+		author = user
+	}
+
+	comment, err := h.useCase.AddComment(r.Context(), todoID, author, req.Body)
+	if err != nil {
+		http.Error(w, err.Error(), commentErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Location", "/comments/"+strconv.Itoa(comment.ID))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(comment)
+}
+
+// UpdateCommentHandler: PATCH /comments/{id}。本文を書き換え、編集履歴を含めた更新後のコメントを返します
+func (h *CommentHandler) UpdateCommentHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	var req commentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	comment, err := h.useCase.EditComment(r.Context(), id, commentUser(r, req.Author), req.Body)
+	if err != nil {
+		http.Error(w, err.Error(), commentErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(comment)
+}
+
+// DeleteCommentHandler: DELETE /comments/{id}
+func (h *CommentHandler) DeleteCommentHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.useCase.DeleteComment(r.Context(), id, commentUser(r, "")); err != nil {
+		http.Error(w, err.Error(), commentErrorStatus(err))
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// commentUser は投稿者本人かを確認する相手です。認証済みならユーザー ID、そうでなければリクエストで名乗った投稿者名（省略した場合は確認しない）
+func commentUser(r *http.Request, author string) string {
+	if user, ok := middleware.UserIDFromContext(r.Context()); ok {
+		return user
+	}
+	return author
+}
+
+// commentErrorStatus はコメントの操作のエラーに対応するステータスコードを返します
+func commentErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrCommentBodyEmpty), errors.Is(err, domain.ErrCommentTooLong), errors.Is(err, domain.ErrCommentAuthorEmpty):
+		return http.StatusBadRequest
+	case errors.Is(err, domain.ErrCommentForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, domain.ErrTodoNotFound), errors.Is(err, domain.ErrCommentNotFound):
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
+	}
+}
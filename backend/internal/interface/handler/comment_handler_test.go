@@ -0,0 +1,132 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"todo_app_golang/internal/domain"
+	"todo_app_golang/internal/interface/middleware"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockCommentUseCase struct {
+	mock.Mock
+}
+
+func (m *mockCommentUseCase) ListComments(ctx context.Context, todoID int) ([]*domain.Comment, error) {
+	args := m.Called(ctx, todoID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Comment), args.Error(1)
+}
+
+func (m *mockCommentUseCase) AddComment(ctx context.Context, todoID int, author, body string) (*domain.Comment, error) {
+	args := m.Called(ctx, todoID, author, body)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Comment), args.Error(1)
+}
+
+func (m *mockCommentUseCase) EditComment(ctx context.Context, id int, user, body string) (*domain.Comment, error) {
+	args := m.Called(ctx, id, user, body)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Comment), args.Error(1)
+}
+
+func (m *mockCommentUseCase) DeleteComment(ctx context.Context, id int, user string) error {
+	args := m.Called(ctx, id, user)
+	return args.Error(0)
+}
+
+func TestCommentHandler_CreateCommentHandler(t *testing.T) {
+	t.Run("成功：認証していない場合はリクエストの投稿者名で作成すること", func(t *testing.T) {
+		mockUC := new(mockCommentUseCase)
+		mockUC.On("AddComment", mock.Anything, 1, "alice", "**確認**しました").
+			Return(&domain.Comment{ID: 3, TodoID: 1, Author: "alice", Body: "**確認**しました"}, nil)
+
+		req := httptest.NewRequest(http.MethodPost, "/todos/1/comments", strings.NewReader(`{"author":"alice","body":"**確認**しました"}`))
+		req.SetPathValue("id", "1")
+		rr := httptest.NewRecorder()
+		NewCommentHandler(mockUC).CreateCommentHandler(rr, req)
+
+		assert.Equal(t, http.StatusCreated, rr.Code)
+		assert.Equal(t, "/comments/3", rr.Header().Get("Location"))
+		mockUC.AssertExpectations(t)
+	})
+
+	t.Run("成功：認証済みの場合はユーザー ID を投稿者にすること", func(t *testing.T) {
+		mockUC := new(mockCommentUseCase)
+		mockUC.On("AddComment", mock.Anything, 1, "user-1", "本文").Return(&domain.Comment{ID: 3}, nil)
+
+		req := httptest.NewRequest(http.MethodPost, "/todos/1/comments", strings.NewReader(`{"author":"alice","body":"本文"}`))
+		req = req.WithContext(middleware.WithUserID(req.Context(), "user-1"))
+		req.SetPathValue("id", "1")
+		rr := httptest.NewRecorder()
+		NewCommentHandler(mockUC).CreateCommentHandler(rr, req)
+
+		assert.Equal(t, http.StatusCreated, rr.Code)
+		mockUC.AssertExpectations(t)
+	})
+
+	t.Run("失敗：タスクが存在しない場合は404を返すこと", func(t *testing.T) {
+		mockUC := new(mockCommentUseCase)
+		mockUC.On("AddComment", mock.Anything, 9, "alice", "本文").Return(nil, domain.ErrTodoNotFound)
+
+		req := httptest.NewRequest(http.MethodPost, "/todos/9/comments", strings.NewReader(`{"author":"alice","body":"本文"}`))
+		req.SetPathValue("id", "9")
+		rr := httptest.NewRecorder()
+		NewCommentHandler(mockUC).CreateCommentHandler(rr, req)
+
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+}
+
+func TestCommentHandler_UpdateCommentHandler(t *testing.T) {
+	t.Run("失敗：ほかの人のコメントの場合は403を返すこと", func(t *testing.T) {
+		mockUC := new(mockCommentUseCase)
+		mockUC.On("EditComment", mock.Anything, 3, "user-2", "書き換え").Return(nil, domain.ErrCommentForbidden)
+
+		req := httptest.NewRequest(http.MethodPatch, "/comments/3", strings.NewReader(`{"body":"書き換え"}`))
+		req = req.WithContext(middleware.WithUserID(req.Context(), "user-2"))
+		req.SetPathValue("id", "3")
+		rr := httptest.NewRecorder()
+		NewCommentHandler(mockUC).UpdateCommentHandler(rr, req)
+
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+		mockUC.AssertExpectations(t)
+	})
+
+	t.Run("失敗：本文が空の場合は400を返すこと", func(t *testing.T) {
+		mockUC := new(mockCommentUseCase)
+		mockUC.On("EditComment", mock.Anything, 3, "", " ").Return(nil, domain.ErrCommentBodyEmpty)
+
+		req := httptest.NewRequest(http.MethodPatch, "/comments/3", strings.NewReader(`{"body":" "}`))
+		req.SetPathValue("id", "3")
+		rr := httptest.NewRecorder()
+		NewCommentHandler(mockUC).UpdateCommentHandler(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+}
+
+func TestCommentHandler_DeleteCommentHandler(t *testing.T) {
+	t.Run("失敗：存在しない場合は404を返すこと", func(t *testing.T) {
+		mockUC := new(mockCommentUseCase)
+		mockUC.On("DeleteComment", mock.Anything, 99, "").Return(domain.ErrCommentNotFound)
+
+		req := httptest.NewRequest(http.MethodDelete, "/comments/99", nil)
+		req.SetPathValue("id", "99")
+		rr := httptest.NewRecorder()
+		NewCommentHandler(mockUC).DeleteCommentHandler(rr, req)
+
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+}
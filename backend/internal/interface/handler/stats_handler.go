@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+	"todo_app_golang/internal/domain"
+)
+
+type StatsUseCaseInterface interface {
+	GetStats(ctx context.Context, from, to time.Time, loc *time.Location) (*domain.Stats, error)
+	GetLeadTimes(ctx context.Context, from, to time.Time, loc *time.Location) (*domain.LeadTimeStats, error)
+	GetWeeklyThroughput(ctx context.Context, from, to time.Time, loc *time.Location) ([]domain.WeeklyCount, error)
+}
+
+type StatsHandler struct {
+	useCase StatsUseCaseInterface
+}
+
+func NewStatsHandler(uc StatsUseCaseInterface) *StatsHandler {
+	return &StatsHandler{useCase: uc}
+}
+
+// GetStatsHandler: GET /stats?from=YYYY-MM-DD&to=YYYY-MM-DD&tz=Asia/Tokyo
+func (h *StatsHandler) GetStatsHandler(w http.ResponseWriter, r *http.Request) {
+	from, to, loc, ok := parseStatsQuery(w, r)
+	if !ok {
+		return
+	}
+
+	stats, err := h.useCase.GetStats(r.Context(), from, to, loc)
+	writeStatsResponse(w, stats, err)
+}
+
+// GetLeadTimesHandler: GET /analytics/lead-time?from=YYYY-MM-DD&to=YYYY-MM-DD&tz=Asia/Tokyo
+func (h *StatsHandler) GetLeadTimesHandler(w http.ResponseWriter, r *http.Request) {
+	from, to, loc, ok := parseStatsQuery(w, r)
+	if !ok {
+		return
+	}
+
+	stats, err := h.useCase.GetLeadTimes(r.Context(), from, to, loc)
+	writeStatsResponse(w, stats, err)
+}
+
+// GetThroughputHandler: GET /analytics/throughput?from=YYYY-MM-DD&to=YYYY-MM-DD&tz=Asia/Tokyo
+func (h *StatsHandler) GetThroughputHandler(w http.ResponseWriter, r *http.Request) {
+	from, to, loc, ok := parseStatsQuery(w, r)
+	if !ok {
+		return
+	}
+
+	counts, err := h.useCase.GetWeeklyThroughput(r.Context(), from, to, loc)
+	writeStatsResponse(w, counts, err)
+}
+
+// parseStatsQuery は from / to / tz クエリを解釈します。不正な場合は400を返して false を返します
+func parseStatsQuery(w http.ResponseWriter, r *http.Request) (time.Time, time.Time, *time.Location, bool) {
+	query := r.URL.Query()
+
+	loc := time.UTC
+	if tz := query.Get("tz"); tz != "" {
+		l, err := time.LoadLocation(tz)
+		if err != nil {
+			http.Error(w, "Invalid tz", http.StatusBadRequest)
+			return time.Time{}, time.Time{}, nil, false
+		}
+		loc = l
+	}
+
+	var from, to time.Time
+	for name, dst := range map[string]*time.Time{"from": &from, "to": &to} {
+		v := query.Get(name)
+		if v == "" {
+			continue
+		}
+		d, err := time.ParseInLocation("2006-01-02", v, loc)
+		if err != nil {
+			http.Error(w, "Invalid "+name, http.StatusBadRequest)
+			return time.Time{}, time.Time{}, nil, false
+		}
+		*dst = d
+	}
+	return from, to, loc, true
+}
+
+func writeStatsResponse(w http.ResponseWriter, v any, err error) {
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidRange) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,150 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"todo_app_golang/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockStatsUseCase struct {
+	mock.Mock
+}
+
+func (m *mockStatsUseCase) GetStats(ctx context.Context, from, to time.Time, loc *time.Location) (*domain.Stats, error) {
+	args := m.Called(ctx, from, to, loc)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Stats), args.Error(1)
+}
+
+func (m *mockStatsUseCase) GetLeadTimes(ctx context.Context, from, to time.Time, loc *time.Location) (*domain.LeadTimeStats, error) {
+	args := m.Called(ctx, from, to, loc)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.LeadTimeStats), args.Error(1)
+}
+
+func (m *mockStatsUseCase) GetWeeklyThroughput(ctx context.Context, from, to time.Time, loc *time.Location) ([]domain.WeeklyCount, error) {
+	args := m.Called(ctx, from, to, loc)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.WeeklyCount), args.Error(1)
+}
+
+func TestStatsHandler_GetStatsHandler(t *testing.T) {
+	t.Run("成功：期間とタイムゾーンを指定して集計結果が返ること", func(t *testing.T) {
+		mockUC := new(mockStatsUseCase)
+		h := NewStatsHandler(mockUC)
+		tokyo, _ := time.LoadLocation("Asia/Tokyo")
+
+		mockUC.On("GetStats", mock.Anything,
+			time.Date(2026, 10, 1, 0, 0, 0, 0, tokyo),
+			time.Date(2026, 10, 7, 0, 0, 0, 0, tokyo),
+			tokyo,
+		).Return(&domain.Stats{Total: 2, Completed: 1, CompletionRate: 50}, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/stats?from=2026-10-01&to=2026-10-07&tz=Asia/Tokyo", nil)
+		rr := httptest.NewRecorder()
+
+		h.GetStatsHandler(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Contains(t, rr.Body.String(), `"completion_rate":50`)
+		mockUC.AssertExpectations(t)
+	})
+
+	t.Run("失敗：日付の形式が不正な場合に400を返すこと", func(t *testing.T) {
+		mockUC := new(mockStatsUseCase)
+		h := NewStatsHandler(mockUC)
+
+		req := httptest.NewRequest(http.MethodGet, "/stats?from=10/01", nil)
+		rr := httptest.NewRecorder()
+
+		h.GetStatsHandler(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("失敗：不明なタイムゾーンの場合に400を返すこと", func(t *testing.T) {
+		mockUC := new(mockStatsUseCase)
+		h := NewStatsHandler(mockUC)
+
+		req := httptest.NewRequest(http.MethodGet, "/stats?tz=Mars/Olympus", nil)
+		rr := httptest.NewRecorder()
+
+		h.GetStatsHandler(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("失敗：期間が不正な場合に400を返すこと", func(t *testing.T) {
+		mockUC := new(mockStatsUseCase)
+		h := NewStatsHandler(mockUC)
+		mockUC.On("GetStats", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, domain.ErrInvalidRange)
+
+		req := httptest.NewRequest(http.MethodGet, "/stats?from=2026-10-07&to=2026-10-01", nil)
+		rr := httptest.NewRecorder()
+
+		h.GetStatsHandler(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+}
+
+func TestStatsHandler_GetLeadTimesHandler(t *testing.T) {
+	mockUC := new(mockStatsUseCase)
+	h := NewStatsHandler(mockUC)
+	mockUC.On("GetLeadTimes", mock.Anything, time.Time{}, time.Time{}, time.UTC).Return(&domain.LeadTimeStats{
+		Overall:    domain.LeadTime{Count: 2, MedianHours: 5, P90Hours: 9},
+		ByPriority: map[string]domain.LeadTime{},
+		ByProject:  map[string]domain.LeadTime{},
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/analytics/lead-time", nil)
+	rr := httptest.NewRecorder()
+
+	h.GetLeadTimesHandler(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"overall":{"count":2,"median_hours":5,"p90_hours":9},"by_priority":{},"by_project":{}}`, rr.Body.String())
+	mockUC.AssertExpectations(t)
+}
+
+func TestStatsHandler_GetThroughputHandler(t *testing.T) {
+	t.Run("成功：週別の完了件数が返ること", func(t *testing.T) {
+		mockUC := new(mockStatsUseCase)
+		h := NewStatsHandler(mockUC)
+		mockUC.On("GetWeeklyThroughput", mock.Anything, mock.Anything, mock.Anything, time.UTC).
+			Return([]domain.WeeklyCount{{WeekStart: "2026-10-05", Count: 3}, {WeekStart: "2026-10-12", Count: 1}}, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/analytics/throughput?from=2026-10-05&to=2026-10-15", nil)
+		rr := httptest.NewRecorder()
+
+		h.GetThroughputHandler(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.JSONEq(t, `[{"week_start":"2026-10-05","count":3},{"week_start":"2026-10-12","count":1}]`, rr.Body.String())
+	})
+
+	t.Run("失敗：ユースケースのエラー時に500を返すこと", func(t *testing.T) {
+		mockUC := new(mockStatsUseCase)
+		h := NewStatsHandler(mockUC)
+		mockUC.On("GetWeeklyThroughput", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, context.DeadlineExceeded)
+
+		req := httptest.NewRequest(http.MethodGet, "/analytics/throughput", nil)
+		rr := httptest.NewRecorder()
+
+		h.GetThroughputHandler(rr, req)
+
+		assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	})
+}
@@ -3,18 +3,23 @@ package handler
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
+	"time"
 	"todo_app_golang/internal/domain"
 )
 
 // ハンドラーが必要とする機能をインターフェースとして定義
 type TodoUseCaseInterface interface {
-	CreateTodo(ctx context.Context, title string) error
+	AddTodo(ctx context.Context, in domain.TodoInput) (*domain.Todo, error)
 	GetAllTodos(ctx context.Context) ([]*domain.Todo, error)
+	ListTodos(ctx context.Context, filter domain.TodoFilter) ([]*domain.Todo, error)
+	UpdateTodo(ctx context.Context, id int, in domain.TodoInput) (*domain.Todo, error)
 	DeleteTodo(ctx context.Context, id int) error
 	UpdateTodoStatus(ctx context.Context, id int, isCompleted bool) error
 	GetTodoByID(ctx context.Context, id int) (*domain.Todo, error)
+	ImportTodos(ctx context.Context, todos []*domain.Todo) (*domain.ImportResult, error)
 }
 
 type TodoHandler struct {
@@ -27,38 +32,136 @@ func NewTodoHandler(uc TodoUseCaseInterface) *TodoHandler {
 	return &TodoHandler{useCase: uc}
 }
 
-// CreateTodoHandler: POST /todos
+// todoRequest は作成・更新のリクエストボディです
+type todoRequest struct {
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	Priority    string     `json:"priority"` // 省略した場合は medium
+	DueDate     *time.Time `json:"due_date"`
+}
+
+func (req todoRequest) input() domain.TodoInput {
+	return domain.TodoInput{Title: req.Title, Description: req.Description, Priority: req.Priority, DueDate: req.DueDate}
+}
+
+// CreateTodoHandler: POST /todos。作成したタスクを返します
 func (h *TodoHandler) CreateTodoHandler(w http.ResponseWriter, r *http.Request) {
-	var req struct {
-		Title string `json:"title"`
-	}
+	var req todoRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, "無効なリクエストボディです", http.StatusBadRequest)
 		return
 	}
 
-	err := h.useCase.CreateTodo(r.Context(), req.Title)
+	todo, err := h.useCase.AddTodo(r.Context(), req.input())
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, err.Error(), inputErrorStatus(err))
 		return
 	}
 
+	w.Header().Set("Location", "/todos/"+strconv.Itoa(todo.ID))
+	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
-	w.Write([]byte("Todo Created"))
+	json.NewEncoder(w).Encode(todo)
 }
 
-// GetAllTodosHandler: GET /todos
+// GetAllTodosHandler: GET /todos?completed=false&priority=high&q=資料&due_before=2026-11-01T00:00:00Z&assignee=me（条件はすべて省略可）
 func (h *TodoHandler) GetAllTodosHandler(w http.ResponseWriter, r *http.Request) {
-	todos, err := h.useCase.GetAllTodos(r.Context())
+	filter, err := parseTodoFilter(r)
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, domain.ErrUnauthenticated) {
+			status = http.StatusUnauthorized
+		}
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	var todos []*domain.Todo
+	if filter == (domain.TodoFilter{}) {
+		todos, err = h.useCase.GetAllTodos(r.Context())
+	} else {
+		todos, err = h.useCase.ListTodos(r.Context(), filter)
+	}
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	if todos == nil {
+		todos = []*domain.Todo{} // 0件の場合も null ではなく [] を返す
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(todos)
 }
 
+// parseTodoFilter は一覧の絞り込み条件をクエリから読み取ります
+func parseTodoFilter(r *http.Request) (domain.TodoFilter, error) {
+	query := r.URL.Query()
+	filter := domain.TodoFilter{Priority: query.Get("priority"), Query: query.Get("q")}
+	if v := query.Get("completed"); v != "" {
+		completed, err := strconv.ParseBool(v)
+		if err != nil {
+			return filter, errors.New("Invalid completed")
+		}
+		filter.IsCompleted = &completed
+	}
+	if v := query.Get("due_before"); v != "" {
+		due, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return filter, errors.New("Invalid due_before")
+		}
+		filter.DueBefore = &due
+	}
+	if v := query.Get("assignee"); v != "" {
+		assignee, err := resolveUser(r, v)
+		if err != nil {
+			return filter, err
+		}
+		filter.AssigneeID = assignee
+	}
+	return filter, nil
+}
+
+// UpdateTodoHandler: PUT /todos/{id}。タイトル・詳細説明・優先度・期限を置き換え（完了状態は変えない）、更新後のタスクを返します
+func (h *TodoHandler) UpdateTodoHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	var req todoRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	todo, err := h.useCase.UpdateTodo(r.Context(), id, req.input())
+	if err != nil {
+		http.Error(w, err.Error(), inputErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(todo)
+}
+
+// inputErrorStatus は作成・更新のエラーに対応するステータスコードを返します
+func inputErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrTitleEmpty), errors.Is(err, domain.ErrInvalidPriority), errors.Is(err, domain.ErrUserIDEmpty):
+		return http.StatusBadRequest
+	case errors.Is(err, domain.ErrUnauthenticated):
+		return http.StatusUnauthorized
+	case errors.Is(err, domain.ErrTodoForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, domain.ErrTodoNotFound):
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
 func (h *TodoHandler) DeleteTodoHandler(w http.ResponseWriter, r *http.Request) {
 	// リクエストから Context を取得する
 	ctx := r.Context()
@@ -70,7 +173,7 @@ func (h *TodoHandler) DeleteTodoHandler(w http.ResponseWriter, r *http.Request)
 	}
 
 	if err := h.useCase.DeleteTodo(ctx, id); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, err.Error(), inputErrorStatus(err))
 		return
 	}
 	w.WriteHeader(http.StatusNoContent)
@@ -98,7 +201,7 @@ func (h *TodoHandler) UpdateTodoStatusHandler(w http.ResponseWriter, r *http.Req
 
 	// UseCase の呼び出し
 	if err := h.useCase.UpdateTodoStatus(ctx, id, input.IsCompleted); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, err.Error(), inputErrorStatus(err))
 		return
 	}
 
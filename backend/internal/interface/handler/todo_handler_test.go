@@ -6,7 +6,9 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 	"todo_app_golang/internal/domain"
+	"todo_app_golang/internal/interface/middleware"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -17,9 +19,28 @@ type mockTodoUseCase struct {
 	mock.Mock
 }
 
-func (m *mockTodoUseCase) CreateTodo(ctx context.Context, title string) error {
-	args := m.Called(ctx, title)
-	return args.Error(0)
+func (m *mockTodoUseCase) AddTodo(ctx context.Context, in domain.TodoInput) (*domain.Todo, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Todo), args.Error(1)
+}
+
+func (m *mockTodoUseCase) ListTodos(ctx context.Context, filter domain.TodoFilter) ([]*domain.Todo, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Todo), args.Error(1)
+}
+
+func (m *mockTodoUseCase) UpdateTodo(ctx context.Context, id int, in domain.TodoInput) (*domain.Todo, error) {
+	args := m.Called(ctx, id, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Todo), args.Error(1)
 }
 
 func (m *mockTodoUseCase) GetAllTodos(ctx context.Context) ([]*domain.Todo, error) {
@@ -40,14 +61,31 @@ func (m *mockTodoUseCase) UpdateTodoStatus(ctx context.Context, id int, isComple
 	return args.Error(0)
 }
 
+func (m *mockTodoUseCase) GetTodoByID(ctx context.Context, id int) (*domain.Todo, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Todo), args.Error(1)
+}
+
+func (m *mockTodoUseCase) ImportTodos(ctx context.Context, todos []*domain.Todo) (*domain.ImportResult, error) {
+	args := m.Called(ctx, todos)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.ImportResult), args.Error(1)
+}
+
 // --- テストケース ---
 
 func TestTodoHandler_CreateTodoHandler_Mock(t *testing.T) {
 	mockUC := new(mockTodoUseCase)
 	h := NewTodoHandler(mockUC)
 
-	// 設定: CreateTodo が呼ばれたら nil (成功) を返す
-	mockUC.On("CreateTodo", mock.Anything, "Mockテストタスク").Return(nil)
+	// 設定: AddTodo が呼ばれたら作成したタスクを返す
+	mockUC.On("AddTodo", mock.Anything, domain.TodoInput{Title: "Mockテストタスク"}).
+		Return(&domain.Todo{ID: 7, Title: "Mockテストタスク", Priority: domain.PriorityMedium}, nil)
 
 	jsonBody := []byte(`{"title": "Mockテストタスク"}`)
 	req := httptest.NewRequest(http.MethodPost, "/todos", bytes.NewBuffer(jsonBody))
@@ -56,6 +94,8 @@ func TestTodoHandler_CreateTodoHandler_Mock(t *testing.T) {
 	h.CreateTodoHandler(rr, req)
 
 	assert.Equal(t, http.StatusCreated, rr.Code)
+	assert.Equal(t, "/todos/7", rr.Header().Get("Location"))
+	assert.Contains(t, rr.Body.String(), `"id":7`)
 	mockUC.AssertExpectations(t)
 }
 
@@ -64,7 +104,7 @@ func TestTodoHandler_CreateTodoHandler_Error(t *testing.T) {
 	h := NewTodoHandler(mockUC)
 
 	// 設定: エラーを返すようにする
-	mockUC.On("CreateTodo", mock.Anything, "test").Return(context.DeadlineExceeded)
+	mockUC.On("AddTodo", mock.Anything, domain.TodoInput{Title: "test"}).Return(nil, context.DeadlineExceeded)
 
 	req := httptest.NewRequest(http.MethodPost, "/todos", bytes.NewBuffer([]byte(`{"title":"test"}`)))
 	rr := httptest.NewRecorder()
@@ -90,6 +130,14 @@ func TestTodoHandler_DeleteTodoHandler(t *testing.T) {
 
 	assert.Equal(t, http.StatusNoContent, w.Code)
 	mockUC.AssertExpectations(t)
+
+	// 担当者など、削除する権限がない場合は 403
+	mockUC.On("DeleteTodo", mock.Anything, 2).Return(domain.ErrTodoForbidden)
+	req = httptest.NewRequest(http.MethodDelete, "/todos/2", nil)
+	req.SetPathValue("id", "2")
+	w = httptest.NewRecorder()
+	handler.DeleteTodoHandler(w, req)
+	assert.Equal(t, http.StatusForbidden, w.Code)
 }
 
 func TestTodoHandler_UpdateTodoStatusHandler(t *testing.T) {
@@ -136,4 +184,125 @@ func TestTodoHandler_UpdateTodoStatusHandler(t *testing.T) {
 		// 検証
 		assert.Equal(t, http.StatusBadRequest, rr.Code)
 	})
+
+	t.Run("失敗：存在しないタスクの場合に404を返すこと", func(t *testing.T) {
+		mockUC := new(mockTodoUseCase)
+		h := NewTodoHandler(mockUC)
+
+		req := httptest.NewRequest(http.MethodPatch, "/todos/999", bytes.NewBuffer([]byte(`{"is_completed": true}`)))
+		req.SetPathValue("id", "999")
+		rr := httptest.NewRecorder()
+		mockUC.On("UpdateTodoStatus", mock.Anything, 999, true).Return(domain.ErrTodoNotFound)
+
+		h.UpdateTodoStatusHandler(rr, req)
+
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+}
+
+func TestTodoHandler_CreateTodoHandler_Input(t *testing.T) {
+	due := time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("成功：優先度・期限・詳細説明を指定して作成できること", func(t *testing.T) {
+		mockUC := new(mockTodoUseCase)
+		h := NewTodoHandler(mockUC)
+		in := domain.TodoInput{Title: "資料作成", Description: "第3章まで", Priority: domain.PriorityHigh, DueDate: &due}
+		mockUC.On("AddTodo", mock.Anything, in).Return(&domain.Todo{ID: 3, Title: "資料作成"}, nil)
+
+		body := `{"title":"資料作成","description":"第3章まで","priority":"high","due_date":"2026-11-01T00:00:00Z"}`
+		rr := httptest.NewRecorder()
+		h.CreateTodoHandler(rr, httptest.NewRequest(http.MethodPost, "/todos", bytes.NewBufferString(body)))
+
+		assert.Equal(t, http.StatusCreated, rr.Code)
+		mockUC.AssertExpectations(t)
+	})
+
+	t.Run("失敗：優先度が不正な場合は400を返すこと", func(t *testing.T) {
+		mockUC := new(mockTodoUseCase)
+		h := NewTodoHandler(mockUC)
+		mockUC.On("AddTodo", mock.Anything, mock.Anything).Return(nil, domain.ErrInvalidPriority)
+
+		rr := httptest.NewRecorder()
+		h.CreateTodoHandler(rr, httptest.NewRequest(http.MethodPost, "/todos", bytes.NewBufferString(`{"title":"a","priority":"urgent"}`)))
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+}
+
+func TestTodoHandler_GetAllTodosHandler(t *testing.T) {
+	t.Run("成功：条件を指定した場合は絞り込んだ一覧を返すこと", func(t *testing.T) {
+		mockUC := new(mockTodoUseCase)
+		h := NewTodoHandler(mockUC)
+		open := false
+		due := time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)
+		mockUC.On("ListTodos", mock.Anything, domain.TodoFilter{IsCompleted: &open, Priority: "high", Query: "資料", DueBefore: &due}).
+			Return([]*domain.Todo{{ID: 1, Title: "資料作成"}}, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/todos?completed=false&priority=high&q=%E8%B3%87%E6%96%99&due_before=2026-11-01T00:00:00Z", nil)
+		rr := httptest.NewRecorder()
+		h.GetAllTodosHandler(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Contains(t, rr.Body.String(), "資料作成")
+		mockUC.AssertExpectations(t)
+	})
+
+	t.Run("成功：assignee=me は認証済みのユーザーの担当で絞り込むこと", func(t *testing.T) {
+		mockUC := new(mockTodoUseCase)
+		h := NewTodoHandler(mockUC)
+		mockUC.On("ListTodos", mock.Anything, domain.TodoFilter{AssigneeID: "alice"}).Return([]*domain.Todo{}, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/todos?assignee=me", nil)
+		req = req.WithContext(middleware.WithUserID(req.Context(), "alice"))
+		rr := httptest.NewRecorder()
+		h.GetAllTodosHandler(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		mockUC.AssertExpectations(t)
+
+		// 認証していない場合は自分が誰か分からない
+		rr = httptest.NewRecorder()
+		h.GetAllTodosHandler(rr, httptest.NewRequest(http.MethodGet, "/todos?assignee=me", nil))
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	})
+
+	t.Run("失敗：条件が解釈できない場合は400を返すこと", func(t *testing.T) {
+		h := NewTodoHandler(new(mockTodoUseCase))
+		for _, target := range []string{"/todos?completed=maybe", "/todos?due_before=2026-11-01"} {
+			rr := httptest.NewRecorder()
+			h.GetAllTodosHandler(rr, httptest.NewRequest(http.MethodGet, target, nil))
+			assert.Equal(t, http.StatusBadRequest, rr.Code, target)
+		}
+	})
+}
+
+func TestTodoHandler_UpdateTodoHandler(t *testing.T) {
+	t.Run("成功：内容を置き換えて更新後のタスクを返すこと", func(t *testing.T) {
+		mockUC := new(mockTodoUseCase)
+		h := NewTodoHandler(mockUC)
+		mockUC.On("UpdateTodo", mock.Anything, 5, domain.TodoInput{Title: "新しいタイトル", Priority: domain.PriorityLow}).
+			Return(&domain.Todo{ID: 5, Title: "新しいタイトル", Priority: domain.PriorityLow}, nil)
+
+		req := httptest.NewRequest(http.MethodPut, "/todos/5", bytes.NewBufferString(`{"title":"新しいタイトル","priority":"low"}`))
+		req.SetPathValue("id", "5")
+		rr := httptest.NewRecorder()
+		h.UpdateTodoHandler(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Contains(t, rr.Body.String(), "新しいタイトル")
+		mockUC.AssertExpectations(t)
+	})
+
+	t.Run("失敗：存在しない場合は404を返すこと", func(t *testing.T) {
+		mockUC := new(mockTodoUseCase)
+		h := NewTodoHandler(mockUC)
+		mockUC.On("UpdateTodo", mock.Anything, 99, mock.Anything).Return(nil, domain.ErrTodoNotFound)
+
+		req := httptest.NewRequest(http.MethodPut, "/todos/99", bytes.NewBufferString(`{"title":"a"}`))
+		req.SetPathValue("id", "99")
+		rr := httptest.NewRecorder()
+		h.UpdateTodoHandler(rr, req)
+
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
 }
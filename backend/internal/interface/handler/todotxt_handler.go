@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"todo_app_golang/internal/interface/todotxt"
+	"todo_app_golang/internal/logging"
+)
+
+type TodoTxtHandler struct {
+	useCase TodoUseCaseInterface
+}
+
+func NewTodoTxtHandler(uc TodoUseCaseInterface) *TodoTxtHandler {
+	return &TodoTxtHandler{useCase: uc}
+}
+
+// ExportHandler: GET /todos/export.txt
+func (h *TodoTxtHandler) ExportHandler(w http.ResponseWriter, r *http.Request) {
+	todos, err := h.useCase.GetAllTodos(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="todo.txt"`)
+	if err := todotxt.Encode(w, todos); err != nil {
+		// ヘッダーは送信済みのためステータスは変えられない（クライアントの切断など）
+		logging.FromContext(r.Context()).WarnContext(r.Context(), "Failed to write todo.txt export", slog.Any("error", err))
+	}
+}
+
+// ImportHandler: POST /todos/import/todotxt
+// todo.txt には ID が無いため、取り込んだ行は常に新しいタスクとして作成します（同じファイルを再度取り込むと重複する）
+func (h *TodoTxtHandler) ImportHandler(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportBytes)
+
+	body, err := importFile(r)
+	if err != nil {
+		http.Error(w, "ファイルを読み込めません", http.StatusBadRequest)
+		return
+	}
+	defer body.Close()
+
+	decoded, err := todotxt.Decode(body)
+	if err != nil {
+		http.Error(w, "ファイルを読み込めません", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.useCase.ImportTodos(r.Context(), decoded.Todos)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	result.Skipped = append(decoded.Skipped, result.Skipped...)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
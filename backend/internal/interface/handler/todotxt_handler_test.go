@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"todo_app_golang/internal/domain"
+	"todo_app_golang/internal/logging"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestTodoTxtHandler_ExportHandler(t *testing.T) {
+	mockUC := new(mockTodoUseCase)
+	h := NewTodoTxtHandler(mockUC)
+	created := time.Date(2026, 10, 1, 12, 0, 0, 0, time.Local)
+	mockUC.On("GetAllTodos", mock.Anything).Return([]*domain.Todo{
+		{ID: 1, Title: "タスク +仕事", Priority: "high", CreatedAt: created},
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/todos/export.txt", nil)
+	rr := httptest.NewRecorder()
+
+	h.ExportHandler(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "text/plain; charset=utf-8", rr.Header().Get("Content-Type"))
+	assert.Equal(t, "(A) 2026-10-01 タスク +仕事\n", rr.Body.String())
+	mockUC.AssertExpectations(t)
+}
+
+func TestTodoTxtHandler_ExportHandler_WriteError(t *testing.T) {
+	mockUC := new(mockTodoUseCase)
+	h := NewTodoTxtHandler(mockUC)
+	mockUC.On("GetAllTodos", mock.Anything).Return([]*domain.Todo{{ID: 1, Title: "タスク"}}, nil)
+
+	var logs bytes.Buffer
+	req := httptest.NewRequest(http.MethodGet, "/todos/export.txt", nil)
+	req = req.WithContext(logging.WithLogger(req.Context(), slog.New(slog.NewTextHandler(&logs, nil))))
+
+	h.ExportHandler(&failingWriter{ResponseRecorder: httptest.NewRecorder()}, req)
+
+	assert.Contains(t, logs.String(), "Failed to write todo.txt export")
+}
+
+func TestTodoTxtHandler_ImportHandler(t *testing.T) {
+	t.Run("成功：各行が Todo として取り込まれること", func(t *testing.T) {
+		mockUC := new(mockTodoUseCase)
+		h := NewTodoTxtHandler(mockUC)
+		mockUC.On("ImportTodos", mock.Anything, mock.MatchedBy(func(todos []*domain.Todo) bool {
+			return len(todos) == 2 && todos[0].Priority == "high" && todos[1].IsCompleted
+		})).Return(&domain.ImportResult{Created: 2, Skipped: []domain.ImportSkipped{}}, nil)
+
+		body := bytes.NewBufferString("(A) 電話する\nx 2026-10-03 2026-10-01 レビュー\n")
+		req := httptest.NewRequest(http.MethodPost, "/todos/import/todotxt", body)
+		rr := httptest.NewRecorder()
+
+		h.ImportHandler(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.JSONEq(t, `{"created":2,"updated":0,"skipped":[]}`, rr.Body.String())
+		mockUC.AssertExpectations(t)
+	})
+
+	t.Run("失敗：ユースケースのエラー時に500を返すこと", func(t *testing.T) {
+		mockUC := new(mockTodoUseCase)
+		h := NewTodoTxtHandler(mockUC)
+		mockUC.On("ImportTodos", mock.Anything, mock.Anything).Return(nil, context.DeadlineExceeded)
+
+		req := httptest.NewRequest(http.MethodPost, "/todos/import/todotxt", bytes.NewBufferString("タスク\n"))
+		rr := httptest.NewRecorder()
+
+		h.ImportHandler(rr, req)
+
+		assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	})
+}
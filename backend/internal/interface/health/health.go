@@ -0,0 +1,126 @@
+package health
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// checkTimeout は1つのチェックにかける時間の上限です（プローブのタイムアウトより短くする）
+const checkTimeout = 2 * time.Second
+
+// Check は readiness の判定項目です
+type Check struct {
+	Name string
+	Run  func(ctx context.Context) error
+}
+
+// CheckResult は1項目分の結果です
+type CheckResult struct {
+	Status     string  `json:"status"` // ok / error
+	DurationMs float64 `json:"duration_ms"`
+	Error      string  `json:"error,omitempty"`
+}
+
+// Response は /healthz・/readyz のレスポンスです
+type Response struct {
+	Status string                 `json:"status"` // ok / unavailable
+	Checks map[string]CheckResult `json:"checks,omitempty"`
+}
+
+type Handler struct {
+	checks []Check
+}
+
+// NewHandler は readiness で実行するチェックを受け取ります
+func NewHandler(checks ...Check) *Handler {
+	return &Handler{checks: checks}
+}
+
+// LivenessHandler: GET /healthz
+// プロセスが応答できることだけを返します（DB 障害で再起動させないよう依存先は確認しない）
+func (h *Handler) LivenessHandler(w http.ResponseWriter, r *http.Request) {
+	writeResponse(w, http.StatusOK, Response{Status: "ok"})
+}
+
+// ReadinessHandler: GET /readyz
+// すべてのチェックを並行に実行し、1つでも失敗した場合は 503 を返します
+func (h *Handler) ReadinessHandler(w http.ResponseWriter, r *http.Request) {
+	resp := Response{Status: "ok", Checks: make(map[string]CheckResult, len(h.checks))}
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for _, c := range h.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result := run(r.Context(), c)
+
+			mu.Lock()
+			defer mu.Unlock()
+			resp.Checks[c.Name] = result
+			if result.Status != "ok" {
+				resp.Status = "unavailable"
+			}
+		}()
+	}
+	wg.Wait()
+
+	status := http.StatusOK
+	if resp.Status != "ok" {
+		status = http.StatusServiceUnavailable
+	}
+	writeResponse(w, status, resp)
+}
+
+func run(ctx context.Context, c Check) CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	start := time.Now()
+	err := c.Run(ctx)
+	result := CheckResult{
+		Status:     "ok",
+		DurationMs: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		result.Status = "error"
+		result.Error = err.Error()
+	}
+	return result
+}
+
+func writeResponse(w http.ResponseWriter, status int, resp Response) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// DatabaseCheck は DB に接続できることを確認します
+func DatabaseCheck(db *sql.DB) Check {
+	return Check{Name: "database", Run: db.PingContext}
+}
+
+// MigrationCheck はスキーマが期待するバージョンまで適用済みで、失敗したマイグレーションが無いことを確認します
+func MigrationCheck(current func(ctx context.Context) (version uint, dirty bool, err error), expected uint) Check {
+	return Check{Name: "migrations", Run: func(ctx context.Context) error {
+		version, dirty, err := current(ctx)
+		if err != nil {
+			return err
+		}
+		if dirty {
+			return fmt.Errorf("migration %d is dirty", version)
+		}
+		if version != expected {
+			return fmt.Errorf("schema version is %d, expected %d", version, expected)
+		}
+		return nil
+	}}
+}
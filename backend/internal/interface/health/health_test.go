@@ -0,0 +1,84 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func okCheck(name string) Check {
+	return Check{Name: name, Run: func(ctx context.Context) error { return nil }}
+}
+
+func TestLivenessHandler(t *testing.T) {
+	h := NewHandler(Check{Name: "database", Run: func(ctx context.Context) error { return errors.New("down") }})
+	rr := httptest.NewRecorder()
+
+	h.LivenessHandler(rr, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	// 依存先の障害に関係なく 200 を返す
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"status":"ok"}`, rr.Body.String())
+}
+
+func TestReadinessHandler(t *testing.T) {
+	t.Run("すべてのチェックが成功した場合に200を返すこと", func(t *testing.T) {
+		h := NewHandler(okCheck("database"), okCheck("workers"))
+		rr := httptest.NewRecorder()
+
+		h.ReadinessHandler(rr, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		var resp Response
+		assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+		assert.Equal(t, "ok", resp.Status)
+		assert.Equal(t, "ok", resp.Checks["database"].Status)
+		assert.Equal(t, "ok", resp.Checks["workers"].Status)
+	})
+
+	t.Run("失敗：チェックが1つでも失敗した場合に503と理由を返すこと", func(t *testing.T) {
+		h := NewHandler(okCheck("database"), Check{Name: "migrations", Run: func(ctx context.Context) error {
+			return errors.New("schema version is 3, expected 4")
+		}})
+		rr := httptest.NewRecorder()
+
+		h.ReadinessHandler(rr, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+		assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+		var resp Response
+		assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+		assert.Equal(t, "unavailable", resp.Status)
+		assert.Equal(t, "ok", resp.Checks["database"].Status)
+		assert.Equal(t, "error", resp.Checks["migrations"].Status)
+		assert.Equal(t, "schema version is 3, expected 4", resp.Checks["migrations"].Error)
+	})
+
+	t.Run("失敗：応答しないチェックはタイムアウトすること", func(t *testing.T) {
+		h := NewHandler(Check{Name: "database", Run: func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}})
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel() // プローブ側のタイムアウトを模擬
+		rr := httptest.NewRecorder()
+
+		h.ReadinessHandler(rr, httptest.NewRequest(http.MethodGet, "/readyz", nil).WithContext(ctx))
+
+		assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+	})
+}
+
+func TestMigrationCheck(t *testing.T) {
+	current := func(version uint, dirty bool) func(context.Context) (uint, bool, error) {
+		return func(context.Context) (uint, bool, error) { return version, dirty, nil }
+	}
+
+	assert.NoError(t, MigrationCheck(current(4, false), 4).Run(context.Background()))
+	assert.ErrorContains(t, MigrationCheck(current(3, false), 4).Run(context.Background()), "expected 4")
+	assert.ErrorContains(t, MigrationCheck(current(4, true), 4).Run(context.Background()), "dirty")
+}
@@ -0,0 +1,293 @@
+package ical
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"todo_app_golang/internal/domain"
+)
+
+// ErrNotCalendar は入力が VCALENDAR として解釈できない場合のエラーです
+var ErrNotCalendar = errors.New("iCalendar 形式のデータではありません")
+
+// DecodeResult は .ics の解析結果です
+type DecodeResult struct {
+	Todos   []*domain.Todo
+	Skipped []domain.ImportSkipped
+}
+
+// contentLine は1つのコンテンツ行 (NAME;PARAM=VALUE:value) を表します
+type contentLine struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+// component は BEGIN〜END で囲まれた要素です（ネストした VALARM 等のプロパティは含みません）
+type component struct {
+	name  string
+	props map[string]contentLine
+}
+
+// Decode は .ics を読み込み、VTODO / VEVENT を Todo に変換します。
+// 変換できなかった要素は理由とともに Skipped に記録されます。
+func Decode(r io.Reader) (*DecodeResult, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &DecodeResult{Skipped: []domain.ImportSkipped{}}
+	var (
+		stack      []string
+		current    *component
+		inCalendar bool
+	)
+
+	for _, raw := range lines {
+		line, ok := parseLine(raw)
+		if !ok {
+			continue
+		}
+
+		switch line.name {
+		case "BEGIN":
+			name := strings.ToUpper(line.value)
+			stack = append(stack, name)
+			if name == "VCALENDAR" {
+				inCalendar = true
+				continue
+			}
+			// VCALENDAR 直下の要素のみを対象とする
+			if len(stack) == 2 && inCalendar {
+				current = &component{name: name, props: map[string]contentLine{}}
+			}
+		case "END":
+			if len(stack) == 0 {
+				continue
+			}
+			stack = stack[:len(stack)-1]
+			if len(stack) == 1 && current != nil {
+				result.add(current)
+				current = nil
+			}
+		default:
+			// 同名プロパティは最初の値を採用する
+			if current != nil && len(stack) == 2 {
+				if _, exists := current.props[line.name]; !exists {
+					current.props[line.name] = line
+				}
+			}
+		}
+	}
+
+	if !inCalendar {
+		return nil, ErrNotCalendar
+	}
+	return result, nil
+}
+
+func (res *DecodeResult) add(c *component) {
+	uid := c.props["UID"].value
+
+	switch c.name {
+	case "VTODO":
+	case "VEVENT":
+		if isEventUID(uid) {
+			// WithEvents で出力した期限イベントは VTODO と同じタスクのため、別のタスクとして取り込まない
+			res.skip(c.name, uid, "このアプリが出力した期限のイベントのため取り込みません")
+			return
+		}
+	case "VTIMEZONE":
+		// タイムゾーン定義は日時の解釈にのみ使われるため報告しない
+		return
+	default:
+		res.skip(c.name, uid, "サポートされていないコンポーネントです")
+		return
+	}
+
+	todo, reason := toTodo(c)
+	if reason != "" {
+		res.skip(c.name, uid, reason)
+		return
+	}
+	res.Todos = append(res.Todos, todo)
+}
+
+func (res *DecodeResult) skip(component, uid, reason string) {
+	res.Skipped = append(res.Skipped, domain.ImportSkipped{Component: component, UID: uid, Reason: reason})
+}
+
+// toTodo はコンポーネントを Todo に変換します。変換できない場合は理由を返します
+func toTodo(c *component) (*domain.Todo, string) {
+	summary := unescapeText(c.props["SUMMARY"].value)
+	if strings.TrimSpace(summary) == "" {
+		return nil, "SUMMARY がありません"
+	}
+
+	status := strings.ToUpper(c.props["STATUS"].value)
+	if status == "CANCELLED" {
+		return nil, "キャンセル済みのため取り込みません"
+	}
+
+	todo := &domain.Todo{
+		Title:       summary,
+		Description: unescapeText(c.props["DESCRIPTION"].value),
+		Priority:    "medium",
+		CreatedAt:   time.Now(),
+	}
+
+	if uid := c.props["UID"].value; uid != "" {
+		todo.ICalUID = &uid
+		// このアプリが出力したタスクは ID で照合する（元のタスクは ICalUID を持たないため）
+		if c.name == "VTODO" {
+			if id, ok := ParseUID(uid); ok {
+				todo.ID = id
+			}
+		}
+	}
+
+	// VTODO は DUE、VEVENT は DTSTART を期限として扱う
+	dueProp := "DUE"
+	if c.name == "VEVENT" {
+		dueProp = "DTSTART"
+	}
+	if line, ok := c.props[dueProp]; ok {
+		due, err := parseDateTime(line)
+		if err != nil {
+			return nil, dueProp + " の日時が不正です"
+		}
+		todo.DueDate = &due
+	}
+
+	if line, ok := c.props["CREATED"]; ok {
+		if created, err := parseDateTime(line); err == nil {
+			todo.CreatedAt = created
+		}
+	}
+
+	if line, ok := c.props["PRIORITY"]; ok {
+		p, err := strconv.Atoi(line.value)
+		if err != nil || p < 0 || p > 9 {
+			return nil, "PRIORITY の値が不正です"
+		}
+		todo.Priority = priorityName(p)
+	}
+
+	todo.IsCompleted = status == "COMPLETED" ||
+		c.props["PERCENT-COMPLETE"].value == "100" ||
+		c.props["COMPLETED"].value != ""
+	if line, ok := c.props["COMPLETED"]; ok && todo.IsCompleted {
+		if completed, err := parseDateTime(line); err == nil {
+			todo.CompletedAt = &completed
+		}
+	}
+
+	return todo, ""
+}
+
+// priorityName は RFC 5545 の PRIORITY を low/medium/high に変換します (1〜4: 高, 5: 中, 6〜9: 低)
+func priorityName(p int) string {
+	switch {
+	case p >= 1 && p <= 4:
+		return "high"
+	case p >= 6:
+		return "low"
+	default:
+		// 0 (未定義) と 5 は medium とする
+		return "medium"
+	}
+}
+
+// parseDateTime は DATE-TIME（UTC / TZID 指定 / フローティング）と DATE 形式を解釈します
+func parseDateTime(line contentLine) (time.Time, error) {
+	if line.params["VALUE"] == "DATE" || len(line.value) == len("20060102") {
+		return time.ParseInLocation("20060102", line.value, time.UTC)
+	}
+	if strings.HasSuffix(line.value, "Z") {
+		return time.Parse(dateTimeLayout, line.value)
+	}
+
+	loc := time.UTC
+	if tzid := line.params["TZID"]; tzid != "" {
+		if l, err := time.LoadLocation(tzid); err == nil {
+			loc = l
+		}
+	}
+	return time.ParseInLocation("20060102T150405", line.value, loc)
+}
+
+// unfold は折り返された行を結合し、論理行の一覧を返します (RFC 5545 3.1)
+func unfold(r io.Reader) ([]string, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var lines []string
+	for scanner.Scan() {
+		text := strings.TrimSuffix(scanner.Text(), "\r")
+		if (strings.HasPrefix(text, " ") || strings.HasPrefix(text, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += text[1:]
+			continue
+		}
+		lines = append(lines, text)
+	}
+	return lines, scanner.Err()
+}
+
+// parseLine は "NAME;PARAM=VALUE:value" 形式の行を分解します
+func parseLine(raw string) (contentLine, bool) {
+	// パラメータ値はダブルクォートで囲まれている場合にコロンを含み得る
+	colon := -1
+	inQuote := false
+	for i, r := range raw {
+		if r == '"' {
+			inQuote = !inQuote
+		} else if r == ':' && !inQuote {
+			colon = i
+			break
+		}
+	}
+	if colon <= 0 {
+		return contentLine{}, false
+	}
+
+	parts := strings.Split(raw[:colon], ";")
+	line := contentLine{
+		name:   strings.ToUpper(parts[0]),
+		params: map[string]string{},
+		value:  raw[colon+1:],
+	}
+	for _, p := range parts[1:] {
+		if k, v, ok := strings.Cut(p, "="); ok {
+			line.params[strings.ToUpper(k)] = strings.Trim(v, `"`)
+		}
+	}
+	return line, true
+}
+
+// unescapeText は escapeText の逆変換です
+func unescapeText(s string) string {
+	var b strings.Builder
+	escaped := false
+	for _, r := range s {
+		if escaped {
+			switch r {
+			case 'n', 'N':
+				b.WriteRune('\n')
+			default:
+				b.WriteRune(r)
+			}
+			escaped = false
+			continue
+		}
+		if r == '\\' {
+			escaped = true
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,132 @@
+package ical
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+	"todo_app_golang/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const sampleCalendar = "BEGIN:VCALENDAR\r\n" +
+	"VERSION:2.0\r\n" +
+	"PRODID:-//Example//EN\r\n" +
+	"BEGIN:VTODO\r\n" +
+	"UID:todo-a@example.com\r\n" +
+	"SUMMARY:買い物\\, 牛乳\r\n" +
+	"DESCRIPTION:1行目\\n2行目が長いので折り返され\r\n" +
+	" ています\r\n" +
+	"DUE;TZID=Asia/Tokyo:20261005T183000\r\n" +
+	"PRIORITY:2\r\n" +
+	"STATUS:COMPLETED\r\n" +
+	"COMPLETED:20261004T120000Z\r\n" +
+	"BEGIN:VALARM\r\n" +
+	"ACTION:DISPLAY\r\n" +
+	"SUMMARY:アラーム\r\n" +
+	"END:VALARM\r\n" +
+	"END:VTODO\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:event-b@example.com\r\n" +
+	"SUMMARY:打ち合わせ\r\n" +
+	"DTSTART;VALUE=DATE:20261010\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VTODO\r\n" +
+	"UID:no-summary@example.com\r\n" +
+	"END:VTODO\r\n" +
+	"BEGIN:VJOURNAL\r\n" +
+	"UID:journal@example.com\r\n" +
+	"SUMMARY:日記\r\n" +
+	"END:VJOURNAL\r\n" +
+	"END:VCALENDAR\r\n"
+
+func TestDecode(t *testing.T) {
+	t.Run("成功：VTODO と VEVENT が Todo に変換されること", func(t *testing.T) {
+		result, err := Decode(strings.NewReader(sampleCalendar))
+		assert.NoError(t, err)
+		assert.Len(t, result.Todos, 2)
+
+		todo := result.Todos[0]
+		assert.Equal(t, "買い物, 牛乳", todo.Title)
+		assert.Equal(t, "1行目\n2行目が長いので折り返されています", todo.Description)
+		assert.Equal(t, "high", todo.Priority)
+		assert.True(t, todo.IsCompleted)
+		assert.True(t, time.Date(2026, 10, 4, 12, 0, 0, 0, time.UTC).Equal(*todo.CompletedAt))
+		assert.Equal(t, "todo-a@example.com", *todo.ICalUID)
+		assert.True(t, time.Date(2026, 10, 5, 9, 30, 0, 0, time.UTC).Equal(*todo.DueDate))
+
+		event := result.Todos[1]
+		assert.Equal(t, "打ち合わせ", event.Title)
+		assert.Equal(t, "medium", event.Priority)
+		assert.False(t, event.IsCompleted)
+		assert.True(t, time.Date(2026, 10, 10, 0, 0, 0, 0, time.UTC).Equal(*event.DueDate))
+	})
+
+	t.Run("成功：変換できない要素が理由とともに報告されること", func(t *testing.T) {
+		result, err := Decode(strings.NewReader(sampleCalendar))
+		assert.NoError(t, err)
+		assert.Equal(t, []domain.ImportSkipped{
+			{Component: "VTODO", UID: "no-summary@example.com", Reason: "SUMMARY がありません"},
+			{Component: "VJOURNAL", UID: "journal@example.com", Reason: "サポートされていないコンポーネントです"},
+		}, result.Skipped)
+	})
+
+	t.Run("成功：Encode の出力を読み戻せること", func(t *testing.T) {
+		due := time.Date(2026, 11, 1, 12, 0, 0, 0, time.UTC)
+		original := []*domain.Todo{
+			{ID: 3, Title: strings.Repeat("長いタイトル; ", 10), Description: "a,b\\c\nd", Priority: "low", DueDate: &due},
+		}
+		var buf bytes.Buffer
+		assert.NoError(t, Encode(&buf, original, EncodeOptions{}))
+
+		result, err := Decode(&buf)
+		assert.NoError(t, err)
+		assert.Len(t, result.Todos, 1)
+		assert.Equal(t, original[0].Title, result.Todos[0].Title)
+		assert.Equal(t, original[0].Description, result.Todos[0].Description)
+		assert.Equal(t, "low", result.Todos[0].Priority)
+		assert.Equal(t, UID(original[0]), *result.Todos[0].ICalUID)
+		assert.Equal(t, 3, result.Todos[0].ID) // 元のタスクと ID で照合できるよう復元する
+		assert.True(t, due.Equal(*result.Todos[0].DueDate))
+	})
+
+	t.Run("成功：このアプリが出力した期限のイベントは取り込まずに報告されること", func(t *testing.T) {
+		due := time.Date(2026, 11, 1, 12, 0, 0, 0, time.UTC)
+		var buf bytes.Buffer
+		assert.NoError(t, Encode(&buf, []*domain.Todo{{ID: 3, Title: "請求書", DueDate: &due}}, EncodeOptions{WithEvents: true}))
+
+		result, err := Decode(&buf)
+		assert.NoError(t, err)
+		assert.Len(t, result.Todos, 1)
+		if assert.Len(t, result.Skipped, 1) {
+			assert.Equal(t, "VEVENT", result.Skipped[0].Component)
+			assert.Equal(t, "todo-3-due@todo_app_golang", result.Skipped[0].UID)
+		}
+	})
+
+	t.Run("失敗：VCALENDAR でない場合はエラーになること", func(t *testing.T) {
+		_, err := Decode(strings.NewReader("hello, world"))
+		assert.ErrorIs(t, err, ErrNotCalendar)
+	})
+}
+
+func TestParseUID(t *testing.T) {
+	id, ok := ParseUID("todo-12@todo_app_golang")
+	assert.True(t, ok)
+	assert.Equal(t, 12, id)
+
+	for _, uid := range []string{"todo-12-due@todo_app_golang", "todo-012@todo_app_golang", "todo-0@todo_app_golang", "todo-12@example.com", "a@example.com"} {
+		_, ok := ParseUID(uid)
+		assert.False(t, ok, uid)
+	}
+}
+
+func TestPriorityName(t *testing.T) {
+	assert.Equal(t, "medium", priorityName(0))
+	assert.Equal(t, "high", priorityName(1))
+	assert.Equal(t, "high", priorityName(4))
+	assert.Equal(t, "medium", priorityName(5))
+	assert.Equal(t, "low", priorityName(6))
+	assert.Equal(t, "low", priorityName(9))
+}
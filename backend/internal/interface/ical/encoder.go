@@ -0,0 +1,214 @@
+package ical
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"todo_app_golang/internal/domain"
+)
+
+const (
+	// ProdID はカレンダーアプリに表示される生成元の識別子です
+	ProdID = "-//todo_app_golang//Todo Calendar//JA"
+
+	// maxLineOctets は RFC 5545 3.1 で推奨される1行あたりの最大オクテット数です
+	maxLineOctets = 75
+
+	dateTimeLayout = "20060102T150405Z"
+
+	// uidDomain は UID と EventUID の "@" 以降です
+	uidDomain = "@todo_app_golang"
+)
+
+// EncodeOptions はカレンダー出力時の挙動を指定します
+type EncodeOptions struct {
+	// WithEvents が true の場合、期限付きのタスクを VEVENT としても出力します
+	WithEvents bool
+	// Now は DTSTAMP に使用する時刻です（ゼロ値の場合は現在時刻）
+	Now time.Time
+}
+
+// Encode は Todo の一覧を RFC 5545 形式の VCALENDAR として書き出します
+func Encode(w io.Writer, todos []*domain.Todo, opts EncodeOptions) error {
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	stamp := formatDateTime(now)
+
+	lw := &lineWriter{w: w}
+	lw.property("BEGIN", "VCALENDAR")
+	lw.property("VERSION", "2.0")
+	lw.property("PRODID", ProdID)
+	lw.property("CALSCALE", "GREGORIAN")
+
+	for _, t := range todos {
+		writeTodo(lw, t, stamp)
+		if opts.WithEvents && t.DueDate != nil {
+			writeEvent(lw, t, stamp)
+		}
+	}
+
+	lw.property("END", "VCALENDAR")
+	return lw.err
+}
+
+// UID はタスクごとに不変となる UID を返します。
+// インポートしたタスクは取り込み元の UID をそのまま使います。
+func UID(t *domain.Todo) string {
+	if t.ICalUID != nil && *t.ICalUID != "" {
+		return *t.ICalUID
+	}
+	return fmt.Sprintf("todo-%d"+uidDomain, t.ID)
+}
+
+// EventUID は期限イベント用の UID を返します（VTODO と衝突しないよう別の値にする）
+func EventUID(t *domain.Todo) string {
+	return fmt.Sprintf("todo-%d-due"+uidDomain, t.ID)
+}
+
+// ParseUID は UID が出力したタスクの UID（todo-<id>@todo_app_golang）であればタスクの ID を返します
+func ParseUID(uid string) (int, bool) {
+	return parseUID(uid, "")
+}
+
+// isEventUID は UID が EventUID で出力した期限イベントのものかを返します
+func isEventUID(uid string) bool {
+	_, ok := parseUID(uid, "-due")
+	return ok
+}
+
+func parseUID(uid, suffix string) (int, bool) {
+	rest, ok := strings.CutPrefix(uid, "todo-")
+	if !ok {
+		return 0, false
+	}
+	rest, ok = strings.CutSuffix(rest, suffix+uidDomain)
+	if !ok {
+		return 0, false
+	}
+	id, err := strconv.Atoi(rest)
+	if err != nil || id <= 0 || strconv.Itoa(id) != rest {
+		return 0, false
+	}
+	return id, true
+}
+
+// PriorityValue は low/medium/high を RFC 5545 の PRIORITY (1〜9, 0は未定義) に変換します
+func PriorityValue(priority string) int {
+	switch priority {
+	case "high":
+		return 1
+	case "medium":
+		return 5
+	case "low":
+		return 9
+	default:
+		return 0
+	}
+}
+
+func writeTodo(lw *lineWriter, t *domain.Todo, stamp string) {
+	lw.property("BEGIN", "VTODO")
+	lw.property("UID", UID(t))
+	lw.property("DTSTAMP", stamp)
+	if !t.CreatedAt.IsZero() {
+		lw.property("CREATED", formatDateTime(t.CreatedAt))
+	}
+	if !t.UpdatedAt.IsZero() {
+		lw.property("LAST-MODIFIED", formatDateTime(t.UpdatedAt))
+	}
+	lw.property("SUMMARY", escapeText(t.Title))
+	if t.Description != "" {
+		lw.property("DESCRIPTION", escapeText(t.Description))
+	}
+	if t.DueDate != nil {
+		lw.property("DUE", formatDateTime(*t.DueDate))
+	}
+	if p := PriorityValue(t.Priority); p != 0 {
+		lw.property("PRIORITY", fmt.Sprint(p))
+	}
+	if t.IsCompleted {
+		lw.property("STATUS", "COMPLETED")
+		lw.property("PERCENT-COMPLETE", "100")
+		if t.CompletedAt != nil {
+			lw.property("COMPLETED", formatDateTime(*t.CompletedAt))
+		}
+	} else {
+		lw.property("STATUS", "NEEDS-ACTION")
+	}
+	lw.property("END", "VTODO")
+}
+
+// writeEvent は期限日時を長さ0のイベントとして出力します。
+// DTEND は DTSTART より後でなければならないため出力しない（日時の DTSTART だけの場合は同じ時刻に終わる。RFC 5545 3.6.1）
+func writeEvent(lw *lineWriter, t *domain.Todo, stamp string) {
+	lw.property("BEGIN", "VEVENT")
+	lw.property("UID", EventUID(t))
+	lw.property("DTSTAMP", stamp)
+	lw.property("DTSTART", formatDateTime(*t.DueDate))
+	lw.property("SUMMARY", escapeText("期限: "+t.Title))
+	if t.Description != "" {
+		lw.property("DESCRIPTION", escapeText(t.Description))
+	}
+	lw.property("TRANSP", "TRANSPARENT")
+	lw.property("END", "VEVENT")
+}
+
+func formatDateTime(t time.Time) string {
+	return t.UTC().Format(dateTimeLayout)
+}
+
+// escapeText は TEXT 型の値をエスケープします (RFC 5545 3.3.11)
+func escapeText(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ReplaceAll(s, "\r\n", "\n") {
+		switch r {
+		case '\\':
+			b.WriteString(`\\`)
+		case ';':
+			b.WriteString(`\;`)
+		case ',':
+			b.WriteString(`\,`)
+		case '\n', '\r':
+			b.WriteString(`\n`)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// lineWriter はコンテンツ行を折り返しながら CRLF 区切りで書き出します。
+// 最初に発生したエラーを保持し、以降の書き込みは行いません。
+type lineWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (lw *lineWriter) property(name, value string) {
+	if lw.err != nil {
+		return
+	}
+	_, lw.err = io.WriteString(lw.w, fold(name+":"+value))
+}
+
+// fold は 75 オクテットを超える行を RFC 5545 3.1 に従って折り返します。
+// UTF-8 のマルチバイト文字の途中では分割しません。
+func fold(line string) string {
+	var b strings.Builder
+	width := 0
+	for _, r := range line {
+		size := len(string(r))
+		if width+size > maxLineOctets {
+			b.WriteString("\r\n ")
+			width = 1 // 継続行の先頭スペース分
+		}
+		b.WriteRune(r)
+		width += size
+	}
+	b.WriteString("\r\n")
+	return b.String()
+}
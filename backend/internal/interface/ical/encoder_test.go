@@ -0,0 +1,88 @@
+package ical
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+	"todo_app_golang/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncode(t *testing.T) {
+	now := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	due := time.Date(2026, 10, 5, 18, 30, 0, 0, time.FixedZone("JST", 9*60*60))
+
+	todos := []*domain.Todo{
+		{ID: 1, Title: "買い物, 牛乳; 卵", Description: "1行目\n2行目\\", Priority: "high", DueDate: &due, CreatedAt: now},
+		{ID: 2, Title: "完了済み", IsCompleted: true, Priority: "low", CreatedAt: now},
+	}
+
+	t.Run("成功：VTODO が RFC 5545 形式で出力されること", func(t *testing.T) {
+		var buf bytes.Buffer
+		err := Encode(&buf, todos, EncodeOptions{Now: now})
+		assert.NoError(t, err)
+
+		out := buf.String()
+		assert.True(t, strings.HasPrefix(out, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n"))
+		assert.True(t, strings.HasSuffix(out, "END:VCALENDAR\r\n"))
+		assert.Equal(t, 2, strings.Count(out, "BEGIN:VTODO\r\n"))
+		assert.NotContains(t, out, "BEGIN:VEVENT")
+
+		assert.Contains(t, out, "UID:todo-1@todo_app_golang\r\n")
+		assert.Contains(t, out, "DTSTAMP:20261001T090000Z\r\n")
+		assert.Contains(t, out, `SUMMARY:買い物\, 牛乳\; 卵`+"\r\n")
+		assert.Contains(t, out, `DESCRIPTION:1行目\n2行目\\`+"\r\n")
+		// 期限は UTC に変換される
+		assert.Contains(t, out, "DUE:20261005T093000Z\r\n")
+		assert.Contains(t, out, "PRIORITY:1\r\n")
+		assert.Contains(t, out, "STATUS:NEEDS-ACTION\r\n")
+
+		assert.Contains(t, out, "PRIORITY:9\r\n")
+		assert.Contains(t, out, "STATUS:COMPLETED\r\n")
+	})
+
+	t.Run("成功：WithEvents 指定時は期限付きタスクのみ VEVENT が出力されること", func(t *testing.T) {
+		var buf bytes.Buffer
+		err := Encode(&buf, todos, EncodeOptions{Now: now, WithEvents: true})
+		assert.NoError(t, err)
+
+		out := buf.String()
+		assert.Equal(t, 1, strings.Count(out, "BEGIN:VEVENT\r\n"))
+		assert.Contains(t, out, "UID:todo-1-due@todo_app_golang\r\n")
+		assert.Contains(t, out, "DTSTART:20261005T093000Z\r\n")
+		assert.NotContains(t, out, "DTEND") // DTSTART と同じ DTEND は RFC 5545 に反する
+	})
+}
+
+func TestPriorityValue(t *testing.T) {
+	assert.Equal(t, 1, PriorityValue("high"))
+	assert.Equal(t, 5, PriorityValue("medium"))
+	assert.Equal(t, 9, PriorityValue("low"))
+	assert.Equal(t, 0, PriorityValue(""))
+}
+
+func TestFold(t *testing.T) {
+	t.Run("75オクテット以下の行は折り返されないこと", func(t *testing.T) {
+		line := strings.Repeat("a", 75)
+		assert.Equal(t, line+"\r\n", fold(line))
+	})
+
+	t.Run("長い行は75オクテットごとに折り返されること", func(t *testing.T) {
+		folded := fold(strings.Repeat("a", 200))
+		for _, l := range strings.Split(strings.TrimSuffix(folded, "\r\n"), "\r\n") {
+			assert.LessOrEqual(t, len(l), 75)
+		}
+		// 折り返しを戻すと元の行になる
+		assert.Equal(t, strings.Repeat("a", 200), strings.ReplaceAll(strings.TrimSuffix(folded, "\r\n"), "\r\n ", ""))
+	})
+
+	t.Run("マルチバイト文字の途中で分割されないこと", func(t *testing.T) {
+		folded := fold("SUMMARY:" + strings.Repeat("あ", 40))
+		for _, l := range strings.Split(strings.TrimSuffix(folded, "\r\n"), "\r\n") {
+			assert.LessOrEqual(t, len(l), 75)
+			assert.True(t, strings.ToValidUTF8(l, "?") == l)
+		}
+	})
+}
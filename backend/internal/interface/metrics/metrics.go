@@ -0,0 +1,112 @@
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// unmatchedRoute はどのパターンにも一致しなかったリクエストのラベル値です
+// （存在しないパスごとに時系列が増えないよう1つにまとめる）
+const unmatchedRoute = "unmatched"
+
+// Metrics は Prometheus のレジストリと HTTP 計測用のメトリクスを保持します
+type Metrics struct {
+	registry        *prometheus.Registry
+	requestsTotal   *prometheus.CounterVec
+	requestDuration *prometheus.HistogramVec
+}
+
+// New はメトリクスを生成します。db を渡すとコネクションプールの状態も公開します
+func New(db *sql.DB, todos TodoStatsGetter) *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		requestsTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "http_requests_total",
+			Help: "HTTP リクエスト数（ルートパターン・メソッド・ステータス別）",
+		}, []string{"method", "route", "status"}),
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "http_request_duration_seconds",
+			Help:    "HTTP リクエストの処理時間（秒）",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"method", "route"}),
+	}
+
+	m.registry.MustRegister(
+		m.requestsTotal,
+		m.requestDuration,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	if db != nil {
+		m.registry.MustRegister(collectors.NewDBStatsCollector(db, "todo"))
+	}
+	if todos != nil {
+		m.registry.MustRegister(newTodoCollector(todos))
+	}
+	return m
+}
+
+// Handler は /metrics 用のハンドラーを返します
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// Middleware はリクエスト数と処理時間を ServeMux に登録したルートパターンごとに記録します。
+// ServeMux がリクエストに設定する Pattern を参照するため、mux の外側で包んでください。
+func (m *Metrics) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+		next.ServeHTTP(rec, r)
+
+		route := r.Pattern
+		if route == "" {
+			route = unmatchedRoute
+		}
+		m.requestsTotal.WithLabelValues(r.Method, route, strconv.Itoa(rec.status)).Inc()
+		m.requestDuration.WithLabelValues(r.Method, route).Observe(time.Since(start).Seconds())
+	})
+}
+
+// statusRecorder はハンドラーが書き込んだステータスコードを記録します
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// Unwrap は http.ResponseController が元の ResponseWriter を参照できるようにします
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// RegisterCache はキャッシュのヒット・ミスの累計を公開します。stats は累計の (ヒット数, ミス数) を返す関数です
+func (m *Metrics) RegisterCache(stats func() (hits, misses uint64)) {
+	m.registry.MustRegister(
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Name: "todo_cache_hits_total",
+			Help: "タスクの取得でキャッシュにヒットした回数",
+		}, func() float64 {
+			hits, _ := stats()
+			return float64(hits)
+		}),
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Name: "todo_cache_misses_total",
+			Help: "タスクの取得でキャッシュにヒットせず DB に問い合わせた回数",
+		}, func() float64 {
+			_, misses := stats()
+			return float64(misses)
+		}),
+	)
+}
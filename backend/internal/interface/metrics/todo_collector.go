@@ -0,0 +1,69 @@
+package metrics
+
+import (
+	"context"
+	"log/slog"
+	"time"
+	"todo_app_golang/internal/domain"
+	"todo_app_golang/internal/logging"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// scrapeTimeout はスクレイプ時に集計クエリを待つ最大時間です
+const scrapeTimeout = 5 * time.Second
+
+// TodoStatsGetter はタスクのゲージを算出するために必要な機能です
+type TodoStatsGetter interface {
+	GetStats(ctx context.Context, from, to time.Time, loc *time.Location) (*domain.Stats, error)
+}
+
+// todoCollector はスクレイプのたびに集計を行い、タスク数のゲージを公開します
+type todoCollector struct {
+	stats TodoStatsGetter
+
+	total     *prometheus.Desc
+	open      *prometheus.Desc
+	overdue   *prometheus.Desc
+	dueToday  *prometheus.Desc
+	scrapeErr *prometheus.Desc
+}
+
+func newTodoCollector(stats TodoStatsGetter) *todoCollector {
+	return &todoCollector{
+		stats:     stats,
+		total:     prometheus.NewDesc("todo_todos", "タスクの総数", nil, nil),
+		open:      prometheus.NewDesc("todo_todos_open", "未完了のタスク数", nil, nil),
+		overdue:   prometheus.NewDesc("todo_todos_overdue", "期限切れの未完了タスク数", nil, nil),
+		dueToday:  prometheus.NewDesc("todo_todos_due_today", "今日が期限の未完了タスク数（UTC 基準）", nil, nil),
+		scrapeErr: prometheus.NewDesc("todo_stats_scrape_error", "直近のスクレイプで集計に失敗した場合は 1", nil, nil),
+	}
+}
+
+func (c *todoCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.total
+	ch <- c.open
+	ch <- c.overdue
+	ch <- c.dueToday
+	ch <- c.scrapeErr
+}
+
+func (c *todoCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), scrapeTimeout)
+	defer cancel()
+
+	// 日別の集計は不要なため、期間は今日1日だけにする
+	now := time.Now()
+	stats, err := c.stats.GetStats(ctx, now, now, time.UTC)
+	if err != nil {
+		logging.FromContext(ctx).ErrorContext(ctx, "failed to collect todo stats", slog.Any("error", err))
+		ch <- prometheus.MustNewConstMetric(c.scrapeErr, prometheus.GaugeValue, 1)
+		return
+	}
+
+	ch <- prometheus.MustNewConstMetric(c.total, prometheus.GaugeValue, float64(stats.Total))
+	ch <- prometheus.MustNewConstMetric(c.open, prometheus.GaugeValue, float64(stats.Active))
+	ch <- prometheus.MustNewConstMetric(c.overdue, prometheus.GaugeValue, float64(stats.Overdue))
+	ch <- prometheus.MustNewConstMetric(c.dueToday, prometheus.GaugeValue, float64(stats.DueToday))
+	ch <- prometheus.MustNewConstMetric(c.scrapeErr, prometheus.GaugeValue, 0)
+}
@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Authenticate は認証プロキシ（oauth2-proxy など）が付けたヘッダーからユーザーを取り出し、Context に格納します。
+// ヘッダーがないリクエストには 401 を返します（SkipPaths のパスはそのまま通す）。
+// ヘッダーはクライアントも自由に付けられるため、認証プロキシを経由しないと API に届かない構成でだけ使います
+func Authenticate(header string, skipPaths []string) func(http.Handler) http.Handler {
+	skip := make(map[string]bool, len(skipPaths))
+	for _, p := range skipPaths {
+		skip[p] = true
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if skip[r.URL.Path] {
+				next.ServeHTTP(w, r)
+				return
+			}
+			userID := strings.TrimSpace(r.Header.Get(header))
+			if userID == "" {
+				WriteProblem(w, http.StatusUnauthorized, "ログインが必要です")
+				return
+			}
+			orig := r
+			r = r.WithContext(WithUserID(r.Context(), userID))
+			next.ServeHTTP(w, r)
+			// RequestLogger と同じく、ServeMux が設定したルートパターンを外側のミドルウェア（メトリクス等）に返す
+			orig.Pattern = r.Pattern
+		})
+	}
+}
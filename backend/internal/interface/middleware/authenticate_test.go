@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthenticate(t *testing.T) {
+	var gotUser string
+	h := Authenticate("X-Forwarded-User", []string{"/healthz"})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotUser, _ = UserIDFromContext(r.Context())
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	t.Run("ヘッダーのユーザーを Context に格納すること", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/todos", nil)
+		req.Header.Set("X-Forwarded-User", " alice ")
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "alice", gotUser)
+	})
+
+	t.Run("外側にルートパターンを返すこと", func(t *testing.T) {
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /todos/{id}", func(w http.ResponseWriter, r *http.Request) {})
+		req := httptest.NewRequest(http.MethodGet, "/todos/1", nil)
+		req.Header.Set("X-Forwarded-User", "alice")
+		Authenticate("X-Forwarded-User", nil)(mux).ServeHTTP(httptest.NewRecorder(), req)
+
+		assert.Equal(t, "GET /todos/{id}", req.Pattern)
+	})
+
+	t.Run("ヘッダーがない場合は 401 の problem レスポンスを返すこと", func(t *testing.T) {
+		gotUser = ""
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/todos", nil))
+
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+		assert.Equal(t, "application/problem+json", rr.Header().Get("Content-Type"))
+		assert.Empty(t, gotUser)
+	})
+
+	t.Run("SkipPaths のパスはヘッダーがなくても通すこと", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+	})
+}
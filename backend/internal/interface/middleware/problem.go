@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// Problem は RFC 9457 (Problem Details for HTTP APIs) 形式のエラーレスポンスです
+type Problem struct {
+	Type   string `json:"type"`
+	Title  string `json:"title"`
+	Status int    `json:"status"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// WriteProblem は application/problem+json のエラーレスポンスを書き込みます。
+// type は個別の説明ページを用意していないため about:blank とし、title はステータスの説明にします
+func WriteProblem(w http.ResponseWriter, status int, detail string) {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+	})
+}
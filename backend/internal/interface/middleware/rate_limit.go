@@ -0,0 +1,118 @@
+package middleware
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"todo_app_golang/internal/domain"
+	"todo_app_golang/internal/logging"
+	"todo_app_golang/internal/ratelimit"
+)
+
+// RateLimit-* はレスポンスに付ける残り回数のヘッダーです（IETF httpapi の RateLimit header fields）
+const (
+	RateLimitLimitHeader     = "RateLimit-Limit"
+	RateLimitRemainingHeader = "RateLimit-Remaining"
+	RateLimitResetHeader     = "RateLimit-Reset"
+)
+
+// RateLimitOptions は RateLimit の設定です
+type RateLimitOptions struct {
+	Read  ratelimit.Budget // GET / HEAD / OPTIONS
+	Write ratelimit.Budget // それ以外のメソッド
+	// TrustProxy が true の場合、X-Forwarded-For の末尾（直前のプロキシが付けたもの）をクライアントの IP とみなす。
+	// リバースプロキシの後ろで動かす場合だけ有効にする
+	TrustProxy bool
+	// SkipPaths のパスは制限しない（ヘルスチェックやメトリクスの収集など）
+	SkipPaths []string
+}
+
+// WithUserID は認証済みのユーザーを Context に格納します（domain.WithUserID と同じ）。
+// 認証を行うミドルウェアが設定すると、RateLimit は IP ではなくユーザーごとに制限します
+func WithUserID(ctx context.Context, userID string) context.Context {
+	return domain.WithUserID(ctx, userID)
+}
+
+// UserIDFromContext は WithUserID で格納したユーザーを返します
+func UserIDFromContext(ctx context.Context) (string, bool) {
+	return domain.UserIDFromContext(ctx)
+}
+
+// RateLimit はクライアント（認証済みならユーザー、そうでなければ IP）ごとにリクエスト数を制限します。
+// 読み込みと書き込みは別々のバケットで数え、超えた場合は 429 と Retry-After を返します。
+// limiter がエラーを返した場合（共有している DB の障害など）は、サービスを止めないよう制限せずに通します
+func RateLimit(limiter ratelimit.Limiter, opts RateLimitOptions) func(http.Handler) http.Handler {
+	skip := make(map[string]bool, len(opts.SkipPaths))
+	for _, p := range opts.SkipPaths {
+		skip[p] = true
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if skip[r.URL.Path] {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			kind, budget := "write", opts.Write
+			switch r.Method {
+			case http.MethodGet, http.MethodHead, http.MethodOptions:
+				kind, budget = "read", opts.Read
+			}
+			key := kind + ":" + clientKey(r, opts.TrustProxy)
+
+			result, err := limiter.Allow(r.Context(), key, budget)
+			if err != nil {
+				logging.FromContext(r.Context()).WarnContext(r.Context(), "rate limiter failed, allowing request", slog.Any("error", err))
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			h := w.Header()
+			h.Set(RateLimitLimitHeader, strconv.Itoa(result.Limit))
+			h.Set(RateLimitRemainingHeader, strconv.Itoa(result.Remaining))
+			h.Set(RateLimitResetHeader, strconv.Itoa(ceilSeconds(result.Reset)))
+			if !result.Allowed {
+				h.Set("Retry-After", strconv.Itoa(max(ceilSeconds(result.RetryAfter), 1)))
+				WriteProblem(w, http.StatusTooManyRequests, "リクエストが多すぎます。Retry-After 秒後に再試行してください")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// clientKey はリクエストを制限する単位のキーを返します
+func clientKey(r *http.Request, trustProxy bool) string {
+	if userID, ok := UserIDFromContext(r.Context()); ok {
+		return "user:" + userID
+	}
+	return "ip:" + clientIP(r, trustProxy)
+}
+
+// clientIP はクライアントの IP を返します。X-Forwarded-For はクライアントが自由に付けられるため、
+// trustProxy の場合でも直前のプロキシが追加した末尾の値だけを使います
+func clientIP(r *http.Request, trustProxy bool) string {
+	if trustProxy {
+		if xff := r.Header.Values("X-Forwarded-For"); len(xff) > 0 {
+			parts := strings.Split(xff[len(xff)-1], ",")
+			if ip := net.ParseIP(strings.TrimSpace(parts[len(parts)-1])); ip != nil {
+				return ip.String()
+			}
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,108 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"todo_app_golang/internal/ratelimit"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// failingLimiter は常にエラーを返す ratelimit.Limiter です
+type failingLimiter struct{}
+
+func (failingLimiter) Allow(context.Context, string, ratelimit.Budget) (ratelimit.Result, error) {
+	return ratelimit.Result{}, errors.New("db is down")
+}
+
+func TestRateLimit(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	opts := RateLimitOptions{
+		Read:      ratelimit.Budget{Rate: 60, Burst: 2},
+		Write:     ratelimit.Budget{Rate: 60, Burst: 1},
+		SkipPaths: []string{"/healthz"},
+	}
+	do := func(h http.Handler, method, path, remoteAddr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		req.RemoteAddr = remoteAddr
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, req)
+		return rr
+	}
+
+	t.Run("上限を超えると 429 の problem レスポンスと Retry-After を返すこと", func(t *testing.T) {
+		h := RateLimit(ratelimit.NewMemory(), opts)(ok)
+
+		rr := do(h, http.MethodGet, "/todos", "192.0.2.1:1234")
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "2", rr.Header().Get(RateLimitLimitHeader))
+		assert.Equal(t, "1", rr.Header().Get(RateLimitRemainingHeader))
+		assert.Equal(t, "1", rr.Header().Get(RateLimitResetHeader))
+		do(h, http.MethodGet, "/todos", "192.0.2.1:1234")
+
+		rr = do(h, http.MethodGet, "/todos", "192.0.2.1:5678")
+		assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+		assert.Equal(t, "application/problem+json", rr.Header().Get("Content-Type"))
+		assert.Equal(t, "1", rr.Header().Get("Retry-After"))
+		assert.Equal(t, "0", rr.Header().Get(RateLimitRemainingHeader))
+
+		var problem Problem
+		assert.NoError(t, json.NewDecoder(rr.Body).Decode(&problem))
+		assert.Equal(t, http.StatusTooManyRequests, problem.Status)
+		assert.Equal(t, "Too Many Requests", problem.Title)
+	})
+
+	t.Run("読み込みと書き込みは別々に数えること", func(t *testing.T) {
+		h := RateLimit(ratelimit.NewMemory(), opts)(ok)
+
+		assert.Equal(t, http.StatusOK, do(h, http.MethodPost, "/todos", "192.0.2.1:1").Code)
+		assert.Equal(t, http.StatusTooManyRequests, do(h, http.MethodDelete, "/todos/1", "192.0.2.1:1").Code)
+		assert.Equal(t, http.StatusOK, do(h, http.MethodGet, "/todos", "192.0.2.1:1").Code)
+	})
+
+	t.Run("クライアントの IP ごと、認証済みならユーザーごとに数えること", func(t *testing.T) {
+		h := RateLimit(ratelimit.NewMemory(), opts)(ok)
+
+		do(h, http.MethodPost, "/todos", "192.0.2.1:1")
+		assert.Equal(t, http.StatusOK, do(h, http.MethodPost, "/todos", "192.0.2.2:1").Code)
+
+		// 同じ IP でもユーザーが違えば別に数える
+		req := httptest.NewRequest(http.MethodPost, "/todos", nil)
+		req.RemoteAddr = "192.0.2.1:1"
+		req = req.WithContext(WithUserID(req.Context(), "alice"))
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
+	})
+
+	t.Run("プロキシを信頼する場合だけ X-Forwarded-For の末尾を IP とみなすこと", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/todos", nil)
+		req.RemoteAddr = "10.0.0.1:1234"
+		req.Header.Set("X-Forwarded-For", "203.0.113.9, 198.51.100.7")
+
+		assert.Equal(t, "10.0.0.1", clientIP(req, false))
+		assert.Equal(t, "198.51.100.7", clientIP(req, true))
+	})
+
+	t.Run("除外したパスは制限しないこと", func(t *testing.T) {
+		h := RateLimit(ratelimit.NewMemory(), opts)(ok)
+
+		for range 5 {
+			rr := do(h, http.MethodGet, "/healthz", "192.0.2.1:1")
+			assert.Equal(t, http.StatusOK, rr.Code)
+			assert.Empty(t, rr.Header().Get(RateLimitLimitHeader))
+		}
+	})
+
+	t.Run("判定に失敗した場合は制限せずに通すこと", func(t *testing.T) {
+		h := RateLimit(failingLimiter{}, opts)(ok)
+
+		assert.Equal(t, http.StatusOK, do(h, http.MethodPost, "/todos", "192.0.2.1:1").Code)
+	})
+}
@@ -0,0 +1,108 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"net/http"
+	"time"
+	"todo_app_golang/internal/logging"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RequestIDHeader はリクエストIDを受け渡すヘッダー名です
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength は受け付けるリクエストIDの最大長です
+const maxRequestIDLength = 128
+
+// RequestLogger はリクエストIDの採番・伝播とアクセスログの出力を行います。
+// クライアントから妥当な X-Request-ID が送られた場合はそれを引き継ぎ、無ければ新たに採番します。
+// request_id 付きのロガーを Context に格納するため、ユースケースやリポジトリからは
+// logging.FromContext で同じリクエストIDを持つロガーを取得できます。
+func RequestLogger(logger *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+
+			requestID := r.Header.Get(RequestIDHeader)
+			if !validRequestID(requestID) {
+				requestID = newRequestID()
+			}
+			w.Header().Set(RequestIDHeader, requestID)
+
+			reqLogger := logger.With(slog.String("request_id", requestID))
+			if sc := trace.SpanContextFromContext(r.Context()); sc.IsValid() {
+				// トレースと突き合わせられるよう trace_id もログに含める
+				reqLogger = reqLogger.With(slog.String("trace_id", sc.TraceID().String()))
+			}
+			orig := r
+			r = r.WithContext(logging.WithLogger(r.Context(), reqLogger))
+
+			rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(rec, r)
+			// ServeMux が設定したルートパターンを外側のミドルウェア（otelhttp 等）からも参照できるようにする
+			orig.Pattern = r.Pattern
+
+			level := slog.LevelInfo
+			if rec.status >= http.StatusInternalServerError {
+				level = slog.LevelError
+			}
+			reqLogger.LogAttrs(r.Context(), level, "http request",
+				slog.String("method", r.Method),
+				slog.String("path", r.URL.Path),
+				slog.String("route", r.Pattern),
+				slog.Int("status", rec.status),
+				slog.Int("bytes", rec.bytes),
+				slog.Duration("duration", time.Since(start)),
+				slog.String("remote_addr", r.RemoteAddr),
+				slog.String("user_agent", r.UserAgent()),
+			)
+		})
+	}
+}
+
+// validRequestID はログやヘッダーに埋め込んでも安全な値かを判定します
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '-', c == '_', c == '.':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// statusRecorder はハンドラーが書き込んだステータスコードとサイズを記録します
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	n, err := r.ResponseWriter.Write(b)
+	r.bytes += n
+	return n, err
+}
+
+// Unwrap は http.ResponseController が元の ResponseWriter を参照できるようにします
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
@@ -0,0 +1,123 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"todo_app_golang/internal/logging"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// newTestServer は Context のロガーでログを1行出力するハンドラーを mux に登録します
+func newTestServer(buf *bytes.Buffer) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /todos/{id}", func(w http.ResponseWriter, r *http.Request) {
+		logging.FromContext(r.Context()).Info("handler called")
+		w.WriteHeader(http.StatusTeapot)
+		w.Write([]byte("body"))
+	})
+	logger := slog.New(slog.NewJSONHandler(buf, nil))
+	return RequestLogger(logger)(mux)
+}
+
+func decodeLines(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var entries []map[string]any
+	dec := json.NewDecoder(buf)
+	for dec.More() {
+		var entry map[string]any
+		assert.NoError(t, dec.Decode(&entry))
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestRequestLogger(t *testing.T) {
+	t.Run("リクエストIDが採番され、レスポンスヘッダーとログに含まれること", func(t *testing.T) {
+		var buf bytes.Buffer
+		h := newTestServer(&buf)
+
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/todos/1", nil))
+
+		requestID := rr.Header().Get(RequestIDHeader)
+		assert.Len(t, requestID, 32)
+
+		entries := decodeLines(t, &buf)
+		assert.Len(t, entries, 2)
+		// ハンドラー内のログにも同じリクエストIDが付与される
+		assert.Equal(t, "handler called", entries[0]["msg"])
+		assert.Equal(t, requestID, entries[0]["request_id"])
+
+		access := entries[1]
+		assert.Equal(t, "http request", access["msg"])
+		assert.Equal(t, requestID, access["request_id"])
+		assert.Equal(t, "GET /todos/{id}", access["route"])
+		assert.Equal(t, float64(http.StatusTeapot), access["status"])
+		assert.Equal(t, float64(4), access["bytes"])
+		assert.Contains(t, access, "duration")
+	})
+
+	t.Run("クライアントから送られたリクエストIDを引き継ぐこと", func(t *testing.T) {
+		var buf bytes.Buffer
+		h := newTestServer(&buf)
+
+		req := httptest.NewRequest(http.MethodGet, "/todos/1", nil)
+		req.Header.Set(RequestIDHeader, "frontend-abc_123.4")
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, req)
+
+		assert.Equal(t, "frontend-abc_123.4", rr.Header().Get(RequestIDHeader))
+		assert.Equal(t, "frontend-abc_123.4", decodeLines(t, &buf)[1]["request_id"])
+	})
+
+	t.Run("不正な文字を含むリクエストIDは採番し直すこと", func(t *testing.T) {
+		var buf bytes.Buffer
+		h := newTestServer(&buf)
+
+		req := httptest.NewRequest(http.MethodGet, "/todos/1", nil)
+		req.Header.Set(RequestIDHeader, "bad id\ninjected")
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, req)
+
+		assert.NotEqual(t, "bad id\ninjected", rr.Header().Get(RequestIDHeader))
+		assert.Len(t, rr.Header().Get(RequestIDHeader), 32)
+	})
+
+	t.Run("5xx のレスポンスは ERROR レベルで記録されること", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := slog.New(slog.NewJSONHandler(&buf, nil))
+		h := RequestLogger(logger)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "boom", http.StatusInternalServerError)
+		}))
+
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+		entries := decodeLines(t, &buf)
+		assert.Equal(t, "ERROR", entries[0]["level"])
+		assert.Equal(t, float64(http.StatusInternalServerError), entries[0]["status"])
+	})
+	t.Run("トレース中のリクエストは trace_id を記録し、外側にルートパターンを返すこと", func(t *testing.T) {
+		var buf bytes.Buffer
+		h := newTestServer(&buf)
+
+		traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+		spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+		ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+			TraceID: traceID, SpanID: spanID, TraceFlags: trace.FlagsSampled,
+		}))
+		req := httptest.NewRequest(http.MethodGet, "/todos/1", nil).WithContext(ctx)
+		h.ServeHTTP(httptest.NewRecorder(), req)
+
+		entries := decodeLines(t, &buf)
+		assert.Equal(t, traceID.String(), entries[0]["trace_id"])
+		assert.Equal(t, traceID.String(), entries[1]["trace_id"])
+		assert.Equal(t, "GET /todos/{id}", req.Pattern)
+	})
+}
@@ -0,0 +1,343 @@
+// Package openapi は API の OpenAPI 3.1 仕様 (openapi.yaml) を同梱し、
+// 仕様の配信とリクエスト・レスポンスの検証を行います。
+// ハンドラーやルーティングを変更した場合は openapi.yaml も合わせて更新してください
+package openapi
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed openapi.yaml
+var specYAML []byte
+
+// specURL はスキーマをコンパイルする際に仕様全体を登録する URL です（外部からは参照しない）
+const specURL = "mem:///openapi.json"
+
+// Spec は読み込んだ仕様です
+type Spec struct {
+	json       []byte                // /openapi.json で返す内容
+	operations map[string]*operation // キーは "GET /todos/{id}" の形式（ServeMux のパターンと同じ）
+	router     *http.ServeMux        // リクエストに対応するオペレーションを ServeMux と同じ規則で探す
+}
+
+type operation struct {
+	parameters []*parameter
+	body       *requestBody         // リクエストボディを受け付けない場合は nil
+	responses  map[string]*response // キーはステータスコードまたは "default"
+}
+
+type parameter struct {
+	name     string
+	in       string // path / query
+	required bool
+	typ      string // スキーマの type（文字列から値に変換するために使う）
+	schema   *jsonschema.Schema
+}
+
+type requestBody struct {
+	required bool
+	content  map[string]*jsonschema.Schema // メディアタイプごとのスキーマ（JSON 以外は nil）
+}
+
+type response struct {
+	content map[string]*jsonschema.Schema
+}
+
+// Load は同梱している仕様を読み込み、スキーマをコンパイルします
+func Load() (*Spec, error) {
+	var raw any
+	if err := yaml.Unmarshal(specYAML, &raw); err != nil {
+		return nil, fmt.Errorf("parse openapi.yaml: %w", err)
+	}
+	specJSON, err := json.Marshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("convert openapi.yaml to JSON: %w", err)
+	}
+	// スキーマの検証では数値を json.Number で扱うため、JSON から読み直す
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(specJSON))
+	if err != nil {
+		return nil, err
+	}
+
+	c := jsonschema.NewCompiler()
+	c.AssertFormat()
+	if err := c.AddResource(specURL, doc); err != nil {
+		return nil, err
+	}
+	l := &loader{doc: doc, compiler: c}
+
+	s := &Spec{json: specJSON, operations: map[string]*operation{}, router: http.NewServeMux()}
+	paths, _ := doc.(map[string]any)["paths"].(map[string]any)
+	for path, item := range paths {
+		item := item.(map[string]any)
+		itemPtr := "/paths/" + escape(path)
+		for _, method := range []string{"get", "post", "put", "patch", "delete"} {
+			op, ok := item[method].(map[string]any)
+			if !ok {
+				continue
+			}
+			compiled, err := l.operation(item, op, itemPtr, itemPtr+"/"+method)
+			if err != nil {
+				return nil, fmt.Errorf("%s %s: %w", strings.ToUpper(method), path, err)
+			}
+			pattern := strings.ToUpper(method) + " " + path
+			s.operations[pattern] = compiled
+			s.router.Handle(pattern, http.NotFoundHandler())
+		}
+	}
+	return s, nil
+}
+
+// Handler は仕様を JSON で返すハンドラーです（GET /openapi.json）
+func (s *Spec) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(s.json)
+	})
+}
+
+// Operations は仕様に記載しているオペレーションを "GET /todos/{id}" の形式で返します
+func (s *Spec) Operations() []string {
+	ops := make([]string, 0, len(s.operations))
+	for pattern := range s.operations {
+		ops = append(ops, pattern)
+	}
+	sort.Strings(ops)
+	return ops
+}
+
+// lookup はリクエストに対応するオペレーションとパスパラメータを返します。仕様に無い場合は nil を返します
+func (s *Spec) lookup(r *http.Request) (*operation, map[string]string) {
+	_, pattern := s.router.Handler(r)
+	op, ok := s.operations[pattern]
+	if !ok {
+		return nil, nil
+	}
+	_, path, _ := strings.Cut(pattern, " ")
+	return op, pathValues(path, r.URL.Path)
+}
+
+// ValidateResponse はレスポンスが仕様の pattern のオペレーションに記載したステータス・Content-Type・スキーマに一致するかを検証します
+func (s *Spec) ValidateResponse(pattern string, status int, header http.Header, body []byte) error {
+	op, ok := s.operations[pattern]
+	if !ok {
+		return fmt.Errorf("%s is not in the spec", pattern)
+	}
+	res, ok := op.responses[fmt.Sprint(status)]
+	if !ok {
+		if res, ok = op.responses["default"]; !ok {
+			return fmt.Errorf("%s: status %d is not in the spec", pattern, status)
+		}
+	}
+
+	if len(res.content) == 0 {
+		if len(body) > 0 {
+			return fmt.Errorf("%s: status %d must not have a body", pattern, status)
+		}
+		return nil
+	}
+	mediaType, _, _ := mime.ParseMediaType(header.Get("Content-Type"))
+	schema, ok := matchMediaType(res.content, mediaType)
+	if !ok {
+		return fmt.Errorf("%s: content type %q is not in the spec for status %d", pattern, mediaType, status)
+	}
+	if schema == nil {
+		return nil
+	}
+	return validateJSON(schema, body)
+}
+
+// pathValues はパターンの {name} に対応するパスの値を返します
+func pathValues(pattern, path string) map[string]string {
+	values := map[string]string{}
+	segments := strings.Split(path, "/")
+	for i, seg := range strings.Split(pattern, "/") {
+		if strings.HasPrefix(seg, "{") && i < len(segments) {
+			values[strings.Trim(seg, "{}")] = segments[i]
+		}
+	}
+	return values
+}
+
+// matchMediaType は mediaType に一致するスキーマを、完全一致・type/*・*/* の順に探します
+func matchMediaType(content map[string]*jsonschema.Schema, mediaType string) (*jsonschema.Schema, bool) {
+	if schema, ok := content[mediaType]; ok {
+		return schema, true
+	}
+	if typ, _, ok := strings.Cut(mediaType, "/"); ok {
+		if schema, ok := content[typ+"/*"]; ok {
+			return schema, true
+		}
+	}
+	schema, ok := content["*/*"]
+	return schema, ok
+}
+
+// isJSON は application/json や application/problem+json など JSON で表すメディアタイプかを判定します
+func isJSON(mediaType string) bool {
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+func validateJSON(schema *jsonschema.Schema, body []byte) error {
+	v, err := jsonschema.UnmarshalJSON(bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("invalid JSON: %w", err)
+	}
+	return describe(schema.Validate(v))
+}
+
+// describe は検証エラーを1行にまとめます（先頭のスキーマの URL を含む行は除く）
+func describe(err error) error {
+	var verr *jsonschema.ValidationError
+	if !errors.As(err, &verr) {
+		return err
+	}
+	var msgs []string
+	for _, line := range strings.Split(verr.Error(), "\n") {
+		line = strings.TrimPrefix(strings.TrimSpace(line), "- ")
+		if line == "" || strings.HasPrefix(line, "jsonschema validation failed") {
+			continue
+		}
+		msgs = append(msgs, line)
+	}
+	return errors.New(strings.Join(msgs, "; "))
+}
+
+// escape は JSON Pointer のトークンとしてエスケープします
+func escape(token string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(token)
+}
+
+// loader は仕様のオブジェクトを辿りながら、$ref を解決してスキーマをコンパイルします
+type loader struct {
+	doc      any
+	compiler *jsonschema.Compiler
+}
+
+// resolve は "#/components/..." 形式の $ref を辿り、参照先のオブジェクトとその JSON Pointer を返します
+func (l *loader) resolve(v any, ptr string) (map[string]any, string, error) {
+	obj, ok := v.(map[string]any)
+	if !ok {
+		return nil, "", fmt.Errorf("%s: not an object", ptr)
+	}
+	ref, ok := obj["$ref"].(string)
+	if !ok {
+		return obj, ptr, nil
+	}
+	target, ok := strings.CutPrefix(ref, "#")
+	if !ok {
+		return nil, "", fmt.Errorf("%s: external $ref %q is not supported", ptr, ref)
+	}
+	var cur any = l.doc
+	for _, token := range strings.Split(strings.TrimPrefix(target, "/"), "/") {
+		token = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+		m, ok := cur.(map[string]any)
+		if !ok {
+			return nil, "", fmt.Errorf("%s: $ref %q not found", ptr, ref)
+		}
+		if cur, ok = m[token]; !ok {
+			return nil, "", fmt.Errorf("%s: $ref %q not found", ptr, ref)
+		}
+	}
+	return l.resolve(cur, target)
+}
+
+func (l *loader) schema(ptr string) (*jsonschema.Schema, error) {
+	return l.compiler.Compile(specURL + "#" + ptr)
+}
+
+func (l *loader) operation(item, op map[string]any, itemPtr, opPtr string) (*operation, error) {
+	compiled := &operation{responses: map[string]*response{}}
+
+	// パス共通のパラメータの後にオペレーション固有のパラメータを読む
+	for _, src := range []struct {
+		params any
+		ptr    string
+	}{{item["parameters"], itemPtr}, {op["parameters"], opPtr}} {
+		params, _ := src.params.([]any)
+		for i, p := range params {
+			param, err := l.parameter(p, fmt.Sprintf("%s/parameters/%d", src.ptr, i))
+			if err != nil {
+				return nil, err
+			}
+			compiled.parameters = append(compiled.parameters, param)
+		}
+	}
+
+	if body, ok := op["requestBody"]; ok {
+		obj, ptr, err := l.resolve(body, opPtr+"/requestBody")
+		if err != nil {
+			return nil, err
+		}
+		required, _ := obj["required"].(bool)
+		content, err := l.content(obj, ptr)
+		if err != nil {
+			return nil, err
+		}
+		compiled.body = &requestBody{required: required, content: content}
+	}
+
+	responses, _ := op["responses"].(map[string]any)
+	for status, res := range responses {
+		obj, ptr, err := l.resolve(res, opPtr+"/responses/"+status)
+		if err != nil {
+			return nil, err
+		}
+		content, err := l.content(obj, ptr)
+		if err != nil {
+			return nil, err
+		}
+		compiled.responses[status] = &response{content: content}
+	}
+	return compiled, nil
+}
+
+func (l *loader) parameter(v any, ptr string) (*parameter, error) {
+	obj, ptr, err := l.resolve(v, ptr)
+	if err != nil {
+		return nil, err
+	}
+	p := &parameter{}
+	p.name, _ = obj["name"].(string)
+	p.in, _ = obj["in"].(string)
+	p.required, _ = obj["required"].(bool)
+	if schemaObj, ok := obj["schema"]; ok {
+		resolved, _, err := l.resolve(schemaObj, ptr+"/schema")
+		if err != nil {
+			return nil, err
+		}
+		p.typ, _ = resolved["type"].(string)
+		if p.schema, err = l.schema(ptr + "/schema"); err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+func (l *loader) content(obj map[string]any, ptr string) (map[string]*jsonschema.Schema, error) {
+	content, _ := obj["content"].(map[string]any)
+	schemas := make(map[string]*jsonschema.Schema, len(content))
+	for mediaType, media := range content {
+		schemas[mediaType] = nil
+		if _, ok := media.(map[string]any)["schema"]; !ok || !isJSON(mediaType) {
+			continue
+		}
+		schema, err := l.schema(ptr + "/content/" + escape(mediaType) + "/schema")
+		if err != nil {
+			return nil, err
+		}
+		schemas[mediaType] = schema
+	}
+	return schemas, nil
+}
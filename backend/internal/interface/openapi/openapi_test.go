@@ -0,0 +1,120 @@
+package openapi
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func loadSpec(t *testing.T) *Spec {
+	t.Helper()
+	spec, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	return spec
+}
+
+func TestLoad(t *testing.T) {
+	spec := loadSpec(t)
+
+	t.Run("すべてのオペレーションを ServeMux のパターンの形式で返すこと", func(t *testing.T) {
+		ops := spec.Operations()
+		assert.Contains(t, ops, "GET /todos")
+		assert.Contains(t, ops, "PATCH /todos/{id}")
+		assert.Contains(t, ops, "GET /analytics/lead-time")
+	})
+
+	t.Run("/openapi.json で OpenAPI 3.1 の JSON を返すこと", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		spec.Handler().ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+
+		assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+		var doc map[string]any
+		assert.NoError(t, json.NewDecoder(rr.Body).Decode(&doc))
+		assert.Equal(t, "3.1.0", doc["openapi"])
+	})
+}
+
+func TestSpec_Middleware(t *testing.T) {
+	spec := loadSpec(t)
+	var gotBody string
+	h := spec.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		gotBody = string(b)
+		w.WriteHeader(http.StatusTeapot)
+	}))
+	do := func(method, target, contentType, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, req)
+		return rr
+	}
+
+	t.Run("仕様に合うリクエストはボディを読める状態でハンドラーに渡すこと", func(t *testing.T) {
+		rr := do(http.MethodPost, "/todos", "application/json", `{"title":"牛乳を買う"}`)
+		assert.Equal(t, http.StatusTeapot, rr.Code)
+		assert.Equal(t, `{"title":"牛乳を買う"}`, gotBody)
+
+		assert.Equal(t, http.StatusTeapot, do(http.MethodGet, "/stats?from=2026-10-01&tz=Asia/Tokyo", "", "").Code)
+		assert.Equal(t, http.StatusTeapot, do(http.MethodPatch, "/todos/3", "application/json", `{"is_completed":true}`).Code)
+	})
+
+	t.Run("ボディがスキーマに違反する場合は 400 の problem を返すこと", func(t *testing.T) {
+		for _, body := range []string{`{"title":""}`, `{}`, `{"title":1}`, `not json`} {
+			rr := do(http.MethodPost, "/todos", "application/json", body)
+			assert.Equal(t, http.StatusBadRequest, rr.Code, body)
+			assert.Equal(t, "application/problem+json", rr.Header().Get("Content-Type"))
+		}
+
+		rr := do(http.MethodPatch, "/todos/3", "application/json", `{"is_completed":"yes"}`)
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.Contains(t, rr.Body.String(), "is_completed")
+	})
+
+	t.Run("パラメータが仕様に違反する場合は 400 を返すこと", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, do(http.MethodGet, "/todos/abc", "", "").Code)
+		assert.Equal(t, http.StatusBadRequest, do(http.MethodDelete, "/todos/0", "", "").Code)
+		assert.Equal(t, http.StatusBadRequest, do(http.MethodGet, "/stats?from=2026/10/01", "", "").Code)
+		assert.Equal(t, http.StatusBadRequest, do(http.MethodGet, "/calendar.ics?events=maybe", "", "").Code)
+	})
+
+	t.Run("Content-Type が仕様に無い場合は 415 を返すこと", func(t *testing.T) {
+		assert.Equal(t, http.StatusUnsupportedMediaType, do(http.MethodPost, "/todos", "text/plain", `{"title":"a"}`).Code)
+		// インポートはファイルそのものも受け付ける
+		assert.Equal(t, http.StatusTeapot, do(http.MethodPost, "/todos/import/todotxt", "text/plain", "買い物").Code)
+	})
+
+	t.Run("仕様に無いルートはそのまま通すこと", func(t *testing.T) {
+		assert.Equal(t, http.StatusTeapot, do(http.MethodGet, "/unknown", "", "").Code)
+		assert.Equal(t, http.StatusTeapot, do(http.MethodPost, "/todos/1", "", "").Code)
+	})
+}
+
+func TestSpec_ValidateResponse(t *testing.T) {
+	spec := loadSpec(t)
+	jsonHeader := http.Header{"Content-Type": {"application/json"}}
+
+	t.Run("スキーマに合うレスポンスはエラーにならないこと", func(t *testing.T) {
+		body := `[{"id":1,"title":"a","description":"","is_completed":false,"priority":"high","due_date":null,` +
+			`"created_at":"2026-10-01T00:00:00Z","updated_at":"2026-10-01T00:00:00Z","completed_at":null,` +
+			`"owner_id":null,"assignee_id":"alice","watchers":[]}]`
+		assert.NoError(t, spec.ValidateResponse("GET /todos", http.StatusOK, jsonHeader, []byte(body)))
+		assert.NoError(t, spec.ValidateResponse("DELETE /todos/{id}", http.StatusNoContent, http.Header{}, nil))
+	})
+
+	t.Run("仕様と食い違うレスポンスはエラーになること", func(t *testing.T) {
+		assert.ErrorContains(t, spec.ValidateResponse("GET /todos", http.StatusOK, jsonHeader, []byte(`null`)), "array")
+		assert.ErrorContains(t, spec.ValidateResponse("GET /todos", http.StatusTeapot, jsonHeader, []byte(`[]`)), "status 418")
+		assert.ErrorContains(t, spec.ValidateResponse("GET /todos", http.StatusOK, http.Header{"Content-Type": {"text/html"}}, nil), "text/html")
+		assert.ErrorContains(t, spec.ValidateResponse("GET /nothing", http.StatusOK, jsonHeader, nil), "not in the spec")
+	})
+}
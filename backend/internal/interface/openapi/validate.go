@@ -0,0 +1,130 @@
+package openapi
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"todo_app_golang/internal/interface/middleware"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+// maxValidatedBodyBytes は JSON のリクエストボディとして検証する最大サイズです。
+// これを超える JSON は受け付けない（ファイルのアップロードは JSON ではないため対象外）
+const maxValidatedBodyBytes = 1 << 20
+
+// errUnsupportedMediaType は Content-Type が仕様に無いことを表します
+var errUnsupportedMediaType = errors.New("unsupported media type")
+
+// Middleware はリクエストのパスパラメータ・クエリパラメータ・ボディを仕様で検証します。
+// 違反している場合は 400（Content-Type が仕様に無い場合は 415）の problem レスポンスを返し、ハンドラーを呼びません。
+// 仕様に無いルートはそのまま通します（ServeMux が 404 / 405 を返す）
+func (s *Spec) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		op, pathValues := s.lookup(r)
+		if op == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if err := op.validateRequest(r, pathValues); err != nil {
+			status := http.StatusBadRequest
+			if errors.Is(err, errUnsupportedMediaType) {
+				status = http.StatusUnsupportedMediaType
+			}
+			middleware.WriteProblem(w, status, err.Error())
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// validateRequest はリクエストを検証します。JSON のボディは読み込んで検証した後、ハンドラーが読めるよう戻します
+func (op *operation) validateRequest(r *http.Request, pathValues map[string]string) error {
+	var errs []error
+	query := r.URL.Query()
+	for _, p := range op.parameters {
+		var value string
+		var present bool
+		switch p.in {
+		case "path":
+			value, present = pathValues[p.name]
+		case "query":
+			present = query.Has(p.name)
+			value = query.Get(p.name)
+		default:
+			continue
+		}
+		if !present {
+			if p.required {
+				errs = append(errs, fmt.Errorf("%s parameter %q is required", p.in, p.name))
+			}
+			continue
+		}
+		if err := p.validate(value); err != nil {
+			errs = append(errs, fmt.Errorf("%s parameter %q: %w", p.in, p.name, err))
+		}
+	}
+
+	if op.body != nil {
+		if err := op.body.validate(r); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// validate はクエリやパスの文字列を、スキーマの type に合わせて変換してから検証します
+func (p *parameter) validate(value string) error {
+	if p.schema == nil {
+		return nil
+	}
+	var v any = value
+	switch p.typ {
+	case "integer", "number", "boolean":
+		// 変換できない場合は文字列のまま検証し、型の不一致として報告する
+		if parsed, err := jsonschema.UnmarshalJSON(strings.NewReader(value)); err == nil {
+			v = parsed
+		}
+	}
+	return describe(p.schema.Validate(v))
+}
+
+func (b *requestBody) validate(r *http.Request) error {
+	if r.ContentLength == 0 || r.Body == nil || r.Body == http.NoBody {
+		if b.required {
+			return errors.New("request body is required")
+		}
+		return nil
+	}
+
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		mediaType = "application/octet-stream" // Content-Type が無い場合（RFC 9110 8.3）
+	}
+	schema, ok := matchMediaType(b.content, mediaType)
+	if !ok {
+		return fmt.Errorf("%w: %q", errUnsupportedMediaType, mediaType)
+	}
+	if schema == nil {
+		return nil
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxValidatedBodyBytes+1))
+	r.Body.Close()
+	if err != nil {
+		return fmt.Errorf("read request body: %w", err)
+	}
+	if len(body) > maxValidatedBodyBytes {
+		return fmt.Errorf("request body is larger than %d bytes", maxValidatedBodyBytes)
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	if err := validateJSON(schema, body); err != nil {
+		return fmt.Errorf("request body: %w", err)
+	}
+	return nil
+}
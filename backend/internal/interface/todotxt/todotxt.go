@@ -0,0 +1,276 @@
+package todotxt
+
+import (
+	"bufio"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+	"todo_app_golang/internal/domain"
+)
+
+const dateLayout = "2006-01-02"
+
+// descTag は Description を入れる key:value タグのキーです（todo.txt に詳細説明の書式が無いための独自の拡張）
+const descTag = "desc"
+
+// Task は todo.txt の1行を表します
+// https://github.com/todotxt/todo.txt
+type Task struct {
+	Completed      bool
+	Priority       string // "A"〜"Z"（未設定は空文字）
+	CompletionDate *time.Time
+	CreationDate   *time.Time
+	// Text は日付・優先度を除いた本文です（+project / @context / key:value を含む）
+	Text     string
+	Projects []string
+	Contexts []string
+	Tags     map[string]string
+}
+
+// ParseLine は todo.txt の1行を解析します。空行の場合は false を返します
+func ParseLine(line string) (*Task, bool) {
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return nil, false
+	}
+
+	task := &Task{Tags: map[string]string{}}
+	rest := line
+
+	if strings.HasPrefix(rest, "x ") {
+		task.Completed = true
+		rest = rest[2:]
+	}
+	if len(rest) >= 4 && rest[0] == '(' && rest[1] >= 'A' && rest[1] <= 'Z' && rest[2] == ')' && rest[3] == ' ' {
+		task.Priority = string(rest[1])
+		rest = rest[4:]
+	}
+
+	// 完了済みの場合は「完了日 作成日」、未完了の場合は「作成日」の順に並ぶ
+	first, afterFirst := cutDate(rest)
+	if first != nil {
+		rest = afterFirst
+		if task.Completed {
+			task.CompletionDate = first
+			if second, afterSecond := cutDate(rest); second != nil {
+				task.CreationDate = second
+				rest = afterSecond
+			}
+		} else {
+			task.CreationDate = first
+		}
+	}
+
+	task.Text = strings.TrimSpace(rest)
+	for _, word := range strings.Fields(task.Text) {
+		switch {
+		case len(word) > 1 && word[0] == '+':
+			task.Projects = append(task.Projects, word[1:])
+		case len(word) > 1 && word[0] == '@':
+			task.Contexts = append(task.Contexts, word[1:])
+		default:
+			if k, v, ok := strings.Cut(word, ":"); ok && k != "" && v != "" && !strings.Contains(v, "/") {
+				task.Tags[k] = v
+			}
+		}
+	}
+	return task, true
+}
+
+// String は Task を todo.txt の1行に整形します
+func (t *Task) String() string {
+	var parts []string
+	if t.Completed {
+		parts = append(parts, "x")
+	}
+	if t.Priority != "" {
+		parts = append(parts, "("+t.Priority+")")
+	}
+	if t.Completed && t.CompletionDate != nil {
+		parts = append(parts, t.CompletionDate.Format(dateLayout))
+	}
+	// 完了済みで完了日が無い場合、作成日を書くと完了日と誤読されるため省略する
+	if t.CreationDate != nil && (!t.Completed || t.CompletionDate != nil) {
+		parts = append(parts, t.CreationDate.Format(dateLayout))
+	}
+	parts = append(parts, t.Text)
+	return strings.Join(parts, " ")
+}
+
+// FromTodo は Todo を todo.txt の Task に変換します。
+// 優先度は high/medium/low を (A)/(B)/(C) に対応させ、期限は due: タグとして出力します。
+// Description は1語に収まるよう空白・改行・"/" などをパーセントエンコードして desc: タグとして出力します。
+func FromTodo(todo *domain.Todo) *Task {
+	task := &Task{
+		Completed: todo.IsCompleted,
+		Priority:  priorityLetter(todo.Priority),
+		Text:      singleLine(todo.Title),
+		Tags:      map[string]string{},
+	}
+
+	if !todo.CreatedAt.IsZero() {
+		created := todo.CreatedAt.In(time.Local)
+		task.CreationDate = &created
+	}
+	if todo.IsCompleted && todo.CompletedAt != nil {
+		completed := todo.CompletedAt.In(time.Local)
+		task.CompletionDate = &completed
+	}
+	if todo.DueDate != nil {
+		due := todo.DueDate.In(time.Local).Format(dateLayout)
+		task.Tags["due"] = due
+		task.Text += " due:" + due
+	}
+	if todo.Description != "" {
+		// "/" を含むと URL とみなされてタグとして読めないため、PathEscape で "/" もエスケープする
+		desc := url.PathEscape(todo.Description)
+		task.Tags[descTag] = desc
+		task.Text += " " + descTag + ":" + desc
+	}
+	return task
+}
+
+// ToTodo は Task を Todo に変換します。
+// +project / @context はタイトルに残し、due: タグは期限、desc: タグは詳細説明として取り出します。
+// タグは FromTodo が行末に付けるため同じキーが複数ある場合は最後のものを使い、
+// 値を解釈できない場合（"review due:soon" など）はタイトルの一部としてそのまま残します。
+func (t *Task) ToTodo() (*domain.Todo, error) {
+	title := t.Text
+	todo := &domain.Todo{
+		IsCompleted: t.Completed,
+		Priority:    priorityName(t.Priority),
+		CreatedAt:   time.Now(),
+	}
+
+	if t.CreationDate != nil {
+		todo.CreatedAt = *t.CreationDate
+	}
+	if t.Completed && t.CompletionDate != nil {
+		completed := *t.CompletionDate
+		todo.CompletedAt = &completed
+	}
+	if due, ok := t.Tags["due"]; ok {
+		if d, err := time.ParseInLocation(dateLayout, due, time.Local); err == nil {
+			todo.DueDate = &d
+			title = removeLastWord(title, "due:"+due)
+		}
+	}
+	if desc, ok := t.Tags[descTag]; ok {
+		if d, err := url.PathUnescape(desc); err == nil {
+			todo.Description = d
+			title = removeLastWord(title, descTag+":"+desc)
+		}
+	}
+
+	todo.Title = title
+	if todo.Title == "" {
+		return nil, domain.ErrTitleEmpty
+	}
+	return todo, nil
+}
+
+// Encode は Todo の一覧を todo.txt 形式で書き出します
+func Encode(w io.Writer, todos []*domain.Todo) error {
+	for _, todo := range todos {
+		if _, err := io.WriteString(w, FromTodo(todo).String()+"\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DecodeResult は todo.txt の解析結果です
+type DecodeResult struct {
+	Todos   []*domain.Todo
+	Skipped []domain.ImportSkipped
+}
+
+// Decode は todo.txt を読み込み Todo に変換します。変換できなかった行は Skipped に記録されます。
+// todo.txt にはタスクを識別する ID が無いため ICalUID は設定せず、取り込んだ行は常に新しいタスクになります
+// （ID はサーバーごとに採番されるため、ほかの環境のエクスポートで既存のタスクを上書きしないよう照合もしない）
+func Decode(r io.Reader) (*DecodeResult, error) {
+	result := &DecodeResult{Skipped: []domain.ImportSkipped{}}
+	// desc: タグはパーセントエンコードで長くなるため、行の長さに上限のある bufio.Scanner は使わない
+	// （入力全体の大きさはハンドラーで制限する）
+	br := bufio.NewReader(r)
+
+	lineNo := 0
+	for {
+		line, err := br.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		if line != "" {
+			lineNo++
+			result.add(lineNo, line)
+		}
+		if err == io.EOF {
+			return result, nil
+		}
+	}
+}
+
+func (res *DecodeResult) add(lineNo int, line string) {
+	task, ok := ParseLine(line)
+	if !ok {
+		return
+	}
+	todo, err := task.ToTodo()
+	if err != nil {
+		res.Skipped = append(res.Skipped, domain.ImportSkipped{Line: lineNo, Reason: err.Error()})
+		return
+	}
+	res.Todos = append(res.Todos, todo)
+}
+
+func priorityLetter(priority string) string {
+	switch priority {
+	case "high":
+		return "A"
+	case "medium":
+		return "B"
+	case "low":
+		return "C"
+	default:
+		return ""
+	}
+}
+
+// priorityName は (A)=high, (B)=medium, (C) 以降=low に変換します。未設定は medium とします
+func priorityName(letter string) string {
+	switch letter {
+	case "A":
+		return "high"
+	case "", "B":
+		return "medium"
+	default:
+		return "low"
+	}
+}
+
+func cutDate(s string) (*time.Time, string) {
+	word, rest, _ := strings.Cut(s, " ")
+	d, err := time.ParseInLocation(dateLayout, word, time.Local)
+	if err != nil {
+		return nil, s
+	}
+	return &d, rest
+}
+
+// removeLastWord は s の単語のうち最後に現れる word を1つだけ取り除きます
+func removeLastWord(s, word string) string {
+	fields := strings.Fields(s)
+	for i := len(fields) - 1; i >= 0; i-- {
+		if fields[i] == word {
+			fields = append(fields[:i], fields[i+1:]...)
+			break
+		}
+	}
+	return strings.Join(fields, " ")
+}
+
+// singleLine は改行を空白に置き換えます（todo.txt は1行1タスクのため）
+func singleLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
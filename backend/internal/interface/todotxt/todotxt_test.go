@@ -0,0 +1,217 @@
+package todotxt
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+	"todo_app_golang/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func date(y int, m time.Month, d int) time.Time {
+	return time.Date(y, m, d, 0, 0, 0, 0, time.Local)
+}
+
+func TestParseLine(t *testing.T) {
+	t.Run("成功：未完了タスクの各要素が解析されること", func(t *testing.T) {
+		task, ok := ParseLine("(A) 2026-10-01 電話する +仕事 @オフィス due:2026-10-05")
+
+		assert.True(t, ok)
+		assert.False(t, task.Completed)
+		assert.Equal(t, "A", task.Priority)
+		assert.True(t, date(2026, 10, 1).Equal(*task.CreationDate))
+		assert.Nil(t, task.CompletionDate)
+		assert.Equal(t, "電話する +仕事 @オフィス due:2026-10-05", task.Text)
+		assert.Equal(t, []string{"仕事"}, task.Projects)
+		assert.Equal(t, []string{"オフィス"}, task.Contexts)
+		assert.Equal(t, "2026-10-05", task.Tags["due"])
+	})
+
+	t.Run("成功：完了済みタスクは完了日と作成日の順で解析されること", func(t *testing.T) {
+		task, ok := ParseLine("x 2026-10-03 2026-10-01 レビュー")
+
+		assert.True(t, ok)
+		assert.True(t, task.Completed)
+		assert.True(t, date(2026, 10, 3).Equal(*task.CompletionDate))
+		assert.True(t, date(2026, 10, 1).Equal(*task.CreationDate))
+		assert.Equal(t, "レビュー", task.Text)
+	})
+
+	t.Run("成功：URL は key:value タグとして扱われないこと", func(t *testing.T) {
+		task, _ := ParseLine("資料を読む https://example.com")
+		assert.Empty(t, task.Tags)
+	})
+
+	t.Run("空行は無視されること", func(t *testing.T) {
+		_, ok := ParseLine("   ")
+		assert.False(t, ok)
+	})
+}
+
+func TestTask_ToTodo(t *testing.T) {
+	t.Run("成功：優先度と期限が変換され、project と context はタイトルに残ること", func(t *testing.T) {
+		task, _ := ParseLine("(C) 2026-10-01 掃除 +家 @週末 due:2026-10-05")
+
+		todo, err := task.ToTodo()
+
+		assert.NoError(t, err)
+		assert.Equal(t, "掃除 +家 @週末", todo.Title)
+		assert.Equal(t, "low", todo.Priority)
+		assert.True(t, date(2026, 10, 5).Equal(*todo.DueDate))
+		assert.True(t, date(2026, 10, 1).Equal(todo.CreatedAt))
+	})
+
+	t.Run("成功：優先度が無い場合は medium になること", func(t *testing.T) {
+		task, _ := ParseLine("メモ")
+		todo, err := task.ToTodo()
+
+		assert.NoError(t, err)
+		assert.Equal(t, "medium", todo.Priority)
+	})
+
+	t.Run("成功：期限として解釈できない due: はタイトルに残ること", func(t *testing.T) {
+		task, _ := ParseLine("メモ due:tomorrow")
+		todo, err := task.ToTodo()
+
+		assert.NoError(t, err)
+		assert.Equal(t, "メモ due:tomorrow", todo.Title)
+		assert.Nil(t, todo.DueDate)
+	})
+
+	t.Run("失敗：本文が空の場合はエラーになること", func(t *testing.T) {
+		task, _ := ParseLine("(A) 2026-10-01 due:2026-10-05")
+		_, err := task.ToTodo()
+		assert.Equal(t, domain.ErrTitleEmpty, err)
+	})
+}
+
+func TestEncodeDecode_RoundTrip(t *testing.T) {
+	due := date(2026, 11, 1)
+	completedAt := date(2026, 9, 5)
+	original := []*domain.Todo{
+		{Title: "牛乳を買う +買い物 @スーパー", Priority: "high", DueDate: &due, CreatedAt: date(2026, 10, 1)},
+		{Title: "報告書", Priority: "medium", CreatedAt: date(2026, 10, 2)},
+		{Title: "完了した作業", Priority: "low", IsCompleted: true, CreatedAt: date(2026, 9, 1), CompletedAt: &completedAt},
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(t, Encode(&buf, original))
+	assert.Equal(t, strings.Join([]string{
+		"(A) 2026-10-01 牛乳を買う +買い物 @スーパー due:2026-11-01",
+		"(B) 2026-10-02 報告書",
+		"x (C) 2026-09-05 2026-09-01 完了した作業",
+		"",
+	}, "\n"), buf.String())
+
+	result, err := Decode(&buf)
+	assert.NoError(t, err)
+	assert.Empty(t, result.Skipped)
+	assert.Len(t, result.Todos, 3)
+	for i, todo := range result.Todos {
+		assert.Equal(t, original[i].Title, todo.Title)
+		assert.Equal(t, original[i].Priority, todo.Priority)
+		assert.Equal(t, original[i].IsCompleted, todo.IsCompleted)
+		assert.True(t, original[i].CreatedAt.Equal(todo.CreatedAt))
+		if original[i].CompletedAt != nil {
+			assert.True(t, original[i].CompletedAt.Equal(*todo.CompletedAt))
+		} else {
+			assert.Nil(t, todo.CompletedAt)
+		}
+		if original[i].DueDate != nil {
+			assert.True(t, original[i].DueDate.Equal(*todo.DueDate))
+		} else {
+			assert.Nil(t, todo.DueDate)
+		}
+	}
+}
+
+func TestEncodeDecode_Description(t *testing.T) {
+	original := []*domain.Todo{{
+		Title:       "議事録",
+		Priority:    "medium",
+		Description: "参加者: 佐藤, 鈴木\n資料は https://example.com/a%20b を参照",
+		CreatedAt:   date(2026, 10, 1),
+	}}
+
+	var buf bytes.Buffer
+	assert.NoError(t, Encode(&buf, original))
+	// 1行・1語に収まり、URL とみなされないこと
+	assert.Equal(t, 1, strings.Count(buf.String(), "\n"))
+	task, _ := ParseLine(buf.String())
+	assert.Contains(t, task.Tags, "desc")
+
+	result, err := Decode(&buf)
+	assert.NoError(t, err)
+	if assert.Len(t, result.Todos, 1) {
+		assert.Equal(t, "議事録", result.Todos[0].Title)
+		assert.Equal(t, original[0].Description, result.Todos[0].Description)
+	}
+
+	t.Run("成功：エンコード後に 64KB を超える長い説明も読み戻せること", func(t *testing.T) {
+		long := []*domain.Todo{{Title: "長い説明", Priority: "medium", Description: strings.Repeat("説明文", 3000), CreatedAt: date(2026, 10, 1)}}
+		var buf bytes.Buffer
+		assert.NoError(t, Encode(&buf, long))
+		assert.Greater(t, buf.Len(), 64*1024)
+
+		result, err := Decode(&buf)
+		assert.NoError(t, err)
+		assert.Empty(t, result.Skipped)
+		if assert.Len(t, result.Todos, 1) {
+			assert.Equal(t, long[0].Description, result.Todos[0].Description)
+		}
+	})
+
+	t.Run("成功：desc タグのエスケープが不正な場合はタイトルに残ること", func(t *testing.T) {
+		task, _ := ParseLine("メモ desc:%zz")
+		todo, err := task.ToTodo()
+
+		assert.NoError(t, err)
+		assert.Equal(t, "メモ desc:%zz", todo.Title)
+		assert.Empty(t, todo.Description)
+	})
+}
+
+func TestEncodeDecode_ReservedWordsInTitle(t *testing.T) {
+	due := date(2026, 10, 5)
+	todos := []*domain.Todo{
+		{Title: "review due:soon", Priority: "medium", CreatedAt: date(2026, 10, 1)},
+		{Title: "締切 due:2026-12-31 を確認 desc:memo", Priority: "medium", Description: "詳細", DueDate: &due, CreatedAt: date(2026, 10, 1)},
+	}
+	var buf bytes.Buffer
+	assert.NoError(t, Encode(&buf, todos))
+
+	result, err := Decode(&buf)
+
+	assert.NoError(t, err)
+	assert.Empty(t, result.Skipped)
+	if assert.Len(t, result.Todos, 2) {
+		assert.Equal(t, "review due:soon", result.Todos[0].Title)
+		assert.Nil(t, result.Todos[0].DueDate)
+
+		// 行末に付けたタグが使われ、タイトル中の同じキーの語はそのまま残る
+		assert.Equal(t, todos[1].Title, result.Todos[1].Title)
+		assert.True(t, due.Equal(*result.Todos[1].DueDate))
+		assert.Equal(t, "詳細", result.Todos[1].Description)
+	}
+}
+
+func TestDecode_AlwaysCreates(t *testing.T) {
+	// todo.txt には ID が無いため、同じファイルを取り込んでも既存のタスクとは照合しない
+	result, err := Decode(strings.NewReader("(A) 2026-10-01 電話する\n"))
+
+	assert.NoError(t, err)
+	assert.Len(t, result.Todos, 1)
+	assert.Nil(t, result.Todos[0].ICalUID)
+}
+
+func TestDecode_Skipped(t *testing.T) {
+	result, err := Decode(strings.NewReader("タスク1\n\n(A) due:2026-10-05\n"))
+
+	assert.NoError(t, err)
+	assert.Len(t, result.Todos, 1)
+	assert.Len(t, result.Skipped, 1)
+	assert.Equal(t, 3, result.Skipped[0].Line)
+	assert.Equal(t, domain.ErrTitleEmpty.Error(), result.Skipped[0].Reason)
+}
@@ -0,0 +1,47 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLevel(t *testing.T) {
+	assert.Equal(t, slog.LevelDebug, ParseLevel("debug"))
+	assert.Equal(t, slog.LevelInfo, ParseLevel("INFO"))
+	assert.Equal(t, slog.LevelWarn, ParseLevel("warn"))
+	assert.Equal(t, slog.LevelError, ParseLevel("error"))
+	assert.Equal(t, slog.LevelInfo, ParseLevel(""))
+	assert.Equal(t, slog.LevelInfo, ParseLevel("unknown"))
+}
+
+func TestNew(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(&buf, "warn")
+
+	logger.Info("表示されない")
+	logger.Warn("表示される", slog.Int("id", 1))
+
+	var entry map[string]any
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.Equal(t, "表示される", entry["msg"])
+	assert.Equal(t, "WARN", entry["level"])
+	assert.Equal(t, float64(1), entry["id"])
+}
+
+func TestFromContext(t *testing.T) {
+	t.Run("Context に格納したロガーが取得できること", func(t *testing.T) {
+		logger := slog.New(slog.NewJSONHandler(&bytes.Buffer{}, nil))
+		ctx := WithLogger(context.Background(), logger)
+
+		assert.Same(t, logger, FromContext(ctx))
+	})
+
+	t.Run("格納されていない場合はデフォルトのロガーが返ること", func(t *testing.T) {
+		assert.Same(t, slog.Default(), FromContext(context.Background()))
+	})
+}
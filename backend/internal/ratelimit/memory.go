@@ -0,0 +1,56 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// pruneInterval は満杯に戻ったバケットを片付ける間隔です
+const pruneInterval = time.Minute
+
+// Memory はプロセス内にバケットを保持する Limiter です。
+// 制限はインスタンスごとに別々にかかります
+type Memory struct {
+	mu         sync.Mutex
+	tats       map[string]time.Time
+	lastPruned time.Time
+	now        func() time.Time
+}
+
+// NewMemory は Memory を生成します
+func NewMemory() *Memory {
+	return &Memory{tats: map[string]time.Time{}, now: time.Now}
+}
+
+func (m *Memory) Allow(ctx context.Context, key string, budget Budget) (Result, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	m.prune(now)
+
+	tat, result := budget.Take(m.tats[key], now)
+	m.tats[key] = tat
+	return result, nil
+}
+
+// Len は保持しているバケットの数を返します
+func (m *Memory) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.tats)
+}
+
+// prune は満杯に戻った（状態を持たなくても同じ判定になる）バケットを削除します
+func (m *Memory) prune(now time.Time) {
+	if now.Sub(m.lastPruned) < pruneInterval {
+		return
+	}
+	m.lastPruned = now
+	for key, tat := range m.tats {
+		if !tat.After(now) {
+			delete(m.tats, key)
+		}
+	}
+}
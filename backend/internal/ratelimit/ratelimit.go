@@ -0,0 +1,72 @@
+// Package ratelimit はクライアントごとのリクエスト数をトークンバケットで制限します。
+//
+// バケットの状態は GCRA（Generic Cell Rate Algorithm）と同じく「理論到着時刻 (TAT)」1つで表します。
+// TAT が現在時刻以前ならバケットは満杯で、1回受け付けるごとに Interval だけ先に進みます。
+// 状態が時刻1つで済むため、メモリ上でも DB の1行でも同じ計算で判定できます
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+// Budget はトークンバケットの設定です。Burst 回まで連続して受け付け、1分あたり Rate 回分ずつ回復します
+type Budget struct {
+	Rate  int // 1分あたりに回復する回数
+	Burst int // バケットの容量
+}
+
+// Interval はトークンが1つ回復するまでの時間です
+func (b Budget) Interval() time.Duration {
+	return time.Minute / time.Duration(b.Rate)
+}
+
+// Tolerance は空のバケットが満杯に戻るまでの時間です。TAT が現在時刻よりこれ以上先になる場合は拒否します
+func (b Budget) Tolerance() time.Duration {
+	return b.Interval() * time.Duration(b.Burst)
+}
+
+// Result は判定結果です。RateLimit-* ヘッダーに使います
+type Result struct {
+	Allowed    bool
+	Limit      int           // バケットの容量
+	Remaining  int           // 残りの回数
+	Reset      time.Duration // バケットが満杯に戻るまでの時間
+	RetryAfter time.Duration // 拒否した場合、次に受け付けられるまでの時間
+}
+
+// Limiter は key ごとにリクエストを受け付けるかを判定します
+type Limiter interface {
+	Allow(ctx context.Context, key string, budget Budget) (Result, error)
+}
+
+// Take は前回までの TAT（初回はゼロ値）と現在時刻から判定し、更新後の TAT と結果を返します。
+// 拒否した場合 TAT は変わりません
+func (b Budget) Take(tat, now time.Time) (time.Time, Result) {
+	next := tat
+	if next.Before(now) {
+		next = now
+	}
+	next = next.Add(b.Interval())
+	if next.Sub(now) > b.Tolerance() {
+		return tat, b.Result(false, tat, now)
+	}
+	return next, b.Result(true, next, now)
+}
+
+// Result は判定後の TAT から結果を組み立てます。
+// 判定を DB 側で行う場合など、Take を使わずに判定した実装が使います
+func (b Budget) Result(allowed bool, tat, now time.Time) Result {
+	reset := max(tat.Sub(now), 0)
+	r := Result{
+		Allowed:   allowed,
+		Limit:     b.Burst,
+		Remaining: max(int((b.Tolerance()-reset)/b.Interval()), 0),
+		Reset:     reset,
+	}
+	if !allowed {
+		// TAT が Tolerance - Interval まで戻れば次の1回が収まる
+		r.RetryAfter = max(reset-b.Tolerance()+b.Interval(), 0)
+	}
+	return r
+}
@@ -0,0 +1,85 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBudget_Take(t *testing.T) {
+	budget := Budget{Rate: 60, Burst: 3} // 1秒に1回回復
+	now := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("容量までは連続して受け付け、超えると拒否すること", func(t *testing.T) {
+		var tat time.Time
+		var r Result
+		for i, remaining := range []int{2, 1, 0} {
+			tat, r = budget.Take(tat, now)
+			assert.True(t, r.Allowed, i)
+			assert.Equal(t, 3, r.Limit)
+			assert.Equal(t, remaining, r.Remaining)
+		}
+		assert.Equal(t, 3*time.Second, r.Reset)
+
+		denied, r := budget.Take(tat, now)
+		assert.False(t, r.Allowed)
+		assert.Equal(t, tat, denied, "拒否した場合は状態を変えないこと")
+		assert.Equal(t, 0, r.Remaining)
+		assert.Equal(t, time.Second, r.RetryAfter)
+	})
+
+	t.Run("時間の経過とともに回復すること", func(t *testing.T) {
+		var tat time.Time
+		for range 3 {
+			tat, _ = budget.Take(tat, now)
+		}
+
+		_, r := budget.Take(tat, now.Add(500*time.Millisecond))
+		assert.False(t, r.Allowed)
+		assert.Equal(t, 500*time.Millisecond, r.RetryAfter)
+
+		tat, r = budget.Take(tat, now.Add(time.Second))
+		assert.True(t, r.Allowed)
+		assert.Equal(t, 0, r.Remaining)
+
+		// 満杯に戻った後も容量より多くは貯まらない
+		_, r = budget.Take(tat, now.Add(time.Hour))
+		assert.True(t, r.Allowed)
+		assert.Equal(t, 2, r.Remaining)
+	})
+}
+
+func TestMemory(t *testing.T) {
+	ctx := context.Background()
+	budget := Budget{Rate: 60, Burst: 1}
+
+	t.Run("キーごとに別々に制限すること", func(t *testing.T) {
+		limiter := NewMemory()
+
+		r, err := limiter.Allow(ctx, "a", budget)
+		assert.NoError(t, err)
+		assert.True(t, r.Allowed)
+		r, _ = limiter.Allow(ctx, "a", budget)
+		assert.False(t, r.Allowed)
+		r, _ = limiter.Allow(ctx, "b", budget)
+		assert.True(t, r.Allowed)
+	})
+
+	t.Run("満杯に戻ったバケットは片付けること", func(t *testing.T) {
+		limiter := NewMemory()
+		now := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+		limiter.now = func() time.Time { return now }
+
+		limiter.Allow(ctx, "a", budget)
+		for range 5 {
+			limiter.Allow(ctx, "b", Budget{Rate: 1, Burst: 5}) // 満杯に戻るまで5分かかる
+		}
+		assert.Equal(t, 2, limiter.Len())
+
+		now = now.Add(2 * time.Minute)
+		limiter.Allow(ctx, "c", budget)
+		assert.Equal(t, 2, limiter.Len(), "a だけが片付けられること")
+	})
+}
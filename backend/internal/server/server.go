@@ -0,0 +1,140 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+	"todo_app_golang/internal/config"
+	"todo_app_golang/internal/logging"
+)
+
+// Worker はサーバーと並行して動くバックグラウンド処理です。
+// Run は ctx がキャンセルされたら速やかに終了してください
+type Worker struct {
+	Name string
+	Run  func(ctx context.Context) error
+}
+
+// Workers はバックグラウンド処理の一覧と稼働状況です
+type Workers struct {
+	list []Worker
+
+	mu      sync.Mutex
+	running map[string]bool
+}
+
+func NewWorkers(workers ...Worker) *Workers {
+	return &Workers{list: workers, running: map[string]bool{}}
+}
+
+// Check は動いていないワーカーがあればエラーを返します（readiness チェック用）
+func (ws *Workers) Check(ctx context.Context) error {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	var stopped []string
+	for _, w := range ws.list {
+		if !ws.running[w.Name] {
+			stopped = append(stopped, w.Name)
+		}
+	}
+	if len(stopped) > 0 {
+		return fmt.Errorf("workers not running: %s", strings.Join(stopped, ", "))
+	}
+	return nil
+}
+
+func (ws *Workers) setRunning(name string, running bool) {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	ws.running[name] = running
+}
+
+// New は設定のタイムアウトを適用した http.Server を生成します
+func New(cfg config.ServerConfig, handler http.Handler) *http.Server {
+	return &http.Server{
+		Addr:              cfg.Addr,
+		Handler:           handler,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		ReadTimeout:       cfg.ReadTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+	}
+}
+
+// Run は ctx がキャンセルされるまで（通常は SIGINT / SIGTERM を受けるまで）サーバーとワーカーを動かします。
+// 終了時は新しい接続の受け付けを止め、処理中のリクエストとワーカーの終了を shutdownTimeout まで待ちます。
+// 戻った時点で処理中のリクエストは残っていないため、呼び出し側は DB 等を安全に閉じられます
+func Run(ctx context.Context, srv *http.Server, shutdownTimeout time.Duration, workers *Workers) error {
+	ln, err := net.Listen("tcp", srv.Addr)
+	if err != nil {
+		return err
+	}
+	return Serve(ctx, srv, ln, shutdownTimeout, workers)
+}
+
+// Serve は Run と同じですが、待ち受け済みの Listener を使います（テスト用）
+func Serve(ctx context.Context, srv *http.Server, ln net.Listener, shutdownTimeout time.Duration, workers *Workers) error {
+	logger := logging.FromContext(ctx)
+
+	// ワーカーはサーバーの終了後に止めるため、シグナルとは別の Context で動かす
+	workerCtx, stopWorkers := context.WithCancel(context.WithoutCancel(ctx))
+	defer stopWorkers()
+
+	var wg sync.WaitGroup
+	for _, w := range workers.list {
+		wg.Add(1)
+		workers.setRunning(w.Name, true)
+		go func() {
+			defer wg.Done()
+			defer workers.setRunning(w.Name, false)
+			if err := w.Run(workerCtx); err != nil && !errors.Is(err, context.Canceled) {
+				logger.Error("Worker stopped", slog.String("worker", w.Name), slog.Any("error", err))
+			}
+		}()
+	}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- srv.Serve(ln)
+	}()
+
+	var err error
+	select {
+	case err = <-serveErr:
+		// 待ち受けに失敗した場合もワーカーは止めてから戻る
+		if errors.Is(err, http.ErrServerClosed) {
+			err = nil
+		}
+	case <-ctx.Done():
+		logger.Info("Shutting down", slog.Duration("timeout", shutdownTimeout))
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), shutdownTimeout)
+	defer cancel()
+
+	if shutdownErr := srv.Shutdown(shutdownCtx); shutdownErr != nil {
+		logger.Error("Server shutdown did not complete", slog.Any("error", shutdownErr))
+		err = errors.Join(err, shutdownErr)
+	}
+
+	stopWorkers()
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-shutdownCtx.Done():
+		logger.Error("Workers did not stop in time")
+		err = errors.Join(err, shutdownCtx.Err())
+	}
+	return err
+}
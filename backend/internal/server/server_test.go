@@ -0,0 +1,97 @@
+package server
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+	"todo_app_golang/internal/config"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func listen(t *testing.T) net.Listener {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	return ln
+}
+
+func TestNew(t *testing.T) {
+	cfg := config.Default().Server
+	srv := New(cfg, http.NotFoundHandler())
+
+	assert.Equal(t, cfg.Addr, srv.Addr)
+	assert.Equal(t, cfg.ReadHeaderTimeout, srv.ReadHeaderTimeout)
+	assert.Equal(t, cfg.ReadTimeout, srv.ReadTimeout)
+	assert.Equal(t, cfg.WriteTimeout, srv.WriteTimeout)
+	assert.Equal(t, cfg.IdleTimeout, srv.IdleTimeout)
+}
+
+func TestServe(t *testing.T) {
+	t.Run("終了時に処理中のリクエストとワーカーの完了を待つこと", func(t *testing.T) {
+		started := make(chan struct{})
+		srv := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			close(started)
+			time.Sleep(100 * time.Millisecond)
+			w.Write([]byte("done"))
+		})}
+		ln := listen(t)
+
+		workerStopped := false
+		worker := Worker{Name: "test", Run: func(ctx context.Context) error {
+			<-ctx.Done()
+			workerStopped = true
+			return ctx.Err()
+		}}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		result := make(chan error, 1)
+		workers := NewWorkers(worker)
+		go func() { result <- Serve(ctx, srv, ln, time.Second, workers) }()
+
+		resp := make(chan string, 1)
+		go func() {
+			res, err := http.Get("http://" + ln.Addr().String())
+			if err != nil {
+				resp <- err.Error()
+				return
+			}
+			defer res.Body.Close()
+			body, _ := io.ReadAll(res.Body)
+			resp <- string(body)
+		}()
+
+		<-started
+		assert.NoError(t, workers.Check(context.Background()))
+		cancel() // SIGTERM を受けた状態
+
+		assert.Equal(t, "done", <-resp)
+		assert.NoError(t, <-result)
+		assert.True(t, workerStopped)
+		assert.ErrorContains(t, workers.Check(context.Background()), "test")
+	})
+
+	t.Run("失敗：期限内に終わらないリクエストがある場合はエラーを返すこと", func(t *testing.T) {
+		started := make(chan struct{})
+		release := make(chan struct{})
+		defer close(release)
+		srv := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			close(started)
+			<-release
+		})}
+		ln := listen(t)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		result := make(chan error, 1)
+		go func() { result <- Serve(ctx, srv, ln, 50*time.Millisecond, NewWorkers()) }()
+		go http.Get("http://" + ln.Addr().String())
+
+		<-started
+		cancel()
+
+		assert.ErrorIs(t, <-result, context.DeadlineExceeded)
+	})
+}
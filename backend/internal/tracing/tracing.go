@@ -0,0 +1,103 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.40.0"
+)
+
+// エクスポーターの種類
+const (
+	ExporterNone   = "none"   // トレースを出力しない（伝播のみ行う）
+	ExporterStdout = "stdout" // 標準出力に整形した JSON を出力（ローカルでのデバッグ用）
+	ExporterFile   = "file"   // ファイルに1行1スパンの JSON を追記
+	ExporterOTLP   = "otlp"   // OTLP/HTTP で送信（送信先は OTEL_EXPORTER_OTLP_ENDPOINT 等で指定）
+)
+
+// Config はトレースの設定です
+type Config struct {
+	Exporter    string
+	FilePath    string // Exporter が file の場合の出力先
+	ServiceName string
+}
+
+// Setup はグローバルな TracerProvider と W3C Trace Context の伝播を設定します。
+// 戻り値の関数はサーバー終了時に呼び出し、未送信のスパンを書き出してください
+func Setup(ctx context.Context, cfg Config) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	var (
+		exporter sdktrace.SpanExporter
+		closer   func() error
+		err      error
+	)
+	switch cfg.Exporter {
+	case ExporterNone:
+		return func(context.Context) error { return nil }, nil
+	case ExporterStdout:
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout), stdouttrace.WithPrettyPrint())
+	case ExporterFile:
+		f, openErr := os.OpenFile(cfg.FilePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if openErr != nil {
+			return nil, openErr
+		}
+		closer = f.Close
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(f))
+	case ExporterOTLP:
+		exporter, err = otlptracehttp.New(ctx)
+	default:
+		return nil, fmt.Errorf("unknown trace exporter: %q", cfg.Exporter)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(semconv.ServiceName(cfg.ServiceName)))
+	if err != nil {
+		return nil, err
+	}
+
+	// サンプリングは OTEL_TRACES_SAMPLER / OTEL_TRACES_SAMPLER_ARG で変更できる
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+
+	return func(ctx context.Context) error {
+		err := provider.Shutdown(ctx)
+		if closer != nil {
+			if cerr := closer(); err == nil {
+				err = cerr
+			}
+		}
+		return err
+	}, nil
+}
+
+// HTTPHandler は HTTP サーバーのスパンを記録するミドルウェアです。
+// traceparent ヘッダーを引き継ぎ、スパン名は ServeMux のルートパターン（例: "GET /todos/{id}"）になります
+func HTTPHandler(next http.Handler) http.Handler {
+	return otelhttp.NewHandler(next, "http.server", otelhttp.WithSpanNameFormatter(spanName))
+}
+
+// spanName はルートパターンが確定していればそれを、未確定（ルーティング前・未マッチ）ならメソッド名を返します
+func spanName(_ string, r *http.Request) string {
+	if r.Pattern != "" {
+		return r.Pattern
+	}
+	return r.Method
+}
@@ -0,0 +1,69 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestSetup(t *testing.T) {
+	t.Cleanup(func() { otel.SetTracerProvider(trace.NewNoopTracerProvider()) })
+
+	t.Run("file エクスポーターでスパンがファイルに書き出されること", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "traces.jsonl")
+		shutdown, err := Setup(context.Background(), Config{Exporter: ExporterFile, FilePath: path, ServiceName: "test"})
+		assert.NoError(t, err)
+
+		_, span := otel.Tracer("test").Start(context.Background(), "test-span")
+		span.End()
+		assert.NoError(t, shutdown(context.Background()))
+
+		data, err := os.ReadFile(path)
+		assert.NoError(t, err)
+		assert.Contains(t, string(data), `"Name":"test-span"`)
+		assert.Contains(t, string(data), `"Value":"test"`)
+	})
+
+	t.Run("none の場合は何もせずに成功すること", func(t *testing.T) {
+		shutdown, err := Setup(context.Background(), Config{Exporter: ExporterNone})
+		assert.NoError(t, err)
+		assert.NoError(t, shutdown(context.Background()))
+	})
+
+	t.Run("失敗：未知のエクスポーターはエラーになること", func(t *testing.T) {
+		_, err := Setup(context.Background(), Config{Exporter: "zipkin"})
+		assert.Error(t, err)
+	})
+}
+
+func TestHTTPHandler(t *testing.T) {
+	// traceparent ヘッダーのトレースIDがサーバー側のスパンに引き継がれること
+	_, err := Setup(context.Background(), Config{Exporter: ExporterNone})
+	assert.NoError(t, err)
+
+	exporter := tracetest.NewInMemoryExporter()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+	t.Cleanup(func() { otel.SetTracerProvider(trace.NewNoopTracerProvider()) })
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /todos/{id}", func(w http.ResponseWriter, r *http.Request) {})
+	h := HTTPHandler(mux)
+
+	req := httptest.NewRequest(http.MethodGet, "/todos/1", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := exporter.GetSpans()
+	assert.Len(t, spans, 1)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", spans[0].SpanContext.TraceID().String())
+	assert.Equal(t, "GET /todos/{id}", spans[0].Name)
+}
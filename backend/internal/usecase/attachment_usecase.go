@@ -0,0 +1,296 @@
+package usecase
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"todo_app_golang/internal/domain"
+	"todo_app_golang/internal/logging"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// sniffLen はファイルの種類の判定に使う先頭のバイト数です（http.DetectContentType が参照する長さ）
+const sniffLen = 512
+
+type AttachmentUseCase struct {
+	todos        domain.TodoRepository
+	attachments  domain.AttachmentRepository
+	blobs        domain.BlobStore
+	maxSize      int64
+	allowedTypes []string
+	blobLocks    blobLocks
+	now          func() time.Time
+}
+
+// NewAttachmentUseCase は maxSize バイトまで、allowedTypes の種類のファイルを受け付ける AttachmentUseCase を生成します
+func NewAttachmentUseCase(todos domain.TodoRepository, attachments domain.AttachmentRepository, blobs domain.BlobStore, maxSize int64, allowedTypes []string) *AttachmentUseCase {
+	return &AttachmentUseCase{
+		todos:        todos,
+		attachments:  attachments,
+		blobs:        blobs,
+		maxSize:      maxSize,
+		allowedTypes: allowedTypes,
+		now:          time.Now,
+	}
+}
+
+// MaxSize は1ファイルの上限（バイト）です
+func (u *AttachmentUseCase) MaxSize() int64 {
+	return u.maxSize
+}
+
+// ListAttachments はタスクの添付ファイルを古い順に返します。タスクが無い場合は domain.ErrTodoNotFound を返します
+func (u *AttachmentUseCase) ListAttachments(ctx context.Context, todoID int) (attachments []*domain.Attachment, err error) {
+	ctx, span := startSpan(ctx, "AttachmentUseCase.ListAttachments", attribute.Int("todo.id", todoID))
+	defer func() { endSpan(span, err) }()
+
+	if _, err := u.todos.GetByID(ctx, todoID); err != nil {
+		return nil, err
+	}
+	attachments, err = u.attachments.ListByTodo(ctx, todoID)
+	span.SetAttributes(attribute.Int("attachment.count", len(attachments)))
+	return attachments, err
+}
+
+// Upload は r の中身をタスクに添付します。タスクを編集できないユーザーの場合は domain.ErrTodoForbidden を返します。
+// 種類は先頭の内容から判定し、許可されていない場合は読み込みを続けずに domain.ErrAttachmentTypeNotAllowed を返します。
+// 中身は SHA-256 をキーに保存するため、同じ中身は何度添付しても BlobStore に1つだけ残ります
+func (u *AttachmentUseCase) Upload(ctx context.Context, todoID int, filename string, r io.Reader) (_ *domain.Attachment, err error) {
+	ctx, span := startSpan(ctx, "AttachmentUseCase.Upload", attribute.Int("todo.id", todoID))
+	defer func() { endSpan(span, err) }()
+
+	if err := u.authorizeEdit(ctx, todoID); err != nil {
+		return nil, err
+	}
+
+	br := bufio.NewReaderSize(r, sniffLen)
+	head, err := br.Peek(sniffLen)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	if len(head) == 0 {
+		return nil, domain.ErrAttachmentEmpty
+	}
+	contentType := http.DetectContentType(head)
+	if !u.allowed(contentType) {
+		return nil, domain.ErrAttachmentTypeNotAllowed
+	}
+
+	// 大きさとハッシュが分かるまで BlobStore に渡せないため、いったん一時ファイルに書き出す
+	tmp, err := os.CreateTemp("", "todo-attachment-*")
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		tmp.Close()
+		os.Remove(tmp.Name())
+	}()
+
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(tmp, hash), io.LimitReader(br, u.maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if size > u.maxSize {
+		return nil, domain.ErrAttachmentTooLarge
+	}
+	sum := hex.EncodeToString(hash.Sum(nil))
+
+	// 保存済みでも Put し直す（同じキーへの上書きなので冪等）。Exists を見て省くと、
+	// 最後の参照の削除と入れ違った場合に中身だけが消える。情報の追加までは同じ中身の削除と排他する
+	unlock := u.blobLocks.lock(sum)
+	defer unlock()
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	if err := u.blobs.Put(ctx, sum, tmp, size); err != nil {
+		return nil, err
+	}
+
+	attachment := &domain.Attachment{
+		TodoID:      todoID,
+		Filename:    cleanFilename(filename),
+		ContentType: contentType,
+		Size:        size,
+		SHA256:      sum,
+		CreatedAt:   u.now(),
+	}
+	if err := u.attachments.Create(ctx, attachment); err != nil {
+		return nil, err
+	}
+
+	logging.FromContext(ctx).InfoContext(ctx, "attachment uploaded",
+		slog.Int("todo_id", todoID),
+		slog.Int("attachment_id", attachment.ID),
+		slog.Int64("size", size),
+	)
+	return attachment, nil
+}
+
+// allowed は判定した種類が許可されているかを返します（charset などのパラメーターは無視する）
+func (u *AttachmentUseCase) allowed(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return slices.Contains(u.allowedTypes, mediaType)
+}
+
+// cleanFilename はクライアントが送ったファイル名からディレクトリ部分と制御文字を取り除きます
+func cleanFilename(name string) string {
+	if i := strings.LastIndexAny(name, `/\`); i >= 0 {
+		name = name[i+1:]
+	}
+	name = strings.TrimSpace(strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f {
+			return -1
+		}
+		return r
+	}, name))
+	if name == "" || name == "." || name == ".." {
+		return "attachment"
+	}
+	return name
+}
+
+// OpenAttachment は添付ファイルの情報と中身を返します。中身は呼び出し側で閉じてください
+func (u *AttachmentUseCase) OpenAttachment(ctx context.Context, id int) (_ *domain.Attachment, _ io.ReadSeekCloser, err error) {
+	ctx, span := startSpan(ctx, "AttachmentUseCase.OpenAttachment", attribute.Int("attachment.id", id))
+	defer func() { endSpan(span, err) }()
+
+	attachment, err := u.attachments.GetByID(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	content, err := u.blobs.Open(ctx, attachment.SHA256)
+	if err != nil {
+		return nil, nil, err
+	}
+	return attachment, content, nil
+}
+
+// DeleteAttachment は添付ファイルを削除します。ほかに同じ中身を参照している添付ファイルが無ければ中身も削除します。
+// 添付先のタスクを編集できないユーザーの場合は domain.ErrTodoForbidden を返します
+func (u *AttachmentUseCase) DeleteAttachment(ctx context.Context, id int) (err error) {
+	ctx, span := startSpan(ctx, "AttachmentUseCase.DeleteAttachment", attribute.Int("attachment.id", id))
+	defer func() { endSpan(span, err) }()
+
+	attachment, err := u.attachments.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := u.authorizeEdit(ctx, attachment.TodoID); err != nil {
+		return err
+	}
+	if err := u.attachments.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	logging.FromContext(ctx).InfoContext(ctx, "attachment deleted", slog.Int("attachment_id", id))
+	u.releaseBlob(ctx, attachment.SHA256)
+	return nil
+}
+
+// authorizeEdit はタスクを読み込み、ctx のユーザーが編集（domain.TodoActionEdit）できない場合は domain.ErrTodoForbidden を返します。
+// ユーザーが格納されていない場合（認証していない場合）は TodoUseCase と同じく許可します
+func (u *AttachmentUseCase) authorizeEdit(ctx context.Context, todoID int) error {
+	todo, err := u.todos.GetByID(ctx, todoID)
+	if err != nil {
+		return err
+	}
+	if user, ok := domain.UserIDFromContext(ctx); ok && !todo.Permits(user, domain.TodoActionEdit) {
+		return domain.ErrTodoForbidden
+	}
+	return nil
+}
+
+// DeleteTodoAttachments はタスクの添付ファイルをすべて削除し、ほかから参照されていない中身も削除します。
+// タスクを削除すると情報は ON DELETE CASCADE で消えて中身だけが残るため、TodoUseCase.OnDelete に登録して削除の前に呼びます
+func (u *AttachmentUseCase) DeleteTodoAttachments(ctx context.Context, todoID int) (err error) {
+	ctx, span := startSpan(ctx, "AttachmentUseCase.DeleteTodoAttachments", attribute.Int("todo.id", todoID))
+	defer func() { endSpan(span, err) }()
+
+	attachments, err := u.attachments.ListByTodo(ctx, todoID)
+	if err != nil {
+		return err
+	}
+	span.SetAttributes(attribute.Int("attachment.count", len(attachments)))
+	released := make(map[string]bool, len(attachments))
+	for _, a := range attachments {
+		if err := u.attachments.Delete(ctx, a.ID); err != nil {
+			return err
+		}
+		if !released[a.SHA256] {
+			released[a.SHA256] = true
+			u.releaseBlob(ctx, a.SHA256)
+		}
+	}
+	if len(attachments) > 0 {
+		logging.FromContext(ctx).InfoContext(ctx, "todo attachments deleted", slog.Int("todo_id", todoID), slog.Int("count", len(attachments)))
+	}
+	return nil
+}
+
+// releaseBlob は sum を参照している添付ファイルが無くなっていれば中身を削除します。
+// 情報は削除済みのため、中身を削除できなくてもエラーにはしない（残った中身は次に同じファイルが添付されたときに再利用される）
+func (u *AttachmentUseCase) releaseBlob(ctx context.Context, sum string) {
+	unlock := u.blobLocks.lock(sum)
+	defer unlock()
+
+	refs, err := u.attachments.CountBySHA256(ctx, sum)
+	if err == nil && refs == 0 {
+		err = u.blobs.Delete(ctx, sum)
+	}
+	if err != nil {
+		logging.FromContext(ctx).WarnContext(ctx, "Failed to delete attachment content", slog.String("sha256", sum), slog.Any("error", err))
+	}
+}
+
+// blobLocks は同じ中身の保存（Put から情報の追加まで）と削除（参照数の確認から削除まで）が入れ違わないよう、SHA-256 ごとに排他します。
+// 排他は1プロセス内だけのため、複数のインスタンスで同時に同じ中身を扱う場合の入れ違いは防げません
+type blobLocks struct {
+	mu    sync.Mutex
+	locks map[string]*blobLock
+}
+
+type blobLock struct {
+	sync.Mutex
+	waiters int // ロック中・待機中の数（0 になったら map から消す）
+}
+
+// lock は key のロックを取り、解放する関数を返します
+func (l *blobLocks) lock(key string) (unlock func()) {
+	l.mu.Lock()
+	if l.locks == nil {
+		l.locks = make(map[string]*blobLock)
+	}
+	k := l.locks[key]
+	if k == nil {
+		k = &blobLock{}
+		l.locks[key] = k
+	}
+	k.waiters++
+	l.mu.Unlock()
+
+	k.Lock()
+	return func() {
+		k.Unlock()
+		l.mu.Lock()
+		k.waiters--
+		if k.waiters == 0 {
+			delete(l.locks, key)
+		}
+		l.mu.Unlock()
+	}
+}
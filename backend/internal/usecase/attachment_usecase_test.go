@@ -0,0 +1,299 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+	"todo_app_golang/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockAttachmentRepository struct {
+	mock.Mock
+}
+
+func (m *MockAttachmentRepository) Create(ctx context.Context, attachment *domain.Attachment) error {
+	args := m.Called(ctx, attachment)
+	return args.Error(0)
+}
+
+func (m *MockAttachmentRepository) ListByTodo(ctx context.Context, todoID int) ([]*domain.Attachment, error) {
+	args := m.Called(ctx, todoID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Attachment), args.Error(1)
+}
+
+func (m *MockAttachmentRepository) GetByID(ctx context.Context, id int) (*domain.Attachment, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Attachment), args.Error(1)
+}
+
+func (m *MockAttachmentRepository) Delete(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockAttachmentRepository) CountBySHA256(ctx context.Context, sha256 string) (int, error) {
+	args := m.Called(ctx, sha256)
+	return args.Int(0), args.Error(1)
+}
+
+type MockBlobStore struct {
+	mock.Mock
+	put []byte // Put で受け取った中身
+}
+
+func (m *MockBlobStore) Put(ctx context.Context, key string, r io.Reader, size int64) error {
+	m.put, _ = io.ReadAll(r)
+	args := m.Called(ctx, key, size)
+	return args.Error(0)
+}
+
+func (m *MockBlobStore) Open(ctx context.Context, key string) (io.ReadSeekCloser, error) {
+	args := m.Called(ctx, key)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(io.ReadSeekCloser), args.Error(1)
+}
+
+func (m *MockBlobStore) Exists(ctx context.Context, key string) (bool, error) {
+	args := m.Called(ctx, key)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockBlobStore) Delete(ctx context.Context, key string) error {
+	args := m.Called(ctx, key)
+	return args.Error(0)
+}
+
+func newAttachmentUseCase(now time.Time, maxSize int64) (*AttachmentUseCase, *MockTodoRepository, *MockAttachmentRepository, *MockBlobStore) {
+	todos, attachments, blobs := new(MockTodoRepository), new(MockAttachmentRepository), new(MockBlobStore)
+	uc := NewAttachmentUseCase(todos, attachments, blobs, maxSize, []string{"image/png", "application/pdf"})
+	uc.now = func() time.Time { return now }
+	return uc, todos, attachments, blobs
+}
+
+func TestUpload(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	png := append([]byte("\x89PNG\r\n\x1a\n"), bytes.Repeat([]byte{0}, 600)...)
+	sum := sha256.Sum256(png)
+	pngSHA := hex.EncodeToString(sum[:])
+
+	t.Run("成功：内容から種類を判定し、SHA-256 をキーにして保存すること", func(t *testing.T) {
+		uc, todos, attachments, blobs := newAttachmentUseCase(now, 1024)
+		todos.On("GetByID", mock.Anything, 1).Return(&domain.Todo{ID: 1}, nil)
+		blobs.On("Put", mock.Anything, pngSHA, int64(len(png))).Return(nil)
+		attachments.On("Create", mock.Anything, mock.AnythingOfType("*domain.Attachment")).Return(nil)
+
+		a, err := uc.Upload(ctx, 1, `C:\Users\alice\画面.png`, bytes.NewReader(png))
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, a.TodoID)
+		assert.Equal(t, "画面.png", a.Filename)
+		assert.Equal(t, "image/png", a.ContentType)
+		assert.Equal(t, int64(len(png)), a.Size)
+		assert.Equal(t, pngSHA, a.SHA256)
+		assert.Equal(t, now, a.CreatedAt)
+		assert.Equal(t, png, blobs.put)
+		blobs.AssertExpectations(t)
+		attachments.AssertExpectations(t)
+	})
+
+	t.Run("成功：同じ中身が保存済みでも同じキーに保存し直すこと", func(t *testing.T) {
+		// 保存済みかを見て省くと、最後の参照の削除と入れ違った場合に中身だけが消える
+		uc, todos, attachments, blobs := newAttachmentUseCase(now, 1024)
+		todos.On("GetByID", mock.Anything, 1).Return(&domain.Todo{ID: 1}, nil)
+		blobs.On("Put", mock.Anything, pngSHA, int64(len(png))).Return(nil)
+		attachments.On("Create", mock.Anything, mock.AnythingOfType("*domain.Attachment")).Return(nil)
+
+		a, err := uc.Upload(ctx, 1, "copy.png", bytes.NewReader(png))
+
+		assert.NoError(t, err)
+		assert.Equal(t, pngSHA, a.SHA256)
+		blobs.AssertExpectations(t)
+		blobs.AssertNotCalled(t, "Exists", mock.Anything, mock.Anything)
+	})
+
+	t.Run("失敗：許可されていない種類の場合", func(t *testing.T) {
+		uc, todos, attachments, blobs := newAttachmentUseCase(now, 1024)
+		todos.On("GetByID", mock.Anything, 1).Return(&domain.Todo{ID: 1}, nil)
+
+		// 拡張子が .png でも中身で判定する
+		_, err := uc.Upload(ctx, 1, "script.png", strings.NewReader("<html><script>alert(1)</script></html>"))
+
+		assert.ErrorIs(t, err, domain.ErrAttachmentTypeNotAllowed)
+		blobs.AssertNotCalled(t, "Put", mock.Anything, mock.Anything, mock.Anything)
+		attachments.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("失敗：上限を超える場合", func(t *testing.T) {
+		uc, todos, attachments, blobs := newAttachmentUseCase(now, int64(len(png)-1))
+		todos.On("GetByID", mock.Anything, 1).Return(&domain.Todo{ID: 1}, nil)
+
+		_, err := uc.Upload(ctx, 1, "big.png", bytes.NewReader(png))
+
+		assert.ErrorIs(t, err, domain.ErrAttachmentTooLarge)
+		blobs.AssertNotCalled(t, "Put", mock.Anything, mock.Anything, mock.Anything)
+		attachments.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("失敗：空の場合", func(t *testing.T) {
+		uc, todos, _, _ := newAttachmentUseCase(now, 1024)
+		todos.On("GetByID", mock.Anything, 1).Return(&domain.Todo{ID: 1}, nil)
+
+		_, err := uc.Upload(ctx, 1, "empty.png", strings.NewReader(""))
+
+		assert.ErrorIs(t, err, domain.ErrAttachmentEmpty)
+	})
+
+	// 担当者は完了状態の変更しかできないため、添付もできない
+	for _, user := range []string{"bob", "carol"} {
+		t.Run("失敗：所有者以外のユーザーは添付できないこと（"+user+"）", func(t *testing.T) {
+			uc, todos, attachments, blobs := newAttachmentUseCase(now, 1024)
+			todos.On("GetByID", mock.Anything, 1).Return(ownedTodo(), nil)
+
+			_, err := uc.Upload(domain.WithUserID(ctx, user), 1, "a.png", bytes.NewReader(png))
+
+			assert.ErrorIs(t, err, domain.ErrTodoForbidden)
+			blobs.AssertNotCalled(t, "Put", mock.Anything, mock.Anything, mock.Anything)
+			attachments.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		})
+	}
+
+	t.Run("成功：所有者は添付できること", func(t *testing.T) {
+		uc, todos, attachments, blobs := newAttachmentUseCase(now, 1024)
+		todos.On("GetByID", mock.Anything, 1).Return(ownedTodo(), nil)
+		blobs.On("Put", mock.Anything, pngSHA, int64(len(png))).Return(nil)
+		attachments.On("Create", mock.Anything, mock.AnythingOfType("*domain.Attachment")).Return(nil)
+
+		_, err := uc.Upload(domain.WithUserID(ctx, "alice"), 1, "a.png", bytes.NewReader(png))
+
+		assert.NoError(t, err)
+		attachments.AssertExpectations(t)
+	})
+
+	t.Run("失敗：タスクが存在しない場合", func(t *testing.T) {
+		uc, todos, _, blobs := newAttachmentUseCase(now, 1024)
+		todos.On("GetByID", mock.Anything, 99).Return(nil, domain.ErrTodoNotFound)
+
+		_, err := uc.Upload(ctx, 99, "a.png", bytes.NewReader(png))
+
+		assert.ErrorIs(t, err, domain.ErrTodoNotFound)
+		blobs.AssertNotCalled(t, "Put", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+// ownedTodo は alice が所有し、bob が担当するタスクを返します
+func ownedTodo() *domain.Todo {
+	owner, assignee := "alice", "bob"
+	return &domain.Todo{ID: 1, OwnerID: &owner, AssigneeID: &assignee}
+}
+
+func TestDeleteAttachment(t *testing.T) {
+	ctx := context.Background()
+	existing := &domain.Attachment{ID: 3, TodoID: 1, SHA256: strings.Repeat("ab", 32)}
+
+	t.Run("成功：最後の参照を削除した場合は中身も削除すること", func(t *testing.T) {
+		uc, todos, attachments, blobs := newAttachmentUseCase(time.Now(), 1024)
+		todos.On("GetByID", mock.Anything, 1).Return(&domain.Todo{ID: 1}, nil)
+		attachments.On("GetByID", mock.Anything, 3).Return(existing, nil)
+		attachments.On("Delete", mock.Anything, 3).Return(nil)
+		attachments.On("CountBySHA256", mock.Anything, existing.SHA256).Return(0, nil)
+		blobs.On("Delete", mock.Anything, existing.SHA256).Return(nil)
+
+		assert.NoError(t, uc.DeleteAttachment(ctx, 3))
+		blobs.AssertExpectations(t)
+	})
+
+	t.Run("成功：ほかに参照がある場合は中身を残すこと", func(t *testing.T) {
+		uc, todos, attachments, blobs := newAttachmentUseCase(time.Now(), 1024)
+		todos.On("GetByID", mock.Anything, 1).Return(&domain.Todo{ID: 1}, nil)
+		attachments.On("GetByID", mock.Anything, 3).Return(existing, nil)
+		attachments.On("Delete", mock.Anything, 3).Return(nil)
+		attachments.On("CountBySHA256", mock.Anything, existing.SHA256).Return(1, nil)
+
+		assert.NoError(t, uc.DeleteAttachment(ctx, 3))
+		blobs.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+	})
+
+	// 所有者のいるタスクは所有者だけが削除でき、担当者（完了状態の変更だけができる）もほかのユーザーも削除できない
+	for _, user := range []string{"bob", "carol"} {
+		t.Run("失敗：所有者以外のユーザーは削除できないこと（"+user+"）", func(t *testing.T) {
+			uc, todos, attachments, blobs := newAttachmentUseCase(time.Now(), 1024)
+			todos.On("GetByID", mock.Anything, 1).Return(ownedTodo(), nil)
+			attachments.On("GetByID", mock.Anything, 3).Return(existing, nil)
+
+			err := uc.DeleteAttachment(domain.WithUserID(ctx, user), 3)
+
+			assert.ErrorIs(t, err, domain.ErrTodoForbidden)
+			attachments.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+			blobs.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+		})
+	}
+
+	t.Run("成功：所有者は削除できること", func(t *testing.T) {
+		uc, todos, attachments, _ := newAttachmentUseCase(time.Now(), 1024)
+		todos.On("GetByID", mock.Anything, 1).Return(ownedTodo(), nil)
+		attachments.On("GetByID", mock.Anything, 3).Return(existing, nil)
+		attachments.On("Delete", mock.Anything, 3).Return(nil)
+		attachments.On("CountBySHA256", mock.Anything, existing.SHA256).Return(1, nil)
+
+		assert.NoError(t, uc.DeleteAttachment(domain.WithUserID(ctx, "alice"), 3))
+		attachments.AssertExpectations(t)
+	})
+
+	t.Run("失敗：添付ファイルが存在しない場合", func(t *testing.T) {
+		uc, _, attachments, _ := newAttachmentUseCase(time.Now(), 1024)
+		attachments.On("GetByID", mock.Anything, 99).Return(nil, domain.ErrAttachmentNotFound)
+
+		assert.ErrorIs(t, uc.DeleteAttachment(ctx, 99), domain.ErrAttachmentNotFound)
+		attachments.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+	})
+}
+
+func TestDeleteTodoAttachments(t *testing.T) {
+	ctx := context.Background()
+	shared, own := strings.Repeat("ab", 32), strings.Repeat("cd", 32)
+
+	t.Run("成功：添付ファイルを削除し、ほかから参照されていない中身だけを削除すること", func(t *testing.T) {
+		uc, _, attachments, blobs := newAttachmentUseCase(time.Now(), 1024)
+		attachments.On("ListByTodo", mock.Anything, 1).Return([]*domain.Attachment{
+			{ID: 3, TodoID: 1, SHA256: own},
+			{ID: 4, TodoID: 1, SHA256: own},
+			{ID: 5, TodoID: 1, SHA256: shared},
+		}, nil)
+		attachments.On("Delete", mock.Anything, mock.AnythingOfType("int")).Return(nil)
+		attachments.On("CountBySHA256", mock.Anything, own).Return(0, nil).Once()
+		attachments.On("CountBySHA256", mock.Anything, shared).Return(1, nil).Once()
+		blobs.On("Delete", mock.Anything, own).Return(nil).Once()
+
+		assert.NoError(t, uc.DeleteTodoAttachments(ctx, 1))
+		attachments.AssertNumberOfCalls(t, "Delete", 3)
+		attachments.AssertExpectations(t)
+		blobs.AssertExpectations(t)
+	})
+
+	t.Run("失敗：情報を削除できない場合はエラーを返すこと", func(t *testing.T) {
+		uc, _, attachments, blobs := newAttachmentUseCase(time.Now(), 1024)
+		attachments.On("ListByTodo", mock.Anything, 1).Return([]*domain.Attachment{{ID: 3, TodoID: 1, SHA256: own}}, nil)
+		attachments.On("Delete", mock.Anything, 3).Return(errors.New("db error"))
+
+		assert.Error(t, uc.DeleteTodoAttachments(ctx, 1))
+		blobs.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,110 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+	"time"
+	"todo_app_golang/internal/domain"
+	"todo_app_golang/internal/logging"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+type CommentUseCase struct {
+	todos    domain.TodoRepository
+	comments domain.CommentRepository
+	now      func() time.Time
+}
+
+func NewCommentUseCase(todos domain.TodoRepository, comments domain.CommentRepository) *CommentUseCase {
+	return &CommentUseCase{todos: todos, comments: comments, now: time.Now}
+}
+
+// ListComments はタスクのコメントを古い順に返します。タスクが無い場合は domain.ErrTodoNotFound を返します
+func (u *CommentUseCase) ListComments(ctx context.Context, todoID int) (comments []*domain.Comment, err error) {
+	ctx, span := startSpan(ctx, "CommentUseCase.ListComments", attribute.Int("todo.id", todoID))
+	defer func() { endSpan(span, err) }()
+
+	if _, err := u.todos.GetByID(ctx, todoID); err != nil {
+		return nil, err
+	}
+	comments, err = u.comments.ListByTodo(ctx, todoID)
+	span.SetAttributes(attribute.Int("comment.count", len(comments)))
+	return comments, err
+}
+
+// AddComment は author としてタスクにコメントし、作成したコメントを返します
+func (u *CommentUseCase) AddComment(ctx context.Context, todoID int, author, body string) (_ *domain.Comment, err error) {
+	ctx, span := startSpan(ctx, "CommentUseCase.AddComment", attribute.Int("todo.id", todoID))
+	defer func() { endSpan(span, err) }()
+
+	comment, err := domain.NewComment(todoID, author, body, u.now())
+	if err != nil {
+		return nil, err
+	}
+	if _, err := u.todos.GetByID(ctx, todoID); err != nil {
+		return nil, err
+	}
+	if err := u.comments.Create(ctx, comment); err != nil {
+		return nil, err
+	}
+
+	logging.FromContext(ctx).InfoContext(ctx, "comment created",
+		slog.Int("todo_id", todoID),
+		slog.Int("comment_id", comment.ID),
+	)
+	return comment, nil
+}
+
+// EditComment は本文を書き換え、編集履歴を含めた更新後のコメントを返します。
+// user はリクエストしたユーザーで、空でなければ投稿者本人であることを確認します（認証していない場合は空）
+func (u *CommentUseCase) EditComment(ctx context.Context, id int, user, body string) (_ *domain.Comment, err error) {
+	ctx, span := startSpan(ctx, "CommentUseCase.EditComment", attribute.Int("comment.id", id))
+	defer func() { endSpan(span, err) }()
+
+	if err := domain.ValidateCommentBody(body); err != nil {
+		return nil, err
+	}
+	comment, err := u.authorize(ctx, id, user)
+	if err != nil {
+		return nil, err
+	}
+	// 本文が変わらない場合は履歴を増やさない
+	if comment.Body == body {
+		return comment, nil
+	}
+	if err := u.comments.UpdateBody(ctx, id, body, u.now()); err != nil {
+		return nil, err
+	}
+
+	logging.FromContext(ctx).InfoContext(ctx, "comment edited", slog.Int("comment_id", id))
+	return u.comments.GetByID(ctx, id)
+}
+
+// DeleteComment はコメントを削除します。user は EditComment と同じです
+func (u *CommentUseCase) DeleteComment(ctx context.Context, id int, user string) (err error) {
+	ctx, span := startSpan(ctx, "CommentUseCase.DeleteComment", attribute.Int("comment.id", id))
+	defer func() { endSpan(span, err) }()
+
+	if _, err := u.authorize(ctx, id, user); err != nil {
+		return err
+	}
+	if err := u.comments.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	logging.FromContext(ctx).InfoContext(ctx, "comment deleted", slog.Int("comment_id", id))
+	return nil
+}
+
+// authorize はコメントを取得し、user が空でなければ投稿者本人かを確認します
+func (u *CommentUseCase) authorize(ctx context.Context, id int, user string) (*domain.Comment, error) {
+	comment, err := u.comments.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if user != "" && user != comment.Author {
+		return nil, domain.ErrCommentForbidden
+	}
+	return comment, nil
+}
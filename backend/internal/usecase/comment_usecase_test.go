@@ -0,0 +1,196 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+	"todo_app_golang/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockCommentRepository struct {
+	mock.Mock
+}
+
+func (m *MockCommentRepository) Create(ctx context.Context, comment *domain.Comment) error {
+	args := m.Called(ctx, comment)
+	return args.Error(0)
+}
+
+func (m *MockCommentRepository) ListByTodo(ctx context.Context, todoID int) ([]*domain.Comment, error) {
+	args := m.Called(ctx, todoID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Comment), args.Error(1)
+}
+
+func (m *MockCommentRepository) GetByID(ctx context.Context, id int) (*domain.Comment, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Comment), args.Error(1)
+}
+
+func (m *MockCommentRepository) UpdateBody(ctx context.Context, id int, body string, editedAt time.Time) error {
+	args := m.Called(ctx, id, body, editedAt)
+	return args.Error(0)
+}
+
+func (m *MockCommentRepository) Delete(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func newCommentUseCase(now time.Time) (*CommentUseCase, *MockTodoRepository, *MockCommentRepository) {
+	todos, comments := new(MockTodoRepository), new(MockCommentRepository)
+	uc := NewCommentUseCase(todos, comments)
+	uc.now = func() time.Time { return now }
+	return uc, todos, comments
+}
+
+func TestAddComment(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+
+	t.Run("成功：投稿者と作成日時を設定して保存すること", func(t *testing.T) {
+		uc, todos, comments := newCommentUseCase(now)
+		todos.On("GetByID", mock.Anything, 1).Return(&domain.Todo{ID: 1}, nil)
+		comments.On("Create", mock.Anything, mock.AnythingOfType("*domain.Comment")).Return(nil)
+
+		c, err := uc.AddComment(ctx, 1, " alice ", "確認しました")
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, c.TodoID)
+		assert.Equal(t, "alice", c.Author)
+		assert.Equal(t, now, c.CreatedAt)
+		assert.Equal(t, now, c.UpdatedAt)
+		assert.Empty(t, c.History)
+		comments.AssertExpectations(t)
+	})
+
+	t.Run("失敗：タスクが存在しない場合", func(t *testing.T) {
+		uc, todos, comments := newCommentUseCase(now)
+		todos.On("GetByID", mock.Anything, 99).Return(nil, domain.ErrTodoNotFound)
+
+		_, err := uc.AddComment(ctx, 99, "alice", "確認しました")
+
+		assert.ErrorIs(t, err, domain.ErrTodoNotFound)
+		comments.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("失敗：入力が不正な場合", func(t *testing.T) {
+		for body, want := range map[string]error{
+			" \n ": domain.ErrCommentBodyEmpty,
+			strings.Repeat("あ", domain.MaxCommentLength+1): domain.ErrCommentTooLong,
+		} {
+			uc, _, comments := newCommentUseCase(now)
+			_, err := uc.AddComment(ctx, 1, "alice", body)
+			assert.ErrorIs(t, err, want)
+			comments.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		}
+
+		uc, _, _ := newCommentUseCase(now)
+		_, err := uc.AddComment(ctx, 1, "", "本文")
+		assert.ErrorIs(t, err, domain.ErrCommentAuthorEmpty)
+	})
+}
+
+func TestListComments(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("失敗：タスクが存在しない場合", func(t *testing.T) {
+		uc, todos, comments := newCommentUseCase(time.Now())
+		todos.On("GetByID", mock.Anything, 99).Return(nil, domain.ErrTodoNotFound)
+
+		_, err := uc.ListComments(ctx, 99)
+
+		assert.ErrorIs(t, err, domain.ErrTodoNotFound)
+		comments.AssertNotCalled(t, "ListByTodo", mock.Anything, mock.Anything)
+	})
+}
+
+func TestEditComment(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	existing := &domain.Comment{ID: 5, TodoID: 1, Author: "alice", Body: "v1"}
+
+	t.Run("成功：投稿者本人が編集した場合", func(t *testing.T) {
+		uc, _, comments := newCommentUseCase(now)
+		edited := &domain.Comment{ID: 5, Author: "alice", Body: "v2", History: []domain.CommentRevision{{Body: "v1", EditedAt: now}}}
+		comments.On("GetByID", mock.Anything, 5).Return(existing, nil).Once()
+		comments.On("UpdateBody", mock.Anything, 5, "v2", now).Return(nil)
+		comments.On("GetByID", mock.Anything, 5).Return(edited, nil).Once()
+
+		c, err := uc.EditComment(ctx, 5, "alice", "v2")
+
+		assert.NoError(t, err)
+		assert.Equal(t, edited, c)
+		comments.AssertExpectations(t)
+	})
+
+	t.Run("成功：認証していない場合は投稿者を確認しないこと", func(t *testing.T) {
+		uc, _, comments := newCommentUseCase(now)
+		comments.On("GetByID", mock.Anything, 5).Return(existing, nil)
+		comments.On("UpdateBody", mock.Anything, 5, "v2", now).Return(nil)
+
+		_, err := uc.EditComment(ctx, 5, "", "v2")
+
+		assert.NoError(t, err)
+		comments.AssertExpectations(t)
+	})
+
+	t.Run("成功：本文が変わらない場合は履歴を増やさないこと", func(t *testing.T) {
+		uc, _, comments := newCommentUseCase(now)
+		comments.On("GetByID", mock.Anything, 5).Return(existing, nil)
+
+		c, err := uc.EditComment(ctx, 5, "alice", "v1")
+
+		assert.NoError(t, err)
+		assert.Equal(t, existing, c)
+		comments.AssertNotCalled(t, "UpdateBody", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("失敗：ほかの人のコメントの場合", func(t *testing.T) {
+		uc, _, comments := newCommentUseCase(now)
+		comments.On("GetByID", mock.Anything, 5).Return(existing, nil)
+
+		_, err := uc.EditComment(ctx, 5, "bob", "v2")
+
+		assert.ErrorIs(t, err, domain.ErrCommentForbidden)
+		comments.AssertNotCalled(t, "UpdateBody", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestDeleteComment(t *testing.T) {
+	ctx := context.Background()
+	existing := &domain.Comment{ID: 5, TodoID: 1, Author: "alice", Body: "v1"}
+
+	t.Run("成功：投稿者本人が削除した場合", func(t *testing.T) {
+		uc, _, comments := newCommentUseCase(time.Now())
+		comments.On("GetByID", mock.Anything, 5).Return(existing, nil)
+		comments.On("Delete", mock.Anything, 5).Return(nil)
+
+		assert.NoError(t, uc.DeleteComment(ctx, 5, "alice"))
+		comments.AssertExpectations(t)
+	})
+
+	t.Run("失敗：ほかの人のコメントの場合", func(t *testing.T) {
+		uc, _, comments := newCommentUseCase(time.Now())
+		comments.On("GetByID", mock.Anything, 5).Return(existing, nil)
+
+		assert.ErrorIs(t, uc.DeleteComment(ctx, 5, "bob"), domain.ErrCommentForbidden)
+		comments.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+	})
+
+	t.Run("失敗：コメントが存在しない場合", func(t *testing.T) {
+		uc, _, comments := newCommentUseCase(time.Now())
+		comments.On("GetByID", mock.Anything, 99).Return(nil, domain.ErrCommentNotFound)
+
+		assert.ErrorIs(t, uc.DeleteComment(ctx, 99, ""), domain.ErrCommentNotFound)
+	})
+}
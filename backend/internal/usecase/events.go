@@ -0,0 +1,61 @@
+package usecase
+
+import (
+	"context"
+	"sync"
+	"todo_app_golang/internal/domain"
+)
+
+// watchBuffer は購読者ごとにためておける変更通知の件数です
+const watchBuffer = 64
+
+// broadcaster は変更通知を購読者に配ります。
+// 受信が追いつかずバッファが溢れた購読者は、通知を取りこぼしたまま続かないよう購読を打ち切ります
+type broadcaster struct {
+	mu   sync.Mutex
+	subs map[chan domain.TodoEvent]struct{}
+}
+
+func newBroadcaster() *broadcaster {
+	return &broadcaster{subs: map[chan domain.TodoEvent]struct{}{}}
+}
+
+// subscribe は ctx が終わるまで通知を受け取るチャネルを返します
+func (b *broadcaster) subscribe(ctx context.Context) <-chan domain.TodoEvent {
+	ch := make(chan domain.TodoEvent, watchBuffer)
+	b.mu.Lock()
+	b.subs[ch] = struct{}{}
+	b.mu.Unlock()
+
+	context.AfterFunc(ctx, func() { b.remove(ch) })
+	return ch
+}
+
+// remove は購読を解除してチャネルを閉じます（解除済みの場合は何もしない）
+func (b *broadcaster) remove(ch chan domain.TodoEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, ok := b.subs[ch]; ok {
+		delete(b.subs, ch)
+		close(ch)
+	}
+}
+
+func (b *broadcaster) active() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.subs) > 0
+}
+
+func (b *broadcaster) publish(ev domain.TodoEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for ch := range b.subs {
+		select {
+		case ch <- ev:
+		default:
+			delete(b.subs, ch)
+			close(ch)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package usecase
+
+import (
+	"context"
+	"math"
+	"time"
+	"todo_app_golang/internal/domain"
+)
+
+const (
+	// defaultStatsDays は期間未指定時に日別完了件数・リードタイムを集計する日数です
+	defaultStatsDays = 30
+	// defaultThroughputDays は期間未指定時に週別完了件数を集計する日数（12週間）です
+	defaultThroughputDays = 12 * 7
+	// maxStatsDays は一度に集計できる最大日数です
+	maxStatsDays = 366
+)
+
+type StatsUseCase struct {
+	repo domain.StatsRepository
+	now  func() time.Time
+}
+
+func NewStatsUseCase(repo domain.StatsRepository) *StatsUseCase {
+	return &StatsUseCase{repo: repo, now: time.Now}
+}
+
+// GetStats は集計結果を返します。
+// from / to がゼロ値の場合は、今日までの直近30日間を対象にします。
+func (u *StatsUseCase) GetStats(ctx context.Context, from, to time.Time, loc *time.Location) (*domain.Stats, error) {
+	q, err := u.query(from, to, loc, defaultStatsDays)
+	if err != nil {
+		return nil, err
+	}
+
+	stats, err := u.repo.Stats(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	stats.Active = stats.Total - stats.Completed
+	if stats.Total > 0 {
+		stats.CompletionRate = round1(float64(stats.Completed) / float64(stats.Total) * 100)
+	}
+	return stats, nil
+}
+
+// GetLeadTimes は期間内に完了したタスクのリードタイム（作成→完了）を返します。
+// from / to がゼロ値の場合は、今日までの直近30日間を対象にします。
+func (u *StatsUseCase) GetLeadTimes(ctx context.Context, from, to time.Time, loc *time.Location) (*domain.LeadTimeStats, error) {
+	q, err := u.query(from, to, loc, defaultStatsDays)
+	if err != nil {
+		return nil, err
+	}
+
+	stats, err := u.repo.LeadTimes(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	stats.Overall = roundLeadTime(stats.Overall)
+	for k, v := range stats.ByPriority {
+		stats.ByPriority[k] = roundLeadTime(v)
+	}
+	for k, v := range stats.ByProject {
+		stats.ByProject[k] = roundLeadTime(v)
+	}
+	return stats, nil
+}
+
+// GetWeeklyThroughput は週ごとの完了件数を返します。
+// from / to がゼロ値の場合は、今週までの直近12週間を対象にします。
+func (u *StatsUseCase) GetWeeklyThroughput(ctx context.Context, from, to time.Time, loc *time.Location) ([]domain.WeeklyCount, error) {
+	q, err := u.query(from, to, loc, defaultThroughputDays)
+	if err != nil {
+		return nil, err
+	}
+	return u.repo.WeeklyThroughput(ctx, q)
+}
+
+// query は期間の既定値を補い、妥当性を検証した集計条件を返します
+func (u *StatsUseCase) query(from, to time.Time, loc *time.Location, defaultDays int) (domain.StatsQuery, error) {
+	if loc == nil {
+		loc = time.UTC
+	}
+	now := u.now().In(loc)
+
+	if to.IsZero() {
+		to = now
+	}
+	if from.IsZero() {
+		from = to.AddDate(0, 0, -(defaultDays - 1))
+	}
+	if from.After(to) || to.Sub(from) > maxStatsDays*24*time.Hour {
+		return domain.StatsQuery{}, domain.ErrInvalidRange
+	}
+	return domain.StatsQuery{Now: now, From: from, To: to, Location: loc}, nil
+}
+
+func roundLeadTime(lt domain.LeadTime) domain.LeadTime {
+	lt.MedianHours = round1(lt.MedianHours)
+	lt.P90Hours = round1(lt.P90Hours)
+	return lt
+}
+
+// round1 は小数第1位までに丸めます
+func round1(v float64) float64 {
+	return math.Round(v*10) / 10
+}
@@ -0,0 +1,160 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+	"todo_app_golang/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockStatsRepository struct {
+	mock.Mock
+}
+
+func (m *MockStatsRepository) Stats(ctx context.Context, q domain.StatsQuery) (*domain.Stats, error) {
+	args := m.Called(ctx, q)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Stats), args.Error(1)
+}
+
+func (m *MockStatsRepository) LeadTimes(ctx context.Context, q domain.StatsQuery) (*domain.LeadTimeStats, error) {
+	args := m.Called(ctx, q)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.LeadTimeStats), args.Error(1)
+}
+
+func (m *MockStatsRepository) WeeklyThroughput(ctx context.Context, q domain.StatsQuery) ([]domain.WeeklyCount, error) {
+	args := m.Called(ctx, q)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.WeeklyCount), args.Error(1)
+}
+
+func TestGetStats(t *testing.T) {
+	ctx := context.Background()
+	jst := time.FixedZone("JST", 9*60*60)
+	// 2026-10-15 (木) 01:00 JST
+	fixedNow := time.Date(2026, 10, 14, 16, 0, 0, 0, time.UTC)
+
+	newUseCase := func(repo domain.StatsRepository) *StatsUseCase {
+		uc := NewStatsUseCase(repo)
+		uc.now = func() time.Time { return fixedNow }
+		return uc
+	}
+
+	t.Run("成功：期間未指定の場合は直近30日が対象になり、達成率が計算されること", func(t *testing.T) {
+		mockRepo := new(MockStatsRepository)
+		useCase := newUseCase(mockRepo)
+
+		var captured domain.StatsQuery
+		mockRepo.On("Stats", ctx, mock.AnythingOfType("domain.StatsQuery")).
+			Run(func(args mock.Arguments) { captured = args.Get(1).(domain.StatsQuery) }).
+			Return(&domain.Stats{Total: 3, Completed: 1}, nil)
+
+		stats, err := useCase.GetStats(ctx, time.Time{}, time.Time{}, jst)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, stats.Active)
+		assert.Equal(t, 33.3, stats.CompletionRate)
+
+		assert.Equal(t, "2026-10-15", captured.To.Format("2006-01-02"))
+		assert.Equal(t, "2026-09-16", captured.From.Format("2006-01-02"))
+
+		// 今日・今週の境界は指定したタイムゾーン基準になる
+		todayStart, _ := captured.TodayRange()
+		assert.True(t, time.Date(2026, 10, 15, 0, 0, 0, 0, jst).Equal(todayStart))
+		weekStart, weekEnd := captured.WeekRange()
+		assert.True(t, time.Date(2026, 10, 12, 0, 0, 0, 0, jst).Equal(weekStart))
+		assert.True(t, time.Date(2026, 10, 19, 0, 0, 0, 0, jst).Equal(weekEnd))
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("成功：タスクが0件の場合、達成率は0になること", func(t *testing.T) {
+		mockRepo := new(MockStatsRepository)
+		useCase := newUseCase(mockRepo)
+		mockRepo.On("Stats", ctx, mock.Anything).Return(&domain.Stats{}, nil)
+
+		stats, err := useCase.GetStats(ctx, time.Time{}, time.Time{}, nil)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0.0, stats.CompletionRate)
+	})
+
+	t.Run("失敗：開始日が終了日より後の場合はエラーになること", func(t *testing.T) {
+		mockRepo := new(MockStatsRepository)
+		useCase := newUseCase(mockRepo)
+
+		from := time.Date(2026, 10, 10, 0, 0, 0, 0, time.UTC)
+		to := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+		_, err := useCase.GetStats(ctx, from, to, time.UTC)
+
+		assert.Equal(t, domain.ErrInvalidRange, err)
+		mockRepo.AssertNotCalled(t, "Stats", mock.Anything, mock.Anything)
+	})
+
+	t.Run("失敗：期間が長すぎる場合はエラーになること", func(t *testing.T) {
+		mockRepo := new(MockStatsRepository)
+		useCase := newUseCase(mockRepo)
+
+		from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		to := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+		_, err := useCase.GetStats(ctx, from, to, time.UTC)
+
+		assert.Equal(t, domain.ErrInvalidRange, err)
+	})
+}
+
+func TestGetLeadTimes(t *testing.T) {
+	ctx := context.Background()
+	fixedNow := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+
+	t.Run("成功：リードタイムが小数第1位に丸められること", func(t *testing.T) {
+		mockRepo := new(MockStatsRepository)
+		useCase := NewStatsUseCase(mockRepo)
+		useCase.now = func() time.Time { return fixedNow }
+
+		mockRepo.On("LeadTimes", ctx, mock.AnythingOfType("domain.StatsQuery")).Return(&domain.LeadTimeStats{
+			Overall:    domain.LeadTime{Count: 3, MedianHours: 12.345, P90Hours: 47.99},
+			ByPriority: map[string]domain.LeadTime{"high": {Count: 1, MedianHours: 1.06, P90Hours: 1.06}},
+			ByProject:  map[string]domain.LeadTime{"仕事": {Count: 2, MedianHours: 30.04, P90Hours: 40.96}},
+		}, nil)
+
+		stats, err := useCase.GetLeadTimes(ctx, time.Time{}, time.Time{}, time.UTC)
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.LeadTime{Count: 3, MedianHours: 12.3, P90Hours: 48}, stats.Overall)
+		assert.Equal(t, 1.1, stats.ByPriority["high"].MedianHours)
+		assert.Equal(t, 41.0, stats.ByProject["仕事"].P90Hours)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestGetWeeklyThroughput(t *testing.T) {
+	ctx := context.Background()
+	fixedNow := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+
+	t.Run("成功：期間未指定の場合は直近12週間が対象になること", func(t *testing.T) {
+		mockRepo := new(MockStatsRepository)
+		useCase := NewStatsUseCase(mockRepo)
+		useCase.now = func() time.Time { return fixedNow }
+
+		expected := []domain.WeeklyCount{{WeekStart: "2026-10-12", Count: 4}}
+		mockRepo.On("WeeklyThroughput", ctx, mock.MatchedBy(func(q domain.StatsQuery) bool {
+			return q.From.Format("2006-01-02") == "2026-07-24" && q.To.Equal(fixedNow)
+		})).Return(expected, nil)
+
+		counts, err := useCase.GetWeeklyThroughput(ctx, time.Time{}, time.Time{}, nil)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, counts)
+		mockRepo.AssertExpectations(t)
+	})
+}
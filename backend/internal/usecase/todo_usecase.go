@@ -2,38 +2,400 @@ package usecase
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"strings"
 	"todo_app_golang/internal/domain"
+	"todo_app_golang/internal/logging"
+
+	"go.opentelemetry.io/otel/attribute"
 )
 
 type TodoUseCase struct {
-	repo domain.TodoRepository
+	repo     domain.TodoRepository
+	events   *broadcaster
+	onDelete []func(ctx context.Context, id int) error
 }
 
 func NewTodoUseCase(repo domain.TodoRepository) *TodoUseCase {
-	return &TodoUseCase{repo: repo}
+	return &TodoUseCase{repo: repo, events: newBroadcaster()}
+}
+
+// OnDelete はタスクを削除する前に呼ぶ処理を登録します（添付ファイルの中身の片付けなど）。
+// 処理がエラーを返した場合はタスクを削除しません。起動時に登録し、リクエストの処理中には呼ばないでください
+func (u *TodoUseCase) OnDelete(f func(ctx context.Context, id int) error) {
+	u.onDelete = append(u.onDelete, f)
 }
 
-// CreateTodo はバリデーションを行ってから保存を依頼します
+// CreateTodo はタイトルだけを指定してタスクを作成します
 func (u *TodoUseCase) CreateTodo(ctx context.Context, title string) error {
-	todo, err := domain.NewTodo(title)
+	_, err := u.AddTodo(ctx, domain.TodoInput{Title: title})
+	return err
+}
+
+// AddTodo はバリデーションを行ってから保存を依頼し、作成したタスクを返します
+func (u *TodoUseCase) AddTodo(ctx context.Context, in domain.TodoInput) (_ *domain.Todo, err error) {
+	ctx, span := startSpan(ctx, "TodoUseCase.AddTodo")
+	defer func() { endSpan(span, err) }()
+
+	if err := in.Validate(); err != nil {
+		return nil, err
+	}
+	todo, err := domain.NewTodo(in.Title)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	todo.Description = in.Description
+	todo.Priority = in.Priority
+	todo.DueDate = in.DueDate
+	if user, ok := domain.UserIDFromContext(ctx); ok {
+		todo.OwnerID = &user
 	}
-	return u.repo.Create(ctx, todo)
+	if err := u.repo.Create(ctx, todo); err != nil {
+		return nil, err
+	}
+
+	logging.FromContext(ctx).InfoContext(ctx, "todo created", slog.Int("todo_id", todo.ID))
+	u.notify(ctx, domain.TodoCreated, todo.ID, todo)
+	return todo, nil
 }
 
-func (u *TodoUseCase) GetAllTodos(ctx context.Context) ([]*domain.Todo, error) {
-	return u.repo.FetchAll(ctx)
+func (u *TodoUseCase) GetAllTodos(ctx context.Context) (todos []*domain.Todo, err error) {
+	ctx, span := startSpan(ctx, "TodoUseCase.GetAllTodos")
+	defer func() { endSpan(span, err) }()
+
+	todos, err = u.repo.FetchAll(ctx)
+	span.SetAttributes(attribute.Int("todo.count", len(todos)))
+	return todos, err
 }
 
-func (u *TodoUseCase) DeleteTodo(ctx context.Context, id int) error {
-	return u.repo.Delete(ctx, id)
+// ListTodos は filter の条件に一致するタスクを返します
+func (u *TodoUseCase) ListTodos(ctx context.Context, filter domain.TodoFilter) (todos []*domain.Todo, err error) {
+	ctx, span := startSpan(ctx, "TodoUseCase.ListTodos")
+	defer func() { endSpan(span, err) }()
+
+	all, err := u.repo.FetchAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	todos = make([]*domain.Todo, 0, len(all))
+	for _, todo := range all {
+		if filter.Match(todo) {
+			todos = append(todos, todo)
+		}
+	}
+	span.SetAttributes(attribute.Int("todo.count", len(todos)))
+	return todos, nil
 }
 
-func (u *TodoUseCase) UpdateTodoStatus(ctx context.Context, id int, isCompleted bool) error {
-	return u.repo.UpdateStatus(ctx, id, isCompleted)
+func (u *TodoUseCase) DeleteTodo(ctx context.Context, id int) (err error) {
+	ctx, span := startSpan(ctx, "TodoUseCase.DeleteTodo", attribute.Int("todo.id", id))
+	defer func() { endSpan(span, err) }()
+
+	// 担当者は削除できない。存在しない ID の削除は従来どおりエラーにしない
+	if err := u.authorize(ctx, id, can(domain.TodoActionDelete)); err != nil {
+		if errors.Is(err, domain.ErrTodoNotFound) {
+			return nil
+		}
+		return err
+	}
+	for _, f := range u.onDelete {
+		if err := f(ctx, id); err != nil {
+			return err
+		}
+	}
+	if err := u.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	logging.FromContext(ctx).InfoContext(ctx, "todo deleted", slog.Int("todo_id", id))
+	u.notify(ctx, domain.TodoDeleted, id, nil)
+	return nil
 }
 
-func (u *TodoUseCase) GetTodoByID(ctx context.Context, id int) (*domain.Todo, error) {
+func (u *TodoUseCase) UpdateTodoStatus(ctx context.Context, id int, isCompleted bool) (err error) {
+	ctx, span := startSpan(ctx, "TodoUseCase.UpdateTodoStatus", attribute.Int("todo.id", id))
+	defer func() { endSpan(span, err) }()
+
+	if err := u.authorize(ctx, id, can(domain.TodoActionUpdateStatus)); err != nil {
+		return err
+	}
+	if err := u.repo.UpdateStatus(ctx, id, isCompleted); err != nil {
+		return notFound(err)
+	}
+
+	logging.FromContext(ctx).InfoContext(ctx, "todo status updated",
+		slog.Int("todo_id", id),
+		slog.Bool("is_completed", isCompleted),
+	)
+	u.notify(ctx, domain.TodoUpdated, id, nil)
+	return nil
+}
+
+// UpdateTodo はタイトル・詳細説明・優先度・期限を in の内容で置き換え、更新後のタスクを返します（完了状態は変えない）
+func (u *TodoUseCase) UpdateTodo(ctx context.Context, id int, in domain.TodoInput) (_ *domain.Todo, err error) {
+	ctx, span := startSpan(ctx, "TodoUseCase.UpdateTodo", attribute.Int("todo.id", id))
+	defer func() { endSpan(span, err) }()
+
+	if err := in.Validate(); err != nil {
+		return nil, err
+	}
+	todo, err := u.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if user, ok := domain.UserIDFromContext(ctx); ok && !todo.Permits(user, domain.TodoActionEdit) {
+		return nil, domain.ErrTodoForbidden
+	}
+	todo.Title = in.Title
+	todo.Description = in.Description
+	todo.Priority = in.Priority
+	todo.DueDate = in.DueDate
+	if err := u.repo.Update(ctx, todo); err != nil {
+		return nil, notFound(err)
+	}
+	// 更新日時などは DB 側で決まるため読み直す
+	if todo, err = u.repo.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	logging.FromContext(ctx).InfoContext(ctx, "todo updated", slog.Int("todo_id", id))
+	u.notify(ctx, domain.TodoUpdated, id, todo)
+	return todo, nil
+}
+
+func (u *TodoUseCase) GetTodoByID(ctx context.Context, id int) (todo *domain.Todo, err error) {
+	ctx, span := startSpan(ctx, "TodoUseCase.GetTodoByID", attribute.Int("todo.id", id))
+	defer func() { endSpan(span, err) }()
+
 	return u.repo.GetByID(ctx, id)
 }
+
+// GetTodosByIDs は ids のタスクをまとめて取得します。見つからない ID は結果に含めません
+func (u *TodoUseCase) GetTodosByIDs(ctx context.Context, ids []int) (todos []*domain.Todo, err error) {
+	ctx, span := startSpan(ctx, "TodoUseCase.GetTodosByIDs", attribute.Int("todo.count", len(ids)))
+	defer func() { endSpan(span, err) }()
+
+	return u.repo.GetByIDs(ctx, ids)
+}
+
+// ImportTodos は外部フォーマットから変換した Todo を保存します。
+// ID（このアプリが出力したタスクの場合）または ICalUID が既存のタスクと一致する場合は新規作成せずに上書きします（再インポート時の重複排除）。
+// 認証している場合、作成したタスクの所有者はそのユーザーになり、編集する権限のない既存のタスクはスキップします
+func (u *TodoUseCase) ImportTodos(ctx context.Context, todos []*domain.Todo) (_ *domain.ImportResult, err error) {
+	ctx, span := startSpan(ctx, "TodoUseCase.ImportTodos", attribute.Int("todo.count", len(todos)))
+	defer func() { endSpan(span, err) }()
+
+	result := &domain.ImportResult{Skipped: []domain.ImportSkipped{}}
+	user, authenticated := domain.UserIDFromContext(ctx)
+
+	for _, todo := range todos {
+		if todo.Title == "" {
+			skipped := domain.ImportSkipped{Reason: domain.ErrTitleEmpty.Error()}
+			if todo.ICalUID != nil {
+				skipped.UID = *todo.ICalUID
+			}
+			result.Skipped = append(result.Skipped, skipped)
+			continue
+		}
+
+		existing, err := u.findImported(ctx, todo)
+		if err == nil {
+			if authenticated && !existing.Permits(user, domain.TodoActionEdit) {
+				result.Skipped = append(result.Skipped, domain.ImportSkipped{UID: importUID(todo), Reason: domain.ErrTodoForbidden.Error()})
+				continue
+			}
+			todo.ID = existing.ID
+			if err := u.repo.Update(ctx, todo); err != nil {
+				return nil, err
+			}
+			u.notify(ctx, domain.TodoUpdated, todo.ID, todo)
+			result.Updated++
+			continue
+		}
+		if !errors.Is(err, domain.ErrTodoNotFound) {
+			return nil, err
+		}
+		todo.ID = 0
+
+		if authenticated {
+			todo.OwnerID = &user
+		}
+		if err := u.repo.Create(ctx, todo); err != nil {
+			return nil, err
+		}
+		u.notify(ctx, domain.TodoCreated, todo.ID, todo)
+		result.Created++
+	}
+
+	logging.FromContext(ctx).InfoContext(ctx, "todos imported",
+		slog.Int("created", result.Created),
+		slog.Int("updated", result.Updated),
+		slog.Int("skipped", len(result.Skipped)),
+	)
+	return result, nil
+}
+
+// findImported は取り込む todo と同じタスクを探します。見つからない場合は domain.ErrTodoNotFound を返します。
+// ID が指定されている場合（このアプリが出力した UID から復元した場合）は ID で探し、元のタスクの ICalUID を引き継ぎます
+func (u *TodoUseCase) findImported(ctx context.Context, todo *domain.Todo) (*domain.Todo, error) {
+	if todo.ID != 0 {
+		existing, err := u.repo.GetByID(ctx, todo.ID)
+		if err == nil {
+			todo.ICalUID = existing.ICalUID
+			return existing, nil
+		}
+		if !errors.Is(err, domain.ErrTodoNotFound) {
+			return nil, err
+		}
+	}
+	if todo.ICalUID == nil {
+		return nil, domain.ErrTodoNotFound
+	}
+	return u.repo.GetByICalUID(ctx, *todo.ICalUID)
+}
+
+// importUID はスキップした理由とともに報告する UID です
+func importUID(todo *domain.Todo) string {
+	if todo.ICalUID == nil {
+		return ""
+	}
+	return *todo.ICalUID
+}
+
+// AssignTodo は担当者を設定し、更新後のタスクを返します（認証している場合は所有者だけが変更できる）
+func (u *TodoUseCase) AssignTodo(ctx context.Context, id int, assigneeID string) (_ *domain.Todo, err error) {
+	ctx, span := startSpan(ctx, "TodoUseCase.AssignTodo", attribute.Int("todo.id", id))
+	defer func() { endSpan(span, err) }()
+
+	if strings.TrimSpace(assigneeID) == "" {
+		return nil, domain.ErrUserIDEmpty
+	}
+	if err := u.authorize(ctx, id, can(domain.TodoActionAssign)); err != nil {
+		return nil, err
+	}
+	if err := u.repo.SetAssignee(ctx, id, &assigneeID); err != nil {
+		return nil, err
+	}
+	return u.reload(ctx, id, "todo assigned", slog.String("assignee_id", assigneeID))
+}
+
+// UnassignTodo は担当者を解除し、更新後のタスクを返します（所有者のほか、担当者自身も解除できる）
+func (u *TodoUseCase) UnassignTodo(ctx context.Context, id int) (_ *domain.Todo, err error) {
+	ctx, span := startSpan(ctx, "TodoUseCase.UnassignTodo", attribute.Int("todo.id", id))
+	defer func() { endSpan(span, err) }()
+
+	err = u.authorize(ctx, id, func(todo *domain.Todo, user string) bool {
+		return todo.Permits(user, domain.TodoActionAssign) || todo.IsAssignedTo(user)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := u.repo.SetAssignee(ctx, id, nil); err != nil {
+		return nil, err
+	}
+	return u.reload(ctx, id, "todo unassigned")
+}
+
+// AddTodoWatcher は userID をウォッチャーに追加し、更新後のタスクを返します。
+// 認証している場合、所有者は誰でも追加でき、ほかのユーザーは自分だけを追加できます
+func (u *TodoUseCase) AddTodoWatcher(ctx context.Context, id int, userID string) (_ *domain.Todo, err error) {
+	ctx, span := startSpan(ctx, "TodoUseCase.AddTodoWatcher", attribute.Int("todo.id", id))
+	defer func() { endSpan(span, err) }()
+
+	if err := u.authorizeWatcher(ctx, id, userID); err != nil {
+		return nil, err
+	}
+	if err := u.repo.AddWatcher(ctx, id, userID); err != nil {
+		return nil, err
+	}
+	return u.reload(ctx, id, "todo watcher added", slog.String("user_id", userID))
+}
+
+// RemoveTodoWatcher は userID をウォッチャーから外し、更新後のタスクを返します（権限は AddTodoWatcher と同じ）
+func (u *TodoUseCase) RemoveTodoWatcher(ctx context.Context, id int, userID string) (_ *domain.Todo, err error) {
+	ctx, span := startSpan(ctx, "TodoUseCase.RemoveTodoWatcher", attribute.Int("todo.id", id))
+	defer func() { endSpan(span, err) }()
+
+	if err := u.authorizeWatcher(ctx, id, userID); err != nil {
+		return nil, err
+	}
+	if err := u.repo.RemoveWatcher(ctx, id, userID); err != nil {
+		return nil, err
+	}
+	return u.reload(ctx, id, "todo watcher removed", slog.String("user_id", userID))
+}
+
+func (u *TodoUseCase) authorizeWatcher(ctx context.Context, id int, userID string) error {
+	if strings.TrimSpace(userID) == "" {
+		return domain.ErrUserIDEmpty
+	}
+	return u.authorize(ctx, id, func(todo *domain.Todo, user string) bool {
+		return user == userID || todo.Permits(user, domain.TodoActionAssign)
+	})
+}
+
+// authorize は ctx のユーザーが id のタスクを操作できるかを allowed で確認し、できない場合は domain.ErrTodoForbidden を返します。
+// ユーザーが格納されていない場合（認証していない場合）はタスクを読み込まずに許可します
+func (u *TodoUseCase) authorize(ctx context.Context, id int, allowed func(todo *domain.Todo, user string) bool) error {
+	user, ok := domain.UserIDFromContext(ctx)
+	if !ok {
+		return nil
+	}
+	todo, err := u.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !allowed(todo, user) {
+		return domain.ErrTodoForbidden
+	}
+	return nil
+}
+
+// can は action を行えるかを Todo.Permits で確認する authorize の引数を返します
+func can(action domain.TodoAction) func(todo *domain.Todo, user string) bool {
+	return func(todo *domain.Todo, user string) bool { return todo.Permits(user, action) }
+}
+
+// reload は担当者やウォッチャーを変更したタスクを読み直し、ログに記録して変更を通知します
+func (u *TodoUseCase) reload(ctx context.Context, id int, msg string, attrs ...slog.Attr) (*domain.Todo, error) {
+	todo, err := u.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	logging.FromContext(ctx).LogAttrs(ctx, slog.LevelInfo, msg, append([]slog.Attr{slog.Int("todo_id", id)}, attrs...)...)
+	u.notify(ctx, domain.TodoUpdated, id, todo)
+	return todo, nil
+}
+
+// WatchTodos は ctx が終わるまでタスクの変更通知を受け取るチャネルを返します。
+// 通知はこのプロセスで行った変更に限られます。
+// 受信が追いつかない場合は ctx が終わる前にチャネルが閉じられるため、一覧を取り直してから購読し直してください
+func (u *TodoUseCase) WatchTodos(ctx context.Context) <-chan domain.TodoEvent {
+	return u.events.subscribe(ctx)
+}
+
+// notify は購読者がいれば変更を通知します。todo が nil の場合（削除以外）は読み直してから通知します
+func (u *TodoUseCase) notify(ctx context.Context, typ domain.TodoEventType, id int, todo *domain.Todo) {
+	if !u.events.active() {
+		return
+	}
+	if todo == nil && typ != domain.TodoDeleted {
+		var err error
+		if todo, err = u.repo.GetByID(ctx, id); err != nil {
+			logging.FromContext(ctx).WarnContext(ctx, "failed to load todo for change notification",
+				slog.Int("todo_id", id), slog.Any("error", err))
+			return
+		}
+	}
+	u.events.publish(domain.TodoEvent{Type: typ, ID: id, Todo: todo})
+}
+
+// notFound はリポジトリが更新対象の行を見つけられなかったエラー (sql.ErrNoRows) を domain.ErrTodoNotFound に置き換えます
+func notFound(err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.ErrTodoNotFound
+	}
+	return err
+}
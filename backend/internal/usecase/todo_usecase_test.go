@@ -2,7 +2,10 @@ package usecase
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"testing"
+	"time"
 	"todo_app_golang/internal/domain"
 
 	"github.com/stretchr/testify/assert"
@@ -46,11 +49,42 @@ func (m *MockTodoRepository) GetByID(ctx context.Context, id int) (*domain.Todo,
 	return args.Get(0).(*domain.Todo), args.Error(1)
 }
 
+func (m *MockTodoRepository) GetByIDs(ctx context.Context, ids []int) ([]*domain.Todo, error) {
+	args := m.Called(ctx, ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Todo), args.Error(1)
+}
+
 func (m *MockTodoRepository) Update(ctx context.Context, todo *domain.Todo) error {
 	args := m.Called(ctx, todo)
 	return args.Error(0)
 }
 
+func (m *MockTodoRepository) GetByICalUID(ctx context.Context, uid string) (*domain.Todo, error) {
+	args := m.Called(ctx, uid)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Todo), args.Error(1)
+}
+
+func (m *MockTodoRepository) SetAssignee(ctx context.Context, id int, assigneeID *string) error {
+	args := m.Called(ctx, id, assigneeID)
+	return args.Error(0)
+}
+
+func (m *MockTodoRepository) AddWatcher(ctx context.Context, id int, userID string) error {
+	args := m.Called(ctx, id, userID)
+	return args.Error(0)
+}
+
+func (m *MockTodoRepository) RemoveWatcher(ctx context.Context, id int, userID string) error {
+	args := m.Called(ctx, id, userID)
+	return args.Error(0)
+}
+
 func TestCreateTodo(t *testing.T) {
 	mockRepo := new(MockTodoRepository)
 	uc := NewTodoUseCase(mockRepo)
@@ -58,7 +92,7 @@ func TestCreateTodo(t *testing.T) {
 
 	t.Run("成功：タイトルがある場合", func(t *testing.T) {
 		// モックの期待値を設定 (Anyはどんな引数でも許容する場合に使用)
-		mockRepo.On("Create", ctx, mock.AnythingOfType("*domain.Todo")).Return(nil)
+		mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.Todo")).Return(nil)
 
 		err := uc.CreateTodo(ctx, "買い物に行く")
 
@@ -72,6 +106,50 @@ func TestCreateTodo(t *testing.T) {
 	})
 }
 
+func TestAddTodo(t *testing.T) {
+	ctx := context.Background()
+	due := time.Date(2026, 10, 31, 0, 0, 0, 0, time.UTC)
+
+	t.Run("成功：指定した項目で作成し、優先度の既定値は medium になること", func(t *testing.T) {
+		mockRepo := new(MockTodoRepository)
+		useCase := NewTodoUseCase(mockRepo)
+		mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.Todo")).Return(nil)
+
+		todo, err := useCase.AddTodo(ctx, domain.TodoInput{Title: "請求書を送る", Description: "10月分", DueDate: &due})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "10月分", todo.Description)
+		assert.Equal(t, domain.PriorityMedium, todo.Priority)
+		assert.Equal(t, &due, todo.DueDate)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("成功：認証している場合はそのユーザーが所有者になること", func(t *testing.T) {
+		mockRepo := new(MockTodoRepository)
+		useCase := NewTodoUseCase(mockRepo)
+		mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(t *domain.Todo) bool {
+			return t.OwnerID != nil && *t.OwnerID == "alice"
+		})).Return(nil)
+
+		todo, err := useCase.AddTodo(domain.WithUserID(ctx, "alice"), domain.TodoInput{Title: "請求書を送る"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "alice", *todo.OwnerID)
+		assert.Equal(t, []string{}, todo.Watchers)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("失敗：優先度が不正な場合", func(t *testing.T) {
+		mockRepo := new(MockTodoRepository)
+		useCase := NewTodoUseCase(mockRepo)
+
+		_, err := useCase.AddTodo(ctx, domain.TodoInput{Title: "タスク", Priority: "urgent"})
+
+		assert.Equal(t, domain.ErrInvalidPriority, err)
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+}
+
 func TestGetAllTodos(t *testing.T) {
 	ctx := context.Background()
 
@@ -85,7 +163,7 @@ func TestGetAllTodos(t *testing.T) {
 		}
 
 		// モックの設定: 引数 ctx で呼ばれたら、mockTodos と nil を返す
-		mockRepo.On("FetchAll", ctx).Return(mockTodos, nil)
+		mockRepo.On("FetchAll", mock.Anything).Return(mockTodos, nil)
 
 		// 実行
 		todos, err := useCase.GetAllTodos(ctx)
@@ -100,7 +178,7 @@ func TestGetAllTodos(t *testing.T) {
 	t.Run("成功：データが0件の場合に空の配列が返ること", func(t *testing.T) {
 		mockRepo := new(MockTodoRepository)
 		useCase := NewTodoUseCase(mockRepo)
-		mockRepo.On("FetchAll", ctx).Return([]*domain.Todo{}, nil)
+		mockRepo.On("FetchAll", mock.Anything).Return([]*domain.Todo{}, nil)
 
 		todos, err := useCase.GetAllTodos(ctx)
 
@@ -111,6 +189,38 @@ func TestGetAllTodos(t *testing.T) {
 	})
 }
 
+func TestListTodos(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+	yesterday := now.AddDate(0, 0, -1)
+	tomorrow := now.AddDate(0, 0, 1)
+	mockRepo := new(MockTodoRepository)
+	useCase := NewTodoUseCase(mockRepo)
+	mockRepo.On("FetchAll", mock.Anything).Return([]*domain.Todo{
+		{ID: 1, Title: "牛乳を買う", Priority: "high", DueDate: &yesterday},
+		{ID: 2, Title: "報告書", Description: "Q3 の売上をまとめる", Priority: "low", DueDate: &tomorrow},
+		{ID: 3, Title: "掃除", Priority: "high", IsCompleted: true},
+	}, nil)
+	open := false
+
+	ids := func(filter domain.TodoFilter) []int {
+		todos, err := useCase.ListTodos(ctx, filter)
+		assert.NoError(t, err)
+		var ids []int
+		for _, todo := range todos {
+			ids = append(ids, todo.ID)
+		}
+		return ids
+	}
+
+	assert.Equal(t, []int{1, 2, 3}, ids(domain.TodoFilter{}))
+	assert.Equal(t, []int{1, 2}, ids(domain.TodoFilter{IsCompleted: &open}))
+	assert.Equal(t, []int{1, 3}, ids(domain.TodoFilter{Priority: "high"}))
+	assert.Equal(t, []int{2}, ids(domain.TodoFilter{Query: "q3"}))
+	assert.Equal(t, []int{1}, ids(domain.TodoFilter{DueBefore: &now}))
+	assert.Empty(t, ids(domain.TodoFilter{IsCompleted: &open, Priority: "high", Query: "掃除"}))
+}
+
 func TestDeleteTodo(t *testing.T) {
 	mockRepo := new(MockTodoRepository)
 	useCase := NewTodoUseCase(mockRepo)
@@ -118,7 +228,7 @@ func TestDeleteTodo(t *testing.T) {
 	targetID := 1
 
 	// 「Deleteが呼ばれたらnilを返す」と定義
-	mockRepo.On("Delete", ctx, targetID).Return(nil)
+	mockRepo.On("Delete", mock.Anything, targetID).Return(nil)
 
 	err := useCase.DeleteTodo(ctx, targetID)
 
@@ -126,6 +236,34 @@ func TestDeleteTodo(t *testing.T) {
 	mockRepo.AssertExpectations(t) // 実際に呼ばれたかチェック
 }
 
+func TestDeleteTodo_OnDelete(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("成功：登録した処理を呼んでから削除すること", func(t *testing.T) {
+		mockRepo := new(MockTodoRepository)
+		useCase := NewTodoUseCase(mockRepo)
+		var called []int
+		useCase.OnDelete(func(_ context.Context, id int) error {
+			called = append(called, id)
+			return nil
+		})
+		mockRepo.On("Delete", mock.Anything, 1).Return(nil)
+
+		assert.NoError(t, useCase.DeleteTodo(ctx, 1))
+		assert.Equal(t, []int{1}, called)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("失敗：登録した処理がエラーを返した場合は削除しないこと", func(t *testing.T) {
+		mockRepo := new(MockTodoRepository)
+		useCase := NewTodoUseCase(mockRepo)
+		useCase.OnDelete(func(context.Context, int) error { return errors.New("storage error") })
+
+		assert.Error(t, useCase.DeleteTodo(ctx, 1))
+		mockRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+	})
+}
+
 func TestUpdateTodoStatus(t *testing.T) {
 	ctx := context.Background()
 
@@ -136,7 +274,7 @@ func TestUpdateTodoStatus(t *testing.T) {
 		nextStatus := true
 
 		// 期待値設定
-		mockRepo.On("UpdateStatus", ctx, targetID, nextStatus).Return(nil)
+		mockRepo.On("UpdateStatus", mock.Anything, targetID, nextStatus).Return(nil)
 
 		// 実行
 		err := useCase.UpdateTodoStatus(ctx, targetID, nextStatus)
@@ -145,6 +283,56 @@ func TestUpdateTodoStatus(t *testing.T) {
 		assert.NoError(t, err)
 		mockRepo.AssertExpectations(t)
 	})
+
+	t.Run("失敗：タスクが見つからない場合は ErrTodoNotFound になること", func(t *testing.T) {
+		mockRepo := new(MockTodoRepository)
+		useCase := NewTodoUseCase(mockRepo)
+		mockRepo.On("UpdateStatus", mock.Anything, 99, true).Return(sql.ErrNoRows)
+
+		err := useCase.UpdateTodoStatus(ctx, 99, true)
+
+		assert.Equal(t, domain.ErrTodoNotFound, err)
+	})
+}
+
+func TestUpdateTodo(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("成功：完了状態を保ったまま内容を置き換えること", func(t *testing.T) {
+		mockRepo := new(MockTodoRepository)
+		useCase := NewTodoUseCase(mockRepo)
+		stored := &domain.Todo{ID: 5, Title: "旧タイトル", Priority: "low", IsCompleted: true}
+		mockRepo.On("GetByID", mock.Anything, 5).Return(stored, nil)
+		mockRepo.On("Update", mock.Anything, mock.MatchedBy(func(t *domain.Todo) bool {
+			return t.Title == "新タイトル" && t.Priority == "high" && t.IsCompleted
+		})).Return(nil)
+
+		todo, err := useCase.UpdateTodo(ctx, 5, domain.TodoInput{Title: "新タイトル", Priority: "high"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "新タイトル", todo.Title)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("失敗：タスクが見つからない場合", func(t *testing.T) {
+		mockRepo := new(MockTodoRepository)
+		useCase := NewTodoUseCase(mockRepo)
+		mockRepo.On("GetByID", mock.Anything, 99).Return(nil, domain.ErrTodoNotFound)
+
+		_, err := useCase.UpdateTodo(ctx, 99, domain.TodoInput{Title: "タスク"})
+
+		assert.Equal(t, domain.ErrTodoNotFound, err)
+	})
+
+	t.Run("失敗：タイトルが空の場合はリポジトリを呼ばないこと", func(t *testing.T) {
+		mockRepo := new(MockTodoRepository)
+		useCase := NewTodoUseCase(mockRepo)
+
+		_, err := useCase.UpdateTodo(ctx, 5, domain.TodoInput{})
+
+		assert.Equal(t, domain.ErrTitleEmpty, err)
+		mockRepo.AssertExpectations(t)
+	})
 }
 
 func TestGetTodoByID(t *testing.T) {
@@ -160,7 +348,7 @@ func TestGetTodoByID(t *testing.T) {
 			Priority: "high",
 		}
 
-		mockRepo.On("GetByID", ctx, targetID).Return(expectedTodo, nil)
+		mockRepo.On("GetByID", mock.Anything, targetID).Return(expectedTodo, nil)
 
 		todo, err := useCase.GetTodoByID(ctx, targetID)
 
@@ -174,7 +362,7 @@ func TestGetTodoByID(t *testing.T) {
 		useCase := NewTodoUseCase(mockRepo)
 		targetID := 99
 
-		mockRepo.On("GetByID", ctx, targetID).Return(nil, domain.ErrTodoNotFound)
+		mockRepo.On("GetByID", mock.Anything, targetID).Return(nil, domain.ErrTodoNotFound)
 
 		todo, err := useCase.GetTodoByID(ctx, targetID)
 
@@ -183,3 +371,313 @@ func TestGetTodoByID(t *testing.T) {
 		mockRepo.AssertExpectations(t)
 	})
 }
+
+func TestImportTodos(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("成功：UID が一致するタスクは上書きし、それ以外は新規作成すること", func(t *testing.T) {
+		mockRepo := new(MockTodoRepository)
+		useCase := NewTodoUseCase(mockRepo)
+
+		existingUID := "existing@example.com"
+		newUID := "new@example.com"
+		todos := []*domain.Todo{
+			{Title: "既存タスク", ICalUID: &existingUID},
+			{Title: "新規タスク", ICalUID: &newUID},
+			{Title: "UIDなしタスク"},
+		}
+
+		mockRepo.On("GetByICalUID", mock.Anything, existingUID).Return(&domain.Todo{ID: 7}, nil)
+		mockRepo.On("GetByICalUID", mock.Anything, newUID).Return(nil, domain.ErrTodoNotFound)
+		mockRepo.On("Update", mock.Anything, mock.MatchedBy(func(t *domain.Todo) bool { return t.ID == 7 })).Return(nil)
+		mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.Todo")).Return(nil).Twice()
+
+		result, err := useCase.ImportTodos(ctx, todos)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, result.Created)
+		assert.Equal(t, 1, result.Updated)
+		assert.Empty(t, result.Skipped)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("成功：ID が指定されたタスクは ID で照合し、見つからない場合は UID で照合すること", func(t *testing.T) {
+		mockRepo := new(MockTodoRepository)
+		useCase := NewTodoUseCase(mockRepo)
+
+		ownUID, goneUID := "todo-7@todo_app_golang", "todo-99@todo_app_golang"
+		todos := []*domain.Todo{
+			{ID: 7, Title: "このアプリのタスク", ICalUID: &ownUID},
+			{ID: 99, Title: "削除済みのタスク", ICalUID: &goneUID},
+		}
+
+		mockRepo.On("GetByID", mock.Anything, 7).Return(&domain.Todo{ID: 7}, nil)
+		mockRepo.On("GetByID", mock.Anything, 99).Return(nil, domain.ErrTodoNotFound)
+		mockRepo.On("GetByICalUID", mock.Anything, goneUID).Return(nil, domain.ErrTodoNotFound)
+		// 元のタスクは ICalUID を持たないため、上書きしても UID は付けない
+		mockRepo.On("Update", mock.Anything, mock.MatchedBy(func(t *domain.Todo) bool { return t.ID == 7 && t.ICalUID == nil })).Return(nil)
+		mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(t *domain.Todo) bool { return t.ID == 0 })).Return(nil)
+
+		result, err := useCase.ImportTodos(ctx, todos)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, result.Created)
+		assert.Equal(t, 1, result.Updated)
+		mockRepo.AssertExpectations(t)
+		mockRepo.AssertNotCalled(t, "GetByICalUID", mock.Anything, ownUID)
+	})
+
+	t.Run("成功：タイトルが空のタスクはスキップされること", func(t *testing.T) {
+		mockRepo := new(MockTodoRepository)
+		useCase := NewTodoUseCase(mockRepo)
+
+		result, err := useCase.ImportTodos(ctx, []*domain.Todo{{Title: ""}})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, result.Created)
+		assert.Len(t, result.Skipped, 1)
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("成功：認証している場合、編集できない既存のタスクはスキップし、作成したタスクの所有者になること", func(t *testing.T) {
+		mockRepo := new(MockTodoRepository)
+		useCase := NewTodoUseCase(mockRepo)
+		owner := "bob"
+		existingUID := "bob@example.com"
+
+		mockRepo.On("GetByICalUID", mock.Anything, existingUID).Return(&domain.Todo{ID: 7, OwnerID: &owner}, nil)
+		mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(t *domain.Todo) bool {
+			return t.OwnerID != nil && *t.OwnerID == "alice"
+		})).Return(nil)
+
+		result, err := useCase.ImportTodos(domain.WithUserID(ctx, "alice"), []*domain.Todo{
+			{Title: "bob のタスク", ICalUID: &existingUID},
+			{Title: "新規タスク"},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, result.Created)
+		assert.Equal(t, 0, result.Updated)
+		assert.Equal(t, []domain.ImportSkipped{{UID: existingUID, Reason: domain.ErrTodoForbidden.Error()}}, result.Skipped)
+		mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("失敗：リポジトリのエラーがそのまま返ること", func(t *testing.T) {
+		mockRepo := new(MockTodoRepository)
+		useCase := NewTodoUseCase(mockRepo)
+		uid := "broken@example.com"
+
+		mockRepo.On("GetByICalUID", mock.Anything, uid).Return(nil, context.DeadlineExceeded)
+
+		_, err := useCase.ImportTodos(ctx, []*domain.Todo{{Title: "タスク", ICalUID: &uid}})
+
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
+
+func TestWatchTodos(t *testing.T) {
+	t.Run("成功：購読後の変更が順に通知されること", func(t *testing.T) {
+		mockRepo := new(MockTodoRepository)
+		useCase := NewTodoUseCase(mockRepo)
+		ctx, cancel := context.WithCancel(context.Background())
+		mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.Todo")).Run(func(args mock.Arguments) {
+			args.Get(1).(*domain.Todo).ID = 1
+		}).Return(nil)
+		mockRepo.On("UpdateStatus", mock.Anything, 1, true).Return(nil)
+		mockRepo.On("GetByID", mock.Anything, 1).Return(&domain.Todo{ID: 1, Title: "牛乳を買う", IsCompleted: true}, nil)
+		mockRepo.On("Delete", mock.Anything, 1).Return(nil)
+
+		events := useCase.WatchTodos(ctx)
+		assert.NoError(t, useCase.CreateTodo(ctx, "牛乳を買う"))
+		assert.NoError(t, useCase.UpdateTodoStatus(ctx, 1, true))
+		assert.NoError(t, useCase.DeleteTodo(ctx, 1))
+
+		created, updated, deleted := <-events, <-events, <-events
+		assert.Equal(t, domain.TodoCreated, created.Type)
+		assert.Equal(t, "牛乳を買う", created.Todo.Title)
+		assert.Equal(t, domain.TodoUpdated, updated.Type)
+		assert.True(t, updated.Todo.IsCompleted)
+		assert.Equal(t, domain.TodoEvent{Type: domain.TodoDeleted, ID: 1}, deleted)
+
+		// ctx が終わるとチャネルが閉じられる
+		cancel()
+		_, ok := <-events
+		assert.False(t, ok)
+	})
+
+	t.Run("受信が追いつかない購読者は打ち切られること", func(t *testing.T) {
+		mockRepo := new(MockTodoRepository)
+		useCase := NewTodoUseCase(mockRepo)
+		mockRepo.On("Delete", mock.Anything, mock.Anything).Return(nil)
+
+		events := useCase.WatchTodos(context.Background())
+		for i := 0; i <= watchBuffer; i++ {
+			assert.NoError(t, useCase.DeleteTodo(context.Background(), i))
+		}
+
+		received := 0
+		for range events {
+			received++
+		}
+		assert.Equal(t, watchBuffer, received)
+		assert.False(t, useCase.events.active())
+	})
+
+	t.Run("購読者がいない場合は通知のためにタスクを読み直さないこと", func(t *testing.T) {
+		mockRepo := new(MockTodoRepository)
+		useCase := NewTodoUseCase(mockRepo)
+		mockRepo.On("UpdateStatus", mock.Anything, 1, false).Return(nil)
+
+		assert.NoError(t, useCase.UpdateTodoStatus(context.Background(), 1, false))
+		mockRepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+	})
+}
+
+func TestTodoPermissions(t *testing.T) {
+	owner, assignee := "alice", "bob"
+	todo := &domain.Todo{ID: 1, Title: "alice のタスク", OwnerID: &owner, AssigneeID: &assignee}
+	as := func(user string) context.Context { return domain.WithUserID(context.Background(), user) }
+
+	t.Run("成功：担当者は完了状態を変更できること", func(t *testing.T) {
+		mockRepo := new(MockTodoRepository)
+		useCase := NewTodoUseCase(mockRepo)
+		mockRepo.On("GetByID", mock.Anything, 1).Return(todo, nil)
+		mockRepo.On("UpdateStatus", mock.Anything, 1, true).Return(nil)
+
+		assert.NoError(t, useCase.UpdateTodoStatus(as(assignee), 1, true))
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("失敗：担当者は削除・編集できないこと", func(t *testing.T) {
+		mockRepo := new(MockTodoRepository)
+		useCase := NewTodoUseCase(mockRepo)
+		mockRepo.On("GetByID", mock.Anything, 1).Return(todo, nil)
+
+		assert.Equal(t, domain.ErrTodoForbidden, useCase.DeleteTodo(as(assignee), 1))
+		_, err := useCase.UpdateTodo(as(assignee), 1, domain.TodoInput{Title: "書き換え"})
+		assert.Equal(t, domain.ErrTodoForbidden, err)
+		mockRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+		mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("失敗：所有者でも担当者でもないユーザーは完了状態を変更できないこと", func(t *testing.T) {
+		mockRepo := new(MockTodoRepository)
+		useCase := NewTodoUseCase(mockRepo)
+		mockRepo.On("GetByID", mock.Anything, 1).Return(todo, nil)
+
+		assert.Equal(t, domain.ErrTodoForbidden, useCase.UpdateTodoStatus(as("carol"), 1, true))
+		mockRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("成功：所有者は削除でき、存在しない ID の削除はエラーにならないこと", func(t *testing.T) {
+		mockRepo := new(MockTodoRepository)
+		useCase := NewTodoUseCase(mockRepo)
+		mockRepo.On("GetByID", mock.Anything, 1).Return(todo, nil)
+		mockRepo.On("GetByID", mock.Anything, 99).Return(nil, domain.ErrTodoNotFound)
+		mockRepo.On("Delete", mock.Anything, 1).Return(nil)
+
+		assert.NoError(t, useCase.DeleteTodo(as(owner), 1))
+		assert.NoError(t, useCase.DeleteTodo(as(owner), 99))
+		mockRepo.AssertNumberOfCalls(t, "Delete", 1)
+	})
+
+	t.Run("成功：所有者のいないタスクは誰でも操作できること", func(t *testing.T) {
+		mockRepo := new(MockTodoRepository)
+		useCase := NewTodoUseCase(mockRepo)
+		mockRepo.On("GetByID", mock.Anything, 2).Return(&domain.Todo{ID: 2, Title: "共有"}, nil)
+		mockRepo.On("Delete", mock.Anything, 2).Return(nil)
+
+		assert.NoError(t, useCase.DeleteTodo(as("carol"), 2))
+	})
+}
+
+func TestAssignTodo(t *testing.T) {
+	owner, assignee := "alice", "bob"
+	todo := &domain.Todo{ID: 1, Title: "alice のタスク", OwnerID: &owner, AssigneeID: &assignee}
+	as := func(user string) context.Context { return domain.WithUserID(context.Background(), user) }
+
+	t.Run("成功：所有者は担当者を設定でき、更新後のタスクを返すこと", func(t *testing.T) {
+		mockRepo := new(MockTodoRepository)
+		useCase := NewTodoUseCase(mockRepo)
+		mockRepo.On("GetByID", mock.Anything, 1).Return(todo, nil)
+		mockRepo.On("SetAssignee", mock.Anything, 1, mock.MatchedBy(func(id *string) bool { return id != nil && *id == "carol" })).Return(nil)
+
+		got, err := useCase.AssignTodo(as(owner), 1, "carol")
+
+		assert.NoError(t, err)
+		assert.Equal(t, todo, got)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("失敗：担当者はほかのユーザーに付け替えられないが、自分の担当は解除できること", func(t *testing.T) {
+		mockRepo := new(MockTodoRepository)
+		useCase := NewTodoUseCase(mockRepo)
+		mockRepo.On("GetByID", mock.Anything, 1).Return(todo, nil)
+		mockRepo.On("SetAssignee", mock.Anything, 1, (*string)(nil)).Return(nil)
+
+		_, err := useCase.AssignTodo(as(assignee), 1, "carol")
+		assert.Equal(t, domain.ErrTodoForbidden, err)
+		_, err = useCase.UnassignTodo(as("carol"), 1)
+		assert.Equal(t, domain.ErrTodoForbidden, err)
+
+		_, err = useCase.UnassignTodo(as(assignee), 1)
+		assert.NoError(t, err)
+		mockRepo.AssertNumberOfCalls(t, "SetAssignee", 1)
+	})
+
+	t.Run("失敗：担当者が空の場合", func(t *testing.T) {
+		mockRepo := new(MockTodoRepository)
+		useCase := NewTodoUseCase(mockRepo)
+
+		_, err := useCase.AssignTodo(context.Background(), 1, " ")
+
+		assert.Equal(t, domain.ErrUserIDEmpty, err)
+		mockRepo.AssertNotCalled(t, "SetAssignee", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("失敗：タスクが見つからない場合は ErrTodoNotFound になること", func(t *testing.T) {
+		mockRepo := new(MockTodoRepository)
+		useCase := NewTodoUseCase(mockRepo)
+		mockRepo.On("SetAssignee", mock.Anything, 99, mock.Anything).Return(domain.ErrTodoNotFound)
+
+		_, err := useCase.AssignTodo(context.Background(), 99, "bob")
+
+		assert.Equal(t, domain.ErrTodoNotFound, err)
+	})
+}
+
+func TestTodoWatchers(t *testing.T) {
+	owner := "alice"
+	todo := &domain.Todo{ID: 1, Title: "alice のタスク", OwnerID: &owner}
+	as := func(user string) context.Context { return domain.WithUserID(context.Background(), user) }
+
+	t.Run("成功：自分自身はウォッチ・解除でき、所有者はほかのユーザーも追加できること", func(t *testing.T) {
+		mockRepo := new(MockTodoRepository)
+		useCase := NewTodoUseCase(mockRepo)
+		mockRepo.On("GetByID", mock.Anything, 1).Return(todo, nil)
+		mockRepo.On("AddWatcher", mock.Anything, 1, "bob").Return(nil).Twice()
+		mockRepo.On("RemoveWatcher", mock.Anything, 1, "bob").Return(nil)
+
+		_, err := useCase.AddTodoWatcher(as("bob"), 1, "bob")
+		assert.NoError(t, err)
+		_, err = useCase.RemoveTodoWatcher(as("bob"), 1, "bob")
+		assert.NoError(t, err)
+		_, err = useCase.AddTodoWatcher(as(owner), 1, "bob")
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("失敗：所有者以外はほかのユーザーを追加・解除できないこと", func(t *testing.T) {
+		mockRepo := new(MockTodoRepository)
+		useCase := NewTodoUseCase(mockRepo)
+		mockRepo.On("GetByID", mock.Anything, 1).Return(todo, nil)
+
+		_, err := useCase.AddTodoWatcher(as("bob"), 1, "carol")
+		assert.Equal(t, domain.ErrTodoForbidden, err)
+		_, err = useCase.RemoveTodoWatcher(as("bob"), 1, owner)
+		assert.Equal(t, domain.ErrTodoForbidden, err)
+		mockRepo.AssertNotCalled(t, "AddWatcher", mock.Anything, mock.Anything, mock.Anything)
+		mockRepo.AssertNotCalled(t, "RemoveWatcher", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_todos_ical_uid;
+ALTER TABLE todos DROP COLUMN IF EXISTS ical_uid;
\ No newline at end of file
@@ -0,0 +1,3 @@
+-- iCalendar からインポートしたタスクの UID（再インポート時の重複排除に使用）
+ALTER TABLE todos ADD COLUMN ical_uid TEXT;
+CREATE UNIQUE INDEX idx_todos_ical_uid ON todos (ical_uid);
\ No newline at end of file
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_todos_completed_at;
+ALTER TABLE todos DROP COLUMN IF EXISTS completed_at;
\ No newline at end of file
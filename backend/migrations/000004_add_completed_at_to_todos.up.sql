@@ -0,0 +1,7 @@
+-- 完了日時（未完了に戻した場合は NULL）
+ALTER TABLE todos ADD COLUMN completed_at TIMESTAMP WITH TIME ZONE;
+
+-- 既存の完了済みタスクは最終更新日時を完了日時とみなす
+UPDATE todos SET completed_at = COALESCE(updated_at, created_at) WHERE is_completed IS TRUE;
+
+CREATE INDEX idx_todos_completed_at ON todos (completed_at);
\ No newline at end of file
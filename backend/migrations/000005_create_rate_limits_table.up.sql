@@ -0,0 +1,8 @@
+-- レート制限の状態（キーごとの理論到着時刻）。複数インスタンスで制限を共有するために使う
+-- 失われても制限が一時的に緩むだけなので、WAL を書かない UNLOGGED テーブルにする
+CREATE UNLOGGED TABLE rate_limits (
+    key TEXT PRIMARY KEY,
+    tat TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX idx_rate_limits_tat ON rate_limits (tat);
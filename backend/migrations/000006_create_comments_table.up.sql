@@ -0,0 +1,21 @@
+-- タスクへのコメント。タスクを削除するとコメント（と編集履歴）も削除する
+CREATE TABLE comments (
+    id SERIAL PRIMARY KEY,
+    todo_id INTEGER NOT NULL REFERENCES todos (id) ON DELETE CASCADE,
+    author TEXT NOT NULL,
+    body TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_comments_todo_id ON comments (todo_id, created_at);
+
+-- 編集で置き換えられる前の本文（編集のたびに1行追加する）
+CREATE TABLE comment_revisions (
+    id SERIAL PRIMARY KEY,
+    comment_id INTEGER NOT NULL REFERENCES comments (id) ON DELETE CASCADE,
+    body TEXT NOT NULL,
+    edited_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX idx_comment_revisions_comment_id ON comment_revisions (comment_id);
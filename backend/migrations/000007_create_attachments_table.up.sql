@@ -0,0 +1,14 @@
+-- タスクの添付ファイルの情報。中身は sha256 をキーにして BlobStore に保存する（同じ中身は1つだけ）
+-- タスクを削除すると情報も削除する
+CREATE TABLE attachments (
+    id SERIAL PRIMARY KEY,
+    todo_id INTEGER NOT NULL REFERENCES todos (id) ON DELETE CASCADE,
+    filename TEXT NOT NULL,
+    content_type TEXT NOT NULL,
+    size BIGINT NOT NULL CHECK (size > 0),
+    sha256 CHAR(64) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_attachments_todo_id ON attachments (todo_id, created_at);
+CREATE INDEX idx_attachments_sha256 ON attachments (sha256);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS todo_watchers;
+DROP INDEX IF EXISTS idx_todos_assignee_id;
+ALTER TABLE todos DROP COLUMN IF EXISTS assignee_id;
+ALTER TABLE todos DROP COLUMN IF EXISTS owner_id;
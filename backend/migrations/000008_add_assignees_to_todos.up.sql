@@ -0,0 +1,11 @@
+-- タスクを作成したユーザーと担当者（認証せずに作成したタスクは所有者なしで、誰でも操作できる）
+ALTER TABLE todos ADD COLUMN owner_id TEXT;
+ALTER TABLE todos ADD COLUMN assignee_id TEXT;
+CREATE INDEX idx_todos_assignee_id ON todos (assignee_id);
+
+-- タスクをウォッチしているユーザー。タスクを削除するとウォッチも削除する
+CREATE TABLE todo_watchers (
+    todo_id INTEGER NOT NULL REFERENCES todos (id) ON DELETE CASCADE,
+    user_id TEXT NOT NULL,
+    PRIMARY KEY (todo_id, user_id)
+);
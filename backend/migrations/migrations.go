@@ -0,0 +1,49 @@
+// Package migrations は SQL マイグレーションファイルをバイナリに同梱します
+package migrations
+
+import (
+	"embed"
+	"io/fs"
+	"strconv"
+	"strings"
+)
+
+// FS は golang-migrate 形式（{version}_{title}.up.sql / .down.sql）の Postgres 用マイグレーションファイルです
+//
+//go:embed *.sql
+var FS embed.FS
+
+// sqliteFS は SQLite 用のマイグレーションファイルです（ファイル名の形式は FS と同じ）
+//
+//go:embed sqlite/*.sql
+var sqliteFS embed.FS
+
+// SQLite は SQLite 用のマイグレーションファイルを FS と同じくルート直下に並べたものです
+var SQLite, _ = fs.Sub(sqliteFS, "sqlite")
+
+// LatestVersion は同梱している Postgres 用マイグレーションの最新バージョンを返します
+func LatestVersion() uint {
+	return latestVersion(FS)
+}
+
+// LatestSQLiteVersion は同梱している SQLite 用マイグレーションの最新バージョンを返します
+func LatestSQLiteVersion() uint {
+	return latestVersion(SQLite)
+}
+
+func latestVersion(fsys fs.FS) uint {
+	entries, _ := fs.ReadDir(fsys, ".")
+
+	var latest uint
+	for _, e := range entries {
+		prefix, _, ok := strings.Cut(e.Name(), "_")
+		if !ok {
+			continue
+		}
+		v, err := strconv.ParseUint(prefix, 10, 64)
+		if err == nil && uint(v) > latest {
+			latest = uint(v)
+		}
+	}
+	return latest
+}
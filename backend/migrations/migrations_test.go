@@ -0,0 +1,35 @@
+package migrations
+
+import (
+	"io/fs"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLatestVersion(t *testing.T) {
+	// バージョンは 1 からの連番で振る
+	ups, err := fs.Glob(FS, "*.up.sql")
+	assert.NoError(t, err)
+	assert.Equal(t, uint(len(ups)), LatestVersion())
+
+	ups, err = fs.Glob(SQLite, "*.up.sql")
+	assert.NoError(t, err)
+	assert.Equal(t, uint(len(ups)), LatestSQLiteVersion())
+}
+
+func TestFS(t *testing.T) {
+	for name, fsys := range map[string]fs.FS{"postgres": FS, "sqlite": SQLite} {
+		t.Run(name, func(t *testing.T) {
+			// すべての up に対応する down が同梱されていること
+			ups, err := fs.Glob(fsys, "*.up.sql")
+			assert.NoError(t, err)
+			assert.NotEmpty(t, ups)
+			for _, up := range ups {
+				_, err := fs.Stat(fsys, strings.TrimSuffix(up, ".up.sql")+".down.sql")
+				assert.NoError(t, err, up)
+			}
+		})
+	}
+}
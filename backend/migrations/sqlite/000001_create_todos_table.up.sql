@@ -0,0 +1,17 @@
+-- SQLite 版のスキーマ（Postgres の 000001〜000004 を適用した状態に相当）
+-- 日時は Go 側で UTC に揃えて保存する
+CREATE TABLE IF NOT EXISTS todos (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    title TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    is_completed BOOLEAN NOT NULL DEFAULT 0,
+    priority TEXT DEFAULT 'medium' CHECK (priority IN ('low', 'medium', 'high')),
+    due_date DATETIME,
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME,
+    completed_at DATETIME,
+    ical_uid TEXT
+);
+
+CREATE UNIQUE INDEX idx_todos_ical_uid ON todos (ical_uid);
+CREATE INDEX idx_todos_completed_at ON todos (completed_at);
@@ -0,0 +1,20 @@
+-- Postgres の 000006 に相当。外部キーは NewSQLiteDB で有効にしている（PRAGMA foreign_keys）
+CREATE TABLE IF NOT EXISTS comments (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    todo_id INTEGER NOT NULL REFERENCES todos (id) ON DELETE CASCADE,
+    author TEXT NOT NULL,
+    body TEXT NOT NULL,
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL
+);
+
+CREATE INDEX idx_comments_todo_id ON comments (todo_id, created_at);
+
+CREATE TABLE IF NOT EXISTS comment_revisions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    comment_id INTEGER NOT NULL REFERENCES comments (id) ON DELETE CASCADE,
+    body TEXT NOT NULL,
+    edited_at DATETIME NOT NULL
+);
+
+CREATE INDEX idx_comment_revisions_comment_id ON comment_revisions (comment_id);
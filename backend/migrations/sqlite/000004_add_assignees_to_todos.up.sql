@@ -0,0 +1,10 @@
+-- Postgres の 000008 に相当
+ALTER TABLE todos ADD COLUMN owner_id TEXT;
+ALTER TABLE todos ADD COLUMN assignee_id TEXT;
+CREATE INDEX idx_todos_assignee_id ON todos (assignee_id);
+
+CREATE TABLE IF NOT EXISTS todo_watchers (
+    todo_id INTEGER NOT NULL REFERENCES todos (id) ON DELETE CASCADE,
+    user_id TEXT NOT NULL,
+    PRIMARY KEY (todo_id, user_id)
+);
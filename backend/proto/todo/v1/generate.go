@@ -0,0 +1,4 @@
+package todov1
+
+// todo.proto を変更したら protoc (protoc-gen-go / protoc-gen-go-grpc) で再生成してください
+//go:generate protoc -I ../.. --go_out=../.. --go_opt=paths=source_relative --go-grpc_out=../.. --go-grpc_opt=paths=source_relative todo/v1/todo.proto
@@ -1,3 +1,5 @@
+import { type Stats } from '../types/todo';
+
 const API_URL = 'http://localhost:8080/todos';
 
 // 一覧取得
@@ -39,4 +41,16 @@ export const deleteTodo = async (id: number): Promise<void> => {
   if (!response.ok) {
     throw new Error('削除に失敗しました');
   }
-};
\ No newline at end of file
+};
+
+// 統計情報（サーバー側で集計）
+export const fetchStats = async (params: { from?: string; to?: string; tz?: string } = {}): Promise<Stats> => {
+  const query = new URLSearchParams(
+    Object.entries(params).filter((entry): entry is [string, string] => !!entry[1])
+  );
+  const response = await fetch(`http://localhost:8080/stats?${query}`);
+  if (!response.ok) {
+    throw new Error('統計情報の取得に失敗しました');
+  }
+  return await response.json();
+};
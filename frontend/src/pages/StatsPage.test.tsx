@@ -1,11 +1,11 @@
 import { render, screen, act } from '@testing-library/react'; // actを追加
 import { describe, it, expect, vi, beforeEach, afterEach } from 'vitest';
 import { StatsPage } from './StatsPage';
-import { useTodos } from './../contexts/TodoContext';
+import * as api from './../api/todo';
+import { type Stats } from '../types/todo';
 
-vi.mock('./../contexts/TodoContext', () => ({
-  useTodos: vi.fn(),
-}));
+// 集計はサーバー側で行うため、fetchStats をモック化する
+vi.mock('./../api/todo');
 
 // RechartsのResponsiveContainerがテスト環境(JSDOM)でサイズ0にならないためのモック
 vi.mock('recharts', async () => {
@@ -18,55 +18,68 @@ vi.mock('recharts', async () => {
   };
 });
 
+const mockStats = (overrides: Partial<Stats>): Stats => ({
+  total: 0,
+  completed: 0,
+  active: 0,
+  completion_rate: 0,
+  by_priority: { low: 0, medium: 0, high: 0 },
+  overdue: 0,
+  due_today: 0,
+  due_this_week: 0,
+  completed_per_day: [],
+  ...overrides,
+});
+
 describe('StatsPage', () => {
   beforeEach(() => {
     vi.clearAllMocks();
-    vi.useFakeTimers(); // 仮想タイマーを有効にする
   });
 
-  afterEach(() => {
-    vi.useRealTimers(); // タイマーをリセットする
-  });
+  describe('読み込み中', () => {
+    beforeEach(() => {
+      vi.useFakeTimers(); // 仮想タイマーを有効にする
+    });
 
-  it('読み込み中のときは「読み込み中...」が表示されること', async () => {
-    (useTodos as any).mockReturnValue({
-      todos: [],
-      loading: true,
+    afterEach(() => {
+      vi.useRealTimers(); // タイマーをリセットする
     });
 
-    render(<StatsPage />);
-    
-    // 最初は LoadingDelay によって表示されていない
-    expect(screen.queryByText('読み込み中...')).not.toBeInTheDocument();
+    it('読み込み中のときは「読み込み中...」が表示されること', async () => {
+      // 応答が返らないままにする
+      vi.mocked(api.fetchStats).mockReturnValue(new Promise(() => {}));
 
-    // 時間を 300ms 進める
-    act(() => {
-      vi.advanceTimersByTime(300);
-    });
+      render(<StatsPage />);
 
-    // 時間経過後に表示されていることを確認
-    expect(screen.getByText('読み込み中...')).toBeInTheDocument();
-  });
+      // 最初は LoadingDelay によって表示されていない
+      expect(screen.queryByText('読み込み中...')).not.toBeInTheDocument();
+
+      // 時間を 300ms 進める
+      act(() => {
+        vi.advanceTimersByTime(300);
+      });
 
-  it('タスクの統計（件数と達成率）が正しく計算・表示されること', async () => {
-    const mockTodos = [
-      { id: 1, title: 'T1', is_completed: true },
-      { id: 2, title: 'T2', is_completed: true },
-      { id: 3, title: 'T3', is_completed: false },
-      { id: 4, title: 'T4', is_completed: false },
-    ];
-
-    (useTodos as any).mockReturnValue({
-      todos: mockTodos,
-      loading: false,
+      // 時間経過後に表示されていることを確認
+      expect(screen.getByText('読み込み中...')).toBeInTheDocument();
     });
+  });
+
+  it('サーバーで集計した件数と達成率が表示されること', async () => {
+    vi.mocked(api.fetchStats).mockResolvedValue(
+      mockStats({ total: 4, completed: 2, active: 2, completion_rate: 50 })
+    );
 
     render(<StatsPage />);
 
     // 文字が分割されている場合は正規表現を使うか、含んでいるかを確認する
-    expect(screen.getByText(/50/)).toBeInTheDocument();
+    expect(await screen.findByText(/50/)).toBeInTheDocument();
     expect(screen.getByText('達成度')).toBeInTheDocument();
-    
+    expect(api.fetchStats).toHaveBeenCalledTimes(1);
+    // 期限の「今日」「今週」はブラウザのタイムゾーンで集計させる
+    expect(api.fetchStats).toHaveBeenCalledWith({
+      tz: Intl.DateTimeFormat().resolvedOptions().timeZone,
+    });
+
     // 完了済み: 2件
     const completedCard = screen.getByText('完了済み').closest('div');
     expect(completedCard).toHaveTextContent('2件');
@@ -75,37 +88,35 @@ describe('StatsPage', () => {
     expect(activeCard).toHaveTextContent('2件');
   });
 
-  it('タスクが0件のとき、達成率が0%と表示されること', () => {
-    (useTodos as any).mockReturnValue({ todos: [], loading: false });
+  it('タスクが0件のとき、達成率が0%と表示されること', async () => {
+    vi.mocked(api.fetchStats).mockResolvedValue(mockStats({}));
     render(<StatsPage />);
 
     // 1. 中央の「達成度」というラベルのすぐ上にある「0」を特定する
-    // コンテキストを含めた文字列、または要素の階層で特定します
-    const rateElement = screen.getByText('達成度').previousElementSibling;
+    const rateElement = (await screen.findByText('達成度')).previousElementSibling;
     expect(rateElement).toHaveTextContent('0%');
 
-    // 2. 画面上の「0」をすべて取得して、3つあることを確認する
-    const zeroCounts = screen.getAllByText(/0/);
-    expect(zeroCounts).toHaveLength(3);
-    
-    // 3. 個別にカードの中身を確認する（より確実な方法）
+    // 2. 個別にカードの中身を確認する
     const completedCard = screen.getByText('完了済み').closest('div');
     expect(completedCard).toHaveTextContent('0件');
   });
 
-  // 円グラフ（SVG）のテストは複雑なため、計算結果の数値を信じる形に変更するか、
-  // もしプログレスバーを残していないならこのテストケース自体を削除/調整します。
-  it('達成率の数値が中央に表示されていること', () => {
-    const mockTodos = [
-      { id: 1, title: 'T1', is_completed: true },
-      { id: 2, title: 'T2', is_completed: false },
-      { id: 3, title: 'T3', is_completed: false },
-    ]; // 33%
-    (useTodos as any).mockReturnValue({ todos: mockTodos, loading: false });
+  it('達成率は整数に丸めて中央に表示されること', async () => {
+    vi.mocked(api.fetchStats).mockResolvedValue(
+      mockStats({ total: 3, completed: 1, active: 2, completion_rate: 33.33 })
+    );
 
     render(<StatsPage />);
-    
-    // 33という数字が表示されているか確認
-    expect(screen.getByText(/33/)).toBeInTheDocument();
+
+    expect(await screen.findByText('33%')).toBeInTheDocument();
   });
-});
\ No newline at end of file
+
+  it('取得に失敗した場合はエラーメッセージが表示されること', async () => {
+    vi.mocked(api.fetchStats).mockRejectedValue(new Error('統計情報の取得に失敗しました'));
+
+    render(<StatsPage />);
+
+    expect(await screen.findByText('統計情報の取得に失敗しました')).toBeInTheDocument();
+    expect(screen.queryByText('達成度')).not.toBeInTheDocument();
+  });
+});
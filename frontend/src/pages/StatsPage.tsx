@@ -1,11 +1,34 @@
-import { useTodos } from '../contexts/TodoContext';
+import { useEffect, useState } from 'react';
+import { fetchStats } from '../api/todo';
+import { type Stats } from '../types/todo';
 import { LoadingDelay } from '../components/LoadingDelay';
 import { PieChart, Pie, Cell, ResponsiveContainer, Tooltip } from 'recharts';
 
 export const StatsPage = () => {
-  const { todos, loading } = useTodos();
+  // 集計はサーバー側で行う（タスクを全件取得してブラウザで数えない）
+  const [stats, setStats] = useState<Stats | null>(null);
+  const [error, setError] = useState<string | null>(null);
 
-  if (loading) {
+  useEffect(() => {
+    let cancelled = false;
+    // 「今日」「今週」をサーバーの時刻ではなく、ブラウザのタイムゾーンで数える
+    fetchStats({ tz: Intl.DateTimeFormat().resolvedOptions().timeZone })
+      .then((data) => {
+        if (!cancelled) setStats(data);
+      })
+      .catch((err: unknown) => {
+        if (!cancelled) setError(err instanceof Error ? err.message : '統計情報の取得に失敗しました');
+      });
+    return () => {
+      cancelled = true;
+    };
+  }, []);
+
+  if (error) {
+    return <div className="text-center py-10 text-red-500">{error}</div>;
+  }
+
+  if (!stats) {
     return (
       <LoadingDelay delay={300}>
         <div className="text-center py-10 text-slate-400">読み込み中...</div>
@@ -13,10 +36,9 @@ export const StatsPage = () => {
     );
   }
 
-  const totalCount = todos.length;
-  const completedCount = todos.filter((t) => t.is_completed).length;
-  const activeCount = totalCount - completedCount;
-  const completionRate = totalCount > 0 ? Math.round((completedCount / totalCount) * 100) : 0;
+  const completedCount = stats.completed;
+  const activeCount = stats.active;
+  const completionRate = Math.round(stats.completion_rate);
 
   // Recharts用のデータ構造
   const data = [
@@ -6,6 +6,20 @@ export interface Todo {
   priority: 'low' | 'medium' | 'high';
   due_date: string | null;
   created_at: string;
+  completed_at: string | null;
 }
 
-export type FilterType = 'all' | 'active' | 'completed';
\ No newline at end of file
+export type FilterType = 'all' | 'active' | 'completed';
+
+// GET /stats のレスポンス
+export interface Stats {
+  total: number;
+  completed: number;
+  active: number;
+  completion_rate: number;
+  by_priority: Record<'low' | 'medium' | 'high', number>;
+  overdue: number;
+  due_today: number;
+  due_this_week: number;
+  completed_per_day: { date: string; count: number }[];
+}